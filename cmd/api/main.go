@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,14 +11,28 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	handlers "github.com/rafabene/avantpro-backend/internal/handlers/http"
 	"github.com/rafabene/avantpro-backend/internal/handlers/middleware"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
 	"github.com/rafabene/avantpro-backend/internal/infrastructure/config"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/denylist"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/email"
 	"github.com/rafabene/avantpro-backend/internal/infrastructure/i18n"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/lockout"
 	"github.com/rafabene/avantpro-backend/internal/infrastructure/logging"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/notification"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/oauth"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/outbox"
 	"github.com/rafabene/avantpro-backend/internal/infrastructure/persistence/postgres"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/ratelimit"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/webauthn"
+	"github.com/rafabene/avantpro-backend/internal/services"
 
 	_ "github.com/rafabene/avantpro-backend/docs" // Import generated docs
 )
@@ -63,6 +78,37 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// Conectar ao Redis: sem REDIS_URL, a denylist de access tokens, os contadores
+	// de rate limiting e as falhas de login ficam em memória, o que só é correto
+	// com uma única instância
+	var tokenDenylist domain.TokenDenylist = denylist.NewMemoryDenylist()
+	var rateLimiter domain.RateLimiter = ratelimit.NewMemoryLimiter()
+	var loginAttempts domain.LoginAttemptStore = lockout.NewMemoryStore()
+	var redisClient *redis.Client
+	if cfg.Redis.URL != "" {
+		redisOptions, err := redis.ParseURL(cfg.Redis.URL)
+		if err != nil {
+			logger.Error("invalid REDIS_URL", "error", err)
+			log.Fatal(err)
+		}
+		redisClient = redis.NewClient(redisOptions)
+
+		pingCtx, cancelPing := context.WithTimeout(context.Background(), 5*time.Second)
+		err = redisClient.Ping(pingCtx).Err()
+		cancelPing()
+		if err != nil {
+			logger.Error("failed to connect to redis", "error", err)
+			log.Fatal(err)
+		}
+
+		tokenDenylist = denylist.NewRedisDenylist(redisClient)
+		rateLimiter = ratelimit.NewRedisLimiter(redisClient)
+		loginAttempts = lockout.NewRedisStore(redisClient)
+		logger.Info("redis connected")
+	} else {
+		logger.Warn("REDIS_URL not set, using in-memory token denylist, rate limiter and login lockout")
+	}
+
 	// Inicializar i18n
 	i18nService, err := i18n.NewService("./internal/infrastructure/i18n/locales", "en")
 	if err != nil {
@@ -74,14 +120,164 @@ func main() {
 		"supported_languages", i18nService.GetSupportedLanguages(),
 	)
 
+	// Inicializar JWT
+	jwtService, err := auth.NewJWTService(&cfg.JWT)
+	if err != nil {
+		logger.Error("failed to initialize jwt service", "error", err)
+		log.Fatal(err)
+	}
+
+	// Inicializar hash de senhas (argon2id; hashes bcrypt antigos são migrados no login)
+	passwordHasher, err := auth.NewPasswordHasher(&cfg.Password)
+	if err != nil {
+		logger.Error("failed to initialize password hasher", "error", err)
+		log.Fatal(err)
+	}
+
+	// Inicializar relying party dos passkeys (WebAuthn)
+	relyingParty, err := webauthn.NewRelyingParty(&cfg.WebAuthn)
+	if err != nil {
+		logger.Error("failed to initialize webauthn relying party", "error", err)
+		log.Fatal(err)
+	}
+
 	// Inicializar repositories
-	_ = postgres.NewUnitOfWork(db) // TODO: Usar quando implementar specs
+	uow := postgres.NewUnitOfWork(db)
+	userRepo := postgres.NewUserRepository(db)
+	accountRepo := postgres.NewUserAccountRepository(db)
+	activationRepo := postgres.NewActivationTokenRepository(db)
+	passwordResetRepo := postgres.NewPasswordResetTokenRepository(db)
+	inviteRepo := postgres.NewInviteRepository(db)
+	refreshTokenRepo := postgres.NewRefreshTokenRepository(db)
+	mfaRepo := postgres.NewMFARepository(db)
+	passkeyRepo := postgres.NewPasskeyRepository(db)
+	passkeyChallengeRepo := postgres.NewPasskeyChallengeRepository(db)
+	orgRepo := postgres.NewOrganizationRepository(db)
+	memberRepo := postgres.NewOrganizationMemberRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	oauthStateRepo := postgres.NewOAuthStateRepository(db)
+	identityRepo := postgres.NewUserIdentityRepository(db)
+	ssoConfigRepo := postgres.NewSSOConfigRepository(db)
+
+	// Inicializar notificações: email quando o SMTP estiver configurado, log caso contrário
+	var delivery domain.AccountNotifier = notification.NewLogNotifier(logger)
+	if cfg.SMTP.Host != "" {
+		sender, err := email.NewSMTPSender(&cfg.SMTP)
+		if err != nil {
+			logger.Error("failed to initialize smtp sender", "error", err)
+			log.Fatal(err)
+		}
+		renderer, err := email.NewRenderer(i18nService)
+		if err != nil {
+			logger.Error("failed to load email templates", "error", err)
+			log.Fatal(err)
+		}
+		delivery = notification.NewEmailNotifier(sender, renderer, cfg.Server.AppURL)
+		logger.Info("email notifications enabled", "smtp_host", cfg.SMTP.Host)
+	}
+
+	// Inicializar outbox: os services gravam emails e eventos na transação,
+	// e o dispatcher os entrega em segundo plano
+	outboxWriter := outbox.NewWriter(outboxRepo)
+	dispatcher := outbox.NewDispatcher(outboxRepo, logger)
+	outbox.RegisterNotifier(dispatcher, delivery)
+
+	// Inicializar provedores OAuth2: apenas os que têm credenciais configuradas
+	var oauthProviders []domain.OAuthProvider
+	if cfg.OAuth.GoogleClientID != "" {
+		oauthProviders = append(oauthProviders, oauth.NewGoogleProvider(oauth.Config{
+			ClientID:     cfg.OAuth.GoogleClientID,
+			ClientSecret: cfg.OAuth.GoogleClientSecret,
+			RedirectURL:  cfg.OAuth.RedirectURL + "/api/v1/auth/oauth/" + oauth.ProviderGoogle + "/callback",
+		}))
+	}
+	if cfg.OAuth.GitHubClientID != "" {
+		oauthProviders = append(oauthProviders, oauth.NewGitHubProvider(oauth.Config{
+			ClientID:     cfg.OAuth.GitHubClientID,
+			ClientSecret: cfg.OAuth.GitHubClientSecret,
+			RedirectURL:  cfg.OAuth.RedirectURL + "/api/v1/auth/oauth/" + oauth.ProviderGitHub + "/callback",
+		}))
+	}
+	for _, p := range oauthProviders {
+		logger.Info("oauth provider enabled", "provider", p.Name())
+	}
 
 	// Inicializar services
-	// TODO: Adicionar services conforme specs
+	lockoutService := services.NewLockoutService(loginAttempts, accountRepo, outboxWriter, logger)
+	authService := services.NewAuthService(userRepo, refreshTokenRepo, mfaRepo, uow, jwtService, passwordHasher, lockoutService, logger)
+	mfaService := services.NewMFAService(userRepo, mfaRepo, outboxWriter, uow, logger)
+	passkeyService := services.NewPasskeyService(relyingParty, passkeyRepo, passkeyChallengeRepo, userRepo, authService, logger)
+	sessionService := services.NewSessionService(refreshTokenRepo, tokenDenylist, jwtService.AccessExpiry(), logger)
+	orgService := services.NewOrganizationService(orgRepo, memberRepo, userRepo, lockoutService, uow, logger)
+	corsOriginService := services.NewCORSOriginService(orgRepo, logger)
+	userService := services.NewUserService(
+		userRepo, accountRepo, activationRepo, orgRepo, memberRepo,
+		authService, outboxWriter, outboxWriter, uow, logger,
+	)
+	inviteService := services.NewInviteService(
+		inviteRepo, memberRepo, userRepo, accountRepo,
+		authService, outboxWriter, outboxWriter, uow, logger,
+	)
+	passwordResetService := services.NewPasswordResetService(
+		userRepo, accountRepo, passwordResetRepo, refreshTokenRepo,
+		authService, lockoutService, outboxWriter, outboxWriter, uow, logger,
+	)
+	oauthService := services.NewOAuthService(
+		oauthProviders, oauthStateRepo, identityRepo, userRepo, accountRepo,
+		authService, uow, logger,
+	)
+	ssoService := services.NewSSOService(
+		oauth.NewOIDCClient(oauth.OIDCConfig{}), net.DefaultResolver, ssoConfigRepo, oauthStateRepo, identityRepo, userRepo, accountRepo, memberRepo,
+		authService, func(organizationID string) string {
+			return cfg.OAuth.RedirectURL + "/api/v1/auth/sso/" + organizationID + "/callback"
+		},
+		uow, logger,
+	)
+
+	// Modo de autenticação por cookie do frontend web (specs/functional/user-registration.md, seção 8.6)
+	authCookies, err := middleware.NewAuthCookies(&cfg.Cookies, jwtService, cfg.JWT.Secret)
+	if err != nil {
+		logger.Error("invalid auth cookie configuration", "error", err)
+		log.Fatal(err)
+	}
 
 	// Inicializar handlers
-	// TODO: Adicionar handlers conforme specs
+	authHandler := handlers.NewAuthHandler(authService, authCookies)
+	passwordResetHandler := handlers.NewPasswordResetHandler(passwordResetService)
+	mfaHandler := handlers.NewMFAHandler(mfaService)
+	passkeyHandler := handlers.NewPasskeyHandler(passkeyService, authCookies)
+	sessionHandler := handlers.NewSessionHandler(sessionService, authCookies)
+	orgHandler := handlers.NewOrganizationHandler(orgService)
+	userHandler := handlers.NewUserHandler(userService, authCookies)
+	inviteHandler := handlers.NewInviteHandler(inviteService, authCookies)
+	oauthHandler := handlers.NewOAuthHandler(oauthService, authCookies)
+	ssoHandler := handlers.NewSSOHandler(ssoService, authCookies)
+
+	// Inicializar middlewares de autenticação, permissões e rate limiting
+	authMiddleware := middleware.NewAuthMiddleware(jwtService, tokenDenylist)
+	permissions := middleware.NewPermissionMiddleware(orgService)
+	limiter := middleware.NewRateLimiter(rateLimiter)
+
+	// CORS (specs/functional/user-registration.md, seção 8.10): credentials só
+	// para os origins da configuração; origins das organizações entram sem elas
+	cors, err := middleware.NewCORS(middleware.CORSPolicy{
+		AllowedOrigins:      cfg.CORS.OriginList(),
+		AllowCredentials:    true,
+		MaxAge:              time.Duration(cfg.CORS.MaxAge) * time.Second,
+		OrganizationOrigins: true,
+	}, corsOriginService)
+	if err != nil {
+		logger.Error("invalid CORS_ALLOWED_ORIGINS", "error", err)
+		log.Fatal(err)
+	}
+	// O health check pode ser lido de qualquer origin (ex: dashboards de monitoramento)
+	if err := cors.Route("/health", middleware.CORSPolicy{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET"},
+		MaxAge:         time.Duration(cfg.CORS.MaxAge) * time.Second,
+	}); err != nil {
+		log.Fatal(err)
+	}
 
 	// Setup Gin
 	if cfg.Env == "production" {
@@ -91,12 +287,21 @@ func main() {
 	}
 
 	router := gin.New()
-	router.Use(gin.Logger())   // Middleware de logging
-	router.Use(gin.Recovery()) // Middleware de recovery para panics
+	// Sem proxies confiáveis, X-Forwarded-For é ignorado e o IP do cliente é o da
+	// conexão; do contrário, os limites por IP poderiam ser contornados
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxyList()); err != nil {
+		logger.Error("invalid TRUSTED_PROXIES", "error", err)
+		log.Fatal(err)
+	}
+	router.Use(gin.Logger())                              // Middleware de logging
+	router.Use(gin.Recovery())                            // Middleware de recovery para panics
+	router.Use(middleware.SecurityHeaders(&cfg.Security)) // Headers de segurança (CSP, HSTS, X-Frame-Options)
 
 	// Swagger documentation - apenas em development
 	if cfg.Env == "development" {
-		router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+		router.GET("/swagger/*any",
+			middleware.ContentSecurityPolicy(middleware.SwaggerContentSecurityPolicy),
+			ginSwagger.WrapHandler(swaggerFiles.Handler))
 		logger.Info("swagger UI enabled", "url", "http://"+cfg.Server.Host+":"+cfg.Server.Port+"/swagger/index.html")
 	}
 
@@ -110,8 +315,11 @@ func main() {
 	i18nMiddleware := middleware.NewI18nMiddleware(i18nService)
 	router.Use(i18nMiddleware.DetectLanguage())
 
+	// Middleware com IP e User-Agent do cliente, registrados nas sessões
+	router.Use(middleware.ClientInfo())
+
 	// Middleware CORS
-	router.Use(middleware.CORS(cfg.CORS.AllowedOrigins))
+	router.Use(cors.Handle())
 
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
@@ -121,8 +329,90 @@ func main() {
 		})
 	})
 
+	// Limites de requisições (specs/functional/user-registration.md, seção 8.1)
+	loginByEmail := limiter.Limit("login", domain.RateLimit{Limit: 5, Window: 15 * time.Minute}, middleware.ByEmail)
+	loginByIP := limiter.Limit("login", domain.RateLimit{Limit: 30, Window: 15 * time.Minute}, middleware.ByIP)
+	mfaCodeByIP := limiter.Limit("mfa-verify", domain.RateLimit{Limit: 10, Window: 15 * time.Minute}, middleware.ByIP)
+	mfaCodeByUser := limiter.Limit("mfa-code", domain.RateLimit{Limit: 5, Window: 15 * time.Minute}, middleware.ByUser)
+	forgotPasswordByEmail := limiter.Limit("forgot-password", domain.RateLimit{Limit: 3, Window: time.Hour}, middleware.ByEmail)
+	signupByIP := limiter.Limit("signup", domain.RateLimit{Limit: 3, Window: time.Hour}, middleware.ByIP)
+	activateByIP := limiter.Limit("activate", domain.RateLimit{Limit: 5, Window: time.Hour}, middleware.ByIP)
+	resendActivationByEmail := limiter.Limit("resend-activation", domain.RateLimit{Limit: 3, Window: time.Hour}, middleware.ByEmail)
+	acceptInviteByIP := limiter.Limit("accept-invite", domain.RateLimit{Limit: 5, Window: time.Hour}, middleware.ByIP)
+	createInviteByOrganization := limiter.Limit("create-invite", domain.RateLimit{Limit: 10, Window: 24 * time.Hour}, middleware.ByOrganization)
+
 	// API routes
-	_ = router.Group("/api/v1") // TODO: Adicionar rotas conforme specs
+	v1 := router.Group("/api/v1")
+	v1.Use(authCookies.RequireCSRF())
+
+	authGroup := v1.Group("/auth")
+	authGroup.POST("/login", loginByIP, loginByEmail, authHandler.Login)
+	authGroup.POST("/refresh", authHandler.Refresh)
+	authGroup.GET("/csrf", authHandler.CSRFToken)
+	authGroup.POST("/mfa/verify", mfaCodeByIP, authHandler.VerifyMFA)
+	authGroup.POST("/passkeys/login/options", passkeyHandler.LoginOptions)
+	authGroup.POST("/passkeys/login", passkeyHandler.Login)
+	authGroup.POST("/forgot-password", forgotPasswordByEmail, passwordResetHandler.ForgotPassword)
+	authGroup.POST("/reset-password", passwordResetHandler.ResetPassword)
+	authGroup.GET("/oauth/:provider/start", oauthHandler.Start)
+	authGroup.GET("/oauth/:provider/callback", oauthHandler.Callback)
+	authGroup.GET("/sso/:organizationId/start", ssoHandler.Start)
+	authGroup.GET("/sso/:organizationId/callback", ssoHandler.Callback)
+
+	usersGroup := v1.Group("/users")
+	usersGroup.POST("", signupByIP, userHandler.Signup)
+	usersGroup.GET("/activate", activateByIP, userHandler.Activate)
+	usersGroup.POST("/resend-activation", resendActivationByEmail, userHandler.ResendActivation)
+	usersGroup.POST("/invites/accept", acceptInviteByIP, inviteHandler.Accept)
+
+	// Rotas protegidas
+	protected := v1.Group("")
+	protected.Use(authMiddleware.RequireAuth())
+
+	protected.POST("/auth/logout", sessionHandler.Logout)
+
+	mfaGroup := protected.Group("/users/me/mfa")
+	mfaGroup.GET("", mfaHandler.Status)
+	mfaGroup.POST("/totp", mfaHandler.EnrollTOTP)
+	mfaGroup.POST("/totp/confirm", mfaCodeByUser, mfaHandler.ConfirmTOTP)
+	mfaGroup.DELETE("/totp", mfaCodeByUser, mfaHandler.DisableTOTP)
+	mfaGroup.POST("/recovery-codes", mfaCodeByUser, mfaHandler.RegenerateRecoveryCodes)
+
+	passkeyGroup := protected.Group("/users/me/passkeys")
+	passkeyGroup.GET("", passkeyHandler.List)
+	passkeyGroup.POST("/register/options", passkeyHandler.RegistrationOptions)
+	passkeyGroup.POST("/register", passkeyHandler.Register)
+	passkeyGroup.DELETE("/:id", passkeyHandler.Delete)
+
+	sessionGroup := protected.Group("/users/me/sessions")
+	sessionGroup.GET("", sessionHandler.List)
+	sessionGroup.DELETE("", sessionHandler.RevokeAll)
+	sessionGroup.DELETE("/:id", sessionHandler.Revoke)
+
+	orgGroup := protected.Group("/organizations")
+	orgGroup.POST("", orgHandler.Create)
+	orgGroup.GET("", orgHandler.List)
+	orgGroup.GET("/:id", orgHandler.Get)
+	orgGroup.PUT("/:id", orgHandler.Update)
+	orgGroup.DELETE("/:id", orgHandler.Delete)
+	orgGroup.GET("/:id/members", orgHandler.ListMembers)
+	orgGroup.POST("/:id/members", orgHandler.AddMember)
+	orgGroup.PUT("/:id/members/:userId", orgHandler.UpdateMemberRole)
+	orgGroup.DELETE("/:id/members/:userId", orgHandler.RemoveMember)
+	orgGroup.POST("/:id/members/:userId/unlock", orgHandler.UnlockMember)
+
+	inviteGroup := protected.Group("/invites")
+	inviteGroup.Use(middleware.RequireOrganization())
+	inviteGroup.POST("", permissions.RequirePermission(entities.PermissionInvitesWrite), createInviteByOrganization, inviteHandler.Create)
+	inviteGroup.GET("", permissions.RequirePermission(entities.PermissionInvitesRead), inviteHandler.List)
+	inviteGroup.DELETE("/:id", permissions.RequirePermission(entities.PermissionInvitesWrite), inviteHandler.Revoke)
+
+	ssoGroup := protected.Group("/sso")
+	ssoGroup.Use(middleware.RequireOrganization())
+	ssoGroup.GET("/config", permissions.RequirePermission(entities.PermissionSSORead), ssoHandler.GetConfig)
+	ssoGroup.PUT("/config", permissions.RequirePermission(entities.PermissionSSOWrite), ssoHandler.SaveConfig)
+	ssoGroup.POST("/config/verify-domains", permissions.RequirePermission(entities.PermissionSSOWrite), ssoHandler.VerifyDomains)
+	ssoGroup.DELETE("/config", permissions.RequirePermission(entities.PermissionSSOWrite), ssoHandler.DeleteConfig)
 
 	// HTTP Server
 	srv := &http.Server{
@@ -131,6 +421,9 @@ func main() {
 		ReadHeaderTimeout: 10 * time.Second,
 	}
 
+	// Entrega do outbox em segundo plano, junto com o servidor HTTP
+	dispatcher.Start(context.Background())
+
 	// Graceful shutdown
 	go func() {
 		logger.Info("server starting",
@@ -157,5 +450,14 @@ func main() {
 		logger.Error("server forced to shutdown", "error", err)
 	}
 
+	// Parar o dispatcher depois do servidor; mensagens pendentes são entregues na próxima execução
+	dispatcher.Stop()
+
+	if redisClient != nil {
+		if err := redisClient.Close(); err != nil {
+			logger.Error("failed to close redis client", "error", err)
+		}
+	}
+
 	logger.Info("server exited")
 }
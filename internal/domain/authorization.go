@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// MemberAuthorizer é a porta que verifica as permissões do usuário em uma organização
+// A role vem da associação à organização, não do token: o mesmo usuário pode
+// ser admin em uma organização e guest em outra
+type MemberAuthorizer interface {
+	// AuthorizeMember retorna ErrOrganizationNotFound quando o usuário não é membro,
+	// ErrMFARequiredByOrganization sem o segundo fator exigido pela organização
+	// e ErrForbidden quando a role não concede a permissão
+	AuthorizeMember(ctx context.Context, userID, organizationID string, permission entities.Permission) error
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "context"
+
+// ClientInfo identifica o cliente que originou a requisição
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// clientInfoContextKey é a chave do cliente da requisição no context.Context
+type clientInfoContextKey struct{}
+
+// WithClientInfo retorna um contexto associado ao cliente da requisição
+// Services registram esses dados nas sessões emitidas no login e na rotação
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoContextKey{}, info)
+}
+
+// ClientInfoFromContext retorna o cliente associado ao contexto (vazio se não houver)
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoContextKey{}).(ClientInfo)
+	return info
+}
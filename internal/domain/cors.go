@@ -0,0 +1,11 @@
+package domain
+
+import "context"
+
+// OrganizationOrigins é a porta para os origins CORS cadastrados pelas organizações
+// Além dos origins da configuração, o middleware CORS aceita os origins que
+// alguma organização ativa cadastrou (ex: o domínio próprio do frontend)
+type OrganizationOrigins interface {
+	// OrganizationsAllowingOrigin retorna as organizações ativas que aceitam o origin
+	OrganizationsAllowingOrigin(ctx context.Context, origin string) ([]string, error)
+}
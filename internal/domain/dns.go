@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+// TXTResolver é a porta de saída para consultas de registros DNS TXT
+// Comprova a posse dos domínios do SSO; *net.Resolver a implementa
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
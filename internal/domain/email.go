@@ -0,0 +1,16 @@
+package domain
+
+import "context"
+
+// EmailMessage é uma mensagem pronta para envio, com versões HTML e texto
+type EmailMessage struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+// EmailSender é a porta de saída para envio de emails
+type EmailSender interface {
+	Send(ctx context.Context, msg EmailMessage) error
+}
@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+// ActivationToken representa um token de ativação de conta enviado por email
+// Apenas o hash é persistido; um novo reenvio revoga os tokens anteriores
+type ActivationToken struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// IsExpired verifica se o token expirou em relação ao instante informado
+func (t *ActivationToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// IsUsed verifica se o token já ativou a conta
+func (t *ActivationToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+// IsRevoked verifica se o token foi substituído por um reenvio
+func (t *ActivationToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
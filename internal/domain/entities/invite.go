@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+)
+
+// InviteStatus representa o estado de um convite
+type InviteStatus string
+
+const (
+	InviteStatusPending  InviteStatus = "pending"
+	InviteStatusAccepted InviteStatus = "accepted"
+	InviteStatusRevoked  InviteStatus = "revoked"
+	InviteStatusExpired  InviteStatus = "expired"
+)
+
+// Invite representa um convite para entrar em uma organização
+// Apenas o hash do token é persistido
+type Invite struct {
+	ID             string
+	OrganizationID string
+	Email          valueobjects.Email
+	Role           Role
+	TokenHash      string
+	InvitedBy      string
+	Status         InviteStatus
+	ExpiresAt      time.Time
+	AcceptedAt     *time.Time
+	AcceptedBy     *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// IsExpired verifica se o convite expirou em relação ao instante informado
+func (i *Invite) IsExpired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
+
+// CurrentStatus retorna o status considerando a expiração
+// Convites pendentes vencidos são reportados como expirados
+func (i *Invite) CurrentStatus(now time.Time) InviteStatus {
+	if i.Status == InviteStatusPending && i.IsExpired(now) {
+		return InviteStatusExpired
+	}
+	return i.Status
+}
@@ -0,0 +1,34 @@
+package entities
+
+import "time"
+
+// RecoveryCodeCount é a quantidade de códigos de recuperação gerados por vez
+const RecoveryCodeCount = 10
+
+// TOTPEnrollment é o segundo fator TOTP (RFC 6238) de um usuário
+// O cadastro só passa a ser exigido no login depois de confirmado com um código válido
+type TOTPEnrollment struct {
+	UserID      string
+	Secret      string // Base32, lido pelo app autenticador
+	ConfirmedAt *time.Time
+	// LastUsedStep é o último passo de 30s aceito; códigos do mesmo passo ou
+	// anteriores são rejeitados para impedir a reutilização
+	LastUsedStep int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// IsConfirmed verifica se o usuário já confirmou o cadastro no app autenticador
+func (e *TOTPEnrollment) IsConfirmed() bool {
+	return e.ConfirmedAt != nil
+}
+
+// RecoveryCode é um código de uso único que substitui o TOTP quando o
+// usuário perde o app autenticador. Apenas o hash é persistido
+type RecoveryCode struct {
+	ID        string
+	UserID    string
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
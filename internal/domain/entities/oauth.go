@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+// OAuthState guarda o state e o verifier PKCE de um login OAuth2 em andamento
+// Apenas o hash do state é persistido; ele é consumido uma única vez no callback
+type OAuthState struct {
+	ID           string
+	Provider     string
+	StateHash    string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	UsedAt       *time.Time
+	CreatedAt    time.Time
+}
+
+// IsExpired verifica se o state expirou em relação ao instante informado
+func (s *OAuthState) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// UserIdentity vincula um usuário a uma conta num provedor OAuth2
+type UserIdentity struct {
+	ID        string
+	UserID    string
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
@@ -0,0 +1,56 @@
+package entities
+
+import "time"
+
+// OrganizationStatus representa o estado de uma organização
+type OrganizationStatus string
+
+const (
+	OrganizationStatusActive    OrganizationStatus = "active"
+	OrganizationStatusSuspended OrganizationStatus = "suspended"
+	OrganizationStatusCanceled  OrganizationStatus = "canceled"
+)
+
+// Organization representa uma empresa/cliente do sistema
+// É a raiz do isolamento de dados entre clientes
+type Organization struct {
+	ID     string
+	Name   string
+	Status OrganizationStatus
+	// RequireMFA exige que os membros acessem a organização com sessões
+	// autenticadas pelo segundo fator
+	RequireMFA bool
+	// AllowedOrigins são origins CORS extras aceitos para a organização,
+	// no formato scheme://host[:porta]
+	AllowedOrigins []string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// IsActive verifica se a organização está ativa
+func (o *Organization) IsActive() bool {
+	return o.Status == OrganizationStatusActive
+}
+
+// OrganizationMember associa um usuário a uma organização com uma role específica
+// Um mesmo usuário pode ter roles diferentes em cada organização
+type OrganizationMember struct {
+	ID             string
+	OrganizationID string
+	UserID         string
+	Role           Role
+	InvitedBy      *string
+	InvitedAt      time.Time
+	JoinedAt       *time.Time
+	CreatedAt      time.Time
+
+	// Organization é preenchida quando o repositório carrega a organização junto
+	Organization *Organization
+	// User é preenchido quando o repositório carrega o usuário junto
+	User *User
+}
+
+// HasPermission verifica se a role do membro nesta organização concede a permissão
+func (m *OrganizationMember) HasPermission(permission Permission) bool {
+	return m.Role.HasPermission(permission)
+}
@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// OutboxMessage é uma mensagem gravada na mesma transação da alteração de
+// negócio e entregue depois pelo dispatcher, com novas tentativas em caso de falha
+type OutboxMessage struct {
+	ID            string
+	Type          string
+	Payload       []byte
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     *string
+	ProcessedAt   *time.Time
+	FailedAt      *time.Time
+	CreatedAt     time.Time
+}
+
+// IsProcessed verifica se a mensagem já foi entregue
+func (m *OutboxMessage) IsProcessed() bool {
+	return m.ProcessedAt != nil
+}
+
+// IsFailed verifica se a mensagem esgotou as tentativas de entrega
+func (m *OutboxMessage) IsFailed() bool {
+	return m.FailedAt != nil
+}
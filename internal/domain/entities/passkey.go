@@ -0,0 +1,46 @@
+package entities
+
+import "time"
+
+// Cerimônias WebAuthn com challenge pendente
+const (
+	PasskeyCeremonyRegistration = "registration"
+	PasskeyCeremonyLogin        = "login"
+)
+
+// Passkey é uma credencial WebAuthn do usuário; cada usuário pode ter várias
+// (um por dispositivo ou gerenciador de senhas)
+type Passkey struct {
+	ID           string
+	UserID       string
+	CredentialID string // Base64url do ID atribuído pelo autenticador
+	PublicKey    []byte // COSE_Key
+	// SignCount é o último contador de assinaturas visto; um valor que não
+	// avança indica um possível autenticador clonado
+	SignCount      uint32
+	AAGUID         string // Modelo do autenticador
+	Transports     []string
+	Name           string
+	BackupEligible bool
+	BackupState    bool
+	LastUsedAt     *time.Time
+	CreatedAt      time.Time
+}
+
+// PasskeyChallenge é o challenge de uma cerimônia WebAuthn em andamento
+// Apenas o hash é persistido; ele é consumido uma única vez. UserID fica
+// vazio no login, quando o usuário só é conhecido pela credencial usada
+type PasskeyChallenge struct {
+	ID            string
+	UserID        string
+	Ceremony      string
+	ChallengeHash string
+	ExpiresAt     time.Time
+	UsedAt        *time.Time
+	CreatedAt     time.Time
+}
+
+// IsExpired verifica se o challenge expirou em relação ao instante informado
+func (c *PasskeyChallenge) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
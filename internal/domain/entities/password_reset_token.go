@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+// PasswordResetToken representa um token de redefinição de senha enviado por email
+// Apenas o hash é persistido; um novo pedido revoga os tokens anteriores
+type PasswordResetToken struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// IsExpired verifica se o token expirou em relação ao instante informado
+func (t *PasswordResetToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// IsUsed verifica se o token já redefiniu a senha
+func (t *PasswordResetToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+// IsRevoked verifica se o token foi substituído por um novo pedido
+func (t *PasswordResetToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
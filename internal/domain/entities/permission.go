@@ -0,0 +1,131 @@
+package entities
+
+import "strings"
+
+// Permission representa uma permissão no formato "resource.action"
+type Permission string
+
+// Registro de permissões conhecidas
+const (
+	PermissionUsersRead   Permission = "users.read"
+	PermissionUsersWrite  Permission = "users.write"
+	PermissionUsersDelete Permission = "users.delete"
+
+	PermissionSubscriptionsRead   Permission = "subscriptions.read"
+	PermissionSubscriptionsWrite  Permission = "subscriptions.write"
+	PermissionSubscriptionsCancel Permission = "subscriptions.cancel"
+
+	PermissionPaymentsRead    Permission = "payments.read"
+	PermissionPaymentsProcess Permission = "payments.process"
+
+	PermissionOrganizationsRead   Permission = "organizations.read"
+	PermissionOrganizationsWrite  Permission = "organizations.write"
+	PermissionOrganizationsDelete Permission = "organizations.delete"
+
+	PermissionMembersRead  Permission = "members.read"
+	PermissionMembersWrite Permission = "members.write"
+
+	PermissionInvitesRead  Permission = "invites.read"
+	PermissionInvitesWrite Permission = "invites.write"
+
+	PermissionSSORead  Permission = "sso.read"
+	PermissionSSOWrite Permission = "sso.write"
+)
+
+// permissions contém todas as permissões registradas
+var permissions = []Permission{
+	PermissionUsersRead,
+	PermissionUsersWrite,
+	PermissionUsersDelete,
+	PermissionSubscriptionsRead,
+	PermissionSubscriptionsWrite,
+	PermissionSubscriptionsCancel,
+	PermissionPaymentsRead,
+	PermissionPaymentsProcess,
+	PermissionOrganizationsRead,
+	PermissionOrganizationsWrite,
+	PermissionOrganizationsDelete,
+	PermissionMembersRead,
+	PermissionMembersWrite,
+	PermissionInvitesRead,
+	PermissionInvitesWrite,
+	PermissionSSORead,
+	PermissionSSOWrite,
+}
+
+// rolePermissions mapeia cada role para as permissões concedidas
+// Uma permissão "resource.*" concede todas as ações do recurso
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		"users.*",
+		"subscriptions.*",
+		"payments.*",
+		"organizations.*",
+		"members.*",
+		"invites.*",
+		"sso.*",
+	},
+	RoleUser: {
+		PermissionUsersRead,
+		PermissionSubscriptionsRead,
+		PermissionSubscriptionsWrite,
+		PermissionSubscriptionsCancel,
+		PermissionPaymentsRead,
+		PermissionOrganizationsRead,
+		PermissionMembersRead,
+	},
+	RoleGuest: {
+		PermissionOrganizationsRead,
+	},
+}
+
+// AllPermissions retorna todas as permissões registradas
+func AllPermissions() []Permission {
+	return append([]Permission(nil), permissions...)
+}
+
+// IsValid verifica se a permissão está registrada
+func (p Permission) IsValid() bool {
+	for _, registered := range permissions {
+		if p == registered {
+			return true
+		}
+	}
+	return false
+}
+
+// String retorna o valor da permissão
+func (p Permission) String() string {
+	return string(p)
+}
+
+// Grants verifica se a permissão concedida cobre a permissão requerida,
+// considerando o wildcard "resource.*"
+func (p Permission) Grants(required Permission) bool {
+	if p == required {
+		return true
+	}
+
+	resource, action, found := strings.Cut(string(p), ".")
+	if !found || action != "*" {
+		return false
+	}
+
+	requiredResource, _, _ := strings.Cut(string(required), ".")
+	return resource == requiredResource
+}
+
+// Permissions retorna as permissões concedidas à role
+func (r Role) Permissions() []Permission {
+	return append([]Permission(nil), rolePermissions[r]...)
+}
+
+// HasPermission verifica se a role concede a permissão requerida
+func (r Role) HasPermission(required Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted.Grants(required) {
+			return true
+		}
+	}
+	return false
+}
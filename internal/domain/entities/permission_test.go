@@ -0,0 +1,57 @@
+package entities
+
+import "testing"
+
+func TestPermission_Grants(t *testing.T) {
+	tests := []struct {
+		name     string
+		granted  Permission
+		required Permission
+		want     bool
+	}{
+		{"permissão exata", PermissionUsersRead, PermissionUsersRead, true},
+		{"ação diferente", PermissionUsersRead, PermissionUsersWrite, false},
+		{"wildcard do recurso", "users.*", PermissionUsersDelete, true},
+		{"wildcard de outro recurso", "users.*", PermissionPaymentsRead, false},
+		{"prefixo não é wildcard", "users", PermissionUsersRead, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.granted.Grants(tt.required); got != tt.want {
+				t.Errorf("Grants(%s, %s) = %v, esperava %v", tt.granted, tt.required, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRole_HasPermission(t *testing.T) {
+	t.Run("admin possui todas as permissões registradas", func(t *testing.T) {
+		for _, p := range AllPermissions() {
+			if !RoleAdmin.HasPermission(p) {
+				t.Errorf("esperava admin com permissão %s", p)
+			}
+		}
+	})
+
+	t.Run("user pode cancelar assinaturas mas não processar pagamentos", func(t *testing.T) {
+		if !RoleUser.HasPermission(PermissionSubscriptionsCancel) {
+			t.Error("esperava user com subscriptions.cancel")
+		}
+		if RoleUser.HasPermission(PermissionPaymentsProcess) {
+			t.Error("não esperava user com payments.process")
+		}
+	})
+
+	t.Run("guest não possui permissões de escrita", func(t *testing.T) {
+		if RoleGuest.HasPermission(PermissionSubscriptionsWrite) {
+			t.Error("não esperava guest com subscriptions.write")
+		}
+	})
+
+	t.Run("role desconhecida não possui permissões", func(t *testing.T) {
+		if Role("root").HasPermission(PermissionUsersRead) {
+			t.Error("não esperava permissão para role desconhecida")
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+// RefreshToken representa um refresh token persistido
+// Tokens emitidos a partir de um mesmo login compartilham o FamilyID,
+// permitindo revogar toda a cadeia quando um token já usado é reapresentado
+type RefreshToken struct {
+	ID        string
+	UserID    string
+	FamilyID  string
+	TokenHash string
+	// IPAddress e UserAgent identificam o cliente que recebeu o token
+	IPAddress string
+	UserAgent string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// IsExpired verifica se o token expirou em relação ao instante informado
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// IsUsed verifica se o token já foi trocado por um novo par
+func (t *RefreshToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+// IsRevoked verifica se o token foi revogado
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
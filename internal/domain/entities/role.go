@@ -0,0 +1,25 @@
+package entities
+
+// Role representa o papel de um usuário no sistema
+type Role string
+
+const (
+	RoleAdmin Role = "admin"
+	RoleUser  Role = "user"
+	RoleGuest Role = "guest"
+)
+
+// IsValid verifica se a role é um valor conhecido
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleAdmin, RoleUser, RoleGuest:
+		return true
+	default:
+		return false
+	}
+}
+
+// String retorna o valor da role
+func (r Role) String() string {
+	return string(r)
+}
@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// Session representa um login ativo do usuário
+// Corresponde a uma família de refresh tokens: o ID é o FamilyID e os dados
+// do cliente vêm do token mais recente da família
+type Session struct {
+	ID         string
+	UserID     string
+	IPAddress  string
+	UserAgent  string
+	CreatedAt  time.Time // Login que iniciou a sessão
+	LastUsedAt time.Time // Última emissão de tokens (login ou rotação)
+	ExpiresAt  time.Time
+	// Current indica a sessão do access token da requisição
+	Current bool
+}
+
+// userAgentBrowsers mapeia tokens do User-Agent para navegadores
+// A ordem importa: Edge e Opera também se anunciam como Chrome, e Chrome como Safari
+var userAgentBrowsers = []struct{ token, name string }{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"CriOS/", "Chrome"},
+	{"Safari/", "Safari"},
+}
+
+// userAgentPlatforms mapeia tokens do User-Agent para sistemas operacionais
+// A ordem importa: Android também se anuncia como Linux, e iOS como Mac OS X
+var userAgentPlatforms = []struct{ token, name string }{
+	{"Android", "Android"},
+	{"iPhone", "iOS"},
+	{"iPad", "iPadOS"},
+	{"Windows", "Windows"},
+	{"CrOS", "ChromeOS"},
+	{"Mac OS X", "macOS"},
+	{"Linux", "Linux"},
+}
+
+// Device identifica o navegador e o sistema operacional de uma sessão
+// Campos vazios indicam que o User-Agent não foi reconhecido
+type Device struct {
+	Browser string
+	OS      string
+}
+
+// Device descreve o dispositivo da sessão a partir do User-Agent
+func (s *Session) Device() Device {
+	return Device{
+		Browser: matchUserAgent(s.UserAgent, userAgentBrowsers),
+		OS:      matchUserAgent(s.UserAgent, userAgentPlatforms),
+	}
+}
+
+// matchUserAgent retorna o nome do primeiro token presente no User-Agent
+func matchUserAgent(userAgent string, candidates []struct{ token, name string }) string {
+	for _, candidate := range candidates {
+		if strings.Contains(userAgent, candidate.token) {
+			return candidate.name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package entities
+
+import "testing"
+
+func TestSession_Device(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		want      Device
+	}{
+		{
+			"chrome no windows",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36",
+			Device{Browser: "Chrome", OS: "Windows"},
+		},
+		{
+			"edge não é confundido com chrome",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36 Edg/129.0.0.0",
+			Device{Browser: "Edge", OS: "Windows"},
+		},
+		{
+			"safari no iphone",
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 18_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.0 Mobile/15E148 Safari/604.1",
+			Device{Browser: "Safari", OS: "iOS"},
+		},
+		{
+			"firefox no android",
+			"Mozilla/5.0 (Android 14; Mobile; rv:131.0) Gecko/131.0 Firefox/131.0",
+			Device{Browser: "Firefox", OS: "Android"},
+		},
+		{
+			"cliente não reconhecido",
+			"curl/8.10.1",
+			Device{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &Session{UserAgent: tt.userAgent}
+			if got := session.Device(); got != tt.want {
+				t.Errorf("Device() = %+v, esperava %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+)
+
+// ssoProviderPrefix identifica, em oauth_states e user_identities, os logins
+// feitos pelo provedor OIDC de uma organização
+const ssoProviderPrefix = "oidc:"
+
+// ssoDomainVerificationLabel é o subdomínio onde a organização publica o
+// registro TXT que comprova a posse de um domínio (ex: _avantpro-verification.acme.com)
+const ssoDomainVerificationLabel = "_avantpro-verification"
+
+// ssoDomainVerificationPrefix antecede o token no valor do registro TXT
+const ssoDomainVerificationPrefix = "avantpro-verification="
+
+// SSOConfig é a configuração do provedor OpenID Connect de uma organização (SSO corporativo)
+// O client secret nunca é devolvido pela API
+//
+// Um domínio só passa a valer depois que a organização comprova a posse dele
+// publicando o registro TXT com o seu token; sem isso, qualquer admin poderia
+// declarar domínios alheios (inclusive públicos, como gmail.com) e o seu IdP
+// criaria contas para emails que não são da organização.
+type SSOConfig struct {
+	ID                      string
+	OrganizationID          string
+	Issuer                  string
+	ClientID                string
+	ClientSecret            string
+	AllowedDomains          []string // Domínios de email declarados, em minúsculas
+	VerifiedDomains         []string // Domínios declarados cuja posse foi comprovada
+	DomainVerificationToken string   // Token publicado no registro TXT dos domínios
+	DefaultRole             Role     // Role dos membros criados no primeiro login
+	Enabled                 bool
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+}
+
+// Provider retorna o identificador do provedor da organização
+// Cada organização tem o seu, então o mesmo subject em dois issuers não colide
+func (c *SSOConfig) Provider() string {
+	return SSOProvider(c.OrganizationID)
+}
+
+// AllowsEmail verifica se o domínio do email foi declarado e teve a posse comprovada
+func (c *SSOConfig) AllowsEmail(email valueobjects.Email) bool {
+	domain := email.Domain()
+	return c.IsDomainVerified(domain) && containsDomain(c.AllowedDomains, domain)
+}
+
+// IsDomainVerified verifica se a posse do domínio foi comprovada
+func (c *SSOConfig) IsDomainVerified(domain string) bool {
+	return containsDomain(c.VerifiedDomains, domain)
+}
+
+// DomainVerificationRecord retorna o nome e o valor do registro TXT que comprova a posse do domínio
+func (c *SSOConfig) DomainVerificationRecord(domain string) (name, value string) {
+	return ssoDomainVerificationLabel + "." + domain, ssoDomainVerificationPrefix + c.DomainVerificationToken
+}
+
+func containsDomain(domains []string, domain string) bool {
+	for _, d := range domains {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// SSOProvider retorna o identificador do provedor OIDC da organização
+func SSOProvider(organizationID string) string {
+	return ssoProviderPrefix + organizationID
+}
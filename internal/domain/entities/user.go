@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+)
+
+// UserStatus representa o estado da conta do usuário
+type UserStatus string
+
+const (
+	UserStatusInactive  UserStatus = "inactive"  // Email ainda não verificado
+	UserStatusActive    UserStatus = "active"    // Conta ativa
+	UserStatusSuspended UserStatus = "suspended" // Suspensa por um admin
+)
+
+// User representa um usuário do sistema
+type User struct {
+	ID              string
+	Email           valueobjects.Email
+	Name            string
+	PasswordHash    string
+	Role            Role
+	Status          UserStatus
+	AvatarURL       *string
+	EmailVerifiedAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// IsActive verifica se a conta do usuário está ativa
+func (u *User) IsActive() bool {
+	return u.Status == UserStatusActive
+}
+
+// IsAdmin verifica se o usuário possui role de administrador
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// HasPermission verifica se a role do usuário concede a permissão
+func (u *User) HasPermission(permission Permission) bool {
+	return u.Role.HasPermission(permission)
+}
@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// Valores padrão das preferências de uma conta recém-criada
+const (
+	DefaultAccountLocale   = "pt-BR"
+	DefaultAccountTimezone = "America/Sao_Paulo"
+	DefaultAccountTheme    = "light"
+)
+
+// UserAccount guarda o perfil e as preferências de um usuário (relação 1:1)
+type UserAccount struct {
+	ID        string
+	UserID    string
+	FullName  *string
+	AvatarURL *string
+	Phone     *string
+	Locale    string
+	Timezone  string
+	Theme     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -1,6 +1,9 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 // Business errors
 // Nota: Estes são códigos de erro (message IDs para i18n).
@@ -9,8 +12,65 @@ var (
 	ErrUserNotFound       = errors.New("error.user_not_found")
 	ErrEmailAlreadyExists = errors.New("error.email_already_exists")
 	ErrInvalidCredentials = errors.New("error.invalid_credentials")
+	ErrAccountLocked      = errors.New("error.account_locked")
 	ErrUnauthorized       = errors.New("error.unauthorized")
 	ErrForbidden          = errors.New("error.forbidden")
+
+	ErrUserAccountNotFound = errors.New("error.user_account_not_found")
+	ErrAccountNotActive    = errors.New("error.account_not_active")
+
+	ErrInvalidActivationToken = errors.New("error.invalid_activation_token")
+	ErrActivationTokenExpired = errors.New("error.activation_token_expired")
+	ErrAccountAlreadyActive   = errors.New("error.account_already_active")
+
+	ErrInvalidPasswordResetToken = errors.New("error.invalid_password_reset_token")
+	ErrPasswordResetTokenExpired = errors.New("error.password_reset_token_expired")
+
+	ErrInvalidMFAToken           = errors.New("error.invalid_mfa_token")
+	ErrInvalidMFACode            = errors.New("error.invalid_mfa_code")
+	ErrMFANotEnrolled            = errors.New("error.mfa_not_enrolled")
+	ErrMFAAlreadyEnabled         = errors.New("error.mfa_already_enabled")
+	ErrMFARequiredByOrganization = errors.New("error.mfa_required_by_organization")
+	ErrMFASessionRequired        = errors.New("error.mfa_session_required")
+
+	ErrInvalidPasskey           = errors.New("error.invalid_passkey")
+	ErrPasskeyNotFound          = errors.New("error.passkey_not_found")
+	ErrPasskeyAlreadyRegistered = errors.New("error.passkey_already_registered")
+
+	ErrInvalidRefreshToken = errors.New("error.invalid_refresh_token")
+	ErrRefreshTokenReused  = errors.New("error.refresh_token_reused")
+	ErrSessionNotFound     = errors.New("error.session_not_found")
+
+	ErrOrganizationNotFound       = errors.New("error.organization_not_found")
+	ErrMemberNotFound             = errors.New("error.member_not_found")
+	ErrMemberAlreadyExists        = errors.New("error.member_already_exists")
+	ErrMemberInOtherOrganizations = errors.New("error.member_in_other_organizations")
+	ErrLastOrganizationAdmin      = errors.New("error.last_organization_admin")
+	ErrInvalidOrigin              = errors.New("error.invalid_origin")
+	ErrOriginNotAllowed           = errors.New("error.origin_not_allowed")
+
+	ErrInviteNotFound       = errors.New("error.invite_not_found")
+	ErrInvalidInviteToken   = errors.New("error.invalid_invite_token")
+	ErrInviteExpired        = errors.New("error.invite_expired")
+	ErrInviteAlreadyPending = errors.New("error.invite_already_pending")
+
+	ErrOAuthProviderNotSupported = errors.New("error.oauth_provider_not_supported")
+	ErrInvalidOAuthState         = errors.New("error.invalid_oauth_state")
+	ErrOAuthExchangeFailed       = errors.New("error.oauth_exchange_failed")
+	ErrOAuthEmailNotVerified     = errors.New("error.oauth_email_not_verified")
+	ErrIdentityNotFound          = errors.New("error.identity_not_found")
+	ErrIdentityAlreadyLinked     = errors.New("error.identity_already_linked")
+
+	ErrSSONotConfigured         = errors.New("error.sso_not_configured")
+	ErrInvalidSSOIssuer         = errors.New("error.invalid_sso_issuer")
+	ErrInvalidSSODomain         = errors.New("error.invalid_sso_domain")
+	ErrSSOEmailDomainNotAllowed = errors.New("error.sso_email_domain_not_allowed")
+	ErrSSOAccountConflict       = errors.New("error.sso_account_conflict")
+
+	ErrMissingOrganization = errors.New("error.missing_organization")
+	ErrCrossTenantAccess   = errors.New("error.cross_tenant_access")
+
+	ErrInvalidCSRFToken = errors.New("error.invalid_csrf_token")
 )
 
 // Domain errors
@@ -19,6 +79,10 @@ var (
 var (
 	ErrInvalidEmail = errors.New("error.invalid_email")
 	ErrInvalidCPF   = errors.New("error.invalid_cpf")
+
+	ErrPasswordLength   = errors.New("error.password_length")
+	ErrPasswordNoLetter = errors.New("error.password_no_letter")
+	ErrPasswordNoNumber = errors.New("error.password_no_number")
 )
 
 // ProblemType define tipos de problemas (URIs RFC 7807)
@@ -26,13 +90,14 @@ var (
 //
 //nolint:misspell
 const (
-	ProblemTypeValidation   = "/problems/validation-error"
-	ProblemTypeNotFound     = "/problems/not-found"
-	ProblemTypeConflict     = "/problems/conflict"
-	ProblemTypeUnauthorized = "/problems/unauthorized"
-	ProblemTypeForbidden    = "/problems/forbidden"
-	ProblemTypeInternal     = "/problems/internal-error"
-	ProblemTypeBadRequest   = "/problems/bad-request"
+	ProblemTypeValidation    = "/problems/validation-error"
+	ProblemTypeNotFound      = "/problems/not-found"
+	ProblemTypeConflict      = "/problems/conflict"
+	ProblemTypeUnauthorized  = "/problems/unauthorized"
+	ProblemTypeForbidden     = "/problems/forbidden"
+	ProblemTypeInternal      = "/problems/internal-error"
+	ProblemTypeBadRequest    = "/problems/bad-request"
+	ProblemTypeAccountLocked = "/problems/account-locked"
 )
 
 // DomainError representa um erro de domínio com contexto adicional
@@ -53,3 +118,16 @@ func (e *DomainError) Error() string {
 func (e *DomainError) Unwrap() error {
 	return e.Err
 }
+
+// LockedError é o ErrAccountLocked com o tempo restante do bloqueio
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return ErrAccountLocked.Error()
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrAccountLocked
+}
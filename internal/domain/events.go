@@ -0,0 +1,54 @@
+package domain
+
+import "context"
+
+// Tipos de eventos de domínio publicados pelos casos de uso
+const (
+	EventUserSignedUp   = "user.signed_up"
+	EventUserActivated  = "user.activated"
+	EventPasswordReset  = "user.password_reset"
+	EventMFAEnabled     = "user.mfa_enabled"
+	EventMFADisabled    = "user.mfa_disabled"
+	EventInviteAccepted = "invite.accepted"
+)
+
+// Event é um evento de domínio; o Payload é serializado em JSON
+type Event struct {
+	Type    string
+	Payload any
+}
+
+// UserSignedUpEvent é publicado quando um cadastro self-service é concluído
+type UserSignedUpEvent struct {
+	UserID         string `json:"user_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+// UserActivatedEvent é publicado quando a conta é ativada pelo link do email
+type UserActivatedEvent struct {
+	UserID string `json:"user_id"`
+}
+
+// PasswordResetEvent é publicado quando a senha é redefinida pelo link do email
+type PasswordResetEvent struct {
+	UserID string `json:"user_id"`
+}
+
+// MFAEvent é publicado quando o usuário ativa ou desativa o segundo fator
+type MFAEvent struct {
+	UserID string `json:"user_id"`
+	Method string `json:"method"`
+}
+
+// InviteAcceptedEvent é publicado quando um convite vira membro da organização
+type InviteAcceptedEvent struct {
+	InviteID       string `json:"invite_id"`
+	OrganizationID string `json:"organization_id"`
+	UserID         string `json:"user_id"`
+}
+
+// EventPublisher publica eventos de domínio
+// Chamado com o contexto da transação, o evento só é entregue se ela for confirmada
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
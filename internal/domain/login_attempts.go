@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// LoginFailures são as falhas de login seguidas registradas para uma chave
+// (uma conta ou um IP) e o bloqueio em vigor
+type LoginFailures struct {
+	Count       int
+	LockedUntil time.Time // Zero quando não há bloqueio
+}
+
+// LockedAt indica se a chave está bloqueada no instante informado
+func (f LoginFailures) LockedAt(now time.Time) bool {
+	return now.Before(f.LockedUntil)
+}
+
+// LoginAttemptStore é a porta para o registro de falhas de login
+// As falhas de uma chave são esquecidas após o ttl informado na última
+// falha, e os bloqueios expiram sozinhos
+type LoginAttemptStore interface {
+	// Failures retorna as falhas registradas para a chave
+	Failures(ctx context.Context, key string) (LoginFailures, error)
+	// RecordFailure soma uma falha à chave e retorna o novo total
+	RecordFailure(ctx context.Context, key string, ttl time.Duration) (int, error)
+	// Lock bloqueia a chave até o instante informado
+	Lock(ctx context.Context, key string, until time.Time) error
+	// Reset apaga as falhas e o bloqueio da chave
+	Reset(ctx context.Context, key string) error
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+// mfaContextKey é a chave, no context.Context, que indica uma sessão com segundo fator
+type mfaContextKey struct{}
+
+// WithMFAVerified retorna um contexto que indica se a sessão passou pelo segundo fator
+// Services usam a informação para aplicar a exigência de 2FA das organizações
+func WithMFAVerified(ctx context.Context, verified bool) context.Context {
+	return context.WithValue(ctx, mfaContextKey{}, verified)
+}
+
+// MFAVerifiedFromContext indica se a sessão do contexto passou pelo segundo fator
+func MFAVerifiedFromContext(ctx context.Context) bool {
+	verified, _ := ctx.Value(mfaContextKey{}).(bool)
+	return verified
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ActivationNotice contém os dados do email de ativação de conta
+type ActivationNotice struct {
+	Email            string
+	Locale           string
+	OrganizationName string
+	Token            string
+}
+
+// InviteNotice contém os dados do email de convite para uma organização
+type InviteNotice struct {
+	Email            string
+	Locale           string
+	OrganizationName string
+	InviterName      string
+	Role             string
+	Token            string
+	ExpiresAt        time.Time
+}
+
+// SignupAttemptNotice avisa o dono de um email já cadastrado sobre uma
+// nova tentativa de cadastro
+type SignupAttemptNotice struct {
+	Email  string
+	Locale string
+}
+
+// PasswordResetNotice contém os dados do email de redefinição de senha
+type PasswordResetNotice struct {
+	Email     string
+	Locale    string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// AccountLockedNotice avisa o dono da conta sobre um bloqueio por falhas de login
+type AccountLockedNotice struct {
+	Email       string
+	Locale      string
+	IPAddress   string // IP da última tentativa
+	LockedUntil time.Time
+}
+
+// AccountNotifier entrega as mensagens transacionais do ciclo de vida da conta
+type AccountNotifier interface {
+	// SendActivation entrega o token de ativação ao dono do email
+	SendActivation(ctx context.Context, notice ActivationNotice) error
+	// SendInvite entrega o convite para entrar na organização
+	SendInvite(ctx context.Context, notice InviteNotice) error
+	// SendSignupAttempt avisa que alguém tentou se cadastrar com o email
+	SendSignupAttempt(ctx context.Context, notice SignupAttemptNotice) error
+	// SendPasswordReset entrega o link de redefinição de senha
+	SendPasswordReset(ctx context.Context, notice PasswordResetNotice) error
+	// SendAccountLocked avisa que a conta foi bloqueada após falhas de login
+	SendAccountLocked(ctx context.Context, notice AccountLockedNotice) error
+}
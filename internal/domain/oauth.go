@@ -0,0 +1,43 @@
+package domain
+
+import "context"
+
+// OAuthIdentity é a identidade devolvida por um provedor OAuth2 após o login
+type OAuthIdentity struct {
+	Provider      string
+	Subject       string // Identificador estável do usuário no provedor
+	Email         string
+	EmailVerified bool
+	Name          string
+	AvatarURL     string
+}
+
+// OAuthProvider é a porta de saída para um provedor OAuth2 (Google, GitHub)
+type OAuthProvider interface {
+	// Name retorna o identificador do provedor usado nas rotas (ex: "google")
+	Name() string
+	// AuthCodeURL monta a URL de autorização com o state e o desafio PKCE do verifier
+	AuthCodeURL(state, codeVerifier string) string
+	// Exchange troca o código de autorização pela identidade do usuário
+	Exchange(ctx context.Context, code, codeVerifier string) (*OAuthIdentity, error)
+}
+
+// OIDCClientConfig contém os parâmetros do cliente OIDC de uma organização
+type OIDCClientConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+// OIDCClient é a porta de saída para provedores OpenID Connect genéricos (SSO corporativo)
+// O issuer é configurado por organização; endpoints e chaves vêm do documento de descoberta
+type OIDCClient interface {
+	// Discover valida o issuer lendo o seu documento de descoberta
+	Discover(ctx context.Context, issuer string) error
+	// AuthCodeURL monta a URL de autorização com o state, o nonce e o desafio PKCE do verifier
+	AuthCodeURL(ctx context.Context, cfg OIDCClientConfig, state, nonce, codeVerifier string) (string, error)
+	// Exchange troca o código de autorização e retorna a identidade do ID token,
+	// após verificar a assinatura (JWKS), o issuer, a audiência e o nonce
+	Exchange(ctx context.Context, cfg OIDCClientConfig, code, nonce, codeVerifier string) (*OAuthIdentity, error)
+}
@@ -0,0 +1,13 @@
+package domain
+
+// PasswordHasher é a porta para gerar e verificar hashes de senha
+type PasswordHasher interface {
+	// Hash gera o hash da senha com o algoritmo e os parâmetros atuais
+	Hash(password string) (string, error)
+	// Verify compara a senha com o hash armazenado. needsRehash indica que o
+	// hash usa um algoritmo legado ou parâmetros antigos e deve ser regravado
+	Verify(hash, password string) (ok bool, needsRehash bool)
+	// SimulateVerify executa uma verificação descartável com o mesmo custo
+	// de Verify, para não revelar pelo tempo de resposta se a conta existe
+	SimulateVerify(password string)
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimit define quantas requisições uma chave pode fazer por janela
+type RateLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+// RateLimitResult é a decisão do limitador para uma requisição
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter é o tempo até o fim da janela atual
+	ResetAfter time.Duration
+	// RetryAfter é o tempo até a próxima requisição ser aceita (zero se permitida)
+	RetryAfter time.Duration
+}
+
+// RateLimiter é a porta para os contadores de rate limiting
+// Implementações compartilhadas (Redis) aplicam o limite entre todas as réplicas
+type RateLimiter interface {
+	// Allow registra uma requisição da chave e decide se ela está dentro do limite
+	// Requisições recusadas não consomem o limite
+	Allow(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error)
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// InviteRepository define as operações de persistência de convites
+// A organização vem do contexto (domain.WithOrganizationID)
+type InviteRepository interface {
+	// Create retorna ErrInviteAlreadyPending quando já há convite pendente para o email
+	Create(ctx context.Context, invite *entities.Invite) error
+	// FindByID retorna ErrInviteNotFound quando o convite não existe
+	FindByID(ctx context.Context, id string) (*entities.Invite, error)
+	// FindByTokenHash retorna ErrInvalidInviteToken quando o hash não existe
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entities.Invite, error)
+	List(ctx context.Context) ([]*entities.Invite, error)
+	// ExpirePending marca como expirados os convites pendentes vencidos do email
+	ExpirePending(ctx context.Context, email string, now time.Time) error
+	// Revoke retorna ErrInviteNotFound quando o convite não está pendente
+	Revoke(ctx context.Context, id string) error
+	// MarkAccepted retorna ErrInvalidInviteToken quando o convite não está mais pendente
+	MarkAccepted(ctx context.Context, id, userID string, acceptedAt time.Time) error
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// MFARepository define as operações de persistência do segundo fator
+type MFARepository interface {
+	// FindTOTP retorna ErrMFANotEnrolled quando o usuário não iniciou o cadastro
+	FindTOTP(ctx context.Context, userID string) (*entities.TOTPEnrollment, error)
+	// SaveTOTP cria ou substitui o cadastro do usuário, voltando-o para não confirmado
+	SaveTOTP(ctx context.Context, enrollment *entities.TOTPEnrollment) error
+	// ConfirmTOTP marca o cadastro como confirmado e registra o passo usado
+	ConfirmTOTP(ctx context.Context, userID string, step int64) error
+	// UseTOTPStep registra o passo aceito; retorna ErrInvalidMFACode quando
+	// o passo não é posterior ao último usado (código reapresentado)
+	UseTOTPStep(ctx context.Context, userID string, step int64) error
+	// DeleteTOTP remove o cadastro e os códigos de recuperação do usuário
+	DeleteTOTP(ctx context.Context, userID string) error
+
+	// ReplaceRecoveryCodes descarta os códigos anteriores e grava os novos
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codes []*entities.RecoveryCode) error
+	// UseRecoveryCode consome o código; retorna ErrInvalidMFACode quando ele
+	// não existe ou já foi usado
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) error
+	// CountRecoveryCodes conta os códigos ainda não usados
+	CountRecoveryCodes(ctx context.Context, userID string) (int64, error)
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// OAuthStateRepository define as operações de persistência dos states OAuth2
+type OAuthStateRepository interface {
+	Create(ctx context.Context, state *entities.OAuthState) error
+	// Consume marca o state como usado e o retorna
+	// Retorna ErrInvalidOAuthState quando o hash não existe ou já foi usado
+	Consume(ctx context.Context, provider, stateHash string) (*entities.OAuthState, error)
+}
+
+// UserIdentityRepository define as operações de persistência das identidades OAuth2
+type UserIdentityRepository interface {
+	// Create retorna ErrIdentityAlreadyLinked quando a identidade já pertence a um usuário
+	Create(ctx context.Context, identity *entities.UserIdentity) error
+	// FindByProviderSubject retorna ErrIdentityNotFound quando não há vínculo
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*entities.UserIdentity, error)
+}
+
+// SSOConfigRepository define as operações de persistência da configuração de SSO
+// A organização vem do contexto (domain.WithOrganizationID)
+type SSOConfigRepository interface {
+	// Find retorna ErrSSONotConfigured quando a organização não tem SSO
+	Find(ctx context.Context) (*entities.SSOConfig, error)
+	// Save cria ou substitui a configuração da organização
+	Save(ctx context.Context, config *entities.SSOConfig) error
+	// Delete retorna ErrSSONotConfigured quando a organização não tem SSO
+	Delete(ctx context.Context) error
+}
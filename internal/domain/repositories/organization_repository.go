@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// OrganizationRepository define as operações de persistência de organizações
+type OrganizationRepository interface {
+	Create(ctx context.Context, organization *entities.Organization) error
+	FindByID(ctx context.Context, id string) (*entities.Organization, error)
+	Update(ctx context.Context, organization *entities.Organization) error
+	Delete(ctx context.Context, id string) error
+	// FindIDsByAllowedOrigin lista as organizações ativas que aceitam o origin
+	FindIDsByAllowedOrigin(ctx context.Context, origin string) ([]string, error)
+}
+
+// OrganizationMemberRepository define as operações de persistência de membros
+// As consultas ignoram organizações removidas
+type OrganizationMemberRepository interface {
+	Create(ctx context.Context, member *entities.OrganizationMember) error
+	// FindByUserID retorna todas as organizações do usuário (com Organization preenchida)
+	FindByUserID(ctx context.Context, userID string) ([]*entities.OrganizationMember, error)
+	// FindByUserAndOrganization retorna ErrMemberNotFound quando o usuário não é membro
+	FindByUserAndOrganization(ctx context.Context, userID, organizationID string) (*entities.OrganizationMember, error)
+	// FindByOrganization lista os membros da organização (com User preenchido)
+	FindByOrganization(ctx context.Context, organizationID string) ([]*entities.OrganizationMember, error)
+	CountByRole(ctx context.Context, organizationID string, role entities.Role) (int64, error)
+	UpdateRole(ctx context.Context, id string, role entities.Role) error
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// OutboxRepository define as operações de persistência do outbox transacional
+type OutboxRepository interface {
+	// Create grava a mensagem na transação do contexto, se houver
+	Create(ctx context.Context, msg *entities.OutboxMessage) error
+	// ClaimPending reserva até limit mensagens pendentes vencidas, adiando a
+	// próxima tentativa por lease para que outra instância não as entregue junto
+	ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.OutboxMessage, error)
+	// MarkProcessed marca a mensagem como entregue e descarta o payload
+	MarkProcessed(ctx context.Context, id string, processedAt time.Time) error
+	// MarkRetry registra a falha da tentativa e agenda a próxima
+	MarkRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error
+	// MarkFailed registra a falha definitiva quando as tentativas se esgotam e
+	// remove do payload o token do email, que não será mais entregue
+	MarkFailed(ctx context.Context, id string, attempts int, failedAt time.Time, lastError string) error
+}
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// PasskeyRepository define as operações de persistência dos passkeys
+type PasskeyRepository interface {
+	// Create retorna ErrPasskeyAlreadyRegistered quando a credencial já existe
+	Create(ctx context.Context, passkey *entities.Passkey) error
+	// FindByCredentialID retorna ErrPasskeyNotFound quando a credencial não existe
+	FindByCredentialID(ctx context.Context, credentialID string) (*entities.Passkey, error)
+	// ListByUser retorna os passkeys do usuário, do mais antigo ao mais recente
+	ListByUser(ctx context.Context, userID string) ([]*entities.Passkey, error)
+	// UpdateUsage grava o contador e o estado de backup do último login; retorna
+	// ErrInvalidPasskey quando o contador guardado já não é o informado em
+	// previousSignCount (dois logins concorrentes com a mesma assinatura)
+	UpdateUsage(ctx context.Context, id string, previousSignCount, signCount uint32, backupState bool) error
+	// Delete retorna ErrPasskeyNotFound quando o passkey não pertence ao usuário
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// PasskeyChallengeRepository define as operações de persistência dos challenges WebAuthn
+type PasskeyChallengeRepository interface {
+	Create(ctx context.Context, challenge *entities.PasskeyChallenge) error
+	// Consume marca o challenge como usado e o retorna
+	// Retorna ErrInvalidPasskey quando o hash não existe ou já foi usado
+	Consume(ctx context.Context, ceremony, challengeHash string) (*entities.PasskeyChallenge, error)
+}
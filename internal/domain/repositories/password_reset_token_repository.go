@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// PasswordResetTokenRepository define as operações de persistência de tokens de redefinição de senha
+type PasswordResetTokenRepository interface {
+	Create(ctx context.Context, token *entities.PasswordResetToken) error
+	// FindByHash retorna ErrInvalidPasswordResetToken quando o hash não existe
+	FindByHash(ctx context.Context, tokenHash string) (*entities.PasswordResetToken, error)
+	// MarkAsUsed retorna ErrInvalidPasswordResetToken quando o token já havia sido usado ou revogado
+	MarkAsUsed(ctx context.Context, id string) error
+	// RevokeByUser revoga todos os tokens pendentes do usuário
+	RevokeByUser(ctx context.Context, userID string) error
+	// CountCreatedSince conta os tokens emitidos para o usuário desde o instante informado
+	CountCreatedSince(ctx context.Context, userID string, since time.Time) (int64, error)
+}
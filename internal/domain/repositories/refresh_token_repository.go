@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// RefreshTokenRepository define as operações de persistência de refresh tokens
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *entities.RefreshToken) error
+	// FindByHash retorna ErrInvalidRefreshToken quando o hash não existe
+	FindByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error)
+	// MarkAsUsed retorna ErrRefreshTokenReused quando o token já havia sido usado ou revogado
+	MarkAsUsed(ctx context.Context, id string) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	// RevokeByUser revoga todos os refresh tokens ativos do usuário (todas as sessões)
+	RevokeByUser(ctx context.Context, userID string) error
+	// ListSessions retorna as sessões ativas do usuário, da mais recente para a mais antiga
+	ListSessions(ctx context.Context, userID string) ([]*entities.Session, error)
+	// RevokeSession revoga a sessão (família) do usuário
+	// Retorna ErrSessionNotFound quando a sessão não existe ou já terminou
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// UserAccountRepository define as operações de persistência do perfil do usuário
+type UserAccountRepository interface {
+	Create(ctx context.Context, account *entities.UserAccount) error
+	FindByUserID(ctx context.Context, userID string) (*entities.UserAccount, error)
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// UserRepository define as operações de persistência de usuários
+type UserRepository interface {
+	Create(ctx context.Context, user *entities.User) error
+	FindByID(ctx context.Context, id string) (*entities.User, error)
+	FindByEmail(ctx context.Context, email string) (*entities.User, error)
+	// Activate marca a conta como ativa e registra a verificação do email
+	Activate(ctx context.Context, id string, verifiedAt time.Time) error
+	// UpdatePasswordHash substitui o hash da senha do usuário
+	UpdatePasswordHash(ctx context.Context, id, passwordHash string) error
+}
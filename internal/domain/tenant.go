@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+// organizationContextKey é a chave da organização no context.Context
+type organizationContextKey struct{}
+
+// WithOrganizationID retorna um contexto associado à organização informada
+// Repositórios de tabelas de negócio usam essa organização para filtrar as queries
+func WithOrganizationID(ctx context.Context, organizationID string) context.Context {
+	return context.WithValue(ctx, organizationContextKey{}, organizationID)
+}
+
+// OrganizationIDFromContext retorna a organização associada ao contexto
+func OrganizationIDFromContext(ctx context.Context) (string, bool) {
+	organizationID, ok := ctx.Value(organizationContextKey{}).(string)
+	return organizationID, ok && organizationID != ""
+}
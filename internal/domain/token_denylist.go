@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TokenDenylist é a porta para a lista de access tokens revogados
+// Access tokens são validados só pela assinatura; a lista permite encerrá-los
+// antes de expirarem (ex: logout). Cada jti fica na lista até o token expirar;
+// uma sessão revogada entra pela SessionDenylistKey até o último access token
+// dela expirar
+type TokenDenylist interface {
+	// Revoke impede o uso do token até expiresAt
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	// IsRevoked indica se o token está na lista
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// SessionDenylistKey é a chave da denylist que revoga todos os access tokens
+// de uma sessão, inclusive os emitidos para outros dispositivos
+func SessionDenylistKey(sessionID string) string {
+	return "sid:" + sessionID
+}
+
+// AccessToken identifica o access token da requisição autenticada
+type AccessToken struct {
+	ID        string // jti
+	SessionID string // Família de refresh tokens que originou o token
+	ExpiresAt time.Time
+}
+
+// accessTokenContextKey é a chave do access token no context.Context
+type accessTokenContextKey struct{}
+
+// WithAccessToken retorna um contexto associado ao access token da requisição
+// Services usam a informação para identificar e encerrar a sessão atual
+func WithAccessToken(ctx context.Context, token AccessToken) context.Context {
+	return context.WithValue(ctx, accessTokenContextKey{}, token)
+}
+
+// AccessTokenFromContext retorna o access token associado ao contexto
+func AccessTokenFromContext(ctx context.Context) (AccessToken, bool) {
+	token, ok := ctx.Value(accessTokenContextKey{}).(AccessToken)
+	return token, ok && token.ID != ""
+}
@@ -31,6 +31,12 @@ func (e Email) String() string {
 	return e.value
 }
 
+// Domain retorna a parte do email após o "@"
+func (e Email) Domain() string {
+	_, domain, _ := strings.Cut(e.value, "@")
+	return domain
+}
+
 // isValidEmail valida o formato do email
 func isValidEmail(email string) bool {
 	if len(email) < 3 || len(email) > 254 {
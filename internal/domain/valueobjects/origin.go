@@ -0,0 +1,63 @@
+package valueobjects
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+var (
+	ErrInvalidOrigin = errors.New("invalid origin format")
+)
+
+// Origin é um value object com um origin web (scheme://host[:porta]) no
+// formato que os navegadores enviam no header Origin
+type Origin struct {
+	value string
+}
+
+// NewOrigin cria um novo Origin validado
+// Aceita apenas http e https, sem caminho, query ou credenciais; a porta
+// padrão do scheme é removida, como fazem os navegadores
+func NewOrigin(origin string) (Origin, error) {
+	u, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil {
+		return Origin{}, ErrInvalidOrigin
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return Origin{}, ErrInvalidOrigin
+	}
+	if u.Hostname() == "" || u.User != nil || u.RawQuery != "" || u.Fragment != "" || u.Opaque != "" {
+		return Origin{}, ErrInvalidOrigin
+	}
+	if u.Path != "" && u.Path != "/" {
+		return Origin{}, ErrInvalidOrigin
+	}
+	if !isValidHostname(strings.ToLower(u.Hostname())) {
+		return Origin{}, ErrInvalidOrigin
+	}
+
+	host := strings.ToLower(u.Host)
+	if port := u.Port(); (scheme == "https" && port == "443") || (scheme == "http" && port == "80") {
+		host = strings.TrimSuffix(host, ":"+port)
+	}
+
+	return Origin{value: scheme + "://" + host}, nil
+}
+
+// String retorna o valor do origin
+func (o Origin) String() string {
+	return o.value
+}
+
+// isValidHostname aceita nomes DNS, IPv4 e IPv6 (sem wildcards)
+func isValidHostname(host string) bool {
+	for _, r := range host {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '.' && r != '-' && r != ':' {
+			return false
+		}
+	}
+	return true
+}
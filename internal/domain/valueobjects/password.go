@@ -0,0 +1,41 @@
+package valueobjects
+
+import (
+	"unicode"
+
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+)
+
+const (
+	PasswordMinLength = 8
+	PasswordMaxLength = 72 // Limite do bcrypt, mantido para os hashes legados
+)
+
+// ValidatePassword aplica a política de senha e retorna TODAS as violações
+// de uma vez, para que o cliente possa corrigir tudo numa única submissão
+func ValidatePassword(password string) []error {
+	var errs []error
+
+	if len(password) < PasswordMinLength || len(password) > PasswordMaxLength {
+		errs = append(errs, domainerrors.ErrPasswordLength)
+	}
+
+	var hasLetter, hasNumber bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasNumber = true
+		}
+	}
+
+	if !hasLetter {
+		errs = append(errs, domainerrors.ErrPasswordNoLetter)
+	}
+	if !hasNumber {
+		errs = append(errs, domainerrors.ErrPasswordNoNumber)
+	}
+
+	return errs
+}
@@ -0,0 +1,37 @@
+package valueobjects
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+)
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		want     []error
+	}{
+		{"senha válida", "Senha123", nil},
+		{"curta e sem número", "abc", []error{domainerrors.ErrPasswordLength, domainerrors.ErrPasswordNoNumber}},
+		{"sem número", "senhaboa", []error{domainerrors.ErrPasswordNoNumber}},
+		{"sem letra", "12345678", []error{domainerrors.ErrPasswordNoLetter}},
+		{"longa e sem número", strings.Repeat("a", 80), []error{domainerrors.ErrPasswordLength, domainerrors.ErrPasswordNoNumber}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ValidatePassword(tt.password)
+			if len(got) != len(tt.want) {
+				t.Fatalf("esperava %d erros, obteve %v", len(tt.want), got)
+			}
+			for i := range tt.want {
+				if !errors.Is(got[i], tt.want[i]) {
+					t.Errorf("erro %d: esperava %v, obteve %v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"github.com/rafabene/avantpro-backend/internal/services"
+)
+
+// LoginRequest é o corpo de POST /auth/login
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// RefreshRequest é o corpo de POST /auth/refresh
+// No modo cookie o refresh token vem do cookie e o corpo é dispensado
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// VerifyMFARequest é o corpo de POST /auth/mfa/verify
+type VerifyMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"` // Código TOTP ou de recuperação
+}
+
+// ForgotPasswordRequest é o corpo de POST /auth/forgot-password
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest é o corpo de POST /auth/reset-password
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,password"`
+}
+
+// TokenResponse contém os tokens emitidos pela API
+// No modo cookie os tokens vão em cookies HttpOnly e o corpo traz só o token
+// CSRF, com token_type "Cookie"
+type TokenResponse struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"` // segundos
+	CSRFToken    string `json:"csrf_token,omitempty"`
+}
+
+// CSRFTokenResponse é a resposta de GET /auth/csrf
+type CSRFTokenResponse struct {
+	CSRFToken string `json:"csrf_token"`
+}
+
+// ToTokenResponse converte o resultado do AuthService para o DTO de resposta
+func ToTokenResponse(result *services.AuthResult) TokenResponse {
+	return TokenResponse{
+		AccessToken:  result.AccessToken,
+		RefreshToken: result.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(result.ExpiresIn.Seconds()),
+	}
+}
+
+// MFAChallengeResponse é retornada no lugar dos tokens quando o login exige o segundo fator
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int64  `json:"expires_in"` // segundos
+}
+
+// ToMFAChallengeResponse converte o desafio do AuthService para o DTO de resposta
+func ToMFAChallengeResponse(result *services.AuthResult) MFAChallengeResponse {
+	return MFAChallengeResponse{
+		MFARequired: true,
+		MFAToken:    result.MFAToken,
+		ExpiresIn:   int64(result.ExpiresIn.Seconds()),
+	}
+}
@@ -2,6 +2,8 @@ package dto
 
 import (
 	"github.com/gin-gonic/gin"
+
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
 )
 
 // ErrorResponse segue RFC 7807 (Problem Details for HTTP APIs)
@@ -99,27 +101,92 @@ func ConflictErrorResponseI18n(c *gin.Context, detailKey string, params ...map[s
 }
 
 // UnauthorizedErrorResponseI18n cria uma resposta de erro 401
-func UnauthorizedErrorResponseI18n(c *gin.Context) ErrorResponse {
+// detailKey é opcional e substitui o detalhe genérico (ex: "error.invalid_credentials")
+func UnauthorizedErrorResponseI18n(c *gin.Context, detailKey ...string) ErrorResponse {
+	detail := "error.unauthorized.detail"
+	if len(detailKey) > 0 {
+		detail = detailKey[0]
+	}
+
 	return NewErrorResponseI18n(
 		c,
 		"/problems/unauthorized",
 		"error.unauthorized.title",
-		"error.unauthorized.detail",
+		detail,
 		401,
 	)
 }
 
 // ForbiddenErrorResponseI18n cria uma resposta de erro 403
-func ForbiddenErrorResponseI18n(c *gin.Context) ErrorResponse {
+// detailKey é opcional e substitui o detalhe genérico (ex: "error.account_not_active")
+func ForbiddenErrorResponseI18n(c *gin.Context, detailKey ...string) ErrorResponse {
+	detail := "error.forbidden.detail"
+	if len(detailKey) > 0 {
+		detail = detailKey[0]
+	}
+
 	return NewErrorResponseI18n(
 		c,
 		"/problems/forbidden",
 		"error.forbidden.title",
-		"error.forbidden.detail",
+		detail,
 		403,
 	)
 }
 
+// BadRequestErrorResponseI18n cria uma resposta de erro 400 para requisições malformadas
+// detailKey é opcional e substitui o detalhe genérico (ex: "error.invalid_activation_token")
+func BadRequestErrorResponseI18n(c *gin.Context, detailKey ...string) ErrorResponse {
+	detail := "error.bad_request.detail"
+	if len(detailKey) > 0 {
+		detail = detailKey[0]
+	}
+
+	return NewErrorResponseI18n(
+		c,
+		"/problems/bad-request",
+		"error.bad_request.title",
+		detail,
+		400,
+	)
+}
+
+// GoneErrorResponseI18n cria uma resposta de erro 410 para recursos expirados
+func GoneErrorResponseI18n(c *gin.Context, detailKey string) ErrorResponse {
+	return NewErrorResponseI18n(
+		c,
+		"/problems/gone",
+		"error.gone.title",
+		detailKey,
+		410,
+	)
+}
+
+// TooManyRequestsErrorResponseI18n cria uma resposta de erro 429
+func TooManyRequestsErrorResponseI18n(c *gin.Context, detailKey string, params ...map[string]interface{}) ErrorResponse {
+	return NewErrorResponseI18n(
+		c,
+		"/problems/too-many-requests",
+		"error.too_many_requests.title",
+		detailKey,
+		429,
+		params...,
+	)
+}
+
+// AccountLockedErrorResponseI18n cria uma resposta de erro 423 para logins
+// bloqueados após falhas seguidas; retryAfter é a espera em segundos
+func AccountLockedErrorResponseI18n(c *gin.Context, retryAfter int) ErrorResponse {
+	return NewErrorResponseI18n(
+		c,
+		domainerrors.ProblemTypeAccountLocked,
+		"error.account_locked.title",
+		domainerrors.ErrAccountLocked.Error(),
+		423,
+		map[string]interface{}{"RetryAfter": retryAfter},
+	)
+}
+
 // InternalErrorResponseI18n cria uma resposta de erro 500
 func InternalErrorResponseI18n(c *gin.Context) ErrorResponse {
 	return NewErrorResponseI18n(
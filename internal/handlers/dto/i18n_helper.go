@@ -3,7 +3,6 @@ package dto
 import (
 	"github.com/gin-gonic/gin"
 
-	"github.com/rafabene/avantpro-backend/internal/handlers/middleware"
 	"github.com/rafabene/avantpro-backend/internal/infrastructure/i18n"
 )
 
@@ -11,7 +10,7 @@ import (
 // Uso: dto.T(c, "welcome", map[string]interface{}{"Name": "John"})
 func T(c *gin.Context, key string, params ...map[string]interface{}) string {
 	// Buscar serviço i18n do contexto
-	i18nService, exists := c.Get(middleware.I18nServiceContextKey)
+	i18nService, exists := c.Get(i18n.ServiceContextKey)
 	if !exists {
 		// Fallback: retornar a chave se serviço não estiver disponível
 		return key
@@ -31,7 +30,7 @@ func T(c *gin.Context, key string, params ...map[string]interface{}) string {
 
 // GetLanguage retorna o idioma configurado no contexto da requisição
 func GetLanguage(c *gin.Context) string {
-	lang, exists := c.Get(middleware.LanguageContextKey)
+	lang, exists := c.Get(i18n.LanguageContextKey)
 	if !exists {
 		return "en" // Fallback
 	}
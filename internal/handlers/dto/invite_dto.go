@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// CreateInviteRequest é o corpo de POST /invites
+type CreateInviteRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=admin user guest"`
+}
+
+// AcceptInviteRequest é o corpo de POST /users/invites/accept
+// full_name só é usado quando o convite cria um novo usuário
+type AcceptInviteRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	FullName string `json:"full_name" binding:"max=255"`
+}
+
+// InviteResponse representa um convite de organização
+type InviteResponse struct {
+	ID         string     `json:"id"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	Status     string     `json:"status"`
+	InvitedBy  string     `json:"invited_by"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ToInviteResponse converte o convite para o DTO de resposta
+func ToInviteResponse(invite *entities.Invite) InviteResponse {
+	return InviteResponse{
+		ID:         invite.ID,
+		Email:      invite.Email.String(),
+		Role:       invite.Role.String(),
+		Status:     string(invite.CurrentStatus(time.Now())),
+		InvitedBy:  invite.InvitedBy,
+		ExpiresAt:  invite.ExpiresAt,
+		AcceptedAt: invite.AcceptedAt,
+		CreatedAt:  invite.CreatedAt,
+	}
+}
+
+// ToInviteResponses converte uma lista de convites para DTOs de resposta
+func ToInviteResponses(invites []*entities.Invite) []InviteResponse {
+	responses := make([]InviteResponse, 0, len(invites))
+	for _, i := range invites {
+		responses = append(responses, ToInviteResponse(i))
+	}
+	return responses
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"github.com/rafabene/avantpro-backend/internal/services"
+)
+
+// MFACodeRequest é o corpo das operações que exigem um código do segundo fator
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// MFAStatusResponse é a resposta de GET /users/me/mfa
+type MFAStatusResponse struct {
+	TOTPEnabled            bool  `json:"totp_enabled"`
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+// TOTPEnrollmentResponse é a resposta de POST /users/me/mfa/totp
+// O otpauth_uri costuma ser exibido como QR code; o secret permite a digitação manual
+type TOTPEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesResponse contém os códigos de recuperação, exibidos uma única vez
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// ToMFAStatusResponse converte o status do MFAService para o DTO de resposta
+func ToMFAStatusResponse(status *services.MFAStatus) MFAStatusResponse {
+	return MFAStatusResponse{
+		TOTPEnabled:            status.TOTPEnabled,
+		RecoveryCodesRemaining: status.RecoveryCodesRemaining,
+	}
+}
+
+// ToTOTPEnrollmentResponse converte o cadastro do MFAService para o DTO de resposta
+func ToTOTPEnrollmentResponse(setup *services.TOTPSetup) TOTPEnrollmentResponse {
+	return TOTPEnrollmentResponse{
+		Secret:     setup.Secret,
+		OtpauthURI: setup.URI,
+	}
+}
@@ -0,0 +1,109 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// CreateOrganizationRequest é o corpo de POST /organizations
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required,min=2,max=255"`
+}
+
+// UpdateOrganizationRequest é o corpo de PUT /organizations/:id
+type UpdateOrganizationRequest struct {
+	Name string `json:"name" binding:"required,min=2,max=255"`
+	// RequireMFA exige 2FA de todos os membros; omitido mantém o valor atual
+	RequireMFA *bool `json:"require_mfa"`
+	// AllowedOrigins são origins CORS extras (ex: https://app.empresa.com);
+	// omitido mantém os atuais e uma lista vazia remove todos
+	AllowedOrigins []string `json:"allowed_origins"`
+}
+
+// AddMemberRequest é o corpo de POST /organizations/:id/members
+type AddMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=admin user guest"`
+}
+
+// UpdateMemberRoleRequest é o corpo de PUT /organizations/:id/members/:userId
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin user guest"`
+}
+
+// OrganizationResponse representa uma organização e a role do usuário nela
+type OrganizationResponse struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Status         string    `json:"status"`
+	RequireMFA     bool      `json:"require_mfa"`
+	AllowedOrigins []string  `json:"allowed_origins"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// MemberResponse representa um membro de uma organização
+type MemberResponse struct {
+	UserID   string     `json:"user_id"`
+	Email    string     `json:"email,omitempty"`
+	Name     string     `json:"name,omitempty"`
+	Role     string     `json:"role"`
+	JoinedAt *time.Time `json:"joined_at,omitempty"`
+}
+
+// ToOrganizationResponse converte a associação do usuário para o DTO de resposta
+func ToOrganizationResponse(member *entities.OrganizationMember) OrganizationResponse {
+	org := member.Organization
+
+	origins := org.AllowedOrigins
+	if origins == nil {
+		origins = []string{}
+	}
+
+	return OrganizationResponse{
+		ID:             org.ID,
+		Name:           org.Name,
+		Status:         string(org.Status),
+		RequireMFA:     org.RequireMFA,
+		AllowedOrigins: origins,
+		Role:           member.Role.String(),
+		CreatedAt:      org.CreatedAt,
+		UpdatedAt:      org.UpdatedAt,
+	}
+}
+
+// ToOrganizationResponses converte uma lista de associações para DTOs de resposta
+func ToOrganizationResponses(members []*entities.OrganizationMember) []OrganizationResponse {
+	responses := make([]OrganizationResponse, 0, len(members))
+	for _, m := range members {
+		responses = append(responses, ToOrganizationResponse(m))
+	}
+	return responses
+}
+
+// ToMemberResponse converte um membro para o DTO de resposta
+func ToMemberResponse(member *entities.OrganizationMember) MemberResponse {
+	response := MemberResponse{
+		UserID:   member.UserID,
+		Role:     member.Role.String(),
+		JoinedAt: member.JoinedAt,
+	}
+
+	if member.User != nil {
+		response.Email = member.User.Email.String()
+		response.Name = member.User.Name
+	}
+
+	return response
+}
+
+// ToMemberResponses converte uma lista de membros para DTOs de resposta
+func ToMemberResponses(members []*entities.OrganizationMember) []MemberResponse {
+	responses := make([]MemberResponse, 0, len(members))
+	for _, m := range members {
+		responses = append(responses, ToMemberResponse(m))
+	}
+	return responses
+}
@@ -0,0 +1,254 @@
+package dto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	"github.com/rafabene/avantpro-backend/internal/services"
+)
+
+// Base64URL são bytes trafegados em base64url, o formato dos campos binários
+// do WebAuthn em JSON. O padding é aceito na entrada e omitido na saída
+type Base64URL []byte
+
+// MarshalJSON codifica os bytes em base64url sem padding
+func (b Base64URL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+// UnmarshalJSON decodifica uma string base64url, com ou sem padding
+func (b *Base64URL) UnmarshalJSON(data []byte) error {
+	var encoded string
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return err
+	}
+
+	*b = decoded
+	return nil
+}
+
+// PasskeyCredentialDescriptor identifica um passkey nas opções das cerimônias
+type PasskeyCredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+// PasskeyRelyingParty identifica a aplicação para o autenticador
+type PasskeyRelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PasskeyUser identifica o usuário dono do passkey
+type PasskeyUser struct {
+	ID          Base64URL `json:"id"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"displayName"`
+}
+
+// PasskeyCredentialParameter é um algoritmo COSE aceito no cadastro
+type PasskeyCredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+// PasskeyAuthenticatorSelection exige passkeys residentes com verificação do usuário
+type PasskeyAuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// PasskeyCreationOptions segue o PublicKeyCredentialCreationOptionsJSON do
+// WebAuthn e pode ser passado a PublicKeyCredential.parseCreationOptionsFromJSON
+type PasskeyCreationOptions struct {
+	Challenge              string                        `json:"challenge"`
+	RP                     PasskeyRelyingParty           `json:"rp"`
+	User                   PasskeyUser                   `json:"user"`
+	PubKeyCredParams       []PasskeyCredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                         `json:"timeout"` // milissegundos
+	ExcludeCredentials     []PasskeyCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection PasskeyAuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                        `json:"attestation"`
+}
+
+// PasskeyRequestOptions segue o PublicKeyCredentialRequestOptionsJSON do
+// WebAuthn e pode ser passado a PublicKeyCredential.parseRequestOptionsFromJSON
+// allowCredentials vazio deixa o navegador oferecer os passkeys do site
+type PasskeyRequestOptions struct {
+	Challenge        string                        `json:"challenge"`
+	RPID             string                        `json:"rpId"`
+	Timeout          int64                         `json:"timeout"` // milissegundos
+	AllowCredentials []PasskeyCredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                        `json:"userVerification"`
+}
+
+// PasskeyCreationOptionsResponse é a resposta de POST /users/me/passkeys/register/options
+type PasskeyCreationOptionsResponse struct {
+	PublicKey PasskeyCreationOptions `json:"public_key"`
+}
+
+// PasskeyRequestOptionsResponse é a resposta de POST /auth/passkeys/login/options
+type PasskeyRequestOptionsResponse struct {
+	PublicKey PasskeyRequestOptions `json:"public_key"`
+}
+
+// PasskeyAttestationResponse é o campo response do PublicKeyCredential do cadastro
+type PasskeyAttestationResponse struct {
+	ClientDataJSON    Base64URL `json:"clientDataJSON" binding:"required"`
+	AttestationObject Base64URL `json:"attestationObject" binding:"required"`
+	Transports        []string  `json:"transports" binding:"max=10,dive,max=32"`
+}
+
+// PasskeyRegistrationCredential é o PublicKeyCredential.toJSON() do cadastro
+type PasskeyRegistrationCredential struct {
+	ID       Base64URL                  `json:"id" binding:"required"`
+	Type     string                     `json:"type" binding:"required,eq=public-key"`
+	Response PasskeyAttestationResponse `json:"response"`
+}
+
+// PasskeyRegistrationRequest é o corpo de POST /users/me/passkeys/register
+type PasskeyRegistrationRequest struct {
+	Name       string                        `json:"name" binding:"max=100"`
+	Credential PasskeyRegistrationCredential `json:"credential"`
+}
+
+// PasskeyAssertionResponse é o campo response do PublicKeyCredential do login
+type PasskeyAssertionResponse struct {
+	ClientDataJSON    Base64URL `json:"clientDataJSON" binding:"required"`
+	AuthenticatorData Base64URL `json:"authenticatorData" binding:"required"`
+	Signature         Base64URL `json:"signature" binding:"required"`
+	UserHandle        Base64URL `json:"userHandle"`
+}
+
+// PasskeyLoginCredential é o PublicKeyCredential.toJSON() do login
+type PasskeyLoginCredential struct {
+	ID       Base64URL                `json:"id" binding:"required"`
+	Type     string                   `json:"type" binding:"required,eq=public-key"`
+	Response PasskeyAssertionResponse `json:"response"`
+}
+
+// PasskeyLoginRequest é o corpo de POST /auth/passkeys/login
+type PasskeyLoginRequest struct {
+	Credential PasskeyLoginCredential `json:"credential"`
+}
+
+// PasskeyResponse representa um passkey cadastrado, sem a chave pública
+type PasskeyResponse struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	AAGUID         string     `json:"aaguid"`
+	Transports     []string   `json:"transports"`
+	BackupEligible bool       `json:"backup_eligible"`
+	BackupState    bool       `json:"backup_state"` // Sincronizado com outros dispositivos
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// ToInput converte o corpo do cadastro para a entrada do PasskeyService
+func (r *PasskeyRegistrationRequest) ToInput() services.PasskeyRegistrationInput {
+	return services.PasskeyRegistrationInput{
+		Name:              r.Name,
+		ClientDataJSON:    r.Credential.Response.ClientDataJSON,
+		AttestationObject: r.Credential.Response.AttestationObject,
+		Transports:        r.Credential.Response.Transports,
+	}
+}
+
+// ToInput converte o corpo do login para a entrada do PasskeyService
+func (r *PasskeyLoginRequest) ToInput() services.PasskeyLoginInput {
+	return services.PasskeyLoginInput{
+		CredentialID:      r.Credential.ID,
+		ClientDataJSON:    r.Credential.Response.ClientDataJSON,
+		AuthenticatorData: r.Credential.Response.AuthenticatorData,
+		Signature:         r.Credential.Response.Signature,
+		UserHandle:        r.Credential.Response.UserHandle,
+	}
+}
+
+// ToPasskeyCreationOptionsResponse converte as opções do cadastro para o DTO de resposta
+func ToPasskeyCreationOptionsResponse(options *services.PasskeyCreationOptions) PasskeyCreationOptionsResponse {
+	params := make([]PasskeyCredentialParameter, 0, len(options.Algorithms))
+	for _, alg := range options.Algorithms {
+		params = append(params, PasskeyCredentialParameter{Type: "public-key", Alg: alg})
+	}
+
+	return PasskeyCreationOptionsResponse{
+		PublicKey: PasskeyCreationOptions{
+			Challenge: options.Challenge,
+			RP:        PasskeyRelyingParty{ID: options.RPID, Name: options.RPName},
+			User: PasskeyUser{
+				ID:          options.UserHandle,
+				Name:        options.UserName,
+				DisplayName: options.UserDisplayName,
+			},
+			PubKeyCredParams:   params,
+			Timeout:            options.Timeout.Milliseconds(),
+			ExcludeCredentials: toPasskeyDescriptors(options.ExcludeCredentials),
+			AuthenticatorSelection: PasskeyAuthenticatorSelection{
+				ResidentKey:        "required",
+				RequireResidentKey: true,
+				UserVerification:   "required",
+			},
+			Attestation: "none",
+		},
+	}
+}
+
+// ToPasskeyRequestOptionsResponse converte as opções do login para o DTO de resposta
+func ToPasskeyRequestOptionsResponse(options *services.PasskeyRequestOptions) PasskeyRequestOptionsResponse {
+	return PasskeyRequestOptionsResponse{
+		PublicKey: PasskeyRequestOptions{
+			Challenge:        options.Challenge,
+			RPID:             options.RPID,
+			Timeout:          options.Timeout.Milliseconds(),
+			AllowCredentials: []PasskeyCredentialDescriptor{},
+			UserVerification: "required",
+		},
+	}
+}
+
+// ToPasskeyResponse converte o passkey para o DTO de resposta
+func ToPasskeyResponse(passkey *entities.Passkey) PasskeyResponse {
+	return PasskeyResponse{
+		ID:             passkey.ID,
+		Name:           passkey.Name,
+		AAGUID:         passkey.AAGUID,
+		Transports:     passkey.Transports,
+		BackupEligible: passkey.BackupEligible,
+		BackupState:    passkey.BackupState,
+		LastUsedAt:     passkey.LastUsedAt,
+		CreatedAt:      passkey.CreatedAt,
+	}
+}
+
+// ToPasskeyResponses converte uma lista de passkeys para DTOs de resposta
+func ToPasskeyResponses(passkeys []*entities.Passkey) []PasskeyResponse {
+	responses := make([]PasskeyResponse, 0, len(passkeys))
+	for _, p := range passkeys {
+		responses = append(responses, ToPasskeyResponse(p))
+	}
+	return responses
+}
+
+// toPasskeyDescriptors converte os passkeys já cadastrados para descritores
+func toPasskeyDescriptors(credentials []services.PasskeyCredential) []PasskeyCredentialDescriptor {
+	descriptors := make([]PasskeyCredentialDescriptor, 0, len(credentials))
+	for _, credential := range credentials {
+		descriptors = append(descriptors, PasskeyCredentialDescriptor{
+			Type:       "public-key",
+			ID:         credential.ID,
+			Transports: credential.Transports,
+		})
+	}
+	return descriptors
+}
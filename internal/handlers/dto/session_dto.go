@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// SessionDeviceResponse identifica o dispositivo a partir do User-Agent
+// Campos vazios indicam que o cliente não foi reconhecido
+type SessionDeviceResponse struct {
+	Browser string `json:"browser,omitempty"`
+	OS      string `json:"os,omitempty"`
+}
+
+// SessionResponse representa uma sessão ativa do usuário
+type SessionResponse struct {
+	ID         string                `json:"id"`
+	Device     SessionDeviceResponse `json:"device"`
+	IPAddress  string                `json:"ip_address"`
+	UserAgent  string                `json:"user_agent"`
+	Current    bool                  `json:"current"` // Sessão do access token da requisição
+	CreatedAt  time.Time             `json:"created_at"`
+	LastUsedAt time.Time             `json:"last_used_at"`
+	ExpiresAt  time.Time             `json:"expires_at"`
+}
+
+// ToSessionResponse converte a sessão para o DTO de resposta
+func ToSessionResponse(session *entities.Session) SessionResponse {
+	device := session.Device()
+
+	return SessionResponse{
+		ID:         session.ID,
+		Device:     SessionDeviceResponse{Browser: device.Browser, OS: device.OS},
+		IPAddress:  session.IPAddress,
+		UserAgent:  session.UserAgent,
+		Current:    session.Current,
+		CreatedAt:  session.CreatedAt,
+		LastUsedAt: session.LastUsedAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+}
+
+// ToSessionResponses converte uma lista de sessões para DTOs de resposta
+func ToSessionResponses(sessions []*entities.Session) []SessionResponse {
+	responses := make([]SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		responses = append(responses, ToSessionResponse(s))
+	}
+	return responses
+}
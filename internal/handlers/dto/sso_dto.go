@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// SSOConfigRequest é o corpo de PUT /sso/config
+// enabled ausente habilita o SSO
+type SSOConfigRequest struct {
+	Issuer         string   `json:"issuer" binding:"required,url,max=255"`
+	ClientID       string   `json:"client_id" binding:"required,max=255"`
+	ClientSecret   string   `json:"client_secret" binding:"required,max=512"`
+	AllowedDomains []string `json:"allowed_domains" binding:"required,min=1,max=50,dive,required,max=253"`
+	DefaultRole    string   `json:"default_role" binding:"omitempty,oneof=user guest"`
+	Enabled        *bool    `json:"enabled"`
+}
+
+// SSOConfigResponse representa a configuração de SSO da organização, sem o client secret
+// callback_url é o redirect URI a ser registrado no IdP
+type SSOConfigResponse struct {
+	Issuer         string                  `json:"issuer"`
+	ClientID       string                  `json:"client_id"`
+	AllowedDomains []string                `json:"allowed_domains"`
+	Domains        []SSODomainVerification `json:"domains"`
+	DefaultRole    string                  `json:"default_role"`
+	Enabled        bool                    `json:"enabled"`
+	CallbackURL    string                  `json:"callback_url"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+}
+
+// SSODomainVerification é a situação de um domínio do SSO
+// Enquanto verified for false, o domínio não é aceito no login: a organização
+// publica o registro TXT record_name com o valor record_value e pede a verificação
+type SSODomainVerification struct {
+	Domain      string `json:"domain"`
+	Verified    bool   `json:"verified"`
+	RecordName  string `json:"record_name"`
+	RecordValue string `json:"record_value"`
+}
+
+// ToSSOConfigResponse converte a configuração para o DTO de resposta
+func ToSSOConfigResponse(config *entities.SSOConfig, callbackURL string) SSOConfigResponse {
+	domains := make([]SSODomainVerification, 0, len(config.AllowedDomains))
+	for _, d := range config.AllowedDomains {
+		name, value := config.DomainVerificationRecord(d)
+		domains = append(domains, SSODomainVerification{
+			Domain:      d,
+			Verified:    config.IsDomainVerified(d),
+			RecordName:  name,
+			RecordValue: value,
+		})
+	}
+
+	return SSOConfigResponse{
+		Issuer:         config.Issuer,
+		ClientID:       config.ClientID,
+		AllowedDomains: config.AllowedDomains,
+		Domains:        domains,
+		DefaultRole:    config.DefaultRole.String(),
+		Enabled:        config.Enabled,
+		CallbackURL:    callbackURL,
+		CreatedAt:      config.CreatedAt,
+		UpdatedAt:      config.UpdatedAt,
+	}
+}
@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+	"github.com/rafabene/avantpro-backend/internal/services"
+)
+
+// SignupRequest é o corpo de POST /users
+type SignupRequest struct {
+	Email            string `json:"email" binding:"required,email"`
+	Password         string `json:"password" binding:"required,password"`
+	OrganizationName string `json:"organization_name" binding:"required,min=2,max=100"`
+}
+
+// SignupResponse é devolvida tanto para emails novos quanto já cadastrados
+type SignupResponse struct {
+	Message          string `json:"message"`
+	Email            string `json:"email"`
+	OrganizationName string `json:"organization_name"`
+}
+
+// ToSignupResponse converte o resultado do UserService para o DTO de resposta
+func ToSignupResponse(c *gin.Context, result *services.SignupResult) SignupResponse {
+	return SignupResponse{
+		Message:          T(c, "signup_success"),
+		Email:            result.Email,
+		OrganizationName: result.OrganizationName,
+	}
+}
+
+// ResendActivationRequest é o corpo de POST /users/resend-activation
+type ResendActivationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// MessageResponse é uma resposta com apenas uma mensagem traduzida
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// ActivatedUserResponse representa o usuário recém-ativado
+type ActivatedUserResponse struct {
+	ID              string     `json:"id"`
+	Email           string     `json:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+}
+
+// ActivationResponse é a resposta de GET /users/activate (login automático)
+type ActivationResponse struct {
+	TokenResponse
+	User         ActivatedUserResponse `json:"user"`
+	Organization *OrganizationResponse `json:"organization,omitempty"`
+	RedirectTo   string                `json:"redirect_to"`
+}
+
+// activationRedirect é para onde o frontend leva o usuário após ativar a conta
+const activationRedirect = "/dashboard?welcome=true"
+
+// ToActivationResponse converte o resultado da ativação para o DTO de resposta
+func ToActivationResponse(result *services.ActivationResult) ActivationResponse {
+	user := result.Auth.User
+
+	response := ActivationResponse{
+		TokenResponse: ToTokenResponse(result.Auth),
+		User: ActivatedUserResponse{
+			ID:              user.ID,
+			Email:           user.Email.String(),
+			EmailVerifiedAt: user.EmailVerifiedAt,
+		},
+		RedirectTo: activationRedirect,
+	}
+
+	if result.Membership != nil {
+		organization := ToOrganizationResponse(result.Membership)
+		response.Organization = &organization
+	}
+
+	return response
+}
+
+// PasswordValidationErrors aplica a política de senha e traduz todas as violações
+func PasswordValidationErrors(c *gin.Context, field, password string) []ValidationError {
+	errs := valueobjects.ValidatePassword(password)
+
+	fields := make([]ValidationError, 0, len(errs))
+	for _, err := range errs {
+		fields = append(fields, ValidationError{
+			Field:   field,
+			Message: T(c, err.Error()),
+			Tag:     err.Error(),
+		})
+	}
+
+	return fields
+}
@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+)
+
+func init() {
+	// Usar o nome do campo JSON nas mensagens de validação
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" || name == "" {
+				return field.Name
+			}
+			return name
+		})
+
+		// Política de senha (RN-20 a RN-22); as violações são detalhadas em BindingErrorResponseI18n
+		_ = v.RegisterValidation("password", func(fl validator.FieldLevel) bool {
+			return len(valueobjects.ValidatePassword(fl.Field().String())) == 0
+		})
+	}
+}
+
+// BindingErrorResponseI18n converte um erro de binding do Gin em resposta RFC 7807
+// Erros de validação viram 400 com a lista de campos; demais erros viram bad request
+func BindingErrorResponseI18n(c *gin.Context, err error) ErrorResponse {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return BadRequestErrorResponseI18n(c)
+	}
+
+	fields := make([]ValidationError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		// Uma falha na política de senha vira uma entrada por regra violada (RN-25)
+		if fe.Tag() == "password" {
+			if password, ok := fe.Value().(string); ok {
+				if violations := PasswordValidationErrors(c, fe.Field(), password); len(violations) > 0 {
+					fields = append(fields, violations...)
+					continue
+				}
+			}
+		}
+
+		fields = append(fields, ValidationError{
+			Field:   fe.Field(),
+			Message: validationMessage(c, fe),
+			Tag:     fe.Tag(),
+		})
+	}
+
+	return ValidationErrorResponseI18n(c, fields)
+}
+
+// validationMessage traduz um erro de campo usando as chaves validation_<tag>
+func validationMessage(c *gin.Context, fe validator.FieldError) string {
+	params := map[string]interface{}{
+		"Field": fe.Field(),
+		"Min":   fe.Param(),
+		"Max":   fe.Param(),
+		"Param": fe.Param(),
+	}
+
+	key := "validation_" + fe.Tag()
+	message := T(c, key, params)
+	if message == key {
+		// Tag sem tradução específica
+		return fe.Error()
+	}
+
+	return message
+}
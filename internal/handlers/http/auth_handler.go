@@ -0,0 +1,246 @@
+package http
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+	"github.com/rafabene/avantpro-backend/internal/handlers/middleware"
+	"github.com/rafabene/avantpro-backend/internal/services"
+)
+
+// AuthHandler expõe os endpoints de autenticação
+type AuthHandler struct {
+	authService *services.AuthService
+	cookies     *middleware.AuthCookies
+}
+
+// NewAuthHandler cria um novo AuthHandler
+func NewAuthHandler(authService *services.AuthService, cookies *middleware.AuthCookies) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+		cookies:     cookies,
+	}
+}
+
+// Login godoc
+// @Summary Login with email and password
+// @Description Authenticates the user and returns an access token and a refresh token.
+// @Description Users with two-factor authentication receive an mfa_token instead, to be exchanged at /auth/mfa/verify.
+// @Description Repeated failures delay and then temporarily lock sign-in for the account or the IP (423 with Retry-After).
+// @Description With the X-Auth-Mode: cookie header, the tokens are set as HttpOnly cookies and the body carries the CSRF token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param X-Auth-Mode header string false "Token delivery" Enums(cookie)
+// @Param request body dto.LoginRequest true "Credentials"
+// @Success 200 {object} dto.TokenResponse
+// @Success 202 {object} dto.MFAChallengeResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 423 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	result, err := h.authService.Login(c.Request.Context(), req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, dto.UnauthorizedErrorResponseI18n(c, domainerrors.ErrInvalidCredentials.Error()))
+			return
+		}
+		if errors.Is(err, domainerrors.ErrAccountNotActive) {
+			c.JSON(http.StatusForbidden, dto.ForbiddenErrorResponseI18n(c, domainerrors.ErrAccountNotActive.Error()))
+			return
+		}
+		var locked *domainerrors.LockedError
+		if errors.As(err, &locked) {
+			respondLocked(c, locked)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+		return
+	}
+
+	respondAuthResult(c, h.cookies, result)
+}
+
+// respondLocked responde 423 com o tempo restante do bloqueio em Retry-After
+func respondLocked(c *gin.Context, locked *domainerrors.LockedError) {
+	retryAfter := int(math.Ceil(locked.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusLocked, dto.AccountLockedErrorResponseI18n(c, retryAfter))
+}
+
+// VerifyMFA godoc
+// @Summary Complete login with the second factor
+// @Description Exchanges the mfa_token returned by the login and a TOTP or recovery code for an access token and a refresh token.
+// @Description Each TOTP code and each recovery code is accepted only once. After 5 wrong codes in a row the second factor of the user is locked (423)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param X-Auth-Mode header string false "Token delivery" Enums(cookie)
+// @Param request body dto.VerifyMFARequest true "Challenge and code"
+// @Success 200 {object} dto.TokenResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 423 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/mfa/verify [post]
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req dto.VerifyMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	result, err := h.authService.VerifyMFA(c.Request.Context(), req.MFAToken, req.Code)
+	if err != nil {
+		var locked *domainerrors.LockedError
+		switch {
+		case errors.As(err, &locked):
+			respondLocked(c, locked)
+		case errors.Is(err, domainerrors.ErrInvalidMFAToken),
+			errors.Is(err, domainerrors.ErrInvalidMFACode):
+			c.JSON(http.StatusUnauthorized, dto.UnauthorizedErrorResponseI18n(c, err.Error()))
+		case errors.Is(err, domainerrors.ErrAccountNotActive):
+			c.JSON(http.StatusForbidden, dto.ForbiddenErrorResponseI18n(c, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+		}
+		return
+	}
+
+	respondTokens(c, h.cookies, result, h.cookies.Requested(c))
+}
+
+// Refresh godoc
+// @Summary Refresh tokens
+// @Description Exchanges a refresh token for a new token pair. The presented token is rotated and cannot be reused.
+// @Description In cookie mode the refresh token comes from the refresh_token cookie, the body is not needed and the new tokens are set as cookies
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param X-Auth-Mode header string false "Token delivery" Enums(cookie)
+// @Param X-CSRF-Token header string false "CSRF token (cookie mode)"
+// @Param request body dto.RefreshRequest false "Refresh token"
+// @Success 200 {object} dto.TokenResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	cookieMode := h.cookies.Requested(c)
+
+	if cookie, err := c.Cookie(middleware.RefreshTokenCookie); err == nil && cookie != "" {
+		req.RefreshToken, cookieMode = cookie, true
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	result, err := h.authService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrInvalidRefreshToken) {
+			// O cookie com token inválido não serve mais ao navegador
+			if cookieMode {
+				h.cookies.Clear(c)
+			}
+			c.JSON(http.StatusUnauthorized, dto.UnauthorizedErrorResponseI18n(c, domainerrors.ErrInvalidRefreshToken.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+		return
+	}
+
+	respondTokens(c, h.cookies, result, cookieMode)
+}
+
+// CSRFToken godoc
+// @Summary Get the CSRF token
+// @Description Returns the CSRF token of the cookie mode session, issuing a new one when missing, expired or issued for another session.
+// @Description Requests authenticated by cookies must send it in the X-CSRF-Token header on POST, PUT, PATCH and DELETE
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.CSRFTokenResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/csrf [get]
+func (h *AuthHandler) CSRFToken(c *gin.Context) {
+	token, err := h.cookies.CSRFToken(c)
+	if errors.Is(err, middleware.ErrNoCookieSession) {
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedErrorResponseI18n(c))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CSRFTokenResponse{CSRFToken: token})
+}
+
+// respondAuthResult responde com os tokens ou, quando o usuário tem segundo
+// fator, com o desafio a ser concluído em /auth/mfa/verify
+func respondAuthResult(c *gin.Context, cookies *middleware.AuthCookies, result *services.AuthResult) {
+	if result.MFARequired() {
+		c.JSON(http.StatusAccepted, dto.ToMFAChallengeResponse(result))
+		return
+	}
+
+	respondTokens(c, cookies, result, cookies.Requested(c))
+}
+
+// respondTokens responde com os tokens no corpo ou, no modo cookie, em cookies
+func respondTokens(c *gin.Context, cookies *middleware.AuthCookies, result *services.AuthResult, cookieMode bool) {
+	tokens := dto.ToTokenResponse(result)
+	if cookieMode && !deliverTokenCookies(c, cookies, &tokens) {
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// respondActivation responde com a conta ativada e os tokens do login automático
+func respondActivation(c *gin.Context, cookies *middleware.AuthCookies, result *services.ActivationResult) {
+	response := dto.ToActivationResponse(result)
+	if cookies.Requested(c) && !deliverTokenCookies(c, cookies, &response.TokenResponse) {
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// deliverTokenCookies move os tokens da resposta para cookies HttpOnly,
+// deixando no corpo o token CSRF; retorna false se já respondeu com erro
+func deliverTokenCookies(c *gin.Context, cookies *middleware.AuthCookies, tokens *dto.TokenResponse) bool {
+	csrfToken, err := cookies.SetTokens(c, tokens.AccessToken, tokens.RefreshToken, time.Duration(tokens.ExpiresIn)*time.Second)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+		return false
+	}
+
+	tokens.AccessToken = ""
+	tokens.RefreshToken = ""
+	tokens.TokenType = "Cookie"
+	tokens.CSRFToken = csrfToken
+	return true
+}
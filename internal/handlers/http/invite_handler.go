@@ -0,0 +1,205 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+	"github.com/rafabene/avantpro-backend/internal/handlers/middleware"
+	"github.com/rafabene/avantpro-backend/internal/services"
+)
+
+// InviteHandler expõe os endpoints de convites de organização
+type InviteHandler struct {
+	inviteService *services.InviteService
+	cookies       *middleware.AuthCookies
+}
+
+// NewInviteHandler cria um novo InviteHandler
+func NewInviteHandler(inviteService *services.InviteService, cookies *middleware.AuthCookies) *InviteHandler {
+	return &InviteHandler{
+		inviteService: inviteService,
+		cookies:       cookies,
+	}
+}
+
+// Create godoc
+// @Summary Invite to organization
+// @Description Invites an email to the selected organization (admins only). The token is sent by email
+// @Tags invites
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param X-Organization-ID header string false "Organization ID (when the token has none)"
+// @Param request body dto.CreateInviteRequest true "Invite"
+// @Success 201 {object} dto.InviteResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /invites [post]
+func (h *InviteHandler) Create(c *gin.Context) {
+	var req dto.CreateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	invite, err := h.inviteService.Create(
+		c.Request.Context(),
+		middleware.GetUserID(c),
+		middleware.GetOrganizationID(c),
+		services.CreateInviteInput{
+			Email:  req.Email,
+			Role:   entities.Role(req.Role),
+			Locale: dto.GetLanguage(c),
+		},
+	)
+	if err != nil {
+		respondInviteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToInviteResponse(invite))
+}
+
+// List godoc
+// @Summary List invites
+// @Description Lists the invites of the selected organization, newest first
+// @Tags invites
+// @Produce json
+// @Security BearerAuth
+// @Param X-Organization-ID header string false "Organization ID (when the token has none)"
+// @Success 200 {array} dto.InviteResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /invites [get]
+func (h *InviteHandler) List(c *gin.Context) {
+	invites, err := h.inviteService.List(c.Request.Context(), middleware.GetUserID(c), middleware.GetOrganizationID(c))
+	if err != nil {
+		respondInviteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToInviteResponses(invites))
+}
+
+// Revoke godoc
+// @Summary Revoke invite
+// @Description Revokes a pending invite of the selected organization
+// @Tags invites
+// @Security BearerAuth
+// @Param X-Organization-ID header string false "Organization ID (when the token has none)"
+// @Param id path string true "Invite ID"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /invites/{id} [delete]
+func (h *InviteHandler) Revoke(c *gin.Context) {
+	err := h.inviteService.Revoke(c.Request.Context(), middleware.GetUserID(c), middleware.GetOrganizationID(c), c.Param("id"))
+	if err != nil {
+		respondInviteError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Accept godoc
+// @Summary Accept invite
+// @Description Accepts an invite and logs the user in. Existing users must confirm their password;
+// @Description otherwise a new, already active user is created
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.AcceptInviteRequest true "Invite acceptance"
+// @Success 200 {object} dto.ActivationResponse
+// @Success 202 {object} dto.MFAChallengeResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 410 {object} dto.ErrorResponse
+// @Failure 423 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/invites/accept [post]
+func (h *InviteHandler) Accept(c *gin.Context) {
+	var req dto.AcceptInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	result, err := h.inviteService.Accept(c.Request.Context(), services.AcceptInviteInput{
+		Token:    req.Token,
+		Password: req.Password,
+		FullName: req.FullName,
+	})
+	if err != nil {
+		// A política de senha só se aplica a novos usuários, então é verificada no service
+		if isPasswordPolicyError(err) {
+			c.JSON(http.StatusBadRequest, dto.ValidationErrorResponseI18n(c, dto.PasswordValidationErrors(c, "password", req.Password)))
+			return
+		}
+		respondInviteError(c, err)
+		return
+	}
+
+	if result.Auth.MFARequired() {
+		c.JSON(http.StatusAccepted, dto.ToMFAChallengeResponse(result.Auth))
+		return
+	}
+
+	respondActivation(c, h.cookies, result)
+}
+
+// respondInviteError converte erros do InviteService em respostas RFC 7807
+func respondInviteError(c *gin.Context, err error) {
+	var locked *domainerrors.LockedError
+	switch {
+	case errors.As(err, &locked):
+		respondLocked(c, locked)
+	case errors.Is(err, domainerrors.ErrOrganizationNotFound):
+		c.JSON(http.StatusNotFound, dto.NotFoundErrorResponseI18n(c, dto.T(c, "resource.organization")))
+	case errors.Is(err, domainerrors.ErrInviteNotFound):
+		c.JSON(http.StatusNotFound, dto.NotFoundErrorResponseI18n(c, dto.T(c, "resource.invite")))
+	case errors.Is(err, domainerrors.ErrForbidden):
+		c.JSON(http.StatusForbidden, dto.ForbiddenErrorResponseI18n(c))
+	case errors.Is(err, domainerrors.ErrMFARequiredByOrganization):
+		c.JSON(http.StatusForbidden, dto.ForbiddenErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrMemberAlreadyExists),
+		errors.Is(err, domainerrors.ErrInviteAlreadyPending):
+		c.JSON(http.StatusConflict, dto.ConflictErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrInvalidInviteToken):
+		c.JSON(http.StatusBadRequest, dto.BadRequestErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrInviteExpired):
+		c.JSON(http.StatusGone, dto.GoneErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrInvalidCredentials):
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, valueobjects.ErrInvalidEmail):
+		c.JSON(http.StatusBadRequest, dto.BadRequestErrorResponseI18n(c))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+	}
+}
+
+// isPasswordPolicyError verifica se o erro vem da política de senha
+func isPasswordPolicyError(err error) bool {
+	return errors.Is(err, domainerrors.ErrPasswordLength) ||
+		errors.Is(err, domainerrors.ErrPasswordNoLetter) ||
+		errors.Is(err, domainerrors.ErrPasswordNoNumber)
+}
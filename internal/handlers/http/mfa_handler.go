@@ -0,0 +1,172 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+	"github.com/rafabene/avantpro-backend/internal/handlers/middleware"
+	"github.com/rafabene/avantpro-backend/internal/services"
+)
+
+// MFAHandler expõe o cadastro do segundo fator do usuário autenticado
+type MFAHandler struct {
+	mfaService *services.MFAService
+}
+
+// NewMFAHandler cria um novo MFAHandler
+func NewMFAHandler(mfaService *services.MFAService) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
+
+// Status godoc
+// @Summary Get two-factor status
+// @Description Returns whether TOTP is enabled and how many recovery codes are left
+// @Tags mfa
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.MFAStatusResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/me/mfa [get]
+func (h *MFAHandler) Status(c *gin.Context) {
+	status, err := h.mfaService.Status(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToMFAStatusResponse(status))
+}
+
+// EnrollTOTP godoc
+// @Summary Start TOTP enrollment
+// @Description Generates a TOTP secret and its otpauth:// URI for the authenticator app.
+// @Description The second factor is only enforced after it is confirmed with a code
+// @Tags mfa
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.TOTPEnrollmentResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/me/mfa/totp [post]
+func (h *MFAHandler) EnrollTOTP(c *gin.Context) {
+	setup, err := h.mfaService.EnrollTOTP(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToTOTPEnrollmentResponse(setup))
+}
+
+// ConfirmTOTP godoc
+// @Summary Confirm TOTP enrollment
+// @Description Enables TOTP with the first code shown by the authenticator app and returns the recovery codes.
+// @Description The recovery codes are shown only once
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.MFACodeRequest true "TOTP code"
+// @Success 200 {object} dto.RecoveryCodesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/me/mfa/totp/confirm [post]
+func (h *MFAHandler) ConfirmTOTP(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	codes, err := h.mfaService.ConfirmTOTP(c.Request.Context(), middleware.GetUserID(c), req.Code)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTOTP godoc
+// @Summary Disable TOTP
+// @Description Disables the second factor and discards the recovery codes. Requires a TOTP or recovery code
+// @Tags mfa
+// @Accept json
+// @Security BearerAuth
+// @Param request body dto.MFACodeRequest true "TOTP or recovery code"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/me/mfa/totp [delete]
+func (h *MFAHandler) DisableTOTP(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	if err := h.mfaService.DisableTOTP(c.Request.Context(), middleware.GetUserID(c), req.Code); err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replaces the recovery codes; the previous ones stop working. Requires a TOTP or recovery code
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.MFACodeRequest true "TOTP or recovery code"
+// @Success 200 {object} dto.RecoveryCodesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/me/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(c.Request.Context(), middleware.GetUserID(c), req.Code)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// respondMFAError converte erros do MFAService em respostas RFC 7807
+func respondMFAError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domainerrors.ErrInvalidMFACode),
+		errors.Is(err, domainerrors.ErrMFANotEnrolled):
+		c.JSON(http.StatusBadRequest, dto.BadRequestErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrMFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, dto.ConflictErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrUserNotFound):
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedErrorResponseI18n(c))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+	}
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+	"github.com/rafabene/avantpro-backend/internal/handlers/middleware"
+	"github.com/rafabene/avantpro-backend/internal/services"
+)
+
+const (
+	// oauthStateCookie amarra o state ao navegador que iniciou o login,
+	// impedindo que um callback de outra pessoa seja concluído nesta sessão
+	// O path cobre os callbacks OAuth2 (/auth/oauth) e SSO (/auth/sso)
+	oauthStateCookie     = "oauth_state"
+	oauthStateCookiePath = "/api/v1/auth"
+	oauthStateCookieAge  = 10 * 60 // segundos, igual à validade do state
+)
+
+// OAuthHandler expõe os endpoints de login social com provedores OAuth2
+type OAuthHandler struct {
+	oauthService *services.OAuthService
+	cookies      *middleware.AuthCookies
+}
+
+// NewOAuthHandler cria um novo OAuthHandler
+// O cookie do state segue a configuração Secure dos cookies de autenticação
+func NewOAuthHandler(oauthService *services.OAuthService, cookies *middleware.AuthCookies) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: oauthService,
+		cookies:      cookies,
+	}
+}
+
+// Start godoc
+// @Summary Start OAuth2 login
+// @Description Redirects the browser to the provider's authorization page (state + PKCE)
+// @Tags auth
+// @Param provider path string true "Provider" Enums(google, github)
+// @Success 302
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/oauth/{provider}/start [get]
+func (h *OAuthHandler) Start(c *gin.Context) {
+	start, err := h.oauthService.Start(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		respondOAuthError(c, err)
+		return
+	}
+
+	// Lax: o callback chega por navegação vinda do provedor
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, start.State, oauthStateCookieAge, oauthStateCookiePath, "", h.cookies.Secure(), true)
+	c.Redirect(http.StatusFound, start.AuthURL)
+}
+
+// Callback godoc
+// @Summary OAuth2 callback
+// @Description Completes the OAuth2 login, linking the provider account to the user with the same verified email or creating a new user
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Provider" Enums(google, github)
+// @Param code query string true "Authorization code"
+// @Param state query string true "State issued by the start endpoint"
+// @Success 200 {object} dto.TokenResponse
+// @Success 202 {object} dto.MFAChallengeResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/oauth/{provider}/callback [get]
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	state := c.Query("state")
+	cookie, _ := c.Cookie(oauthStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, "", -1, oauthStateCookiePath, "", h.cookies.Secure(), true)
+
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(cookie)) != 1 {
+		respondOAuthError(c, domainerrors.ErrInvalidOAuthState)
+		return
+	}
+
+	// O usuário negou o acesso ou o provedor falhou antes de emitir o código
+	if c.Query("error") != "" || c.Query("code") == "" {
+		respondOAuthError(c, domainerrors.ErrOAuthExchangeFailed)
+		return
+	}
+
+	result, err := h.oauthService.Callback(c.Request.Context(), services.OAuthCallbackInput{
+		Provider: c.Param("provider"),
+		State:    state,
+		Code:     c.Query("code"),
+		Locale:   dto.GetLanguage(c),
+	})
+	if err != nil {
+		respondOAuthError(c, err)
+		return
+	}
+
+	respondAuthResult(c, h.cookies, result)
+}
+
+// respondOAuthError converte erros do OAuthService em respostas RFC 7807
+func respondOAuthError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domainerrors.ErrOAuthProviderNotSupported):
+		c.JSON(http.StatusNotFound, dto.NewErrorResponseI18n(c, domainerrors.ProblemTypeNotFound, "error.not_found.title", err.Error(), http.StatusNotFound))
+	case errors.Is(err, domainerrors.ErrInvalidOAuthState):
+		c.JSON(http.StatusBadRequest, dto.BadRequestErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrOAuthExchangeFailed):
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrOAuthEmailNotVerified),
+		errors.Is(err, domainerrors.ErrAccountNotActive):
+		c.JSON(http.StatusForbidden, dto.ForbiddenErrorResponseI18n(c, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+	}
+}
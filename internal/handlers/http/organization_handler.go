@@ -0,0 +1,334 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+	"github.com/rafabene/avantpro-backend/internal/handlers/middleware"
+	"github.com/rafabene/avantpro-backend/internal/services"
+)
+
+// OrganizationHandler expõe os endpoints de organizações e membros
+type OrganizationHandler struct {
+	orgService *services.OrganizationService
+}
+
+// NewOrganizationHandler cria um novo OrganizationHandler
+func NewOrganizationHandler(orgService *services.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgService: orgService,
+	}
+}
+
+// Create godoc
+// @Summary Create organization
+// @Description Creates an organization and adds the caller as admin
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateOrganizationRequest true "Organization"
+// @Success 201 {object} dto.OrganizationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /organizations [post]
+func (h *OrganizationHandler) Create(c *gin.Context) {
+	var req dto.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	member, err := h.orgService.Create(c.Request.Context(), middleware.GetUserID(c), req.Name)
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToOrganizationResponse(member))
+}
+
+// List godoc
+// @Summary List organizations
+// @Description Lists the organizations the caller belongs to, with the caller's role in each
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.OrganizationResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /organizations [get]
+func (h *OrganizationHandler) List(c *gin.Context) {
+	members, err := h.orgService.List(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToOrganizationResponses(members))
+}
+
+// Get godoc
+// @Summary Get organization
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Success 200 {object} dto.OrganizationResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /organizations/{id} [get]
+func (h *OrganizationHandler) Get(c *gin.Context) {
+	member, err := h.orgService.Get(c.Request.Context(), middleware.GetUserID(c), c.Param("id"))
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToOrganizationResponse(member))
+}
+
+// Update godoc
+// @Summary Update organization
+// @Description Renames the organization, sets whether members must use two-factor authentication and
+// @Description the extra CORS origins (scheme://host[:port]) accepted for the organization.
+// @Description Requires organizations.write in the organization; enabling require_mfa also requires a session verified with a second factor
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param request body dto.UpdateOrganizationRequest true "Organization"
+// @Success 200 {object} dto.OrganizationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /organizations/{id} [put]
+func (h *OrganizationHandler) Update(c *gin.Context) {
+	var req dto.UpdateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	member, err := h.orgService.Update(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), services.UpdateOrganizationInput{
+		Name:           req.Name,
+		RequireMFA:     req.RequireMFA,
+		AllowedOrigins: req.AllowedOrigins,
+	})
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToOrganizationResponse(member))
+}
+
+// Delete godoc
+// @Summary Delete organization
+// @Description Soft deletes the organization. Requires organizations.delete in the organization
+// @Tags organizations
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Success 204
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /organizations/{id} [delete]
+func (h *OrganizationHandler) Delete(c *gin.Context) {
+	if err := h.orgService.Delete(c.Request.Context(), middleware.GetUserID(c), c.Param("id")); err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListMembers godoc
+// @Summary List organization members
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Success 200 {array} dto.MemberResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /organizations/{id}/members [get]
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	members, err := h.orgService.ListMembers(c.Request.Context(), middleware.GetUserID(c), c.Param("id"))
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToMemberResponses(members))
+}
+
+// AddMember godoc
+// @Summary Add organization member
+// @Description Adds an existing user to the organization with the given role
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param request body dto.AddMemberRequest true "Member"
+// @Success 201 {object} dto.MemberResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /organizations/{id}/members [post]
+func (h *OrganizationHandler) AddMember(c *gin.Context) {
+	var req dto.AddMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	member, err := h.orgService.AddMember(
+		c.Request.Context(),
+		middleware.GetUserID(c),
+		c.Param("id"),
+		req.Email,
+		entities.Role(req.Role),
+	)
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToMemberResponse(member))
+}
+
+// UpdateMemberRole godoc
+// @Summary Update member role
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param userId path string true "Member user ID"
+// @Param request body dto.UpdateMemberRoleRequest true "Role"
+// @Success 200 {object} dto.MemberResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /organizations/{id}/members/{userId} [put]
+func (h *OrganizationHandler) UpdateMemberRole(c *gin.Context) {
+	var req dto.UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	member, err := h.orgService.UpdateMemberRole(
+		c.Request.Context(),
+		middleware.GetUserID(c),
+		c.Param("id"),
+		c.Param("userId"),
+		entities.Role(req.Role),
+	)
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToMemberResponse(member))
+}
+
+// RemoveMember godoc
+// @Summary Remove organization member
+// @Tags organizations
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param userId path string true "Member user ID"
+// @Success 204
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /organizations/{id}/members/{userId} [delete]
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	err := h.orgService.RemoveMember(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), c.Param("userId"))
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UnlockMember godoc
+// @Summary Unlock member account
+// @Description Removes the temporary sign-in lock applied to a member after failed login attempts.
+// @Description The lock covers the whole account, so members who also belong to other organizations are refused with 403
+// @Tags organizations
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param userId path string true "Member user ID"
+// @Success 204
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /organizations/{id}/members/{userId}/unlock [post]
+func (h *OrganizationHandler) UnlockMember(c *gin.Context) {
+	err := h.orgService.UnlockMember(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), c.Param("userId"))
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondOrganizationError converte erros do OrganizationService em respostas RFC 7807
+func respondOrganizationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domainerrors.ErrOrganizationNotFound):
+		c.JSON(http.StatusNotFound, dto.NotFoundErrorResponseI18n(c, dto.T(c, "resource.organization")))
+	case errors.Is(err, domainerrors.ErrMemberNotFound):
+		c.JSON(http.StatusNotFound, dto.NotFoundErrorResponseI18n(c, dto.T(c, "resource.member")))
+	case errors.Is(err, domainerrors.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, dto.NotFoundErrorResponseI18n(c, dto.T(c, "resource.user")))
+	case errors.Is(err, domainerrors.ErrForbidden):
+		c.JSON(http.StatusForbidden, dto.ForbiddenErrorResponseI18n(c))
+	case errors.Is(err, domainerrors.ErrMFARequiredByOrganization),
+		errors.Is(err, domainerrors.ErrMFASessionRequired),
+		errors.Is(err, domainerrors.ErrMemberInOtherOrganizations):
+		c.JSON(http.StatusForbidden, dto.ForbiddenErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrMemberAlreadyExists),
+		errors.Is(err, domainerrors.ErrLastOrganizationAdmin):
+		c.JSON(http.StatusConflict, dto.ConflictErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, valueobjects.ErrInvalidEmail):
+		c.JSON(http.StatusBadRequest, dto.BadRequestErrorResponseI18n(c))
+	case errors.Is(err, domainerrors.ErrInvalidOrigin):
+		c.JSON(http.StatusBadRequest, dto.BadRequestErrorResponseI18n(c, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+	}
+}
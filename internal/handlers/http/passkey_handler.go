@@ -0,0 +1,191 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+	"github.com/rafabene/avantpro-backend/internal/handlers/middleware"
+	"github.com/rafabene/avantpro-backend/internal/services"
+)
+
+// PasskeyHandler expõe o cadastro de passkeys e o login sem senha
+type PasskeyHandler struct {
+	passkeyService *services.PasskeyService
+	cookies        *middleware.AuthCookies
+}
+
+// NewPasskeyHandler cria um novo PasskeyHandler
+func NewPasskeyHandler(passkeyService *services.PasskeyService, cookies *middleware.AuthCookies) *PasskeyHandler {
+	return &PasskeyHandler{
+		passkeyService: passkeyService,
+		cookies:        cookies,
+	}
+}
+
+// RegistrationOptions godoc
+// @Summary Start passkey registration
+// @Description Returns the options for navigator.credentials.create. The challenge is valid for 5 minutes
+// @Tags passkeys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.PasskeyCreationOptionsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/me/passkeys/register/options [post]
+func (h *PasskeyHandler) RegistrationOptions(c *gin.Context) {
+	options, err := h.passkeyService.BeginRegistration(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		respondPasskeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPasskeyCreationOptionsResponse(options))
+}
+
+// Register godoc
+// @Summary Register a passkey
+// @Description Verifies the authenticator response (PublicKeyCredential.toJSON()) and stores the passkey
+// @Tags passkeys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.PasskeyRegistrationRequest true "Authenticator response"
+// @Success 201 {object} dto.PasskeyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/me/passkeys/register [post]
+func (h *PasskeyHandler) Register(c *gin.Context) {
+	var req dto.PasskeyRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	passkey, err := h.passkeyService.FinishRegistration(c.Request.Context(), middleware.GetUserID(c), req.ToInput())
+	if err != nil {
+		respondPasskeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToPasskeyResponse(passkey))
+}
+
+// List godoc
+// @Summary List passkeys
+// @Description Lists the passkeys registered by the authenticated user
+// @Tags passkeys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.PasskeyResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/me/passkeys [get]
+func (h *PasskeyHandler) List(c *gin.Context) {
+	passkeys, err := h.passkeyService.List(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		respondPasskeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPasskeyResponses(passkeys))
+}
+
+// Delete godoc
+// @Summary Delete a passkey
+// @Description Removes a passkey of the authenticated user; it can no longer be used to sign in
+// @Tags passkeys
+// @Security BearerAuth
+// @Param id path string true "Passkey ID"
+// @Success 204
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/me/passkeys/{id} [delete]
+func (h *PasskeyHandler) Delete(c *gin.Context) {
+	if err := h.passkeyService.Delete(c.Request.Context(), middleware.GetUserID(c), c.Param("id")); err != nil {
+		respondPasskeyError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// LoginOptions godoc
+// @Summary Start passkey sign-in
+// @Description Returns the options for navigator.credentials.get. allowCredentials is empty so the
+// @Description browser offers the passkeys saved for the site. The challenge is valid for 5 minutes
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.PasskeyRequestOptionsResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/passkeys/login/options [post]
+func (h *PasskeyHandler) LoginOptions(c *gin.Context) {
+	options, err := h.passkeyService.BeginLogin(c.Request.Context())
+	if err != nil {
+		respondPasskeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPasskeyRequestOptionsResponse(options))
+}
+
+// Login godoc
+// @Summary Sign in with a passkey
+// @Description Verifies the authenticator response (PublicKeyCredential.toJSON()) and returns the tokens.
+// @Description User verification is required, so the session counts as two-factor authenticated
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.PasskeyLoginRequest true "Authenticator response"
+// @Success 200 {object} dto.TokenResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/passkeys/login [post]
+func (h *PasskeyHandler) Login(c *gin.Context) {
+	var req dto.PasskeyLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	result, err := h.passkeyService.FinishLogin(c.Request.Context(), req.ToInput())
+	if err != nil {
+		respondPasskeyError(c, err)
+		return
+	}
+
+	respondAuthResult(c, h.cookies, result)
+}
+
+// respondPasskeyError converte erros do PasskeyService em respostas RFC 7807
+// No login, ErrInvalidPasskey vira 401; no cadastro, a resposta é 400
+func respondPasskeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domainerrors.ErrInvalidPasskey):
+		if middleware.GetUserID(c) == "" {
+			c.JSON(http.StatusUnauthorized, dto.UnauthorizedErrorResponseI18n(c, err.Error()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.BadRequestErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrPasskeyNotFound):
+		c.JSON(http.StatusNotFound, dto.NotFoundErrorResponseI18n(c, dto.T(c, "resource.passkey")))
+	case errors.Is(err, domainerrors.ErrPasskeyAlreadyRegistered):
+		c.JSON(http.StatusConflict, dto.ConflictErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrAccountNotActive):
+		c.JSON(http.StatusForbidden, dto.ForbiddenErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrUserNotFound):
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedErrorResponseI18n(c))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+	}
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+	"github.com/rafabene/avantpro-backend/internal/services"
+)
+
+// PasswordResetHandler expõe a redefinição de senha por link enviado por email
+type PasswordResetHandler struct {
+	passwordResetService *services.PasswordResetService
+}
+
+// NewPasswordResetHandler cria um novo PasswordResetHandler
+func NewPasswordResetHandler(passwordResetService *services.PasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{
+		passwordResetService: passwordResetService,
+	}
+}
+
+// ForgotPassword godoc
+// @Summary Request password reset
+// @Description Emails a single-use link to reset the password and revokes the previous links.
+// @Description The response is the same, and takes the same time, whether or not the email is registered
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordRequest true "Email"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/forgot-password [post]
+func (h *PasswordResetHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	if err := h.passwordResetService.ForgotPassword(c.Request.Context(), req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: dto.T(c, "password_reset_requested")})
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Consumes the emailed token, sets the new password and signs the user out of every session
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordRequest true "Token and new password"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 410 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/reset-password [post]
+func (h *PasswordResetHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	if err := h.passwordResetService.ResetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		respondPasswordResetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: dto.T(c, "password_changed")})
+}
+
+// respondPasswordResetError converte erros do PasswordResetService em respostas RFC 7807
+func respondPasswordResetError(c *gin.Context, err error) {
+	switch {
+	case isPasswordPolicyError(err):
+		c.JSON(http.StatusBadRequest, dto.BadRequestErrorResponseI18n(c))
+	case errors.Is(err, domainerrors.ErrInvalidPasswordResetToken):
+		c.JSON(http.StatusBadRequest, dto.BadRequestErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrPasswordResetTokenExpired):
+		c.JSON(http.StatusGone, dto.GoneErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrAccountNotActive):
+		c.JSON(http.StatusForbidden, dto.ForbiddenErrorResponseI18n(c, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+	}
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+	"github.com/rafabene/avantpro-backend/internal/handlers/middleware"
+	"github.com/rafabene/avantpro-backend/internal/services"
+)
+
+// SessionHandler expõe as sessões ativas do usuário autenticado
+type SessionHandler struct {
+	sessionService *services.SessionService
+	cookies        *middleware.AuthCookies
+}
+
+// NewSessionHandler cria um novo SessionHandler
+func NewSessionHandler(sessionService *services.SessionService, cookies *middleware.AuthCookies) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+		cookies:        cookies,
+	}
+}
+
+// List godoc
+// @Summary List active sessions
+// @Description Lists the devices signed in to the authenticated user's account. The session of the
+// @Description access token used in the request is flagged as current
+// @Tags sessions
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.SessionResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/me/sessions [get]
+func (h *SessionHandler) List(c *gin.Context) {
+	sessions, err := h.sessionService.List(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		respondSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToSessionResponses(sessions))
+}
+
+// Revoke godoc
+// @Summary Revoke a session
+// @Description Signs a device out: its refresh token is no longer accepted. Revoking the current
+// @Description session also revokes the access token used in the request; access tokens of other
+// @Description devices remain valid until they expire
+// @Tags sessions
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 204
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/me/sessions/{id} [delete]
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	if err := h.sessionService.Revoke(c.Request.Context(), middleware.GetUserID(c), c.Param("id")); err != nil {
+		respondSessionError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeAll godoc
+// @Summary Sign out everywhere
+// @Description Revokes every session of the authenticated user, including the current one,
+// @Description and the access token used in the request
+// @Tags sessions
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/me/sessions [delete]
+func (h *SessionHandler) RevokeAll(c *gin.Context) {
+	if err := h.sessionService.RevokeAll(c.Request.Context(), middleware.GetUserID(c)); err != nil {
+		respondSessionError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description Ends the current session: revokes its refresh token and the access token used in the request.
+// @Description Authentication cookies (cookie mode) are removed
+// @Tags auth
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/logout [post]
+func (h *SessionHandler) Logout(c *gin.Context) {
+	if err := h.sessionService.Logout(c.Request.Context(), middleware.GetUserID(c)); err != nil {
+		respondSessionError(c, err)
+		return
+	}
+
+	h.cookies.Clear(c)
+	c.Status(http.StatusNoContent)
+}
+
+// respondSessionError converte erros do SessionService em respostas RFC 7807
+func respondSessionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domainerrors.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, dto.NotFoundErrorResponseI18n(c, dto.T(c, "resource.session")))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+	}
+}
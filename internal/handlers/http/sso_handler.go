@@ -0,0 +1,256 @@
+package http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+	"github.com/rafabene/avantpro-backend/internal/handlers/middleware"
+	"github.com/rafabene/avantpro-backend/internal/services"
+)
+
+// SSOHandler expõe o SSO corporativo: a configuração do IdP OIDC da
+// organização e o login por ele
+type SSOHandler struct {
+	ssoService *services.SSOService
+	cookies    *middleware.AuthCookies
+}
+
+// NewSSOHandler cria um novo SSOHandler
+// O cookie do state segue a configuração Secure dos cookies de autenticação
+func NewSSOHandler(ssoService *services.SSOService, cookies *middleware.AuthCookies) *SSOHandler {
+	return &SSOHandler{
+		ssoService: ssoService,
+		cookies:    cookies,
+	}
+}
+
+// GetConfig godoc
+// @Summary Get SSO configuration
+// @Description Returns the OpenID Connect provider of the selected organization (admins only). The client secret is never returned
+// @Tags sso
+// @Produce json
+// @Security BearerAuth
+// @Param X-Organization-ID header string false "Organization ID (when the token has none)"
+// @Success 200 {object} dto.SSOConfigResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /sso/config [get]
+func (h *SSOHandler) GetConfig(c *gin.Context) {
+	organizationID := middleware.GetOrganizationID(c)
+
+	config, err := h.ssoService.GetConfig(c.Request.Context(), middleware.GetUserID(c), organizationID)
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToSSOConfigResponse(config, h.ssoService.CallbackURL(organizationID)))
+}
+
+// SaveConfig godoc
+// @Summary Configure SSO
+// @Description Creates or replaces the OpenID Connect provider of the selected organization (admins only).
+// @Description The issuer must publish a discovery document; users are provisioned on first login with default_role.
+// @Description Each domain only accepts logins after its ownership is proven through the TXT record listed in domains (see POST /sso/config/verify-domains)
+// @Tags sso
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param X-Organization-ID header string false "Organization ID (when the token has none)"
+// @Param request body dto.SSOConfigRequest true "SSO configuration"
+// @Success 200 {object} dto.SSOConfigResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /sso/config [put]
+func (h *SSOHandler) SaveConfig(c *gin.Context) {
+	var req dto.SSOConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	organizationID := middleware.GetOrganizationID(c)
+
+	config, err := h.ssoService.SaveConfig(c.Request.Context(), middleware.GetUserID(c), organizationID, services.SSOConfigInput{
+		Issuer:         req.Issuer,
+		ClientID:       req.ClientID,
+		ClientSecret:   req.ClientSecret,
+		AllowedDomains: req.AllowedDomains,
+		DefaultRole:    entities.Role(req.DefaultRole),
+		Enabled:        enabled,
+	})
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToSSOConfigResponse(config, h.ssoService.CallbackURL(organizationID)))
+}
+
+// VerifyDomains godoc
+// @Summary Verify SSO domains
+// @Description Looks up the TXT record of each pending domain and marks as verified the ones publishing the organization's token (admins only).
+// @Description Domains that do not match stay pending
+// @Tags sso
+// @Produce json
+// @Security BearerAuth
+// @Param X-Organization-ID header string false "Organization ID (when the token has none)"
+// @Success 200 {object} dto.SSOConfigResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /sso/config/verify-domains [post]
+func (h *SSOHandler) VerifyDomains(c *gin.Context) {
+	organizationID := middleware.GetOrganizationID(c)
+
+	config, err := h.ssoService.VerifyDomains(c.Request.Context(), middleware.GetUserID(c), organizationID)
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToSSOConfigResponse(config, h.ssoService.CallbackURL(organizationID)))
+}
+
+// DeleteConfig godoc
+// @Summary Delete SSO configuration
+// @Description Removes the OpenID Connect provider of the selected organization (admins only)
+// @Tags sso
+// @Security BearerAuth
+// @Param X-Organization-ID header string false "Organization ID (when the token has none)"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /sso/config [delete]
+func (h *SSOHandler) DeleteConfig(c *gin.Context) {
+	if err := h.ssoService.DeleteConfig(c.Request.Context(), middleware.GetUserID(c), middleware.GetOrganizationID(c)); err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Start godoc
+// @Summary Start SSO login
+// @Description Redirects the browser to the organization's identity provider (state + nonce + PKCE)
+// @Tags auth
+// @Param organizationId path string true "Organization ID"
+// @Success 302
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/sso/{organizationId}/start [get]
+func (h *SSOHandler) Start(c *gin.Context) {
+	start, err := h.ssoService.Start(c.Request.Context(), c.Param("organizationId"))
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, start.State, oauthStateCookieAge, oauthStateCookiePath, "", h.cookies.Secure(), true)
+	c.Redirect(http.StatusFound, start.AuthURL)
+}
+
+// Callback godoc
+// @Summary SSO callback
+// @Description Completes the SSO login after verifying the ID token. On first login the user and the membership are provisioned
+// @Tags auth
+// @Produce json
+// @Param organizationId path string true "Organization ID"
+// @Param code query string true "Authorization code"
+// @Param state query string true "State issued by the start endpoint"
+// @Success 200 {object} dto.ActivationResponse
+// @Success 202 {object} dto.MFAChallengeResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/sso/{organizationId}/callback [get]
+func (h *SSOHandler) Callback(c *gin.Context) {
+	state := c.Query("state")
+	cookie, _ := c.Cookie(oauthStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, "", -1, oauthStateCookiePath, "", h.cookies.Secure(), true)
+
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(cookie)) != 1 {
+		respondSSOError(c, domainerrors.ErrInvalidOAuthState)
+		return
+	}
+
+	if c.Query("error") != "" || c.Query("code") == "" {
+		respondSSOError(c, domainerrors.ErrOAuthExchangeFailed)
+		return
+	}
+
+	result, err := h.ssoService.Callback(c.Request.Context(), services.SSOCallbackInput{
+		OrganizationID: c.Param("organizationId"),
+		State:          state,
+		Code:           c.Query("code"),
+		Locale:         dto.GetLanguage(c),
+	})
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	if result.Auth.MFARequired() {
+		c.JSON(http.StatusAccepted, dto.ToMFAChallengeResponse(result.Auth))
+		return
+	}
+
+	respondActivation(c, h.cookies, result)
+}
+
+// respondSSOError converte erros do SSOService em respostas RFC 7807
+func respondSSOError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domainerrors.ErrOrganizationNotFound):
+		c.JSON(http.StatusNotFound, dto.NotFoundErrorResponseI18n(c, dto.T(c, "resource.organization")))
+	case errors.Is(err, domainerrors.ErrSSONotConfigured):
+		c.JSON(http.StatusNotFound, dto.NewErrorResponseI18n(c, domainerrors.ProblemTypeNotFound, "error.not_found.title", err.Error(), http.StatusNotFound))
+	case errors.Is(err, domainerrors.ErrInvalidSSOIssuer),
+		errors.Is(err, domainerrors.ErrInvalidSSODomain),
+		errors.Is(err, domainerrors.ErrInvalidOAuthState):
+		c.JSON(http.StatusBadRequest, dto.BadRequestErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrOAuthExchangeFailed):
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrForbidden):
+		c.JSON(http.StatusForbidden, dto.ForbiddenErrorResponseI18n(c))
+	case errors.Is(err, domainerrors.ErrOAuthEmailNotVerified),
+		errors.Is(err, domainerrors.ErrSSOEmailDomainNotAllowed),
+		errors.Is(err, domainerrors.ErrAccountNotActive),
+		errors.Is(err, domainerrors.ErrMFARequiredByOrganization):
+		c.JSON(http.StatusForbidden, dto.ForbiddenErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrSSOAccountConflict):
+		c.JSON(http.StatusConflict, dto.ConflictErrorResponseI18n(c, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+	}
+}
@@ -0,0 +1,135 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+	"github.com/rafabene/avantpro-backend/internal/handlers/middleware"
+	"github.com/rafabene/avantpro-backend/internal/services"
+)
+
+// UserHandler expõe os endpoints de cadastro e ativação de usuários
+type UserHandler struct {
+	userService *services.UserService
+	cookies     *middleware.AuthCookies
+}
+
+// NewUserHandler cria um novo UserHandler
+func NewUserHandler(userService *services.UserService, cookies *middleware.AuthCookies) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+		cookies:     cookies,
+	}
+}
+
+// Signup godoc
+// @Summary Sign up
+// @Description Creates an inactive user, its profile and an organization in one step.
+// @Description The response is the same whether or not the email is already registered
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.SignupRequest true "Signup data"
+// @Success 201 {object} dto.SignupResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users [post]
+func (h *UserHandler) Signup(c *gin.Context) {
+	var req dto.SignupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	result, err := h.userService.Signup(c.Request.Context(), services.SignupInput{
+		Email:            req.Email,
+		Password:         req.Password,
+		OrganizationName: req.OrganizationName,
+		Locale:           dto.GetLanguage(c),
+	})
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToSignupResponse(c, result))
+}
+
+// Activate godoc
+// @Summary Activate account
+// @Description Consumes the emailed activation token, activates the account and logs the user in
+// @Tags users
+// @Produce json
+// @Param token query string true "Activation token"
+// @Success 200 {object} dto.ActivationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 410 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/activate [get]
+func (h *UserHandler) Activate(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, dto.BadRequestErrorResponseI18n(c, domainerrors.ErrInvalidActivationToken.Error()))
+		return
+	}
+
+	result, err := h.userService.Activate(c.Request.Context(), token)
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	respondActivation(c, h.cookies, result)
+}
+
+// ResendActivation godoc
+// @Summary Resend activation email
+// @Description Issues a new activation token and revokes the previous ones.
+// @Description The response is the same whether or not the email is registered
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.ResendActivationRequest true "Email"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/resend-activation [post]
+func (h *UserHandler) ResendActivation(c *gin.Context) {
+	var req dto.ResendActivationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := dto.BindingErrorResponseI18n(c, err)
+		c.JSON(response.Status, response)
+		return
+	}
+
+	if err := h.userService.ResendActivation(c.Request.Context(), req.Email); err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: dto.T(c, "activation_resent")})
+}
+
+// respondUserError converte erros do UserService em respostas RFC 7807
+func respondUserError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, valueobjects.ErrInvalidEmail), isPasswordPolicyError(err):
+		c.JSON(http.StatusBadRequest, dto.BadRequestErrorResponseI18n(c))
+	case errors.Is(err, domainerrors.ErrInvalidActivationToken):
+		c.JSON(http.StatusBadRequest, dto.BadRequestErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrActivationTokenExpired):
+		c.JSON(http.StatusGone, dto.GoneErrorResponseI18n(c, err.Error()))
+	case errors.Is(err, domainerrors.ErrAccountAlreadyActive):
+		c.JSON(http.StatusConflict, dto.ConflictErrorResponseI18n(c, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+	}
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
+)
+
+const (
+	// UserIDContextKey é a chave usada para armazenar o ID do usuário autenticado
+	UserIDContextKey = "user_id"
+	// OrganizationIDContextKey é a chave usada para armazenar a organização da requisição
+	OrganizationIDContextKey = "organization_id"
+	// RoleContextKey é a chave usada para armazenar a role do usuário autenticado
+	RoleContextKey = "role"
+	// SessionIDContextKey é a chave usada para armazenar a sessão do token
+	SessionIDContextKey = "session_id"
+)
+
+// AuthMiddleware valida o Bearer token das requisições protegidas
+type AuthMiddleware struct {
+	jwtService *auth.JWTService
+	denylist   domain.TokenDenylist
+}
+
+// NewAuthMiddleware cria um novo middleware de autenticação
+// Tokens presentes na denylist são rejeitados mesmo com assinatura válida
+func NewAuthMiddleware(jwtService *auth.JWTService, denylist domain.TokenDenylist) *AuthMiddleware {
+	return &AuthMiddleware{
+		jwtService: jwtService,
+		denylist:   denylist,
+	}
+}
+
+// RequireAuth exige um access token válido no header Authorization ou, no
+// modo cookie do frontend web, no cookie access_token
+// Em caso de sucesso, armazena user ID, role e sessão no contexto;
+// o access token e o segundo fator da sessão vão para o context.Context
+func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok && c.GetHeader("Authorization") == "" {
+			token, _ = c.Cookie(AccessTokenCookie)
+			ok = token != ""
+		}
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.UnauthorizedErrorResponseI18n(c))
+			return
+		}
+
+		claims, err := m.jwtService.ValidateAccessToken(token)
+		if err != nil {
+			detailKey := "error.invalid_token"
+			if errors.Is(err, auth.ErrExpiredToken) {
+				detailKey = "error.token_expired"
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.UnauthorizedErrorResponseI18n(c, detailKey))
+			return
+		}
+
+		// Sem acesso à denylist não há como saber se o token foi revogado:
+		// a requisição falha em vez de aceitar um token possivelmente encerrado
+		revoked, err := m.isRevoked(c.Request.Context(), claims)
+		if err != nil {
+			_ = c.Error(err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+			return
+		}
+		if revoked {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.UnauthorizedErrorResponseI18n(c, "error.token_revoked"))
+			return
+		}
+
+		c.Set(UserIDContextKey, claims.Subject)
+		c.Set(RoleContextKey, claims.Role)
+		c.Set(SessionIDContextKey, claims.SessionID)
+
+		ctx := domain.WithMFAVerified(c.Request.Context(), claims.MFA)
+		ctx = domain.WithAccessToken(ctx, domain.AccessToken{
+			ID:        claims.ID,
+			SessionID: claims.SessionID,
+			ExpiresAt: claims.ExpiresAt.Time,
+		})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// isRevoked indica se o token ou a sessão dele estão na denylist
+func (m *AuthMiddleware) isRevoked(ctx context.Context, claims *auth.Claims) (bool, error) {
+	revoked, err := m.denylist.IsRevoked(ctx, claims.ID)
+	if err != nil || revoked || claims.SessionID == "" {
+		return revoked, err
+	}
+
+	return m.denylist.IsRevoked(ctx, domain.SessionDenylistKey(claims.SessionID))
+}
+
+// GetUserID retorna o ID do usuário autenticado
+func GetUserID(c *gin.Context) string {
+	return c.GetString(UserIDContextKey)
+}
+
+// GetOrganizationID retorna a organização selecionada por RequireOrganization
+// (vazio se não houver)
+func GetOrganizationID(c *gin.Context) string {
+	return c.GetString(OrganizationIDContextKey)
+}
+
+// GetRole retorna a role do usuário autenticado
+func GetRole(c *gin.Context) string {
+	return c.GetString(RoleContextKey)
+}
+
+// GetSessionID retorna a sessão (família de refresh tokens) do token
+func GetSessionID(c *gin.Context) string {
+	return c.GetString(SessionIDContextKey)
+}
+
+// bearerToken extrai o token de um header "Bearer <token>"
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
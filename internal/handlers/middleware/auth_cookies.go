@@ -0,0 +1,251 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/config"
+)
+
+const (
+	// AccessTokenCookie guarda o access token no modo cookie
+	AccessTokenCookie = "access_token"
+	// RefreshTokenCookie guarda o refresh token; só é enviado às rotas de /auth
+	RefreshTokenCookie = "refresh_token"
+	// CSRFCookie guarda o token CSRF comparado com o header X-CSRF-Token
+	CSRFCookie = "csrf_token"
+	// CSRFHeader traz o token CSRF nas requisições que alteram dados
+	CSRFHeader = "X-CSRF-Token"
+	// AuthModeHeader com o valor AuthModeCookie pede os tokens em cookies
+	AuthModeHeader = "X-Auth-Mode"
+	AuthModeCookie = "cookie"
+
+	refreshTokenCookiePath = "/api/v1/auth"
+)
+
+// ErrNoCookieSession indica que a requisição não traz cookies de uma sessão válida
+var ErrNoCookieSession = errors.New("no cookie session")
+
+// AuthCookies implementa o modo de autenticação por cookie do frontend web
+// Os tokens ficam em cookies HttpOnly, fora do alcance de JavaScript, e as
+// requisições que alteram dados provam a origem com o token CSRF (double
+// submit): o mesmo valor no cookie e no header X-CSRF-Token. O token é
+// assinado com o segredo da API e expira, então um cookie plantado por outro
+// site não é aceito, e vinculado à sessão dos tokens, então um token obtido
+// em outra sessão também não. Clientes com o header Authorization (apps
+// mobile) não usam cookies e dispensam o token CSRF
+type AuthCookies struct {
+	jwtService *auth.JWTService
+	domain     string
+	secure     bool
+	sameSite   http.SameSite
+	refreshTTL time.Duration
+	csrfTTL    time.Duration
+	secret     []byte
+	now        func() time.Time
+}
+
+// NewAuthCookies cria o modo de autenticação por cookie
+// jwtService identifica a sessão dos tokens e dá a validade do cookie do refresh token
+func NewAuthCookies(cfg *config.AuthCookieConfig, jwtService *auth.JWTService, secret string) (*AuthCookies, error) {
+	var sameSite http.SameSite
+	switch strings.ToLower(cfg.SameSite) {
+	case "strict", "":
+		sameSite = http.SameSiteStrictMode
+	case "lax":
+		sameSite = http.SameSiteLaxMode
+	case "none":
+		if !cfg.Secure {
+			return nil, fmt.Errorf("AUTH_COOKIE_SAMESITE=none requires AUTH_COOKIE_SECURE=true")
+		}
+		sameSite = http.SameSiteNoneMode
+	default:
+		return nil, fmt.Errorf("invalid AUTH_COOKIE_SAMESITE %q", cfg.SameSite)
+	}
+
+	// Chave própria para o CSRF, derivada do segredo dos JWTs
+	key := hmac.New(sha256.New, []byte(secret))
+	key.Write([]byte("csrf"))
+
+	return &AuthCookies{
+		jwtService: jwtService,
+		domain:     cfg.Domain,
+		secure:     cfg.Secure,
+		sameSite:   sameSite,
+		refreshTTL: jwtService.RefreshExpiry(),
+		csrfTTL:    time.Duration(cfg.CSRFTTL) * time.Second,
+		secret:     key.Sum(nil),
+		now:        time.Now,
+	}, nil
+}
+
+// Secure indica se os cookies só trafegam via HTTPS
+func (a *AuthCookies) Secure() bool {
+	return a.secure
+}
+
+// Requested indica se o cliente pediu os tokens em cookies
+func (a *AuthCookies) Requested(c *gin.Context) bool {
+	return strings.EqualFold(c.GetHeader(AuthModeHeader), AuthModeCookie)
+}
+
+// SetTokens grava os tokens em cookies HttpOnly e retorna um novo token CSRF
+// da sessão do access token, que o frontend envia no header X-CSRF-Token
+func (a *AuthCookies) SetTokens(c *gin.Context, accessToken, refreshToken string, accessTTL time.Duration) (string, error) {
+	claims, err := a.jwtService.ValidateAccessToken(accessToken)
+	if err != nil {
+		return "", err
+	}
+
+	csrfToken, err := a.issueCSRFToken(c, claims.SessionID)
+	if err != nil {
+		return "", err
+	}
+
+	a.setCookie(c, AccessTokenCookie, accessToken, "/", accessTTL)
+	a.setCookie(c, RefreshTokenCookie, refreshToken, refreshTokenCookiePath, a.refreshTTL)
+	return csrfToken, nil
+}
+
+// Clear remove os cookies de autenticação (logout)
+func (a *AuthCookies) Clear(c *gin.Context) {
+	a.setCookie(c, AccessTokenCookie, "", "/", -1)
+	a.setCookie(c, RefreshTokenCookie, "", refreshTokenCookiePath, -1)
+	a.setCookie(c, CSRFCookie, "", "/", -1)
+}
+
+// CSRFToken retorna o token CSRF do cookie quando ainda é válido para a
+// sessão dos cookies ou emite um novo; o frontend o consulta após recarregar
+// a página. Retorna ErrNoCookieSession se não há sessão nos cookies
+func (a *AuthCookies) CSRFToken(c *gin.Context) (string, error) {
+	sessionID := a.cookieSessionID(c)
+	if sessionID == "" {
+		return "", ErrNoCookieSession
+	}
+
+	if cookie, err := c.Cookie(CSRFCookie); err == nil && a.validCSRFToken(cookie, sessionID) {
+		return cookie, nil
+	}
+	return a.issueCSRFToken(c, sessionID)
+}
+
+// RequireCSRF exige o token CSRF nas requisições que alteram dados e enviam
+// cookies de autenticação (specs/functional/user-registration.md, seção 8.6)
+// O token vem só do header X-CSRF-Token, que outro site não consegue definir
+// sem passar pelo CORS, e precisa ter sido emitido para a sessão dos cookies
+func (a *AuthCookies) RequireCSRF() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isSafeMethod(c.Request.Method) || c.GetHeader("Authorization") != "" || !hasAuthCookie(c) {
+			c.Next()
+			return
+		}
+
+		token := c.GetHeader(CSRFHeader)
+		cookie, _ := c.Cookie(CSRFCookie)
+		if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(cookie)) != 1 ||
+			!a.validCSRFToken(token, a.cookieSessionID(c)) {
+			c.AbortWithStatusJSON(http.StatusForbidden,
+				dto.ForbiddenErrorResponseI18n(c, domainerrors.ErrInvalidCSRFToken.Error()))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// issueCSRFToken gera um token CSRF da sessão e o grava no cookie
+// Formato: <aleatório>.<expiração unix>.<HMAC-SHA256 da sessão e dos dois>
+// A sessão entra só na assinatura: o token não a expõe
+func (a *AuthCookies) issueCSRFToken(c *gin.Context, sessionID string) (string, error) {
+	nonce, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	payload := nonce + "." + strconv.FormatInt(a.now().Add(a.csrfTTL).Unix(), 10)
+	token := payload + "." + a.sign(sessionID, payload)
+
+	a.setCookie(c, CSRFCookie, token, "/", a.csrfTTL)
+	return token, nil
+}
+
+// validCSRFToken verifica a assinatura para a sessão e a validade do token
+func (a *AuthCookies) validCSRFToken(token, sessionID string) bool {
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 || sessionID == "" {
+		return false
+	}
+	payload, signature := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(a.sign(sessionID, payload))) {
+		return false
+	}
+
+	_, expiry, _ := strings.Cut(payload, ".")
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	return err == nil && a.now().Unix() < expiresAt
+}
+
+func (a *AuthCookies) sign(sessionID, payload string) string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(sessionID + "." + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// cookieSessionID retorna a sessão do access token ou, se ele já expirou,
+// do refresh token dos cookies; vazio quando nenhum dos dois é válido
+func (a *AuthCookies) cookieSessionID(c *gin.Context) string {
+	if token, err := c.Cookie(AccessTokenCookie); err == nil && token != "" {
+		if claims, err := a.jwtService.ValidateAccessToken(token); err == nil {
+			return claims.SessionID
+		}
+	}
+	if token, err := c.Cookie(RefreshTokenCookie); err == nil && token != "" {
+		if claims, err := a.jwtService.ValidateRefreshToken(token); err == nil {
+			return claims.SessionID
+		}
+	}
+	return ""
+}
+
+// setCookie grava um cookie HttpOnly; maxAge negativo remove o cookie
+func (a *AuthCookies) setCookie(c *gin.Context, name, value, path string, maxAge time.Duration) {
+	seconds := int(maxAge.Seconds())
+	if maxAge < 0 {
+		seconds = -1
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   a.domain,
+		MaxAge:   seconds,
+		Secure:   a.secure,
+		HttpOnly: true,
+		SameSite: a.sameSite,
+	})
+}
+
+// hasAuthCookie indica se a requisição traz cookies de autenticação,
+// enviados pelo navegador mesmo em requisições de outros sites
+func hasAuthCookie(c *gin.Context) bool {
+	for _, name := range []string{AccessTokenCookie, RefreshTokenCookie} {
+		if cookie, err := c.Cookie(name); err == nil && cookie != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,341 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/config"
+)
+
+func newTestAuthCookies(t *testing.T) *AuthCookies {
+	t.Helper()
+
+	cookies, err := NewAuthCookies(&config.AuthCookieConfig{Secure: true, SameSite: "strict", CSRFTTL: 3600}, setupTestJWT(t, "15m"), "test-secret")
+	if err != nil {
+		t.Fatalf("falha ao criar AuthCookies: %v", err)
+	}
+	return cookies
+}
+
+// sessionAccessToken gera um access token da sessão informada
+func sessionAccessToken(t *testing.T, authCookies *AuthCookies, sessionID string) string {
+	t.Helper()
+
+	token, err := authCookies.jwtService.GenerateAccessToken("user-123", "user@example.com", "user", sessionID, false)
+	if err != nil {
+		t.Fatalf("falha ao gerar access token: %v", err)
+	}
+	return token
+}
+
+// responseCookies retorna os cookies gravados na resposta, pelo nome
+func responseCookies(w *httptest.ResponseRecorder) map[string]*http.Cookie {
+	cookies := make(map[string]*http.Cookie)
+	for _, cookie := range w.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	return cookies
+}
+
+func TestNewAuthCookies(t *testing.T) {
+	t.Run("SameSite none exige Secure", func(t *testing.T) {
+		if _, err := NewAuthCookies(&config.AuthCookieConfig{SameSite: "none"}, setupTestJWT(t, "15m"), "test-secret"); err == nil {
+			t.Error("esperava erro para SameSite=None sem Secure")
+		}
+		if _, err := NewAuthCookies(&config.AuthCookieConfig{SameSite: "none", Secure: true}, setupTestJWT(t, "15m"), "test-secret"); err != nil {
+			t.Errorf("esperava sucesso, obteve %v", err)
+		}
+	})
+
+	t.Run("recusa SameSite desconhecido", func(t *testing.T) {
+		if _, err := NewAuthCookies(&config.AuthCookieConfig{SameSite: "loose"}, setupTestJWT(t, "15m"), "test-secret"); err == nil {
+			t.Error("esperava erro para SameSite inválido")
+		}
+	})
+}
+
+func TestAuthCookies_SetTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authCookies := newTestAuthCookies(t)
+
+	t.Run("grava os tokens em cookies HttpOnly", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+		access := sessionAccessToken(t, authCookies, "session-1")
+
+		csrfToken, err := authCookies.SetTokens(c, access, "refresh", 15*time.Minute)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve %v", err)
+		}
+		if !authCookies.validCSRFToken(csrfToken, "session-1") {
+			t.Error("esperava token CSRF vinculado à sessão do access token")
+		}
+
+		cookies := responseCookies(w)
+		for name, want := range map[string]struct {
+			value  string
+			path   string
+			maxAge int
+		}{
+			AccessTokenCookie:  {access, "/", 900},
+			RefreshTokenCookie: {"refresh", "/api/v1/auth", 604800},
+			CSRFCookie:         {csrfToken, "/", 3600},
+		} {
+			cookie, ok := cookies[name]
+			if !ok {
+				t.Errorf("%s: esperava cookie", name)
+				continue
+			}
+			if cookie.Value != want.value || cookie.Path != want.path || cookie.MaxAge != want.maxAge {
+				t.Errorf("%s: esperava %+v, obteve valor '%s', path '%s', max-age %d", name, want, cookie.Value, cookie.Path, cookie.MaxAge)
+			}
+			if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteStrictMode {
+				t.Errorf("%s: esperava HttpOnly, Secure e SameSite=Strict, obteve %+v", name, cookie)
+			}
+		}
+	})
+
+	t.Run("logout remove os cookies", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/sessions/logout", nil)
+
+		authCookies.Clear(c)
+
+		cookies := responseCookies(w)
+		for _, name := range []string{AccessTokenCookie, RefreshTokenCookie, CSRFCookie} {
+			if cookie, ok := cookies[name]; !ok || cookie.MaxAge >= 0 || cookie.Value != "" {
+				t.Errorf("%s: esperava cookie removido, obteve %+v", name, cookie)
+			}
+		}
+	})
+
+	t.Run("recusa access token inválido", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+
+		if _, err := authCookies.SetTokens(c, "access", "refresh", 15*time.Minute); err == nil {
+			t.Error("esperava erro para access token inválido")
+		}
+	})
+}
+
+func TestAuthCookies_CSRFToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authCookies := newTestAuthCookies(t)
+
+	request := func(csrfCookie string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/auth/csrf", nil)
+		c.Request.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: sessionAccessToken(t, authCookies, "session-1")})
+		if csrfCookie != "" {
+			c.Request.AddCookie(&http.Cookie{Name: CSRFCookie, Value: csrfCookie})
+		}
+		return c
+	}
+
+	issue := func(sessionID string) string {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		token, _ := authCookies.issueCSRFToken(c, sessionID)
+		return token
+	}
+
+	t.Run("reaproveita o token CSRF válido do cookie", func(t *testing.T) {
+		issued := issue("session-1")
+
+		token, err := authCookies.CSRFToken(request(issued))
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve %v", err)
+		}
+		if token != issued {
+			t.Errorf("esperava o token do cookie, obteve '%s'", token)
+		}
+	})
+
+	t.Run("emite novo token quando o do cookie é de outra sessão", func(t *testing.T) {
+		issued := issue("session-2")
+
+		token, err := authCookies.CSRFToken(request(issued))
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve %v", err)
+		}
+		if token == issued || !authCookies.validCSRFToken(token, "session-1") {
+			t.Errorf("esperava novo token da sessão atual, obteve '%s'", token)
+		}
+	})
+
+	t.Run("recusa requisição sem sessão nos cookies", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/auth/csrf", nil)
+
+		if _, err := authCookies.CSRFToken(c); !errors.Is(err, ErrNoCookieSession) {
+			t.Errorf("esperava ErrNoCookieSession, obteve %v", err)
+		}
+	})
+}
+
+func TestAuthCookies_RequireCSRF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authCookies := newTestAuthCookies(t)
+
+	now := time.Now()
+	authCookies.now = func() time.Time { return now }
+
+	newRouter := func() *gin.Engine {
+		router := gin.New()
+		router.Use(authCookies.RequireCSRF())
+		router.GET("/api/v1/users/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+		router.POST("/api/v1/organizations", func(c *gin.Context) { c.Status(http.StatusCreated) })
+		return router
+	}
+
+	access := sessionAccessToken(t, authCookies, "session-1")
+
+	issue := func() string {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		token, _ := authCookies.issueCSRFToken(c, "session-1")
+		return token
+	}
+
+	send := func(method, csrfCookie, csrfHeader string, headers map[string]string) int {
+		path := "/api/v1/organizations"
+		if method == http.MethodGet {
+			path = "/api/v1/users/me"
+		}
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: access})
+		if csrfCookie != "" {
+			req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: csrfCookie})
+		}
+		if csrfHeader != "" {
+			req.Header.Set(CSRFHeader, csrfHeader)
+		}
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		newRouter().ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("aceita token igual ao do cookie", func(t *testing.T) {
+		token := issue()
+		if code := send(http.MethodPost, token, token, nil); code != http.StatusCreated {
+			t.Errorf("esperava 201, obteve %d", code)
+		}
+	})
+
+	t.Run("recusa requisição sem token", func(t *testing.T) {
+		if code := send(http.MethodPost, issue(), "", nil); code != http.StatusForbidden {
+			t.Errorf("esperava 403, obteve %d", code)
+		}
+	})
+
+	t.Run("recusa token diferente do cookie", func(t *testing.T) {
+		if code := send(http.MethodPost, issue(), issue(), nil); code != http.StatusForbidden {
+			t.Errorf("esperava 403, obteve %d", code)
+		}
+	})
+
+	t.Run("recusa token sem assinatura válida", func(t *testing.T) {
+		forged := "nonce.9999999999.assinatura"
+		if code := send(http.MethodPost, forged, forged, nil); code != http.StatusForbidden {
+			t.Errorf("esperava 403, obteve %d", code)
+		}
+	})
+
+	t.Run("recusa token de outra sessão", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		token, _ := authCookies.issueCSRFToken(c, "session-2")
+
+		if code := send(http.MethodPost, token, token, nil); code != http.StatusForbidden {
+			t.Errorf("esperava 403, obteve %d", code)
+		}
+	})
+
+	t.Run("recusa token no campo de formulário", func(t *testing.T) {
+		token := issue()
+		form := url.Values{"csrf_token": {token}}
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/organizations", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: access})
+		req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: token})
+		newRouter().ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("esperava 403, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("usa a sessão do refresh token sem access token", func(t *testing.T) {
+		token := issue()
+		refresh, _ := authCookies.jwtService.GenerateRefreshToken("user-123", "session-1", false)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/organizations", nil)
+		req.AddCookie(&http.Cookie{Name: RefreshTokenCookie, Value: refresh})
+		req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: token})
+		req.Header.Set(CSRFHeader, token)
+		newRouter().ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Errorf("esperava 201, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("recusa token com cookies de autenticação inválidos", func(t *testing.T) {
+		token := issue()
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/organizations", nil)
+		req.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: "access"})
+		req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: token})
+		req.Header.Set(CSRFHeader, token)
+		newRouter().ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("esperava 403, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("recusa token expirado", func(t *testing.T) {
+		token := issue()
+		now = now.Add(2 * time.Hour)
+		defer func() { now = now.Add(-2 * time.Hour) }()
+
+		if code := send(http.MethodPost, token, token, nil); code != http.StatusForbidden {
+			t.Errorf("esperava 403, obteve %d", code)
+		}
+	})
+
+	t.Run("métodos seguros dispensam o token", func(t *testing.T) {
+		if code := send(http.MethodGet, "", "", nil); code != http.StatusOK {
+			t.Errorf("esperava 200, obteve %d", code)
+		}
+	})
+
+	t.Run("header Authorization dispensa o token", func(t *testing.T) {
+		if code := send(http.MethodPost, "", "", map[string]string{"Authorization": "Bearer token"}); code != http.StatusCreated {
+			t.Errorf("esperava 201, obteve %d", code)
+		}
+	})
+
+	t.Run("requisição sem cookies de autenticação dispensa o token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/organizations", nil))
+		if w.Code != http.StatusCreated {
+			t.Errorf("esperava 201, obteve %d", w.Code)
+		}
+	})
+}
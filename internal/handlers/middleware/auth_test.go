@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/config"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/denylist"
+)
+
+func setupTestJWT(t *testing.T, accessExpiry string) *auth.JWTService {
+	t.Helper()
+
+	service, err := auth.NewJWTService(&config.JWTConfig{
+		Secret:        "test-secret",
+		AccessExpiry:  accessExpiry,
+		RefreshExpiry: "168h",
+	})
+	if err != nil {
+		t.Fatalf("failed to initialize jwt service: %v", err)
+	}
+
+	return service
+}
+
+func TestAuthMiddleware_RequireAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := setupTestJWT(t, "15m")
+	tokenDenylist := denylist.NewMemoryDenylist()
+	middleware := NewAuthMiddleware(jwtService, tokenDenylist)
+
+	newContext := func(authorization string) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/", nil)
+		if authorization != "" {
+			c.Request.Header.Set("Authorization", authorization)
+		}
+		return c, w
+	}
+
+	t.Run("aceita token válido e popula o contexto", func(t *testing.T) {
+		token, _ := jwtService.GenerateAccessToken("user-123", "user@example.com", "admin", "session-1", false)
+		c, w := newContext("Bearer " + token)
+
+		middleware.RequireAuth()(c)
+
+		if c.IsAborted() {
+			t.Fatalf("não esperava abort, status %d", w.Code)
+		}
+		if GetUserID(c) != "user-123" {
+			t.Errorf("esperava user ID 'user-123', obteve '%s'", GetUserID(c))
+		}
+		if GetRole(c) != "admin" {
+			t.Errorf("esperava role 'admin', obteve '%s'", GetRole(c))
+		}
+		if GetSessionID(c) != "session-1" {
+			t.Errorf("esperava sessão 'session-1', obteve '%s'", GetSessionID(c))
+		}
+	})
+
+	t.Run("propaga o access token no contexto", func(t *testing.T) {
+		token, _ := jwtService.GenerateAccessToken("user-123", "user@example.com", "admin", "session-1", false)
+		claims, _ := jwtService.ValidateAccessToken(token)
+		c, _ := newContext("Bearer " + token)
+
+		middleware.RequireAuth()(c)
+
+		current, ok := domain.AccessTokenFromContext(c.Request.Context())
+		if !ok || current.ID != claims.ID || current.SessionID != "session-1" || !current.ExpiresAt.Equal(claims.ExpiresAt.Time) {
+			t.Errorf("access token inesperado no contexto: %+v", current)
+		}
+	})
+
+	t.Run("rejeita token revogado", func(t *testing.T) {
+		token, _ := jwtService.GenerateAccessToken("user-123", "user@example.com", "admin", "session-1", false)
+		claims, _ := jwtService.ValidateAccessToken(token)
+		_ = tokenDenylist.Revoke(context.Background(), claims.ID, claims.ExpiresAt.Time)
+		c, w := newContext("Bearer " + token)
+
+		middleware.RequireAuth()(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("esperava status 401, obteve %d", w.Code)
+		}
+		if GetUserID(c) != "" {
+			t.Error("não esperava user ID no contexto")
+		}
+	})
+
+	t.Run("rejeita token de sessão revogada", func(t *testing.T) {
+		token, _ := jwtService.GenerateAccessToken("user-123", "user@example.com", "admin", "session-2", false)
+		_ = tokenDenylist.Revoke(context.Background(), domain.SessionDenylistKey("session-2"), time.Now().Add(15*time.Minute))
+		c, w := newContext("Bearer " + token)
+
+		middleware.RequireAuth()(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("esperava status 401, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("falha fechado quando a denylist está indisponível", func(t *testing.T) {
+		unavailable := NewAuthMiddleware(jwtService, failingDenylist{})
+		token, _ := jwtService.GenerateAccessToken("user-123", "user@example.com", "admin", "session-1", false)
+		c, w := newContext("Bearer " + token)
+
+		unavailable.RequireAuth()(c)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("esperava status 500, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("propaga o segundo fator da sessão", func(t *testing.T) {
+		for _, mfa := range []bool{false, true} {
+			token, _ := jwtService.GenerateAccessToken("user-123", "user@example.com", "admin", "session-1", mfa)
+			c, _ := newContext("Bearer " + token)
+
+			middleware.RequireAuth()(c)
+
+			if got := domain.MFAVerifiedFromContext(c.Request.Context()); got != mfa {
+				t.Errorf("esperava mfa %v no contexto, obteve %v", mfa, got)
+			}
+		}
+	})
+
+	t.Run("rejeita token de desafio do segundo fator", func(t *testing.T) {
+		token, _ := jwtService.GenerateMFAToken("user-123")
+		c, w := newContext("Bearer " + token)
+
+		middleware.RequireAuth()(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("esperava status 401, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("aceita o access token do cookie sem header", func(t *testing.T) {
+		token, _ := jwtService.GenerateAccessToken("user-123", "user@example.com", "admin", "session-1", false)
+		c, w := newContext("")
+		c.Request.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: token})
+
+		middleware.RequireAuth()(c)
+
+		if c.IsAborted() {
+			t.Fatalf("não esperava abort, status %d", w.Code)
+		}
+		if GetUserID(c) != "user-123" {
+			t.Errorf("esperava user ID 'user-123', obteve '%s'", GetUserID(c))
+		}
+	})
+
+	t.Run("rejeita requisição sem header", func(t *testing.T) {
+		c, w := newContext("")
+
+		middleware.RequireAuth()(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("esperava status 401, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("rejeita esquema diferente de Bearer", func(t *testing.T) {
+		token, _ := jwtService.GenerateAccessToken("user-123", "user@example.com", "admin", "session-1", false)
+		c, w := newContext("Basic " + token)
+
+		middleware.RequireAuth()(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("esperava status 401, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("rejeita refresh token", func(t *testing.T) {
+		token, _ := jwtService.GenerateRefreshToken("user-123", "session-1", false)
+		c, w := newContext("Bearer " + token)
+
+		middleware.RequireAuth()(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("esperava status 401, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("rejeita token expirado", func(t *testing.T) {
+		expired := setupTestJWT(t, "-1m")
+		token, _ := expired.GenerateAccessToken("user-123", "user@example.com", "admin", "session-1", false)
+		c, w := newContext("Bearer " + token)
+
+		middleware.RequireAuth()(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("esperava status 401, obteve %d", w.Code)
+		}
+		if GetUserID(c) != "" {
+			t.Error("não esperava user ID no contexto")
+		}
+	})
+}
+
+// failingDenylist simula a denylist fora do ar
+type failingDenylist struct{}
+
+func (failingDenylist) Revoke(context.Context, string, time.Time) error {
+	return errors.New("connection refused")
+}
+
+func (failingDenylist) IsRevoked(context.Context, string) (bool, error) {
+	return false, errors.New("connection refused")
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+// maxUserAgentLength limita o User-Agent armazenado nas sessões
+const maxUserAgentLength = 512
+
+// ClientInfo propaga o IP e o User-Agent da requisição no context.Context
+// O IP respeita os proxies confiáveis configurados no gin
+func ClientInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userAgent := c.Request.UserAgent()
+		if len(userAgent) > maxUserAgentLength {
+			userAgent = strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+		}
+
+		ctx := domain.WithClientInfo(c.Request.Context(), domain.ClientInfo{
+			IPAddress: c.ClientIP(),
+			UserAgent: userAgent,
+		})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+func TestClientInfo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newContext := func(userAgent string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/", nil)
+		c.Request.RemoteAddr = "203.0.113.10:52100"
+		c.Request.Header.Set("User-Agent", userAgent)
+		return c
+	}
+
+	t.Run("propaga IP e User-Agent no contexto", func(t *testing.T) {
+		c := newContext("Mozilla/5.0 (X11; Linux x86_64) Firefox/128.0")
+
+		ClientInfo()(c)
+
+		info := domain.ClientInfoFromContext(c.Request.Context())
+		if info.IPAddress != "203.0.113.10" {
+			t.Errorf("esperava IP '203.0.113.10', obteve '%s'", info.IPAddress)
+		}
+		if info.UserAgent != "Mozilla/5.0 (X11; Linux x86_64) Firefox/128.0" {
+			t.Errorf("User-Agent inesperado: '%s'", info.UserAgent)
+		}
+	})
+
+	t.Run("trunca User-Agent longo", func(t *testing.T) {
+		c := newContext(strings.Repeat("a", 2*maxUserAgentLength))
+
+		ClientInfo()(c)
+
+		info := domain.ClientInfoFromContext(c.Request.Context())
+		if len(info.UserAgent) != maxUserAgentLength {
+			t.Errorf("esperava %d bytes, obteve %d", maxUserAgentLength, len(info.UserAgent))
+		}
+	})
+}
@@ -1,39 +1,299 @@
 package middleware
 
 import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+)
+
+var (
+	// defaultCORSMethods são os métodos aceitos quando a política não os define
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	// defaultCORSHeaders são os headers aceitos quando a política não os define
+	defaultCORSHeaders = []string{"Accept", "Accept-Language", "Authorization", "Content-Type", OrganizationHeader, AuthModeHeader, CSRFHeader}
+	// defaultCORSExposedHeaders são os headers legíveis pelo frontend quando a
+	// política não os define: os limites de requisições e a espera após 429/423
+	defaultCORSExposedHeaders = []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"}
 )
 
-// CORS configura CORS para a aplicação
-func CORS(allowedOrigins string) gin.HandlerFunc {
-	origins := strings.Split(allowedOrigins, ",")
+// CORSPolicy define as regras de CORS de um conjunto de rotas
+// Métodos, headers e headers expostos nil usam os padrões; uma lista vazia não
+// aceita nenhum
+type CORSPolicy struct {
+	// AllowedOrigins aceita origins exatos ("https://app.avantpro.com.br"),
+	// qualquer subdomínio ("https://*.avantpro.com.br") e "*", qualquer origin,
+	// que não pode ser combinado com AllowCredentials
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge é por quanto tempo o navegador pode reaproveitar o preflight
+	MaxAge time.Duration
+	// OrganizationOrigins também aceita os origins cadastrados pelas organizações,
+	// sempre sem credentials: eles não são configurados pela plataforma
+	OrganizationOrigins bool
+}
+
+// CORS aplica as políticas de CORS da API
+// Preflights de origins não aceitos recebem 403, assim como requisições que
+// alteram dados (POST, PUT, PATCH, DELETE); as demais seguem sem os headers
+// de CORS e o navegador bloqueia a leitura da resposta
+type CORS struct {
+	origins domain.OrganizationOrigins
+	base    *corsRules
+	routes  []corsRoute
+}
+
+// corsRoute associa um prefixo de caminho a uma política
+type corsRoute struct {
+	prefix string
+	rules  *corsRules
+}
+
+// NewCORS cria o middleware CORS com a política padrão de todas as rotas
+// origins pode ser nil quando nenhuma política aceita origins das organizações
+func NewCORS(policy CORSPolicy, origins domain.OrganizationOrigins) (*CORS, error) {
+	rules, err := compileCORSPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
 
+	return &CORS{origins: origins, base: rules}, nil
+}
+
+// Route define a política das rotas sob o prefixo, no lugar da padrão
+// Vale o prefixo mais longo; deve ser chamado antes de o servidor atender
+// requisições. A escolha é pelo caminho (e não pela rota do gin) porque o
+// preflight OPTIONS não corresponde a nenhuma rota registrada
+func (m *CORS) Route(prefix string, policy CORSPolicy) error {
+	rules, err := compileCORSPolicy(policy)
+	if err != nil {
+		return fmt.Errorf("CORS policy for %s: %w", prefix, err)
+	}
+
+	m.routes = append(m.routes, corsRoute{prefix: strings.TrimSuffix(prefix, "/"), rules: rules})
+	sort.SliceStable(m.routes, func(i, j int) bool {
+		return len(m.routes[i].prefix) > len(m.routes[j].prefix)
+	})
+	return nil
+}
+
+// Handle retorna o middleware; deve ser global para também receber os
+// preflights de rotas sem handler OPTIONS
+func (m *CORS) Handle() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		origin := c.Request.Header.Get("Origin")
-
-		// Verificar se origem é permitida
-		allowed := false
-		for _, o := range origins {
-			if strings.TrimSpace(o) == origin || strings.TrimSpace(o) == "*" {
-				allowed = true
-				break
+		rules := m.rulesFor(c.Request.URL.Path)
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		// Caches intermediários não podem reaproveitar a resposta de um origin para outro
+		if !rules.anyOrigin {
+			c.Writer.Header().Add("Vary", "Origin")
+		}
+		if preflight {
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if origin == "" || isSameOrigin(c, origin) {
+			c.Next()
+			return
+		}
+
+		allowed, credentials := m.allow(c, rules, origin, preflight)
+		if !allowed {
+			if preflight || !isSafeMethod(c.Request.Method) {
+				c.AbortWithStatusJSON(http.StatusForbidden,
+					dto.ForbiddenErrorResponseI18n(c, domainerrors.ErrOriginNotAllowed.Error()))
+				return
 			}
+			c.Next()
+			return
 		}
 
-		if allowed {
+		if rules.anyOrigin {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
 			c.Header("Access-Control-Allow-Origin", origin)
-			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+		}
+		if credentials {
 			c.Header("Access-Control-Allow-Credentials", "true")
 		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		if preflight {
+			c.Header("Access-Control-Allow-Methods", rules.methods)
+			c.Header("Access-Control-Allow-Headers", rules.headers)
+			if rules.maxAge != "" {
+				c.Header("Access-Control-Max-Age", rules.maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
+		if rules.exposed != "" {
+			c.Header("Access-Control-Expose-Headers", rules.exposed)
+		}
 		c.Next()
 	}
 }
+
+// rulesFor retorna a política do caminho
+func (m *CORS) rulesFor(path string) *corsRules {
+	for _, route := range m.routes {
+		if path == route.prefix || strings.HasPrefix(path, route.prefix+"/") {
+			return route.rules
+		}
+	}
+	return m.base
+}
+
+// allow indica se o origin é aceito e se a resposta pode liberar credentials
+// Um origin cadastrado por organizações só vale para requisições que não
+// escolhem outra organização pelo header X-Organization-ID; o preflight não
+// traz o valor do header e aceita o origin de qualquer uma delas
+func (m *CORS) allow(c *gin.Context, rules *corsRules, origin string, preflight bool) (bool, bool) {
+	if rules.matches(origin) {
+		return true, rules.policy.AllowCredentials
+	}
+
+	if !rules.policy.OrganizationOrigins || m.origins == nil {
+		return false, false
+	}
+
+	organizationIDs, err := m.origins.OrganizationsAllowingOrigin(c.Request.Context(), origin)
+	if err != nil {
+		_ = c.Error(err)
+		return false, false
+	}
+
+	organizationID := c.GetHeader(OrganizationHeader)
+	if preflight || organizationID == "" {
+		return len(organizationIDs) > 0, false
+	}
+	return slices.Contains(organizationIDs, organizationID), false
+}
+
+// corsRules é uma CORSPolicy validada, com os headers já montados
+type corsRules struct {
+	policy    CORSPolicy
+	anyOrigin bool
+	origins   map[string]bool
+	wildcards []originWildcard
+	methods   string
+	headers   string
+	exposed   string
+	maxAge    string
+}
+
+// originWildcard aceita qualquer subdomínio: prefix é o scheme ("https://")
+// e suffix o domínio pai com a porta (".avantpro.com.br")
+type originWildcard struct {
+	prefix string
+	suffix string
+}
+
+// compileCORSPolicy valida os origins da política e monta os headers
+func compileCORSPolicy(policy CORSPolicy) (*corsRules, error) {
+	rules := &corsRules{
+		policy:  policy,
+		origins: make(map[string]bool),
+		methods: strings.Join(withDefault(policy.AllowedMethods, defaultCORSMethods), ", "),
+		headers: strings.Join(withDefault(policy.AllowedHeaders, defaultCORSHeaders), ", "),
+		exposed: strings.Join(withDefault(policy.ExposedHeaders, defaultCORSExposedHeaders), ", "),
+	}
+	if policy.MaxAge > 0 {
+		rules.maxAge = strconv.Itoa(int(policy.MaxAge.Seconds()))
+	}
+
+	for _, pattern := range policy.AllowedOrigins {
+		pattern = strings.TrimSpace(pattern)
+
+		switch {
+		case pattern == "*":
+			if policy.AllowCredentials {
+				return nil, fmt.Errorf("CORS origin %q cannot be combined with credentials", pattern)
+			}
+			rules.anyOrigin = true
+		case strings.Contains(pattern, "://*."):
+			origin, err := valueobjects.NewOrigin(strings.Replace(pattern, "://*.", "://", 1))
+			if err != nil {
+				return nil, fmt.Errorf("invalid CORS origin %q", pattern)
+			}
+			scheme, parent, _ := strings.Cut(origin.String(), "://")
+			rules.wildcards = append(rules.wildcards, originWildcard{prefix: scheme + "://", suffix: "." + parent})
+		default:
+			origin, err := valueobjects.NewOrigin(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CORS origin %q", pattern)
+			}
+			rules.origins[origin.String()] = true
+		}
+	}
+
+	return rules, nil
+}
+
+// matches indica se a política aceita o origin
+func (r *corsRules) matches(origin string) bool {
+	if r.anyOrigin || r.origins[origin] {
+		return true
+	}
+
+	for _, w := range r.wildcards {
+		if len(origin) <= len(w.prefix)+len(w.suffix) ||
+			!strings.HasPrefix(origin, w.prefix) || !strings.HasSuffix(origin, w.suffix) {
+			continue
+		}
+		if isSubdomain(origin[len(w.prefix) : len(origin)-len(w.suffix)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubdomain valida os rótulos que o wildcard substitui (ex: "app" ou "a.b")
+func isSubdomain(labels string) bool {
+	for _, label := range strings.Split(labels, ".") {
+		if label == "" || strings.HasPrefix(label, "-") {
+			return false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isSameOrigin indica se o origin é o próprio host da API (ex: Swagger UI)
+// Navegadores enviam Origin também em POSTs same-origin
+func isSameOrigin(c *gin.Context, origin string) bool {
+	_, host, ok := strings.Cut(origin, "://")
+	return ok && host == c.Request.Host
+}
+
+// isSafeMethod indica se o método não altera dados
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// withDefault retorna values ou, quando nil, os padrões
+func withDefault(values, defaults []string) []string {
+	if values == nil {
+		return defaults
+	}
+	return values
+}
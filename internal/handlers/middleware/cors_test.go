@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeOrganizationOrigins associa origins às organizações que os cadastraram
+type fakeOrganizationOrigins map[string][]string
+
+func (f fakeOrganizationOrigins) OrganizationsAllowingOrigin(_ context.Context, origin string) ([]string, error) {
+	return f[origin], nil
+}
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(t *testing.T, cors *CORS) *gin.Engine {
+		t.Helper()
+		router := gin.New()
+		router.Use(cors.Handle())
+		router.GET("/api/v1/users/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+		router.POST("/api/v1/organizations", func(c *gin.Context) { c.Status(http.StatusCreated) })
+		router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return router
+	}
+
+	newCORS := func(t *testing.T, origins ...string) *CORS {
+		t.Helper()
+		cors, err := NewCORS(CORSPolicy{
+			AllowedOrigins:      origins,
+			AllowCredentials:    true,
+			MaxAge:              10 * time.Minute,
+			OrganizationOrigins: true,
+		}, fakeOrganizationOrigins{"https://app.cliente.com": {"11111111-1111-1111-1111-111111111111"}})
+		if err != nil {
+			t.Fatalf("falha ao criar CORS: %v", err)
+		}
+		return cors
+	}
+
+	send := func(router *gin.Engine, method, path, origin string, headers map[string]string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.Host = "api.avantpro.com.br"
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	preflight := func(router *gin.Engine, path, origin string) *httptest.ResponseRecorder {
+		return send(router, http.MethodOptions, path, origin, map[string]string{
+			"Access-Control-Request-Method":  "POST",
+			"Access-Control-Request-Headers": "authorization, content-type",
+		})
+	}
+
+	t.Run("preflight de origin permitido", func(t *testing.T) {
+		router := newRouter(t, newCORS(t, "https://app.avantpro.com.br"))
+
+		w := preflight(router, "/api/v1/organizations", "https://app.avantpro.com.br")
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("esperava 204, obteve %d", w.Code)
+		}
+		for name, want := range map[string]string{
+			"Access-Control-Allow-Origin":      "https://app.avantpro.com.br",
+			"Access-Control-Allow-Credentials": "true",
+			"Access-Control-Allow-Methods":     "GET, POST, PUT, PATCH, DELETE",
+			"Access-Control-Allow-Headers":     "Accept, Accept-Language, Authorization, Content-Type, X-Organization-ID, X-Auth-Mode, X-CSRF-Token",
+			"Access-Control-Max-Age":           "600",
+		} {
+			if got := w.Header().Get(name); got != want {
+				t.Errorf("%s: esperava '%s', obteve '%s'", name, want, got)
+			}
+		}
+		if vary := w.Header().Values("Vary"); len(vary) != 3 || vary[0] != "Origin" {
+			t.Errorf("esperava Vary com Origin e os headers do preflight, obteve %v", vary)
+		}
+	})
+
+	t.Run("preflight de origin não permitido recebe 403", func(t *testing.T) {
+		router := newRouter(t, newCORS(t, "https://app.avantpro.com.br"))
+
+		w := preflight(router, "/api/v1/organizations", "https://evil.com")
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("esperava 403, obteve %d", w.Code)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("não esperava Access-Control-Allow-Origin, obteve '%s'", got)
+		}
+	})
+
+	t.Run("requisição permitida expõe os headers de rate limiting", func(t *testing.T) {
+		router := newRouter(t, newCORS(t, "https://app.avantpro.com.br"))
+
+		w := send(router, http.MethodGet, "/api/v1/users/me", "https://app.avantpro.com.br", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("esperava 200, obteve %d", w.Code)
+		}
+		want := "RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, Retry-After"
+		if got := w.Header().Get("Access-Control-Expose-Headers"); got != want {
+			t.Errorf("esperava '%s', obteve '%s'", want, got)
+		}
+		if got := w.Header().Get("Vary"); got != "Origin" {
+			t.Errorf("esperava Vary: Origin, obteve '%s'", got)
+		}
+	})
+
+	t.Run("origin não permitido só é recusado em métodos que alteram dados", func(t *testing.T) {
+		router := newRouter(t, newCORS(t, "https://app.avantpro.com.br"))
+
+		w := send(router, http.MethodGet, "/api/v1/users/me", "https://evil.com", nil)
+		if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("esperava 200 sem headers CORS, obteve %d e '%s'", w.Code, w.Header().Get("Access-Control-Allow-Origin"))
+		}
+
+		if w := send(router, http.MethodPost, "/api/v1/organizations", "https://evil.com", nil); w.Code != http.StatusForbidden {
+			t.Errorf("esperava 403, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("requisições sem Origin ou do próprio host seguem normalmente", func(t *testing.T) {
+		router := newRouter(t, newCORS(t, "https://app.avantpro.com.br"))
+
+		if w := send(router, http.MethodPost, "/api/v1/organizations", "", nil); w.Code != http.StatusCreated {
+			t.Errorf("sem Origin: esperava 201, obteve %d", w.Code)
+		}
+		if w := send(router, http.MethodPost, "/api/v1/organizations", "https://api.avantpro.com.br", nil); w.Code != http.StatusCreated {
+			t.Errorf("mesmo host: esperava 201, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("wildcard aceita subdomínios do domínio", func(t *testing.T) {
+		router := newRouter(t, newCORS(t, "https://*.avantpro.com.br"))
+
+		for origin, allowed := range map[string]bool{
+			"https://app.avantpro.com.br":          true,
+			"https://staging.app.avantpro.com.br":  true,
+			"https://avantpro.com.br":              false,
+			"http://app.avantpro.com.br":           false,
+			"https://evilavantpro.com.br":          false,
+			"https://app.avantpro.com.br.evil.com": false,
+			"https://evil.com/.avantpro.com.br":    false,
+			"https://app.avantpro.com.br:8443":     false,
+		} {
+			w := preflight(router, "/api/v1/organizations", origin)
+			if got := w.Code == http.StatusNoContent; got != allowed {
+				t.Errorf("%s: esperava permitido=%v, obteve status %d", origin, allowed, w.Code)
+			}
+		}
+	})
+
+	t.Run("wildcard com credentials é recusado", func(t *testing.T) {
+		if _, err := NewCORS(CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true}, nil); err == nil {
+			t.Error("esperava erro para '*' com credentials")
+		}
+	})
+
+	t.Run("origins inválidos são recusados", func(t *testing.T) {
+		for _, origin := range []string{"app.avantpro.com.br", "https://app.*.com.br", "https://*.avantpro.com.br/app"} {
+			if _, err := NewCORS(CORSPolicy{AllowedOrigins: []string{origin}}, nil); err == nil {
+				t.Errorf("%s: esperava erro", origin)
+			}
+		}
+	})
+
+	t.Run("política por rota", func(t *testing.T) {
+		cors := newCORS(t, "https://app.avantpro.com.br")
+		if err := cors.Route("/health", CORSPolicy{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}); err != nil {
+			t.Fatalf("falha ao definir política: %v", err)
+		}
+		router := newRouter(t, cors)
+
+		w := send(router, http.MethodGet, "/health", "https://status.example.com", nil)
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+			t.Errorf("esperava '*', obteve '%s'", got)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+			t.Errorf("não esperava credentials, obteve '%s'", got)
+		}
+
+		if w := preflight(router, "/api/v1/organizations", "https://status.example.com"); w.Code != http.StatusForbidden {
+			t.Errorf("esperava política padrão fora do prefixo, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("origins das organizações são aceitos sem credentials", func(t *testing.T) {
+		router := newRouter(t, newCORS(t, "https://app.avantpro.com.br"))
+
+		w := preflight(router, "/api/v1/organizations", "https://app.cliente.com")
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("esperava 204, obteve %d", w.Code)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+			t.Errorf("não esperava credentials, obteve '%s'", got)
+		}
+
+		own := map[string]string{OrganizationHeader: "11111111-1111-1111-1111-111111111111"}
+		if w := send(router, http.MethodPost, "/api/v1/organizations", "https://app.cliente.com", own); w.Code != http.StatusCreated {
+			t.Errorf("própria organização: esperava 201, obteve %d", w.Code)
+		}
+
+		other := map[string]string{OrganizationHeader: "22222222-2222-2222-2222-222222222222"}
+		if w := send(router, http.MethodPost, "/api/v1/organizations", "https://app.cliente.com", other); w.Code != http.StatusForbidden {
+			t.Errorf("outra organização: esperava 403, obteve %d", w.Code)
+		}
+	})
+}
@@ -10,9 +10,9 @@ import (
 
 const (
 	// LanguageContextKey é a chave usada para armazenar o idioma no contexto do Gin
-	LanguageContextKey = "language"
+	LanguageContextKey = i18n.LanguageContextKey
 	// I18nServiceContextKey é a chave usada para armazenar o serviço i18n no contexto
-	I18nServiceContextKey = "i18n_service"
+	I18nServiceContextKey = i18n.ServiceContextKey
 )
 
 // I18nMiddleware gerencia a detecção de idioma nas requisições
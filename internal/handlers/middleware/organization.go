@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+)
+
+// OrganizationHeader escolhe a organização da requisição
+const OrganizationHeader = "X-Organization-ID"
+
+// RequireOrganization exige uma organização selecionada para a requisição
+// A organização vem do header X-Organization-ID; os tokens não a fixam, para
+// que o mesmo login atenda todas as organizações do usuário.
+// Deve rodar após RequireAuth; a associação do usuário à organização é
+// verificada pelos services.
+func RequireOrganization() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		organizationID := c.GetHeader(OrganizationHeader)
+
+		if uuid.Validate(organizationID) != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				dto.BadRequestErrorResponseI18n(c, domainerrors.ErrMissingOrganization.Error()))
+			return
+		}
+
+		c.Set(OrganizationIDContextKey, organizationID)
+		c.Request = c.Request.WithContext(domain.WithOrganizationID(c.Request.Context(), organizationID))
+
+		c.Next()
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+func TestRequireOrganization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const orgID = "8b0f7d8e-3c7a-4f55-9d0e-3f2b1a6c4d21"
+
+	newContext := func(header string) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/", nil)
+		if header != "" {
+			c.Request.Header.Set(OrganizationHeader, header)
+		}
+		return c, w
+	}
+
+	t.Run("usa a organização do header", func(t *testing.T) {
+		c, _ := newContext(orgID)
+
+		RequireOrganization()(c)
+
+		if c.IsAborted() {
+			t.Fatal("não esperava abort")
+		}
+		if got := GetOrganizationID(c); got != orgID {
+			t.Errorf("esperava organização '%s', obteve '%s'", orgID, got)
+		}
+		if got, _ := domain.OrganizationIDFromContext(c.Request.Context()); got != orgID {
+			t.Errorf("esperava organização '%s' no contexto, obteve '%s'", orgID, got)
+		}
+	})
+
+	t.Run("rejeita requisição sem organização", func(t *testing.T) {
+		c, w := newContext("")
+
+		RequireOrganization()(c)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("esperava status 400, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("rejeita organização malformada", func(t *testing.T) {
+		c, w := newContext("nao-e-uuid")
+
+		RequireOrganization()(c)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("esperava status 400, obteve %d", w.Code)
+		}
+	})
+}
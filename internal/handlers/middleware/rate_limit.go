@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+)
+
+// maxRateLimitBody é o máximo do corpo lido para extrair o email da chave
+const maxRateLimitBody = 64 * 1024
+
+// RateLimitKey extrai da requisição a chave limitada
+// Uma chave vazia dispensa o limite para a requisição
+type RateLimitKey func(c *gin.Context) string
+
+// ByIP limita pelo IP do cliente (respeita os proxies confiáveis do gin)
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUser limita pelo usuário autenticado; deve rodar após RequireAuth
+func ByUser(c *gin.Context) string {
+	if userID := GetUserID(c); userID != "" {
+		return "user:" + userID
+	}
+	return ""
+}
+
+// ByOrganization limita pela organização da requisição; deve rodar após RequireOrganization
+func ByOrganization(c *gin.Context) string {
+	if organizationID := GetOrganizationID(c); organizationID != "" {
+		return "organization:" + organizationID
+	}
+	return ""
+}
+
+// ByEmail limita pelo campo "email" do corpo JSON
+// O corpo é restaurado para o handler; o email entra na chave como hash
+func ByEmail(c *gin.Context) string {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRateLimitBody))
+	if err != nil {
+		return ""
+	}
+	c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(body), c.Request.Body), c.Request.Body}
+
+	var payload struct {
+		Email string `json:"email"`
+	}
+	if json.Unmarshal(body, &payload) != nil {
+		return ""
+	}
+
+	email := strings.ToLower(strings.TrimSpace(payload.Email))
+	if email == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(email))
+	return "email:" + hex.EncodeToString(sum[:])
+}
+
+// readCloser relê o trecho já consumido do corpo e fecha o corpo original
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// RateLimiter aplica limites de requisições por rota
+type RateLimiter struct {
+	limiter domain.RateLimiter
+}
+
+// NewRateLimiter cria um novo middleware de rate limiting
+func NewRateLimiter(limiter domain.RateLimiter) *RateLimiter {
+	return &RateLimiter{limiter: limiter}
+}
+
+// Limit limita as requisições da rota por chave; name separa os contadores de rotas diferentes
+// Responde com os headers RateLimit-Limit, RateLimit-Remaining e RateLimit-Reset
+// e, acima do limite, com 429 e Retry-After. Com vários limites na rota, os
+// headers refletem o mais restritivo
+func (r *RateLimiter) Limit(name string, limit domain.RateLimit, key RateLimitKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		k := key(c)
+		if k == "" {
+			c.Next()
+			return
+		}
+
+		result, err := r.limiter.Allow(c.Request.Context(), name+":"+k, limit)
+		if err != nil {
+			// Sem os contadores a requisição segue: indisponibilidade do
+			// limitador não deve derrubar login e cadastro
+			_ = c.Error(err)
+			c.Next()
+			return
+		}
+
+		setRateLimitHeaders(c, result)
+
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, dto.TooManyRequestsErrorResponseI18n(c,
+				"error.rate_limited", map[string]interface{}{"RetryAfter": retryAfter}))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// setRateLimitHeaders grava os headers RateLimit-*, mantendo os de um limite
+// anterior da mesma rota quando ele tem menos requisições restantes
+func setRateLimitHeaders(c *gin.Context, result domain.RateLimitResult) {
+	if previous := c.Writer.Header().Get("RateLimit-Remaining"); previous != "" {
+		if remaining, err := strconv.Atoi(previous); err == nil && remaining < result.Remaining {
+			return
+		}
+	}
+
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+}
+
+// ceilSeconds arredonda a duração para cima em segundos
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
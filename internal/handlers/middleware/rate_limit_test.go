@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/ratelimit"
+)
+
+func TestRateLimiter_Limit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	perHour := domain.RateLimit{Limit: 2, Window: time.Hour}
+
+	// newRouter monta uma rota de login que devolve o email recebido no corpo
+	newRouter := func(handlers ...gin.HandlerFunc) *gin.Engine {
+		router := gin.New()
+		handlers = append(handlers, func(c *gin.Context) {
+			var req struct {
+				Email string `json:"email"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.Status(http.StatusBadRequest)
+				return
+			}
+			c.String(http.StatusOK, req.Email)
+		})
+		router.POST("/login", handlers...)
+		return router
+	}
+
+	post := func(router *gin.Engine, remoteAddr, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/login", strings.NewReader(body))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("recusa acima do limite com 429 e headers", func(t *testing.T) {
+		router := newRouter(NewRateLimiter(ratelimit.NewMemoryLimiter()).Limit("login", perHour, ByIP))
+
+		for i := 0; i < 2; i++ {
+			if w := post(router, "203.0.113.10:1234", `{"email":"a@b.com"}`); w.Code != http.StatusOK {
+				t.Fatalf("requisição %d: esperava 200, obteve %d", i+1, w.Code)
+			}
+		}
+
+		w := post(router, "203.0.113.10:1234", `{"email":"a@b.com"}`)
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("esperava 429, obteve %d", w.Code)
+		}
+		if w.Header().Get("Retry-After") == "" {
+			t.Error("esperava header Retry-After")
+		}
+		if w.Header().Get("RateLimit-Limit") != "2" || w.Header().Get("RateLimit-Remaining") != "0" {
+			t.Errorf("headers inesperados: %v", w.Header())
+		}
+		if !strings.Contains(w.Body.String(), "/problems/too-many-requests") {
+			t.Errorf("esperava problem RFC 7807, obteve %s", w.Body.String())
+		}
+
+		if w := post(router, "198.51.100.7:1234", `{"email":"a@b.com"}`); w.Code != http.StatusOK {
+			t.Errorf("esperava outro IP liberado, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("limita por email preservando o corpo", func(t *testing.T) {
+		router := newRouter(NewRateLimiter(ratelimit.NewMemoryLimiter()).Limit("login", perHour, ByEmail))
+
+		post(router, "203.0.113.10:1234", `{"email":"User@Example.com"}`)
+		w := post(router, "198.51.100.7:1234", `{"email":" user@example.com "}`)
+		if w.Code != http.StatusOK || w.Body.String() != " user@example.com " {
+			t.Fatalf("esperava corpo preservado, obteve %d %q", w.Code, w.Body.String())
+		}
+
+		if w := post(router, "192.0.2.1:1234", `{"email":"user@example.com"}`); w.Code != http.StatusTooManyRequests {
+			t.Errorf("esperava 429 para o mesmo email de outro IP, obteve %d", w.Code)
+		}
+		if w := post(router, "192.0.2.1:1234", `{"email":"other@example.com"}`); w.Code != http.StatusOK {
+			t.Errorf("esperava outro email liberado, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("sem chave a requisição não é limitada", func(t *testing.T) {
+		router := newRouter(NewRateLimiter(ratelimit.NewMemoryLimiter()).Limit("login", perHour, ByEmail))
+
+		for i := 0; i < 3; i++ {
+			if w := post(router, "203.0.113.10:1234", `{}`); w.Code != http.StatusOK {
+				t.Fatalf("requisição %d: esperava 200, obteve %d", i+1, w.Code)
+			}
+		}
+	})
+
+	t.Run("headers refletem o limite mais restritivo", func(t *testing.T) {
+		limiter := NewRateLimiter(ratelimit.NewMemoryLimiter())
+		router := newRouter(
+			limiter.Limit("login", domain.RateLimit{Limit: 10, Window: time.Hour}, ByIP),
+			limiter.Limit("login", perHour, ByEmail),
+		)
+
+		w := post(router, "203.0.113.10:1234", `{"email":"a@b.com"}`)
+		if w.Header().Get("RateLimit-Limit") != "2" || w.Header().Get("RateLimit-Remaining") != "1" {
+			t.Errorf("esperava headers do limite por email, obteve %v", w.Header())
+		}
+	})
+
+	t.Run("limitador indisponível não bloqueia", func(t *testing.T) {
+		router := newRouter(NewRateLimiter(failingLimiter{}).Limit("login", perHour, ByIP))
+
+		if w := post(router, "203.0.113.10:1234", `{"email":"a@b.com"}`); w.Code != http.StatusOK {
+			t.Errorf("esperava 200, obteve %d", w.Code)
+		}
+	})
+}
+
+// failingLimiter simula o armazenamento dos contadores fora do ar
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, domain.RateLimit) (domain.RateLimitResult, error) {
+	return domain.RateLimitResult{}, errors.New("connection refused")
+}
+
+func TestRateLimitKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("usuário e organização vêm do contexto autenticado", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("POST", "/", nil)
+
+		if ByUser(c) != "" || ByOrganization(c) != "" {
+			t.Error("esperava chaves vazias sem autenticação")
+		}
+
+		c.Set(UserIDContextKey, "user-1")
+		c.Set(OrganizationIDContextKey, "org-1")
+		if ByUser(c) != "user:user-1" || ByOrganization(c) != "organization:org-1" {
+			t.Errorf("chaves inesperadas: %q %q", ByUser(c), ByOrganization(c))
+		}
+	})
+}
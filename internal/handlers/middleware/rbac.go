@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/handlers/dto"
+)
+
+// PermissionMiddleware verifica as permissões do usuário na organização da requisição
+type PermissionMiddleware struct {
+	authorizer domain.MemberAuthorizer
+}
+
+// NewPermissionMiddleware cria um novo middleware de permissões
+func NewPermissionMiddleware(authorizer domain.MemberAuthorizer) *PermissionMiddleware {
+	return &PermissionMiddleware{authorizer: authorizer}
+}
+
+// RequirePermission exige que a role do usuário na organização conceda a permissão
+// Deve ser usado após RequireAuth e RequireOrganization; quem não é membro
+// recebe 404, como nos services, para não expor a existência da organização
+func (m *PermissionMiddleware) RequirePermission(permission entities.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := m.authorizer.AuthorizeMember(c.Request.Context(), GetUserID(c), GetOrganizationID(c), permission)
+
+		switch {
+		case err == nil:
+			c.Next()
+		case errors.Is(err, domainerrors.ErrOrganizationNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, dto.NotFoundErrorResponseI18n(c, dto.T(c, "resource.organization")))
+		case errors.Is(err, domainerrors.ErrMFARequiredByOrganization):
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ForbiddenErrorResponseI18n(c, err.Error()))
+		case errors.Is(err, domainerrors.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ForbiddenErrorResponseI18n(c))
+		default:
+			_ = c.Error(err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.InternalErrorResponseI18n(c))
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+)
+
+// fakeMemberAuthorizer concede as permissões da role de cada usuário na organização
+type fakeMemberAuthorizer map[string]entities.Role
+
+func (f fakeMemberAuthorizer) AuthorizeMember(_ context.Context, userID, organizationID string, permission entities.Permission) error {
+	role, ok := f[userID+"@"+organizationID]
+	if !ok {
+		return domainerrors.ErrOrganizationNotFound
+	}
+	if !role.HasPermission(permission) {
+		return domainerrors.ErrForbidden
+	}
+	return nil
+}
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	permissions := NewPermissionMiddleware(fakeMemberAuthorizer{
+		"user-1@org-1": entities.RoleAdmin,
+		"user-1@org-2": entities.RoleGuest,
+		"user-2@org-1": entities.RoleUser,
+	})
+
+	newContext := func(userID, organizationID string) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/", nil)
+		c.Set(UserIDContextKey, userID)
+		c.Set(OrganizationIDContextKey, organizationID)
+		return c, w
+	}
+
+	t.Run("permite role com a permissão", func(t *testing.T) {
+		c, _ := newContext("user-2", "org-1")
+
+		permissions.RequirePermission(entities.PermissionMembersRead)(c)
+
+		if c.IsAborted() {
+			t.Error("não esperava abort")
+		}
+	})
+
+	t.Run("permite role com wildcard", func(t *testing.T) {
+		c, _ := newContext("user-1", "org-1")
+
+		permissions.RequirePermission(entities.PermissionInvitesWrite)(c)
+
+		if c.IsAborted() {
+			t.Error("não esperava abort")
+		}
+	})
+
+	t.Run("usa a role do usuário na organização da requisição", func(t *testing.T) {
+		c, w := newContext("user-1", "org-2")
+
+		permissions.RequirePermission(entities.PermissionInvitesWrite)(c)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("esperava status 403, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("nega role sem a permissão", func(t *testing.T) {
+		c, w := newContext("user-2", "org-1")
+
+		permissions.RequirePermission(entities.PermissionSSOWrite)(c)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("esperava status 403, obteve %d", w.Code)
+		}
+	})
+
+	t.Run("responde 404 para quem não é membro", func(t *testing.T) {
+		c, w := newContext("user-2", "org-2")
+
+		permissions.RequirePermission(entities.PermissionOrganizationsRead)(c)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("esperava status 404, obteve %d", w.Code)
+		}
+	})
+}
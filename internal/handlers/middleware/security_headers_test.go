@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/config"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(cfg config.SecurityHeadersConfig, handlers ...gin.HandlerFunc) http.Header {
+		router := gin.New()
+		router.Use(SecurityHeaders(&cfg))
+		handlers = append(handlers, func(c *gin.Context) { c.Status(http.StatusNoContent) })
+		router.GET("/", handlers...)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		return w.Header()
+	}
+
+	base := config.SecurityHeadersConfig{
+		ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+		HSTSIncludeSubdomains: true,
+	}
+
+	t.Run("envia os headers obrigatórios", func(t *testing.T) {
+		headers := serve(base)
+
+		for name, want := range map[string]string{
+			"X-Frame-Options":         "DENY",
+			"Content-Security-Policy": "default-src 'none'; frame-ancestors 'none'",
+			"X-Content-Type-Options":  "nosniff",
+			"X-Xss-Protection":        "1; mode=block",
+			"Referrer-Policy":         "strict-origin-when-cross-origin",
+		} {
+			if got := headers.Get(name); got != want {
+				t.Errorf("%s: esperava '%s', obteve '%s'", name, want, got)
+			}
+		}
+		if got := headers.Get("Strict-Transport-Security"); got != "" {
+			t.Errorf("não esperava HSTS sem max-age, obteve '%s'", got)
+		}
+	})
+
+	t.Run("envia HSTS quando configurado", func(t *testing.T) {
+		cfg := base
+		cfg.HSTSMaxAge = 63072000
+		cfg.HSTSPreload = true
+
+		want := "max-age=63072000; includeSubDomains; preload"
+		if got := serve(cfg).Get("Strict-Transport-Security"); got != want {
+			t.Errorf("esperava '%s', obteve '%s'", want, got)
+		}
+	})
+
+	t.Run("CSP da rota substitui a global", func(t *testing.T) {
+		headers := serve(base, ContentSecurityPolicy(SwaggerContentSecurityPolicy))
+
+		if got := headers.Get("Content-Security-Policy"); got != SwaggerContentSecurityPolicy {
+			t.Errorf("esperava CSP do Swagger, obteve '%s'", got)
+		}
+		if got := headers.Get("X-Frame-Options"); got != "DENY" {
+			t.Errorf("esperava X-Frame-Options mantido, obteve '%s'", got)
+		}
+	})
+}
@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/config"
+)
+
+const issuer = "avantpro"
+
+// mfaExpiry é a validade do token de desafio emitido quando o login exige o segundo fator
+const mfaExpiry = 5 * time.Minute
+
+// TokenType identifica o propósito de um JWT
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+	// TokenTypeMFA identifica o desafio entre a senha e o segundo fator
+	// Não dá acesso à API: só é trocado por tokens em /auth/mfa/verify
+	TokenTypeMFA TokenType = "mfa"
+)
+
+var (
+	ErrInvalidToken     = errors.New("invalid token")
+	ErrExpiredToken     = errors.New("token expired")
+	ErrInvalidTokenType = errors.New("invalid token type")
+)
+
+// Claims são os claims customizados dos tokens emitidos pela API
+// O ID do usuário é armazenado em RegisteredClaims.Subject
+type Claims struct {
+	Email string    `json:"email,omitempty"`
+	Role  string    `json:"role,omitempty"`
+	Type  TokenType `json:"type"`
+	// SessionID é a família de refresh tokens que originou o access token
+	SessionID string `json:"sid,omitempty"`
+	// MFA indica que a sessão foi autenticada com o segundo fator
+	MFA bool `json:"mfa,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// JWTService gerencia geração e validação de tokens
+type JWTService struct {
+	secretKey     []byte
+	accessExpiry  time.Duration
+	refreshExpiry time.Duration
+}
+
+// NewJWTService cria um novo JWTService a partir da configuração
+func NewJWTService(cfg *config.JWTConfig) (*JWTService, error) {
+	if cfg.Secret == "" {
+		return nil, errors.New("jwt secret is required")
+	}
+
+	accessExpiry, err := time.ParseDuration(cfg.AccessExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_ACCESS_EXPIRY: %w", err)
+	}
+
+	refreshExpiry, err := time.ParseDuration(cfg.RefreshExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_REFRESH_EXPIRY: %w", err)
+	}
+
+	return &JWTService{
+		secretKey:     []byte(cfg.Secret),
+		accessExpiry:  accessExpiry,
+		refreshExpiry: refreshExpiry,
+	}, nil
+}
+
+// AccessExpiry retorna a validade configurada para access tokens
+func (s *JWTService) AccessExpiry() time.Duration {
+	return s.accessExpiry
+}
+
+// RefreshExpiry retorna a validade configurada para refresh tokens
+func (s *JWTService) RefreshExpiry() time.Duration {
+	return s.refreshExpiry
+}
+
+// MFAExpiry retorna a validade do token de desafio do segundo fator
+func (s *JWTService) MFAExpiry() time.Duration {
+	return mfaExpiry
+}
+
+// GenerateAccessToken gera um JWT de acesso
+// sessionID identifica a sessão (família de refresh tokens) e mfa indica
+// que a sessão passou pelo segundo fator
+func (s *JWTService) GenerateAccessToken(userID, email, role, sessionID string, mfa bool) (string, error) {
+	claims := Claims{
+		Email:            email,
+		Role:             role,
+		Type:             TokenTypeAccess,
+		SessionID:        sessionID,
+		MFA:              mfa,
+		RegisteredClaims: s.registeredClaims(userID, s.accessExpiry),
+	}
+
+	return s.sign(claims)
+}
+
+// GenerateRefreshToken gera um JWT de refresh
+// O claim mfa é repassado aos access tokens emitidos na rotação e sessionID
+// permite ao modo cookie vincular o token CSRF à sessão sem o access token
+func (s *JWTService) GenerateRefreshToken(userID, sessionID string, mfa bool) (string, error) {
+	claims := Claims{
+		Type:             TokenTypeRefresh,
+		SessionID:        sessionID,
+		MFA:              mfa,
+		RegisteredClaims: s.registeredClaims(userID, s.refreshExpiry),
+	}
+
+	return s.sign(claims)
+}
+
+// GenerateMFAToken gera o token de desafio que comprova a senha enquanto
+// o segundo fator não é informado
+func (s *JWTService) GenerateMFAToken(userID string) (string, error) {
+	claims := Claims{
+		Type:             TokenTypeMFA,
+		RegisteredClaims: s.registeredClaims(userID, mfaExpiry),
+	}
+
+	return s.sign(claims)
+}
+
+// ValidateToken valida a assinatura e a validade de um JWT
+func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		// Verificar algoritmo de assinatura
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return s.secretKey, nil
+	}, jwt.WithIssuer(issuer), jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	if !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// ValidateAccessToken valida especificamente access tokens
+func (s *JWTService) ValidateAccessToken(tokenString string) (*Claims, error) {
+	return s.validateType(tokenString, TokenTypeAccess)
+}
+
+// ValidateRefreshToken valida especificamente refresh tokens
+func (s *JWTService) ValidateRefreshToken(tokenString string) (*Claims, error) {
+	return s.validateType(tokenString, TokenTypeRefresh)
+}
+
+// ValidateMFAToken valida especificamente tokens de desafio do segundo fator
+func (s *JWTService) ValidateMFAToken(tokenString string) (*Claims, error) {
+	return s.validateType(tokenString, TokenTypeMFA)
+}
+
+func (s *JWTService) validateType(tokenString string, tokenType TokenType) (*Claims, error) {
+	claims, err := s.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Type != tokenType {
+		return nil, ErrInvalidTokenType
+	}
+
+	return claims, nil
+}
+
+func (s *JWTService) registeredClaims(userID string, ttl time.Duration) jwt.RegisteredClaims {
+	now := time.Now()
+
+	return jwt.RegisteredClaims{
+		Issuer:    issuer,
+		Subject:   userID,
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ID:        uuid.New().String(), // JTI único por token
+	}
+}
+
+func (s *JWTService) sign(claims Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(s.secretKey)
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/config"
+)
+
+func newTestJWTService(t *testing.T, secret, accessExpiry string) *JWTService {
+	t.Helper()
+
+	service, err := NewJWTService(&config.JWTConfig{
+		Secret:        secret,
+		AccessExpiry:  accessExpiry,
+		RefreshExpiry: "168h",
+	})
+	if err != nil {
+		t.Fatalf("falha ao criar JWTService: %v", err)
+	}
+
+	return service
+}
+
+func TestNewJWTService(t *testing.T) {
+	t.Run("erro quando secret está vazio", func(t *testing.T) {
+		_, err := NewJWTService(&config.JWTConfig{AccessExpiry: "15m", RefreshExpiry: "168h"})
+		if err == nil {
+			t.Error("esperava erro, obteve sucesso")
+		}
+	})
+
+	t.Run("erro quando expiração é inválida", func(t *testing.T) {
+		_, err := NewJWTService(&config.JWTConfig{Secret: "secret", AccessExpiry: "quinze", RefreshExpiry: "168h"})
+		if err == nil {
+			t.Error("esperava erro, obteve sucesso")
+		}
+	})
+}
+
+func TestJWTService_AccessToken(t *testing.T) {
+	service := newTestJWTService(t, "secret", "15m")
+
+	t.Run("gera e valida access token", func(t *testing.T) {
+		token, err := service.GenerateAccessToken("user-123", "user@example.com", "admin", "session-1", false)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		claims, err := service.ValidateAccessToken(token)
+		if err != nil {
+			t.Fatalf("esperava token válido, obteve erro: %v", err)
+		}
+
+		if claims.Subject != "user-123" {
+			t.Errorf("esperava subject 'user-123', obteve '%s'", claims.Subject)
+		}
+		if claims.Email != "user@example.com" {
+			t.Errorf("esperava email 'user@example.com', obteve '%s'", claims.Email)
+		}
+		if claims.Role != "admin" {
+			t.Errorf("esperava role 'admin', obteve '%s'", claims.Role)
+		}
+		if claims.SessionID != "session-1" {
+			t.Errorf("esperava sid 'session-1', obteve '%s'", claims.SessionID)
+		}
+		if claims.ID == "" {
+			t.Error("esperava jti preenchido")
+		}
+	})
+
+	t.Run("rejeita refresh token como access token", func(t *testing.T) {
+		token, _ := service.GenerateRefreshToken("user-123", "session-1", false)
+
+		_, err := service.ValidateAccessToken(token)
+		if !errors.Is(err, ErrInvalidTokenType) {
+			t.Errorf("esperava ErrInvalidTokenType, obteve %v", err)
+		}
+	})
+
+	t.Run("rejeita assinatura inválida", func(t *testing.T) {
+		other := newTestJWTService(t, "outro-secret", "15m")
+		token, _ := other.GenerateAccessToken("user-123", "user@example.com", "admin", "session-1", false)
+
+		_, err := service.ValidateAccessToken(token)
+		if !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("esperava ErrInvalidToken, obteve %v", err)
+		}
+	})
+
+	t.Run("rejeita token expirado", func(t *testing.T) {
+		expired := newTestJWTService(t, "secret", "-1m")
+		token, _ := expired.GenerateAccessToken("user-123", "user@example.com", "admin", "session-1", false)
+
+		_, err := service.ValidateAccessToken(token)
+		if !errors.Is(err, ErrExpiredToken) {
+			t.Errorf("esperava ErrExpiredToken, obteve %v", err)
+		}
+	})
+}
+
+func TestJWTService_MFAToken(t *testing.T) {
+	service := newTestJWTService(t, "secret", "15m")
+
+	t.Run("gera e valida token de desafio", func(t *testing.T) {
+		token, err := service.GenerateMFAToken("user-123")
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		claims, err := service.ValidateMFAToken(token)
+		if err != nil {
+			t.Fatalf("esperava token válido, obteve erro: %v", err)
+		}
+		if claims.Subject != "user-123" {
+			t.Errorf("esperava subject 'user-123', obteve '%s'", claims.Subject)
+		}
+		if claims.ExpiresAt.Sub(claims.IssuedAt.Time) != service.MFAExpiry() {
+			t.Errorf("esperava validade de %v", service.MFAExpiry())
+		}
+	})
+
+	t.Run("token de desafio não serve como access token", func(t *testing.T) {
+		token, _ := service.GenerateMFAToken("user-123")
+
+		if _, err := service.ValidateAccessToken(token); !errors.Is(err, ErrInvalidTokenType) {
+			t.Errorf("esperava ErrInvalidTokenType, obteve %v", err)
+		}
+	})
+
+	t.Run("claim mfa é preservado nos tokens", func(t *testing.T) {
+		access, _ := service.GenerateAccessToken("user-123", "user@example.com", "admin", "session-1", true)
+		refresh, _ := service.GenerateRefreshToken("user-123", "session-1", true)
+
+		accessClaims, err := service.ValidateAccessToken(access)
+		if err != nil || !accessClaims.MFA {
+			t.Errorf("esperava access token com mfa, obteve %+v (%v)", accessClaims, err)
+		}
+		refreshClaims, err := service.ValidateRefreshToken(refresh)
+		if err != nil || !refreshClaims.MFA {
+			t.Errorf("esperava refresh token com mfa, obteve %+v (%v)", refreshClaims, err)
+		}
+		if refreshClaims != nil && refreshClaims.SessionID != "session-1" {
+			t.Errorf("esperava sid 'session-1' no refresh token, obteve '%s'", refreshClaims.SessionID)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/config"
+)
+
+// Padrões recomendados pela OWASP para argon2id
+const (
+	defaultArgon2Memory      = 19 * 1024 // KiB
+	defaultArgon2Iterations  = 2
+	defaultArgon2Parallelism = 1
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var errMalformedHash = errors.New("malformed password hash")
+
+// argon2Params são os parâmetros de custo codificados em cada hash
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// PasswordHasher implementa domain.PasswordHasher com argon2id
+// Hashes bcrypt de contas antigas continuam válidos e são marcados para rehash
+type PasswordHasher struct {
+	params    argon2Params
+	dummyHash string
+}
+
+// NewPasswordHasher cria um PasswordHasher a partir da configuração
+func NewPasswordHasher(cfg *config.PasswordConfig) (*PasswordHasher, error) {
+	params := argon2Params{
+		memory:      cfg.Memory,
+		iterations:  cfg.Iterations,
+		parallelism: defaultArgon2Parallelism,
+	}
+	if params.memory == 0 {
+		params.memory = defaultArgon2Memory
+	}
+	if params.iterations == 0 {
+		params.iterations = defaultArgon2Iterations
+	}
+	if cfg.Parallelism != 0 {
+		if cfg.Parallelism > math.MaxUint8 {
+			return nil, fmt.Errorf("invalid ARGON2_PARALLELISM: must be at most %d", math.MaxUint8)
+		}
+		params.parallelism = uint8(cfg.Parallelism)
+	}
+	if params.memory < 8*uint32(params.parallelism) {
+		return nil, fmt.Errorf("invalid ARGON2_MEMORY: must be at least %d KiB", 8*uint32(params.parallelism))
+	}
+
+	hasher := &PasswordHasher{params: params}
+
+	// Hash descartável com o custo atual para SimulateVerify
+	dummyHash, err := hasher.Hash("avantpro-timing-dummy")
+	if err != nil {
+		return nil, err
+	}
+	hasher.dummyHash = dummyHash
+
+	return hasher, nil
+}
+
+var _ domain.PasswordHasher = (*PasswordHasher)(nil)
+
+// Hash gera o hash argon2id no formato PHC: $argon2id$v=19$m=,t=,p=$salt$hash
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.iterations, h.params.memory, h.params.parallelism, argon2KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.params.memory, h.params.iterations, h.params.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify compara a senha com um hash argon2id ou bcrypt (legado)
+func (h *PasswordHasher) Verify(hash, password string) (bool, bool) {
+	if isBcryptHash(hash) {
+		ok := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+		return ok, ok
+	}
+
+	params, salt, key, err := decodeArgon2Hash(hash)
+	if err != nil {
+		return false, false
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(candidate, key) != 1 {
+		return false, false
+	}
+
+	return true, params != h.params || len(salt) != argon2SaltLength || len(key) != argon2KeyLength
+}
+
+// SimulateVerify executa uma verificação descartável com o custo atual
+// Previne enumeração de emails por análise do tempo de resposta
+func (h *PasswordHasher) SimulateVerify(password string) {
+	_, _ = h.Verify(h.dummyHash, password)
+}
+
+// isBcryptHash reconhece os prefixos $2a$, $2b$ e $2y$ do bcrypt
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// decodeArgon2Hash extrai parâmetros, salt e chave de um hash no formato PHC
+func decodeArgon2Hash(hash string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, errMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errMalformedHash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return params, nil, nil, errMalformedHash
+	}
+	if params.iterations == 0 || params.parallelism == 0 {
+		return params, nil, nil, errMalformedHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, errMalformedHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, errMalformedHash
+	}
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/config"
+)
+
+func newTestPasswordHasher(t *testing.T, memory, iterations uint32) *PasswordHasher {
+	t.Helper()
+
+	hasher, err := NewPasswordHasher(&config.PasswordConfig{Memory: memory, Iterations: iterations, Parallelism: 1})
+	if err != nil {
+		t.Fatalf("falha ao criar PasswordHasher: %v", err)
+	}
+
+	return hasher
+}
+
+func TestNewPasswordHasher(t *testing.T) {
+	t.Run("usa os padrões quando a configuração está zerada", func(t *testing.T) {
+		hasher, err := NewPasswordHasher(&config.PasswordConfig{})
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if hasher.params.memory != defaultArgon2Memory || hasher.params.iterations != defaultArgon2Iterations {
+			t.Errorf("esperava parâmetros padrão, obteve %+v", hasher.params)
+		}
+	})
+
+	t.Run("erro quando o paralelismo excede o limite", func(t *testing.T) {
+		if _, err := NewPasswordHasher(&config.PasswordConfig{Parallelism: 300}); err == nil {
+			t.Error("esperava erro, obteve sucesso")
+		}
+	})
+
+	t.Run("erro quando a memória é menor que o mínimo", func(t *testing.T) {
+		if _, err := NewPasswordHasher(&config.PasswordConfig{Memory: 8, Parallelism: 4}); err == nil {
+			t.Error("esperava erro, obteve sucesso")
+		}
+	})
+}
+
+func TestPasswordHasher(t *testing.T) {
+	hasher := newTestPasswordHasher(t, 64, 1)
+
+	t.Run("gera hash argon2id no formato PHC", func(t *testing.T) {
+		hash, err := hasher.Hash("Senha123")
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") {
+			t.Errorf("formato inesperado: '%s'", hash)
+		}
+
+		other, _ := hasher.Hash("Senha123")
+		if other == hash {
+			t.Error("esperava salts diferentes para cada hash")
+		}
+	})
+
+	t.Run("verifica a senha correta sem pedir rehash", func(t *testing.T) {
+		hash, _ := hasher.Hash("Senha123")
+
+		ok, needsRehash := hasher.Verify(hash, "Senha123")
+		if !ok || needsRehash {
+			t.Errorf("esperava ok=true e needsRehash=false, obteve %v e %v", ok, needsRehash)
+		}
+	})
+
+	t.Run("rejeita senha incorreta", func(t *testing.T) {
+		hash, _ := hasher.Hash("Senha123")
+
+		if ok, _ := hasher.Verify(hash, "Senha124"); ok {
+			t.Error("esperava senha rejeitada")
+		}
+	})
+
+	t.Run("pede rehash quando os parâmetros mudam", func(t *testing.T) {
+		hash, _ := newTestPasswordHasher(t, 32, 1).Hash("Senha123")
+
+		ok, needsRehash := hasher.Verify(hash, "Senha123")
+		if !ok || !needsRehash {
+			t.Errorf("esperava ok=true e needsRehash=true, obteve %v e %v", ok, needsRehash)
+		}
+	})
+
+	t.Run("aceita hash bcrypt legado e pede rehash", func(t *testing.T) {
+		legacy, _ := bcrypt.GenerateFromPassword([]byte("Senha123"), bcrypt.MinCost)
+
+		ok, needsRehash := hasher.Verify(string(legacy), "Senha123")
+		if !ok || !needsRehash {
+			t.Errorf("esperava ok=true e needsRehash=true, obteve %v e %v", ok, needsRehash)
+		}
+
+		if ok, _ := hasher.Verify(string(legacy), "Senha124"); ok {
+			t.Error("esperava senha rejeitada")
+		}
+	})
+
+	t.Run("rejeita hash malformado", func(t *testing.T) {
+		for _, hash := range []string{"", "texto-plano", "$argon2id$v=19$m=64,t=1,p=1$salt", "$argon2i$v=19$m=64,t=1,p=1$c2FsdA$a2V5"} {
+			if ok, _ := hasher.Verify(hash, "Senha123"); ok {
+				t.Errorf("esperava hash '%s' rejeitado", hash)
+			}
+		}
+	})
+}
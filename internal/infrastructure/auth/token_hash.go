@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// opaqueTokenBytes é a entropia dos tokens enviados por email (256 bits)
+const opaqueTokenBytes = 32
+
+// GenerateOpaqueToken gera um token aleatório codificado em base64url
+// Usado em links enviados por email (ativação, convites)
+func GenerateOpaqueToken() (string, error) {
+	buf := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashToken retorna o SHA-256 (hex) de um token para armazenamento
+// Tokens têm alta entropia, então um hash rápido sem salt é suficiente
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
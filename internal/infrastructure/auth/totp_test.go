@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret é a chave SHA-1 dos vetores de teste do apêndice B da RFC 6238
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCode(t *testing.T) {
+	// Vetores da RFC 6238 truncados para 6 dígitos
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got, err := TOTPCode(rfc6238Secret, time.Unix(tt.unix, 0))
+			if err != nil {
+				t.Fatalf("esperava sucesso, obteve erro: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("esperava '%s', obteve '%s'", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("falha ao gerar segredo: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	code, _ := TOTPCode(secret, now)
+
+	t.Run("aceita o código do passo atual", func(t *testing.T) {
+		step, ok := ValidateTOTP(secret, code, now)
+		if !ok {
+			t.Fatal("esperava código válido")
+		}
+		if step != now.Unix()/30 {
+			t.Errorf("esperava passo %d, obteve %d", now.Unix()/30, step)
+		}
+	})
+
+	t.Run("tolera um passo de diferença no relógio", func(t *testing.T) {
+		if _, ok := ValidateTOTP(secret, code, now.Add(30*time.Second)); !ok {
+			t.Error("esperava código aceito no passo seguinte")
+		}
+		if _, ok := ValidateTOTP(secret, code, now.Add(-30*time.Second)); !ok {
+			t.Error("esperava código aceito no passo anterior")
+		}
+	})
+
+	t.Run("rejeita código fora da janela", func(t *testing.T) {
+		if _, ok := ValidateTOTP(secret, code, now.Add(2*time.Minute)); ok {
+			t.Error("não esperava código aceito dois passos depois")
+		}
+	})
+
+	t.Run("rejeita código malformado", func(t *testing.T) {
+		for _, invalid := range []string{"", "12345", "1234567", "abcdef"} {
+			if _, ok := ValidateTOTP(secret, invalid, now); ok {
+				t.Errorf("não esperava '%s' aceito", invalid)
+			}
+		}
+	})
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := TOTPURI("AvantPro", "joao@email.com", "JBSWY3DPEHPK3PXP")
+
+	for _, part := range []string{"otpauth://totp/AvantPro:joao@email.com?", "secret=JBSWY3DPEHPK3PXP", "issuer=AvantPro", "digits=6", "period=30"} {
+		if !strings.Contains(uri, part) {
+			t.Errorf("esperava '%s' em '%s'", part, uri)
+		}
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("esperava sucesso, obteve erro: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(code) != 19 || strings.Count(code, "-") != 3 {
+			t.Errorf("formato inesperado: '%s'", code)
+		}
+		if seen[code] {
+			t.Errorf("código repetido: '%s'", code)
+		}
+		seen[code] = true
+
+		typed := strings.ToUpper(strings.ReplaceAll(code, "-", " "))
+		if NormalizeRecoveryCode(typed) != code {
+			t.Errorf("esperava '%s' normalizado para '%s'", typed, code)
+		}
+	}
+}
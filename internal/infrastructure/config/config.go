@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -13,16 +14,24 @@ type Config struct {
 	Database DatabaseConfig
 	Redis    RedisConfig
 	JWT      JWTConfig
+	Password PasswordConfig
 	OAuth    OAuthConfig
+	WebAuthn WebAuthnConfig
 	SMTP     SMTPConfig
 	Logging  LoggingConfig
 	CORS     CORSConfig
+	Security SecurityHeadersConfig
+	Cookies  AuthCookieConfig
 }
 
 type ServerConfig struct {
 	Port    string
 	Host    string
 	BaseURL string // URL base da API para construir URIs RFC 7807
+	AppURL  string // URL do frontend, usada nos links enviados por email
+	// TrustedProxies lista (separada por vírgula) os IPs/CIDRs dos proxies
+	// cujo X-Forwarded-For é aceito; vazio usa o IP da conexão
+	TrustedProxies string
 }
 
 type DatabaseConfig struct {
@@ -47,6 +56,13 @@ type JWTConfig struct {
 	RefreshExpiry string
 }
 
+// PasswordConfig define o custo do argon2id; valores zerados usam os padrões
+type PasswordConfig struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint32
+}
+
 type OAuthConfig struct {
 	GoogleClientID     string
 	GoogleClientSecret string
@@ -55,19 +71,53 @@ type OAuthConfig struct {
 	RedirectURL        string
 }
 
+// WebAuthnConfig identifica o relying party dos passkeys
+// RPID é o domínio registrável do frontend e Origins lista, separadas por
+// vírgula, as origens de onde as cerimônias podem partir
+type WebAuthnConfig struct {
+	RPID    string
+	RPName  string
+	Origins string
+}
+
 type SMTPConfig struct {
 	Host     string
 	Port     int
 	User     string
 	Password string
+	From     string // Remetente dos emails, ex: "AvantPro <no-reply@avantpro.com>"
 }
 
 type LoggingConfig struct {
 	Level string
 }
 
+// CORSConfig define os origins confiáveis do frontend
+// AllowedOrigins aceita origins exatos e subdomínios (https://*.avantpro.com.br),
+// separados por vírgula
 type CORSConfig struct {
 	AllowedOrigins string
+	MaxAge         int // Segundos que o navegador reaproveita o preflight
+}
+
+// AuthCookieConfig define os cookies do modo de autenticação por cookie
+// (frontend web), usado no lugar do header Authorization
+type AuthCookieConfig struct {
+	Domain   string // Vazio restringe os cookies ao host da API
+	Secure   bool   // Padrão: true em produção
+	SameSite string // strict, lax ou none (none exige Secure)
+	CSRFTTL  int    // Segundos de validade do token CSRF
+}
+
+// SecurityHeadersConfig define os headers de segurança de todas as respostas
+// Os padrões dependem do ambiente: HSTS só é enviado em produção
+type SecurityHeadersConfig struct {
+	ContentSecurityPolicy string
+	FrameOptions          string // DENY ou SAMEORIGIN
+	ReferrerPolicy        string
+	HSTSMaxAge            int // Segundos; 0 não envia Strict-Transport-Security
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
 }
 
 // Load carrega as configurações do arquivo .env
@@ -79,12 +129,20 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("error reading config file: %w", err)
 	}
 
+	setSecurityHeadersDefaults(viper.GetString("ENV"))
+	viper.SetDefault("CORS_MAX_AGE", 600) // 10 minutos
+	viper.SetDefault("AUTH_COOKIE_SECURE", viper.GetString("ENV") == "production")
+	viper.SetDefault("AUTH_COOKIE_SAMESITE", "strict")
+	viper.SetDefault("CSRF_TOKEN_TTL", 3600) // 1 hora
+
 	config := &Config{
 		Env: viper.GetString("ENV"),
 		Server: ServerConfig{
-			Port:    viper.GetString("PORT"),
-			Host:    viper.GetString("HOST"),
-			BaseURL: viper.GetString("API_BASE_URL"),
+			Port:           viper.GetString("PORT"),
+			Host:           viper.GetString("HOST"),
+			BaseURL:        viper.GetString("API_BASE_URL"),
+			AppURL:         viper.GetString("APP_URL"),
+			TrustedProxies: viper.GetString("TRUSTED_PROXIES"),
 		},
 		Database: DatabaseConfig{
 			Host:        viper.GetString("DB_HOST"),
@@ -105,6 +163,11 @@ func Load() (*Config, error) {
 			AccessExpiry:  viper.GetString("JWT_ACCESS_EXPIRY"),
 			RefreshExpiry: viper.GetString("JWT_REFRESH_EXPIRY"),
 		},
+		Password: PasswordConfig{
+			Memory:      viper.GetUint32("ARGON2_MEMORY"),
+			Iterations:  viper.GetUint32("ARGON2_ITERATIONS"),
+			Parallelism: viper.GetUint32("ARGON2_PARALLELISM"),
+		},
 		OAuth: OAuthConfig{
 			GoogleClientID:     viper.GetString("GOOGLE_CLIENT_ID"),
 			GoogleClientSecret: viper.GetString("GOOGLE_CLIENT_SECRET"),
@@ -112,23 +175,73 @@ func Load() (*Config, error) {
 			GitHubClientSecret: viper.GetString("GITHUB_CLIENT_SECRET"),
 			RedirectURL:        viper.GetString("OAUTH_REDIRECT_URL"),
 		},
+		WebAuthn: WebAuthnConfig{
+			RPID:    viper.GetString("WEBAUTHN_RP_ID"),
+			RPName:  viper.GetString("WEBAUTHN_RP_NAME"),
+			Origins: viper.GetString("WEBAUTHN_ORIGINS"),
+		},
 		SMTP: SMTPConfig{
 			Host:     viper.GetString("SMTP_HOST"),
 			Port:     viper.GetInt("SMTP_PORT"),
 			User:     viper.GetString("SMTP_USER"),
 			Password: viper.GetString("SMTP_PASS"),
+			From:     viper.GetString("SMTP_FROM"),
 		},
 		Logging: LoggingConfig{
 			Level: viper.GetString("LOG_LEVEL"),
 		},
 		CORS: CORSConfig{
 			AllowedOrigins: viper.GetString("CORS_ALLOWED_ORIGINS"),
+			MaxAge:         viper.GetInt("CORS_MAX_AGE"),
+		},
+		Cookies: AuthCookieConfig{
+			Domain:   viper.GetString("AUTH_COOKIE_DOMAIN"),
+			Secure:   viper.GetBool("AUTH_COOKIE_SECURE"),
+			SameSite: viper.GetString("AUTH_COOKIE_SAMESITE"),
+			CSRFTTL:  viper.GetInt("CSRF_TOKEN_TTL"),
+		},
+		Security: SecurityHeadersConfig{
+			ContentSecurityPolicy: viper.GetString("SECURITY_CSP"),
+			FrameOptions:          viper.GetString("SECURITY_FRAME_OPTIONS"),
+			ReferrerPolicy:        viper.GetString("SECURITY_REFERRER_POLICY"),
+			HSTSMaxAge:            viper.GetInt("SECURITY_HSTS_MAX_AGE"),
+			HSTSIncludeSubdomains: viper.GetBool("SECURITY_HSTS_INCLUDE_SUBDOMAINS"),
+			HSTSPreload:           viper.GetBool("SECURITY_HSTS_PRELOAD"),
 		},
 	}
 
 	return config, nil
 }
 
+// setSecurityHeadersDefaults define os padrões dos headers de segurança
+// A API só responde JSON, então a CSP padrão não permite carregar nada.
+// HSTS fica desligado fora de produção para não prender navegadores
+// locais ao HTTPS
+func setSecurityHeadersDefaults(env string) {
+	viper.SetDefault("SECURITY_CSP", "default-src 'none'; frame-ancestors 'none'")
+	viper.SetDefault("SECURITY_FRAME_OPTIONS", "DENY")
+	viper.SetDefault("SECURITY_REFERRER_POLICY", "strict-origin-when-cross-origin")
+	viper.SetDefault("SECURITY_HSTS_INCLUDE_SUBDOMAINS", true)
+	viper.SetDefault("SECURITY_HSTS_PRELOAD", false)
+
+	if env == "production" {
+		viper.SetDefault("SECURITY_HSTS_MAX_AGE", 63072000) // 2 anos
+	} else {
+		viper.SetDefault("SECURITY_HSTS_MAX_AGE", 0)
+	}
+}
+
+// TrustedProxyList retorna os proxies confiáveis, sem entradas vazias
+func (s *ServerConfig) TrustedProxyList() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(s.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
 // DSN retorna a connection string do PostgreSQL
 func (d *DatabaseConfig) DSN() string {
 	return fmt.Sprintf(
@@ -136,3 +249,14 @@ func (d *DatabaseConfig) DSN() string {
 		d.Host, d.Port, d.User, d.Password, d.DBName, d.SSLMode,
 	)
 }
+
+// OriginList retorna os origins permitidos, sem entradas vazias
+func (c *CORSConfig) OriginList() []string {
+	var origins []string
+	for _, origin := range strings.Split(c.AllowedOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
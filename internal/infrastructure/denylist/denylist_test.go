@@ -0,0 +1,101 @@
+package denylist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+// testDenylist verifica o contrato comum às implementações
+func testDenylist(t *testing.T, denylist domain.TokenDenylist) {
+	ctx := context.Background()
+
+	t.Run("token revogado fica na lista", func(t *testing.T) {
+		if err := denylist.Revoke(ctx, "jti-1", time.Now().Add(time.Minute)); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		revoked, err := denylist.IsRevoked(ctx, "jti-1")
+		if err != nil || !revoked {
+			t.Errorf("esperava token revogado, obteve %v (%v)", revoked, err)
+		}
+	})
+
+	t.Run("outros tokens não são afetados", func(t *testing.T) {
+		revoked, err := denylist.IsRevoked(ctx, "jti-2")
+		if err != nil || revoked {
+			t.Errorf("esperava token válido, obteve %v (%v)", revoked, err)
+		}
+	})
+
+	t.Run("token já expirado não entra na lista", func(t *testing.T) {
+		if err := denylist.Revoke(ctx, "jti-3", time.Now().Add(-time.Second)); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		if revoked, _ := denylist.IsRevoked(ctx, "jti-3"); revoked {
+			t.Error("não esperava token expirado na lista")
+		}
+	})
+}
+
+func TestMemoryDenylist(t *testing.T) {
+	denylist := NewMemoryDenylist()
+	testDenylist(t, denylist)
+
+	t.Run("entrada some quando o token expira", func(t *testing.T) {
+		now := time.Now()
+		denylist.now = func() time.Time { return now }
+		_ = denylist.Revoke(context.Background(), "jti-4", now.Add(time.Minute))
+
+		denylist.now = func() time.Time { return now.Add(time.Minute) }
+		if revoked, _ := denylist.IsRevoked(context.Background(), "jti-4"); revoked {
+			t.Error("não esperava token expirado na lista")
+		}
+
+		_ = denylist.Revoke(context.Background(), "jti-5", now.Add(2*time.Minute))
+		if _, ok := denylist.entries["jti-4"]; ok {
+			t.Error("esperava entrada vencida removida")
+		}
+	})
+}
+
+func TestRedisDenylist(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	testDenylist(t, NewRedisDenylist(client))
+
+	t.Run("TTL acompanha a validade restante do token", func(t *testing.T) {
+		_ = NewRedisDenylist(client).Revoke(context.Background(), "jti-6", time.Now().Add(10*time.Minute))
+
+		ttl := server.TTL(keyPrefix + "jti-6")
+		if ttl <= 9*time.Minute || ttl > 10*time.Minute {
+			t.Errorf("esperava TTL de ~10m, obteve %v", ttl)
+		}
+	})
+
+	t.Run("reconecta depois que o servidor reinicia", func(t *testing.T) {
+		// As conexões do pool ficam inválidas; a consulta não pode falhar por
+		// isso, já que o RequireAuth recusa a requisição quando a denylist falha
+		ctx := context.Background()
+		denylist := NewRedisDenylist(client)
+		_ = denylist.Revoke(ctx, "jti-7", time.Now().Add(time.Minute))
+
+		server.Close()
+		if err := server.Restart(); err != nil {
+			t.Fatalf("falha ao reiniciar o servidor: %v", err)
+		}
+
+		revoked, err := denylist.IsRevoked(ctx, "jti-7")
+		if err != nil || !revoked {
+			t.Errorf("esperava token revogado após o reinício, obteve %v (%v)", revoked, err)
+		}
+	})
+}
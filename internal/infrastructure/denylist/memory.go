@@ -0,0 +1,54 @@
+// Package denylist implementa domain.TokenDenylist em memória e no Redis
+package denylist
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryDenylist implementa domain.TokenDenylist em memória
+// Serve para testes e para instâncias únicas; com várias réplicas, um logout
+// só seria conhecido pela réplica que o recebeu
+type MemoryDenylist struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+	now     func() time.Time
+}
+
+// NewMemoryDenylist cria um novo MemoryDenylist
+func NewMemoryDenylist() *MemoryDenylist {
+	return &MemoryDenylist{
+		entries: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+func (d *MemoryDenylist) Revoke(_ context.Context, jti string, expiresAt time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	if !now.Before(expiresAt) {
+		return nil // Token já expirado não precisa entrar na lista
+	}
+
+	// Remover entradas vencidas a cada revogação mantém o mapa limitado
+	// aos tokens ainda válidos
+	for id, exp := range d.entries {
+		if !now.Before(exp) {
+			delete(d.entries, id)
+		}
+	}
+
+	d.entries[jti] = expiresAt
+	return nil
+}
+
+func (d *MemoryDenylist) IsRevoked(_ context.Context, jti string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	expiresAt, ok := d.entries[jti]
+	return ok && d.now().Before(expiresAt), nil
+}
@@ -0,0 +1,41 @@
+package denylist
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix separa as chaves da lista das demais chaves do Redis
+const keyPrefix = "denylist:jti:"
+
+// RedisDenylist implementa domain.TokenDenylist no Redis
+// Cada jti vira uma chave com TTL igual à validade restante do token,
+// compartilhada entre todas as réplicas da API
+type RedisDenylist struct {
+	client *redis.Client
+}
+
+// NewRedisDenylist cria um novo RedisDenylist
+func NewRedisDenylist(client *redis.Client) *RedisDenylist {
+	return &RedisDenylist{client: client}
+}
+
+func (d *RedisDenylist) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt).Truncate(time.Millisecond)
+	if ttl <= 0 {
+		return nil // Token já expirado não precisa entrar na lista
+	}
+
+	return d.client.Set(ctx, keyPrefix+jti, "1", ttl).Err()
+}
+
+func (d *RedisDenylist) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := d.client.Exists(ctx, keyPrefix+jti).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
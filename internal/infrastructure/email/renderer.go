@@ -0,0 +1,126 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/i18n"
+)
+
+//go:embed templates/*.html templates/*.txt
+var templatesFS embed.FS
+
+// Nomes dos templates disponíveis
+const (
+	TemplateActivation    = "activation"
+	TemplateInvite        = "invite"
+	TemplateSignupAttempt = "signup_attempt"
+	TemplatePasswordReset = "password_reset"
+	TemplateAccountLocked = "account_locked"
+)
+
+// Renderer monta os emails transacionais a partir dos templates embutidos
+//
+// Os textos vêm do i18n.Service: cada template chama {{t "chave" .}} e o
+// assunto usa a chave "email.<template>.subject". Os parâmetros recebidos
+// em Render ficam disponíveis tanto para os templates quanto para as traduções.
+type Renderer struct {
+	i18n *i18n.Service
+	html map[string]*htmltemplate.Template
+	text map[string]*texttemplate.Template
+}
+
+// NewRenderer carrega e valida todos os templates
+func NewRenderer(i18nService *i18n.Service) (*Renderer, error) {
+	r := &Renderer{
+		i18n: i18nService,
+		html: make(map[string]*htmltemplate.Template),
+		text: make(map[string]*texttemplate.Template),
+	}
+
+	// As funções reais são ligadas a cada renderização, com o idioma da mensagem
+	funcs := r.funcs("")
+
+	for _, name := range []string{TemplateActivation, TemplateInvite, TemplateSignupAttempt, TemplatePasswordReset, TemplateAccountLocked} {
+		html, err := htmltemplate.New(name).Funcs(htmltemplate.FuncMap(funcs)).
+			ParseFS(templatesFS, "templates/layout.html", "templates/"+name+".html")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse html template %s: %w", name, err)
+		}
+		r.html[name] = html
+
+		text, err := texttemplate.New(name+".txt").Funcs(funcs).
+			ParseFS(templatesFS, "templates/"+name+".txt")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse text template %s: %w", name, err)
+		}
+		r.text[name] = text
+	}
+
+	return r, nil
+}
+
+// Render monta a mensagem do template no idioma informado
+func (r *Renderer) Render(to, lang, name string, params map[string]interface{}) (domain.EmailMessage, error) {
+	html, ok := r.html[name]
+	if !ok {
+		return domain.EmailMessage{}, fmt.Errorf("unknown email template %s", name)
+	}
+
+	data := make(map[string]interface{}, len(params)+2)
+	for k, v := range params {
+		data[k] = v
+	}
+	subject := r.T(lang, "email."+name+".subject", data)
+	data["Subject"] = subject
+	data["Lang"] = lang
+
+	funcs := r.funcs(lang)
+
+	// O template base nunca é executado, então pode ser clonado a cada envio
+	htmlClone, err := html.Clone()
+	if err != nil {
+		return domain.EmailMessage{}, err
+	}
+	var htmlBody bytes.Buffer
+	if err := htmlClone.Funcs(htmltemplate.FuncMap(funcs)).ExecuteTemplate(&htmlBody, "layout", data); err != nil {
+		return domain.EmailMessage{}, fmt.Errorf("failed to render html template %s: %w", name, err)
+	}
+
+	textClone, err := r.text[name].Clone()
+	if err != nil {
+		return domain.EmailMessage{}, err
+	}
+	var textBody bytes.Buffer
+	if err := textClone.Funcs(funcs).Execute(&textBody, data); err != nil {
+		return domain.EmailMessage{}, fmt.Errorf("failed to render text template %s: %w", name, err)
+	}
+
+	return domain.EmailMessage{
+		To:       to,
+		Subject:  subject,
+		TextBody: textBody.String(),
+		HTMLBody: htmlBody.String(),
+	}, nil
+}
+
+// T traduz uma chave no idioma informado
+func (r *Renderer) T(lang, key string, params ...map[string]interface{}) string {
+	return r.i18n.T(lang, key, params...)
+}
+
+// funcs retorna as funções disponíveis nos templates, ligadas ao idioma
+func (r *Renderer) funcs(lang string) texttemplate.FuncMap {
+	return texttemplate.FuncMap{
+		"t": func(key string, params ...map[string]interface{}) string {
+			return r.T(lang, key, params...)
+		},
+		"button": func(url, label string) map[string]string {
+			return map[string]string{"URL": url, "Label": label}
+		},
+	}
+}
@@ -0,0 +1,173 @@
+package email
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/i18n"
+)
+
+func newTestRenderer(t *testing.T) *Renderer {
+	t.Helper()
+
+	i18nService, err := i18n.NewService("../i18n/locales", "en")
+	if err != nil {
+		t.Fatalf("falha ao carregar locales: %v", err)
+	}
+
+	renderer, err := NewRenderer(i18nService)
+	if err != nil {
+		t.Fatalf("falha ao carregar templates: %v", err)
+	}
+	return renderer
+}
+
+func TestRenderer_Render(t *testing.T) {
+	renderer := newTestRenderer(t)
+
+	t.Run("ativação em português", func(t *testing.T) {
+		msg, err := renderer.Render("joao@email.com", "pt-BR", TemplateActivation, map[string]interface{}{
+			"OrganizationName": "Minha Empresa",
+			"Link":             "https://app.avantpro.com.br/activate?token=abc123",
+		})
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+
+		if msg.To != "joao@email.com" {
+			t.Errorf("esperava destinatário 'joao@email.com', obteve '%s'", msg.To)
+		}
+		if msg.Subject != "Ative sua conta no AvantPro - Minha Empresa" {
+			t.Errorf("assunto inesperado: '%s'", msg.Subject)
+		}
+		for _, want := range []string{
+			`organização "Minha Empresa"`,
+			"https://app.avantpro.com.br/activate?token=abc123",
+			"Este link expira em 24 horas.",
+		} {
+			if !strings.Contains(msg.TextBody, want) {
+				t.Errorf("esperava '%s' no texto, obteve:\n%s", want, msg.TextBody)
+			}
+		}
+		if !strings.Contains(msg.HTMLBody, `href="https://app.avantpro.com.br/activate?token=abc123"`) {
+			t.Errorf("esperava link no HTML, obteve:\n%s", msg.HTMLBody)
+		}
+		if !strings.Contains(msg.HTMLBody, `<html lang="pt-BR">`) {
+			t.Error("esperava idioma no HTML")
+		}
+	})
+
+	t.Run("ativação sem organização", func(t *testing.T) {
+		msg, err := renderer.Render("joao@email.com", "en", TemplateActivation, map[string]interface{}{
+			"Link": "https://app.avantpro.com.br/activate?token=abc123",
+		})
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+		if msg.Subject != "Activate your AvantPro account" {
+			t.Errorf("assunto inesperado: '%s'", msg.Subject)
+		}
+	})
+
+	t.Run("convite em espanhol", func(t *testing.T) {
+		msg, err := renderer.Render("maria@email.com", "es", TemplateInvite, map[string]interface{}{
+			"OrganizationName": "Empresa ABC",
+			"InviterName":      "João Silva",
+			"Role":             "Miembro",
+			"ExpiresAt":        "12/11/2025",
+			"Link":             "https://app.avantpro.com.br/accept-invite?token=xyz",
+		})
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+
+		if msg.Subject != "Has sido invitado a Empresa ABC en AvantPro" {
+			t.Errorf("assunto inesperado: '%s'", msg.Subject)
+		}
+		for _, want := range []string{"João Silva", "Rol: Miembro", "(12/11/2025)", "avantpro.com.br"} {
+			if !strings.Contains(msg.TextBody, want) {
+				t.Errorf("esperava '%s' no texto, obteve:\n%s", want, msg.TextBody)
+			}
+		}
+	})
+
+	t.Run("tentativa de cadastro", func(t *testing.T) {
+		msg, err := renderer.Render("joao@email.com", "pt-BR", TemplateSignupAttempt, map[string]interface{}{
+			"LoginLink":          "https://app.avantpro.com.br/login",
+			"ForgotPasswordLink": "https://app.avantpro.com.br/forgot-password",
+		})
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+
+		if msg.Subject != "Tentativa de cadastro detectada - AvantPro" {
+			t.Errorf("assunto inesperado: '%s'", msg.Subject)
+		}
+		if !strings.Contains(msg.TextBody, "https://app.avantpro.com.br/forgot-password") {
+			t.Errorf("esperava link de redefinição, obteve:\n%s", msg.TextBody)
+		}
+	})
+
+	t.Run("redefinição de senha em inglês", func(t *testing.T) {
+		msg, err := renderer.Render("joao@email.com", "en", TemplatePasswordReset, map[string]interface{}{
+			"Link": "https://app.avantpro.com.br/reset-password?token=abc123",
+		})
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+
+		if msg.Subject != "Reset your AvantPro password" {
+			t.Errorf("assunto inesperado: '%s'", msg.Subject)
+		}
+		for _, want := range []string{"https://app.avantpro.com.br/reset-password?token=abc123", "This link expires in 1 hour"} {
+			if !strings.Contains(msg.TextBody, want) {
+				t.Errorf("esperava '%s' no texto, obteve:\n%s", want, msg.TextBody)
+			}
+		}
+		if !strings.Contains(msg.HTMLBody, `href="https://app.avantpro.com.br/reset-password?token=abc123"`) {
+			t.Errorf("esperava link no HTML, obteve:\n%s", msg.HTMLBody)
+		}
+	})
+
+	t.Run("aviso de bloqueio da conta", func(t *testing.T) {
+		msg, err := renderer.Render("joao@email.com", "pt-BR", TemplateAccountLocked, map[string]interface{}{
+			"IPAddress":          "203.0.113.7",
+			"LockedUntil":        "12/11/2025 14:30 UTC",
+			"ForgotPasswordLink": "https://app.avantpro.com.br/forgot-password",
+		})
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+
+		if msg.Subject != "Sua conta AvantPro foi bloqueada temporariamente" {
+			t.Errorf("assunto inesperado: '%s'", msg.Subject)
+		}
+		for _, want := range []string{"12/11/2025 14:30 UTC", "203.0.113.7", "https://app.avantpro.com.br/forgot-password"} {
+			if !strings.Contains(msg.TextBody, want) {
+				t.Errorf("esperava '%s' no texto, obteve:\n%s", want, msg.TextBody)
+			}
+		}
+		if !strings.Contains(msg.HTMLBody, `href="https://app.avantpro.com.br/forgot-password"`) {
+			t.Errorf("esperava link no HTML, obteve:\n%s", msg.HTMLBody)
+		}
+	})
+
+	t.Run("escapa dados no HTML", func(t *testing.T) {
+		msg, err := renderer.Render("joao@email.com", "en", TemplateActivation, map[string]interface{}{
+			"OrganizationName": "<script>alert(1)</script>",
+			"Link":             "https://app.avantpro.com.br/activate?token=abc123",
+		})
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+		if strings.Contains(msg.HTMLBody, "<script>") {
+			t.Errorf("não esperava HTML sem escape, obteve:\n%s", msg.HTMLBody)
+		}
+	})
+
+	t.Run("template desconhecido", func(t *testing.T) {
+		if _, err := renderer.Render("joao@email.com", "en", "inexistente", nil); err == nil {
+			t.Error("esperava erro para template desconhecido")
+		}
+	})
+}
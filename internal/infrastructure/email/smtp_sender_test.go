@@ -0,0 +1,167 @@
+package email
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/config"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/email/smtptest"
+)
+
+func newTestSender(t *testing.T, server *smtptest.Server, user string) *SMTPSender {
+	t.Helper()
+
+	sender, err := NewSMTPSender(&config.SMTPConfig{
+		Host:     server.Host(),
+		Port:     server.Port(),
+		User:     user,
+		Password: "secret",
+		From:     "AvantPro <no-reply@avantpro.com>",
+	})
+	if err != nil {
+		t.Fatalf("falha ao criar sender: %v", err)
+	}
+	return sender
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("entrega mensagem multipart com texto e HTML", func(t *testing.T) {
+		server := smtptest.NewServer()
+		defer server.Close()
+		sender := newTestSender(t, server, "")
+
+		err := sender.Send(ctx, domain.EmailMessage{
+			To:       "joao@email.com",
+			Subject:  "Ative sua conta no AvantPro - Minha Empresa",
+			TextBody: "Olá,\n\nClique no link.",
+			HTMLBody: "<p>Olá,</p><p>Clique no link.</p>",
+		})
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+
+		messages := server.Messages()
+		if len(messages) != 1 {
+			t.Fatalf("esperava 1 mensagem, obteve %d", len(messages))
+		}
+		received := messages[0]
+		if received.From != "no-reply@avantpro.com" {
+			t.Errorf("esperava remetente 'no-reply@avantpro.com', obteve '%s'", received.From)
+		}
+		if len(received.To) != 1 || received.To[0] != "joao@email.com" {
+			t.Errorf("esperava destinatário 'joao@email.com', obteve %v", received.To)
+		}
+
+		parsed, err := mail.ReadMessage(strings.NewReader(received.Data))
+		if err != nil {
+			t.Fatalf("mensagem inválida: %v", err)
+		}
+
+		subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+		if err != nil || subject != "Ative sua conta no AvantPro - Minha Empresa" {
+			t.Errorf("assunto inesperado: '%s' (%v)", subject, err)
+		}
+
+		mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+		if err != nil || mediaType != "multipart/alternative" {
+			t.Fatalf("esperava multipart/alternative, obteve '%s' (%v)", mediaType, err)
+		}
+
+		bodies := make(map[string]string)
+		reader := multipart.NewReader(parsed.Body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("parte inválida: %v", err)
+			}
+			contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+			content, err := io.ReadAll(quotedprintable.NewReader(part))
+			if err != nil {
+				t.Fatalf("falha ao decodificar parte: %v", err)
+			}
+			bodies[contentType] = string(content)
+		}
+
+		// O quoted-printable converte quebras de linha para CRLF
+		if bodies["text/plain"] != "Olá,\r\n\r\nClique no link." {
+			t.Errorf("texto inesperado: %q", bodies["text/plain"])
+		}
+		if bodies["text/html"] != "<p>Olá,</p><p>Clique no link.</p>" {
+			t.Errorf("HTML inesperado: %q", bodies["text/html"])
+		}
+	})
+
+	t.Run("autentica quando há usuário configurado", func(t *testing.T) {
+		server := smtptest.NewServer()
+		defer server.Close()
+		sender := newTestSender(t, server, "mailer")
+
+		if err := sender.Send(ctx, domain.EmailMessage{To: "joao@email.com", Subject: "Teste"}); err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+
+		messages := server.Messages()
+		if len(messages) != 1 || messages[0].User != "mailer" {
+			t.Errorf("esperava mensagem autenticada como 'mailer', obteve %+v", messages)
+		}
+	})
+
+	t.Run("destinatário inválido", func(t *testing.T) {
+		server := smtptest.NewServer()
+		defer server.Close()
+		sender := newTestSender(t, server, "")
+
+		if err := sender.Send(ctx, domain.EmailMessage{To: "invalido", Subject: "Teste"}); err == nil {
+			t.Error("esperava erro para destinatário inválido")
+		}
+		if len(server.Messages()) != 0 {
+			t.Error("não esperava mensagem entregue")
+		}
+	})
+
+	t.Run("contexto cancelado", func(t *testing.T) {
+		server := smtptest.NewServer()
+		defer server.Close()
+		sender := newTestSender(t, server, "")
+
+		canceled, cancel := context.WithTimeout(ctx, time.Nanosecond)
+		defer cancel()
+		<-canceled.Done()
+
+		if err := sender.Send(canceled, domain.EmailMessage{To: "joao@email.com", Subject: "Teste"}); err == nil {
+			t.Error("esperava erro com contexto cancelado")
+		}
+	})
+}
+
+func TestNewSMTPSender(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.SMTPConfig
+	}{
+		{"sem host", config.SMTPConfig{Port: 587, From: "no-reply@avantpro.com"}},
+		{"sem porta", config.SMTPConfig{Host: "smtp.example.com", From: "no-reply@avantpro.com"}},
+		{"remetente inválido", config.SMTPConfig{Host: "smtp.example.com", Port: 587, From: "invalido"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSMTPSender(&tt.cfg); err == nil {
+				t.Error("esperava erro de configuração")
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// Package smtptest fornece um servidor SMTP em memória para testes,
+// no mesmo espírito do net/http/httptest
+package smtptest
+
+import (
+	"bufio"
+	"encoding/base64"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Message é uma mensagem recebida pelo servidor
+type Message struct {
+	From string
+	To   []string
+	// Data é o conteúdo bruto recebido após o comando DATA
+	Data string
+	// User é o usuário autenticado via AUTH PLAIN, se houver
+	User string
+}
+
+// Server é um servidor SMTP mínimo que guarda as mensagens recebidas
+// Não suporta STARTTLS; anuncia AUTH PLAIN e aceita qualquer credencial
+type Server struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewServer inicia um servidor escutando numa porta livre de 127.0.0.1
+func NewServer() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("smtptest: failed to listen: " + err.Error())
+	}
+
+	s := &Server{listener: listener}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Host retorna o host em que o servidor escuta
+func (s *Server) Host() string {
+	host, _, _ := net.SplitHostPort(s.listener.Addr().String())
+	return host
+}
+
+// Port retorna a porta em que o servidor escuta
+func (s *Server) Port() int {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return p
+}
+
+// Messages retorna uma cópia das mensagens recebidas até agora
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Message, len(s.messages))
+	copy(out, s.messages)
+	return out
+}
+
+// Close encerra o servidor e aguarda as conexões abertas terminarem
+func (s *Server) Close() {
+	_ = s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+// handle conduz uma sessão SMTP até o QUIT ou o fechamento da conexão
+func (s *Server) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	r := bufio.NewReader(conn)
+	reply := func(lines ...string) bool {
+		_, err := conn.Write([]byte(strings.Join(lines, "\r\n") + "\r\n"))
+		return err == nil
+	}
+
+	if !reply("220 smtptest ESMTP") {
+		return
+	}
+
+	var msg Message
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb, arg, _ := strings.Cut(line, " ")
+
+		var ok bool
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			ok = reply("250-smtptest", "250-8BITMIME", "250 AUTH PLAIN")
+		case "HELO":
+			ok = reply("250 smtptest")
+		case "AUTH":
+			mechanism, initial, _ := strings.Cut(arg, " ")
+			if !strings.EqualFold(mechanism, "PLAIN") {
+				ok = reply("504 unrecognized authentication type")
+				break
+			}
+			msg.User = plainUser(initial)
+			ok = reply("235 authentication succeeded")
+		case "MAIL":
+			msg.From = address(arg)
+			ok = reply("250 ok")
+		case "RCPT":
+			msg.To = append(msg.To, address(arg))
+			ok = reply("250 ok")
+		case "DATA":
+			if !reply("354 end data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, err := readData(r)
+			if err != nil {
+				return
+			}
+			msg.Data = data
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			msg = Message{User: msg.User}
+			ok = reply("250 ok: queued")
+		case "RSET":
+			msg = Message{User: msg.User}
+			ok = reply("250 ok")
+		case "NOOP":
+			ok = reply("250 ok")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			ok = reply("502 command not implemented")
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// readData lê o corpo até a linha com um único ponto, desfazendo o dot-stuffing
+func readData(r *bufio.Reader) (string, error) {
+	var b strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		trimmed := strings.TrimRight(line, "\r\n")
+		if trimmed == "." {
+			return b.String(), nil
+		}
+		b.WriteString(strings.TrimPrefix(trimmed, "."))
+		b.WriteString("\r\n")
+	}
+}
+
+// address extrai o endereço de "FROM:<a@b.com>" ou "TO:<a@b.com>"
+func address(arg string) string {
+	_, addr, _ := strings.Cut(arg, ":")
+	addr, _, _ = strings.Cut(strings.TrimSpace(addr), " ")
+	return strings.Trim(addr, "<>")
+}
+
+// plainUser extrai o usuário da resposta inicial do AUTH PLAIN
+func plainUser(initial string) string {
+	decoded, err := base64.StdEncoding.DecodeString(initial)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(string(decoded), "\x00")
+	if len(parts) != 3 {
+		return ""
+	}
+	return parts[1]
+}
@@ -10,6 +10,13 @@ import (
 	"text/template"
 )
 
+const (
+	// LanguageContextKey é a chave usada para armazenar o idioma no contexto da requisição
+	LanguageContextKey = "language"
+	// ServiceContextKey é a chave usada para armazenar o serviço i18n no contexto da requisição
+	ServiceContextKey = "i18n_service"
+)
+
 // Service gerencia traduções e internacionalização
 type Service struct {
 	mu              sync.RWMutex
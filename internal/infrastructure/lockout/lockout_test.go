@@ -0,0 +1,110 @@
+package lockout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+// testStore verifica o contrato comum às implementações
+func testStore(t *testing.T, store domain.LoginAttemptStore) {
+	ctx := context.Background()
+
+	t.Run("soma as falhas da chave", func(t *testing.T) {
+		for want := 1; want <= 3; want++ {
+			count, err := store.RecordFailure(ctx, "account:1", time.Hour)
+			if err != nil {
+				t.Fatalf("esperava sucesso, obteve erro: %v", err)
+			}
+			if count != want {
+				t.Errorf("esperava %d falhas, obteve %d", want, count)
+			}
+		}
+
+		failures, err := store.Failures(ctx, "account:1")
+		if err != nil || failures.Count != 3 || !failures.LockedUntil.IsZero() {
+			t.Errorf("esperava 3 falhas sem bloqueio, obteve %+v (%v)", failures, err)
+		}
+	})
+
+	t.Run("bloqueia a chave até o instante informado", func(t *testing.T) {
+		until := time.Now().Add(15 * time.Minute).Truncate(time.Millisecond)
+		if err := store.Lock(ctx, "account:1", until); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		failures, _ := store.Failures(ctx, "account:1")
+		if !failures.LockedUntil.Equal(until) || !failures.LockedAt(time.Now()) {
+			t.Errorf("esperava bloqueio até %v, obteve %+v", until, failures)
+		}
+	})
+
+	t.Run("chaves são independentes", func(t *testing.T) {
+		failures, err := store.Failures(ctx, "account:2")
+		if err != nil || failures.Count != 0 || failures.LockedAt(time.Now()) {
+			t.Errorf("esperava chave sem falhas, obteve %+v (%v)", failures, err)
+		}
+	})
+
+	t.Run("reset apaga falhas e bloqueio", func(t *testing.T) {
+		if err := store.Reset(ctx, "account:1"); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		failures, _ := store.Failures(ctx, "account:1")
+		if failures.Count != 0 || failures.LockedAt(time.Now()) {
+			t.Errorf("esperava chave zerada, obteve %+v", failures)
+		}
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	testStore(t, store)
+
+	t.Run("falhas e bloqueio expiram", func(t *testing.T) {
+		ctx := context.Background()
+		now := time.Now()
+		store.now = func() time.Time { return now }
+		_, _ = store.RecordFailure(ctx, "ip:1", time.Hour)
+		_ = store.Lock(ctx, "ip:1", now.Add(15*time.Minute))
+
+		store.now = func() time.Time { return now.Add(15 * time.Minute) }
+		failures, _ := store.Failures(ctx, "ip:1")
+		if failures.Count != 1 || !failures.LockedUntil.IsZero() {
+			t.Errorf("esperava bloqueio vencido e 1 falha, obteve %+v", failures)
+		}
+
+		store.now = func() time.Time { return now.Add(time.Hour) }
+		if count, _ := store.RecordFailure(ctx, "ip:1", time.Hour); count != 1 {
+			t.Errorf("esperava contagem reiniciada após o ttl, obteve %d", count)
+		}
+	})
+}
+
+func TestRedisStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	testStore(t, NewRedisStore(client))
+
+	t.Run("TTLs acompanham a janela e o bloqueio", func(t *testing.T) {
+		ctx := context.Background()
+		store := NewRedisStore(client)
+		_, _ = store.RecordFailure(ctx, "ip:1", time.Hour)
+		_ = store.Lock(ctx, "ip:1", time.Now().Add(10*time.Minute))
+
+		if ttl := server.TTL(failuresKey("ip:1")); ttl <= 59*time.Minute || ttl > time.Hour {
+			t.Errorf("esperava TTL das falhas de ~1h, obteve %v", ttl)
+		}
+		if ttl := server.TTL(lockedKey("ip:1")); ttl <= 9*time.Minute || ttl > 10*time.Minute {
+			t.Errorf("esperava TTL do bloqueio de ~10m, obteve %v", ttl)
+		}
+	})
+}
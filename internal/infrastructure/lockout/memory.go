@@ -0,0 +1,110 @@
+// Package lockout implementa domain.LoginAttemptStore em memória e no Redis
+package lockout
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+// sweepInterval é o número de falhas registradas entre as limpezas de chaves antigas
+const sweepInterval = 1000
+
+// entry são as falhas em memória de uma chave
+type entry struct {
+	count       int
+	expiresAt   time.Time // Quando as falhas são esquecidas
+	lockedUntil time.Time
+}
+
+// MemoryStore implementa domain.LoginAttemptStore em memória
+// Serve para testes e para instâncias únicas; com várias réplicas, cada uma
+// contaria as falhas separadamente
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+	calls   int
+	now     func() time.Time
+}
+
+// NewMemoryStore cria um novo MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]*entry),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Failures(_ context.Context, key string) (domain.LoginFailures, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.lookup(key, s.now())
+	return domain.LoginFailures{Count: e.count, LockedUntil: e.lockedUntil}, nil
+}
+
+func (s *MemoryStore) RecordFailure(_ context.Context, key string, ttl time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	e := s.lookup(key, now)
+	e.count++
+	e.expiresAt = now.Add(ttl)
+	s.entries[key] = &e
+	return e.count, nil
+}
+
+func (s *MemoryStore) Lock(_ context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.lookup(key, s.now())
+	e.lockedUntil = until
+	s.entries[key] = &e
+	return nil
+}
+
+func (s *MemoryStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// lookup retorna uma cópia da entrada da chave, sem as partes já expiradas
+func (s *MemoryStore) lookup(key string, now time.Time) entry {
+	e, ok := s.entries[key]
+	if !ok {
+		return entry{}
+	}
+
+	current := *e
+	if !now.Before(current.expiresAt) {
+		current.count = 0
+	}
+	if !now.Before(current.lockedUntil) {
+		current.lockedUntil = time.Time{}
+	}
+	return current
+}
+
+// sweep remove periodicamente as chaves sem falhas nem bloqueio em vigor
+func (s *MemoryStore) sweep(now time.Time) {
+	s.calls++
+	if s.calls < sweepInterval {
+		return
+	}
+	s.calls = 0
+
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) && !now.Before(e.lockedUntil) {
+			delete(s.entries, key)
+		}
+	}
+}
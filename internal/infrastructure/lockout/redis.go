@@ -0,0 +1,88 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+// keyPrefix separa as chaves de falhas de login das demais chaves do Redis
+const keyPrefix = "lockout:"
+
+// RedisStore implementa domain.LoginAttemptStore no Redis
+// Cada chave usa dois registros: o contador de falhas, incrementado com INCR
+// e com TTL renovado a cada falha, e o bloqueio, que expira no fim do bloqueio
+type RedisStore struct {
+	client *redis.Client
+	now    func() time.Time
+}
+
+// NewRedisStore cria um novo RedisStore
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client, now: time.Now}
+}
+
+func (s *RedisStore) Failures(ctx context.Context, key string) (domain.LoginFailures, error) {
+	count, err := s.get(ctx, failuresKey(key))
+	if err != nil {
+		return domain.LoginFailures{}, err
+	}
+
+	locked, err := s.get(ctx, lockedKey(key))
+	if err != nil {
+		return domain.LoginFailures{}, err
+	}
+
+	failures := domain.LoginFailures{Count: int(count)}
+	if locked > 0 {
+		failures.LockedUntil = time.UnixMilli(locked)
+	}
+	return failures, nil
+}
+
+func (s *RedisStore) RecordFailure(ctx context.Context, key string, ttl time.Duration) (int, error) {
+	count, err := s.client.Incr(ctx, failuresKey(key)).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.client.PExpire(ctx, failuresKey(key), ttl).Err(); err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (s *RedisStore) Lock(ctx context.Context, key string, until time.Time) error {
+	ttl := until.Sub(s.now()).Truncate(time.Millisecond)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return s.client.Set(ctx, lockedKey(key), until.UnixMilli(), ttl).Err()
+}
+
+func (s *RedisStore) Reset(ctx context.Context, key string) error {
+	return s.client.Del(ctx, failuresKey(key), lockedKey(key)).Err()
+}
+
+// get lê um registro numérico; registros ausentes valem zero
+func (s *RedisStore) get(ctx context.Context, key string) (int64, error) {
+	n, err := s.client.Get(ctx, key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return n, err
+}
+
+func failuresKey(key string) string {
+	return keyPrefix + key + ":failures"
+}
+
+func lockedKey(key string) string {
+	return keyPrefix + key + ":locked"
+}
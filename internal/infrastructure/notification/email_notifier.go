@@ -0,0 +1,78 @@
+package notification
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/email"
+)
+
+// EmailNotifier entrega as mensagens da conta por email
+// Os links apontam para o frontend (appURL), que chama a API com o token
+type EmailNotifier struct {
+	sender   domain.EmailSender
+	renderer *email.Renderer
+	appURL   string
+}
+
+// NewEmailNotifier cria um novo EmailNotifier
+func NewEmailNotifier(sender domain.EmailSender, renderer *email.Renderer, appURL string) *EmailNotifier {
+	return &EmailNotifier{
+		sender:   sender,
+		renderer: renderer,
+		appURL:   strings.TrimRight(appURL, "/"),
+	}
+}
+
+func (n *EmailNotifier) SendActivation(ctx context.Context, notice domain.ActivationNotice) error {
+	return n.send(ctx, notice.Email, notice.Locale, email.TemplateActivation, map[string]interface{}{
+		"OrganizationName": notice.OrganizationName,
+		"Link":             n.link("/activate", notice.Token),
+	})
+}
+
+func (n *EmailNotifier) SendInvite(ctx context.Context, notice domain.InviteNotice) error {
+	return n.send(ctx, notice.Email, notice.Locale, email.TemplateInvite, map[string]interface{}{
+		"OrganizationName": notice.OrganizationName,
+		"InviterName":      notice.InviterName,
+		"Role":             n.renderer.T(notice.Locale, "email.role."+notice.Role),
+		"ExpiresAt":        notice.ExpiresAt.Format(n.renderer.T(notice.Locale, "email.date_format")),
+		"Link":             n.link("/accept-invite", notice.Token),
+	})
+}
+
+func (n *EmailNotifier) SendSignupAttempt(ctx context.Context, notice domain.SignupAttemptNotice) error {
+	return n.send(ctx, notice.Email, notice.Locale, email.TemplateSignupAttempt, map[string]interface{}{
+		"LoginLink":          n.appURL + "/login",
+		"ForgotPasswordLink": n.appURL + "/forgot-password",
+	})
+}
+
+func (n *EmailNotifier) SendPasswordReset(ctx context.Context, notice domain.PasswordResetNotice) error {
+	return n.send(ctx, notice.Email, notice.Locale, email.TemplatePasswordReset, map[string]interface{}{
+		"Link": n.link("/reset-password", notice.Token),
+	})
+}
+
+func (n *EmailNotifier) SendAccountLocked(ctx context.Context, notice domain.AccountLockedNotice) error {
+	return n.send(ctx, notice.Email, notice.Locale, email.TemplateAccountLocked, map[string]interface{}{
+		"IPAddress":          notice.IPAddress,
+		"LockedUntil":        notice.LockedUntil.UTC().Format(n.renderer.T(notice.Locale, "email.time_format")),
+		"ForgotPasswordLink": n.appURL + "/forgot-password",
+	})
+}
+
+func (n *EmailNotifier) send(ctx context.Context, to, locale, template string, params map[string]interface{}) error {
+	msg, err := n.renderer.Render(to, locale, template, params)
+	if err != nil {
+		return err
+	}
+	return n.sender.Send(ctx, msg)
+}
+
+// link monta a URL do frontend com o token na query string
+func (n *EmailNotifier) link(path, token string) string {
+	return n.appURL + path + "?" + url.Values{"token": {token}}.Encode()
+}
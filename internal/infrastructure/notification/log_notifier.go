@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+// LogNotifier registra as mensagens no log em vez de enviá-las
+// Destinado ao desenvolvimento local, quando o SMTP não está configurado
+type LogNotifier struct {
+	logger domain.Logger
+}
+
+// NewLogNotifier cria um novo LogNotifier
+func NewLogNotifier(logger domain.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (n *LogNotifier) SendActivation(_ context.Context, notice domain.ActivationNotice) error {
+	n.logger.Debug("activation token issued",
+		"email", notice.Email,
+		"locale", notice.Locale,
+		"organization", notice.OrganizationName,
+		"token", notice.Token,
+	)
+	return nil
+}
+
+func (n *LogNotifier) SendInvite(_ context.Context, notice domain.InviteNotice) error {
+	n.logger.Debug("invite issued",
+		"email", notice.Email,
+		"locale", notice.Locale,
+		"organization", notice.OrganizationName,
+		"role", notice.Role,
+		"token", notice.Token,
+	)
+	return nil
+}
+
+func (n *LogNotifier) SendSignupAttempt(_ context.Context, notice domain.SignupAttemptNotice) error {
+	n.logger.Debug("signup attempt notice", "email", notice.Email, "locale", notice.Locale)
+	return nil
+}
+
+func (n *LogNotifier) SendPasswordReset(_ context.Context, notice domain.PasswordResetNotice) error {
+	n.logger.Debug("password reset token issued",
+		"email", notice.Email,
+		"locale", notice.Locale,
+		"token", notice.Token,
+	)
+	return nil
+}
+
+func (n *LogNotifier) SendAccountLocked(_ context.Context, notice domain.AccountLockedNotice) error {
+	n.logger.Debug("account locked notice",
+		"email", notice.Email,
+		"locale", notice.Locale,
+		"ip_address", notice.IPAddress,
+		"locked_until", notice.LockedUntil,
+	)
+	return nil
+}
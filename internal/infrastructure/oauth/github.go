@@ -0,0 +1,77 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"golang.org/x/oauth2"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+// ProviderGitHub é o nome do provedor GitHub nas rotas
+const ProviderGitHub = "github"
+
+const githubAPIBaseURL = "https://api.github.com"
+
+var githubEndpoint = oauth2.Endpoint{
+	AuthURL:   "https://github.com/login/oauth/authorize",
+	TokenURL:  "https://github.com/login/oauth/access_token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
+// githubUser é a resposta de GET /user
+type githubUser struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// githubEmail é um item de GET /user/emails
+type githubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+// NewGitHubProvider cria o provedor de login com GitHub
+// O email vem de /user/emails: o email público do perfil é opcional e não
+// informa se foi verificado
+func NewGitHubProvider(cfg Config) domain.OAuthProvider {
+	apiBaseURL := orDefault(cfg.APIBaseURL, githubAPIBaseURL)
+
+	return newProvider(ProviderGitHub, cfg, githubEndpoint, []string{"read:user", "user:email"},
+		func(ctx context.Context, client *http.Client) (*domain.OAuthIdentity, error) {
+			var user githubUser
+			if err := getJSON(ctx, client, apiBaseURL+"/user", &user); err != nil {
+				return nil, err
+			}
+			if user.ID == 0 {
+				return nil, errors.New("user without id")
+			}
+
+			var emails []githubEmail
+			if err := getJSON(ctx, client, apiBaseURL+"/user/emails", &emails); err != nil {
+				return nil, err
+			}
+
+			identity := &domain.OAuthIdentity{
+				Subject:   strconv.FormatInt(user.ID, 10),
+				Name:      orDefault(user.Name, user.Login),
+				AvatarURL: user.AvatarURL,
+			}
+			for _, e := range emails {
+				if e.Primary {
+					identity.Email = e.Email
+					identity.EmailVerified = e.Verified
+					break
+				}
+			}
+
+			return identity, nil
+		},
+	)
+}
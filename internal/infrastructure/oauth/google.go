@@ -0,0 +1,56 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"golang.org/x/oauth2"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+// ProviderGoogle é o nome do provedor Google nas rotas
+const ProviderGoogle = "google"
+
+const googleAPIBaseURL = "https://openidconnect.googleapis.com"
+
+var googleEndpoint = oauth2.Endpoint{
+	AuthURL:   "https://accounts.google.com/o/oauth2/auth",
+	TokenURL:  "https://oauth2.googleapis.com/token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
+// googleUserInfo é a resposta do endpoint userinfo do OpenID Connect
+type googleUserInfo struct {
+	Sub           string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+}
+
+// NewGoogleProvider cria o provedor de login com Google
+func NewGoogleProvider(cfg Config) domain.OAuthProvider {
+	userInfoURL := orDefault(cfg.APIBaseURL, googleAPIBaseURL) + "/v1/userinfo"
+
+	return newProvider(ProviderGoogle, cfg, googleEndpoint, []string{"openid", "email", "profile"},
+		func(ctx context.Context, client *http.Client) (*domain.OAuthIdentity, error) {
+			var info googleUserInfo
+			if err := getJSON(ctx, client, userInfoURL, &info); err != nil {
+				return nil, err
+			}
+			if info.Sub == "" {
+				return nil, errors.New("userinfo without subject")
+			}
+
+			return &domain.OAuthIdentity{
+				Subject:       info.Sub,
+				Email:         info.Email,
+				EmailVerified: info.EmailVerified,
+				Name:          info.Name,
+				AvatarURL:     info.Picture,
+			}, nil
+		},
+	)
+}
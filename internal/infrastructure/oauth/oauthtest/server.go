@@ -0,0 +1,343 @@
+// Package oauthtest fornece um servidor de autorização OAuth2 falso para testes,
+// no mesmo espírito do net/http/httptest
+//
+// O servidor atende as rotas usadas pelos provedores Google e GitHub e valida
+// as credenciais do cliente, o redirect_uri e o desafio PKCE (S256). Também é
+// um provedor OpenID Connect: publica o documento de descoberta e o JWKS e
+// devolve um ID token RS256 quando o escopo "openid" é pedido.
+package oauthtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingKeyID identifica a chave de assinatura no JWKS
+const signingKeyID = "oauthtest"
+
+// User é a conta do usuário no provedor falso
+type User struct {
+	Subject       string
+	Login         string
+	Email         string
+	EmailVerified bool
+	Name          string
+	AvatarURL     string
+
+	// OmitEmailVerified deixa email_verified fora do ID token, como fazem vários IdPs corporativos
+	OmitEmailVerified bool
+}
+
+// authorization é um código emitido e ainda não trocado
+type authorization struct {
+	user          User
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	openID        bool
+}
+
+// Server é um servidor de autorização OAuth2 em memória
+type Server struct {
+	*httptest.Server
+
+	ClientID     string
+	ClientSecret string
+
+	signingKey *rsa.PrivateKey
+
+	mu     sync.Mutex
+	user   User
+	codes  map[string]authorization
+	tokens map[string]User
+}
+
+// NewServer inicia um servidor que aceita apenas as credenciais informadas
+func NewServer(clientID, clientSecret string) *Server {
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic("oauthtest: failed to generate signing key: " + err.Error())
+	}
+
+	s := &Server{
+		signingKey:   signingKey,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		codes:        make(map[string]authorization),
+		tokens:       make(map[string]User),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /authorize", s.handleAuthorize)
+	mux.HandleFunc("POST /token", s.handleToken)
+	mux.HandleFunc("GET /v1/userinfo", s.handleGoogleUserInfo)
+	mux.HandleFunc("GET /user", s.handleGitHubUser)
+	mux.HandleFunc("GET /user/emails", s.handleGitHubEmails)
+	mux.HandleFunc("GET /.well-known/openid-configuration", s.handleDiscovery)
+	mux.HandleFunc("GET /jwks", s.handleJWKS)
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// SetUser define a conta que autoriza os próximos logins
+func (s *Server) SetUser(user User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.user = user
+}
+
+// Authorize simula o navegador: segue a URL de autorização e retorna o
+// code e o state entregues no redirect para o callback
+func (s *Server) Authorize(authURL string) (code, state string, err error) {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+
+	resp, err := client.Get(authURL) //nolint:noctx
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		return "", "", errors.New("oauthtest: authorization rejected: " + resp.Status)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", "", err
+	}
+
+	return location.Query().Get("code"), location.Query().Get("state"), nil
+}
+
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != s.ClientID || q.Get("response_type") != "code" {
+		http.Error(w, "invalid client", http.StatusBadRequest)
+		return
+	}
+	if q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256" {
+		http.Error(w, "pkce required", http.StatusBadRequest)
+		return
+	}
+
+	redirectURI, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+
+	s.mu.Lock()
+	s.codes[code] = authorization{
+		user:          s.user,
+		redirectURI:   q.Get("redirect_uri"),
+		codeChallenge: q.Get("code_challenge"),
+		nonce:         q.Get("nonce"),
+		openID:        strings.Contains(" "+q.Get("scope")+" ", " openid "),
+	}
+	s.mu.Unlock()
+
+	params := redirectURI.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	redirectURI.RawQuery = params.Encode()
+
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	// Aceita as credenciais tanto via Basic Auth quanto no corpo
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != s.ClientID || clientSecret != s.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	s.mu.Lock()
+	auth, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != auth.redirectURI {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != auth.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "pkce verification failed"})
+		return
+	}
+
+	token := randomString()
+	s.mu.Lock()
+	s.tokens[token] = auth.user
+	s.mu.Unlock()
+
+	body := map[string]interface{}{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	}
+	if auth.openID {
+		idToken, err := s.signIDToken(auth)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+			return
+		}
+		body["id_token"] = idToken
+	}
+
+	writeJSON(w, http.StatusOK, body)
+}
+
+// signIDToken emite o ID token da autorização, assinado com a chave do JWKS
+func (s *Server) signIDToken(auth authorization) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            s.URL,
+		"sub":            auth.user.Subject,
+		"aud":            s.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"email":          auth.user.Email,
+		"email_verified": auth.user.EmailVerified,
+		"name":           auth.user.Name,
+		"picture":        auth.user.AvatarURL,
+	}
+	if auth.nonce != "" {
+		claims["nonce"] = auth.nonce
+	}
+	if auth.user.OmitEmailVerified {
+		delete(claims, "email_verified")
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = signingKeyID
+	return token.SignedString(s.signingKey)
+}
+
+func (s *Server) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.URL,
+		"authorization_endpoint":                s.URL + "/authorize",
+		"token_endpoint":                        s.URL + "/token",
+		"userinfo_endpoint":                     s.URL + "/v1/userinfo",
+		"jwks_uri":                              s.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (s *Server) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	public := s.signingKey.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": signingKeyID,
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}},
+	})
+}
+
+func (s *Server) handleGoogleUserInfo(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.authenticate(r)
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"sub":            user.Subject,
+		"email":          user.Email,
+		"email_verified": user.EmailVerified,
+		"name":           user.Name,
+		"picture":        user.AvatarURL,
+	})
+}
+
+func (s *Server) handleGitHubUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.authenticate(r)
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	// O GitHub usa ids numéricos; o email público pode vir vazio
+	id, _ := json.Number(user.Subject).Int64()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":         id,
+		"login":      user.Login,
+		"name":       user.Name,
+		"avatar_url": user.AvatarURL,
+		"email":      nil,
+	})
+}
+
+func (s *Server) handleGitHubEmails(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.authenticate(r)
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, []map[string]interface{}{
+		{"email": "secondary@example.com", "primary": false, "verified": true},
+		{"email": user.Email, "primary": true, "verified": user.EmailVerified},
+	})
+}
+
+// authenticate retorna o usuário dono do access token do header Authorization
+func (s *Server) authenticate(r *http.Request) (User, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return User{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.tokens[token]
+	return user, ok
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func randomString() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,193 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+// oidcDiscoveryTTL é por quanto tempo o documento de descoberta de um issuer
+// fica em cache; as chaves do JWKS são renovadas pela própria go-oidc sempre
+// que um ID token chega assinado com uma chave desconhecida
+const oidcDiscoveryTTL = time.Hour
+
+// oidcMaxProviders limita o cache de documentos de descoberta; cada
+// organização aponta para um issuer, então o limite só pesa sob abuso
+const oidcMaxProviders = 512
+
+var oidcScopes = []string{oidc.ScopeOpenID, "email", "profile"}
+
+// oidcClaims são os claims do ID token usados para montar a identidade
+// Sem email_verified, o email é tratado como não verificado
+type oidcClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+}
+
+// cachedProvider é um documento de descoberta já lido
+type cachedProvider struct {
+	provider  *oidc.Provider
+	expiresAt time.Time
+}
+
+// OIDCConfig ajusta o OIDCClient
+// AllowPrivateNetworks libera issuers http e em endereços privados ou de
+// loopback, apenas para apontar para um IdP local (ex: oauthtest nos testes)
+type OIDCConfig struct {
+	AllowPrivateNetworks bool
+}
+
+// OIDCClient implementa domain.OIDCClient sobre github.com/coreos/go-oidc
+// Um único cliente atende todos os issuers configurados pelas organizações;
+// como o issuer vem do admin da organização, toda requisição sai pelo
+// httpClient, que só conecta em endereços públicos
+type OIDCClient struct {
+	mu         sync.Mutex
+	providers  map[string]cachedProvider
+	httpClient *http.Client
+	allowHTTP  bool
+	now        func() time.Time
+}
+
+// NewOIDCClient cria um novo OIDCClient
+func NewOIDCClient(cfg OIDCConfig) *OIDCClient {
+	return &OIDCClient{
+		providers:  make(map[string]cachedProvider),
+		httpClient: newOIDCHTTPClient(cfg.AllowPrivateNetworks),
+		allowHTTP:  cfg.AllowPrivateNetworks,
+		now:        time.Now,
+	}
+}
+
+func (c *OIDCClient) Discover(ctx context.Context, issuer string) error {
+	_, err := c.provider(c.clientContext(ctx), issuer)
+	return err
+}
+
+func (c *OIDCClient) AuthCodeURL(ctx context.Context, cfg domain.OIDCClientConfig, state, nonce, codeVerifier string) (string, error) {
+	provider, err := c.provider(c.clientContext(ctx), cfg.Issuer)
+	if err != nil {
+		return "", err
+	}
+
+	return oauth2Config(provider, cfg).AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier)), nil
+}
+
+func (c *OIDCClient) Exchange(ctx context.Context, cfg domain.OIDCClientConfig, code, nonce, codeVerifier string) (*domain.OAuthIdentity, error) {
+	ctx = c.clientContext(ctx)
+
+	provider, err := c.provider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := oauth2Config(provider, cfg).Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("oidc: failed to exchange code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("oidc: token response without id_token")
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: cfg.ClientID, Now: c.now}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: invalid id_token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("oidc: id_token nonce mismatch")
+	}
+
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("oidc: failed to decode id_token claims: %w", err)
+	}
+
+	return &domain.OAuthIdentity{
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+		AvatarURL:     claims.Picture,
+	}, nil
+}
+
+// clientContext faz a go-oidc e a oauth2 usarem o httpClient protegido
+func (c *OIDCClient) clientContext(ctx context.Context) context.Context {
+	return oidc.ClientContext(ctx, c.httpClient)
+}
+
+// provider retorna o documento de descoberta do issuer, lendo-o quando não está em cache
+// A go-oidc confere se o issuer do documento é o mesmo configurado
+func (c *OIDCClient) provider(ctx context.Context, issuer string) (*oidc.Provider, error) {
+	if err := validateIssuerURL(issuer, c.allowHTTP); err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+
+	c.mu.Lock()
+	cached, ok := c.providers[issuer]
+	c.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.provider, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: discovery failed for %s: %w", issuer, err)
+	}
+
+	c.mu.Lock()
+	c.evict(now)
+	c.providers[issuer] = cachedProvider{provider: provider, expiresAt: now.Add(oidcDiscoveryTTL)}
+	c.mu.Unlock()
+
+	return provider, nil
+}
+
+// evict abre espaço no cache antes de uma inclusão: descarta os documentos
+// expirados e, se ainda estiver cheio, o que expira primeiro
+// Deve ser chamado com mu travado
+func (c *OIDCClient) evict(now time.Time) {
+	if len(c.providers) < oidcMaxProviders {
+		return
+	}
+
+	oldest := ""
+	for issuer, cached := range c.providers {
+		if !now.Before(cached.expiresAt) {
+			delete(c.providers, issuer)
+			continue
+		}
+		if oldest == "" || cached.expiresAt.Before(c.providers[oldest].expiresAt) {
+			oldest = issuer
+		}
+	}
+
+	if len(c.providers) >= oidcMaxProviders {
+		delete(c.providers, oldest)
+	}
+}
+
+// oauth2Config monta a configuração OAuth2 com os endpoints do documento de descoberta
+func oauth2Config(provider *oidc.Provider, cfg domain.OIDCClientConfig) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       oidcScopes,
+	}
+}
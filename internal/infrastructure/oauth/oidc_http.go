@@ -0,0 +1,101 @@
+package oauth
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// oidcHTTPTimeout limita cada requisição ao IdP (descoberta, JWKS e troca do código)
+const oidcHTTPTimeout = 10 * time.Second
+
+// errUnsafeAddress indica uma conexão recusada por apontar para a rede interna
+var errUnsafeAddress = errors.New("oidc: address not allowed")
+
+// validateIssuerURL recusa issuers que não sejam URLs https absolutas
+// allowHTTP aceita http, para IdPs locais
+func validateIssuerURL(issuer string, allowHTTP bool) error {
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return fmt.Errorf("oidc: invalid issuer: %w", err)
+	}
+
+	if u.Scheme != "https" && !(allowHTTP && u.Scheme == "http") {
+		return fmt.Errorf("oidc: issuer must use https: %s", issuer)
+	}
+	if u.Hostname() == "" || u.User != nil {
+		return fmt.Errorf("oidc: invalid issuer: %s", issuer)
+	}
+
+	return nil
+}
+
+// newOIDCHTTPClient cria o cliente HTTP usado com os IdPs das organizações
+// A verificação de endereço roda no dial, depois da resolução do nome, então
+// vale também para redirects e para os endpoints lidos da descoberta, e não é
+// contornada por um DNS que muda de resposta entre a checagem e a conexão
+func newOIDCHTTPClient(allowPrivateNetworks bool) *http.Client {
+	dialer := &net.Dialer{Timeout: oidcHTTPTimeout}
+	if !allowPrivateNetworks {
+		dialer.Control = rejectPrivateAddress
+	}
+
+	client := &http.Client{
+		Timeout: oidcHTTPTimeout,
+		// Sem Proxy: o dial precisa ver o endereço do IdP, não o do proxy
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: oidcHTTPTimeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+
+	if !allowPrivateNetworks {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if req.URL.Scheme != "https" {
+				return fmt.Errorf("oidc: redirect to non-https url %s", req.URL.Redacted())
+			}
+			if len(via) >= 5 {
+				return errors.New("oidc: too many redirects")
+			}
+			return nil
+		}
+	}
+
+	return client
+}
+
+// rejectPrivateAddress recusa conexões com loopback, redes privadas, link-local
+// (ex: o serviço de metadados da nuvem em 169.254.169.254) e afins
+func rejectPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("%w: %s", errUnsafeAddress, host)
+	}
+
+	return nil
+}
+
+// sharedAddressSpace é a faixa de CGNAT (RFC 6598), que net.IP.IsPrivate não cobre
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
@@ -0,0 +1,196 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/oauth/oauthtest"
+)
+
+const testNonce = "nonce-456"
+
+// localOIDC libera o IdP falso, que escuta em http no loopback
+var localOIDC = OIDCConfig{AllowPrivateNetworks: true}
+
+// oidcLogin percorre o fluxo OIDC completo com o nonce informado na troca
+func oidcLogin(t *testing.T, server *oauthtest.Server, client *OIDCClient, cfg domain.OIDCClientConfig, nonce string) (*domain.OAuthIdentity, error) {
+	t.Helper()
+	ctx := context.Background()
+
+	authURL, err := client.AuthCodeURL(ctx, cfg, "state-123", testNonce, testVerifier)
+	if err != nil {
+		t.Fatalf("falha ao montar a URL de autorização: %v", err)
+	}
+
+	code, _, err := server.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("falha na autorização: %v", err)
+	}
+
+	return client.Exchange(ctx, cfg, code, nonce, testVerifier)
+}
+
+func TestOIDCClient(t *testing.T) {
+	server := oauthtest.NewServer(testClientID, testClientSecret)
+	defer server.Close()
+
+	ana := oauthtest.User{
+		Subject:       "00u1a2b3c4",
+		Email:         "ana@acme.com",
+		EmailVerified: true,
+		Name:          "Ana Souza",
+	}
+	server.SetUser(ana)
+
+	cfg := domain.OIDCClientConfig{
+		Issuer:       server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  "http://localhost:8080/api/v1/auth/sso/org/callback",
+	}
+
+	t.Run("lê o documento de descoberta do issuer", func(t *testing.T) {
+		if err := NewOIDCClient(localOIDC).Discover(context.Background(), server.URL); err != nil {
+			t.Errorf("esperava descoberta com sucesso, obteve %v", err)
+		}
+	})
+
+	t.Run("rejeita issuer sem documento de descoberta", func(t *testing.T) {
+		if err := NewOIDCClient(localOIDC).Discover(context.Background(), server.URL+"/desconhecido"); err == nil {
+			t.Error("esperava erro de descoberta")
+		}
+	})
+
+	t.Run("monta a URL com endpoints da descoberta, nonce e PKCE", func(t *testing.T) {
+		raw, err := NewOIDCClient(localOIDC).AuthCodeURL(context.Background(), cfg, "state-123", testNonce, testVerifier)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		authURL, _ := url.Parse(raw)
+		q := authURL.Query()
+		if authURL.Path != "/authorize" {
+			t.Errorf("esperava endpoint de autorização da descoberta, obteve '%s'", authURL.Path)
+		}
+		if q.Get("nonce") != testNonce || q.Get("code_challenge_method") != "S256" {
+			t.Errorf("esperava nonce e desafio PKCE, obteve %v", q)
+		}
+	})
+
+	t.Run("verifica o ID token e retorna a identidade", func(t *testing.T) {
+		identity, err := oidcLogin(t, server, NewOIDCClient(localOIDC), cfg, testNonce)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		want := domain.OAuthIdentity{
+			Subject:       "00u1a2b3c4",
+			Email:         "ana@acme.com",
+			EmailVerified: true,
+			Name:          "Ana Souza",
+		}
+		if *identity != want {
+			t.Errorf("esperava %+v, obteve %+v", want, *identity)
+		}
+	})
+
+	t.Run("email sem o claim email_verified não é verificado", func(t *testing.T) {
+		withoutClaim := ana
+		withoutClaim.OmitEmailVerified = true
+		server.SetUser(withoutClaim)
+		defer server.SetUser(ana)
+
+		identity, err := oidcLogin(t, server, NewOIDCClient(localOIDC), cfg, testNonce)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if identity.EmailVerified {
+			t.Error("esperava email não verificado")
+		}
+	})
+
+	t.Run("rejeita nonce diferente do emitido", func(t *testing.T) {
+		if _, err := oidcLogin(t, server, NewOIDCClient(localOIDC), cfg, "outro-nonce"); err == nil {
+			t.Error("esperava erro de nonce")
+		}
+	})
+
+	t.Run("rejeita ID token expirado", func(t *testing.T) {
+		client := NewOIDCClient(localOIDC)
+		client.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+		if _, err := oidcLogin(t, server, client, cfg, testNonce); err == nil {
+			t.Error("esperava erro de expiração")
+		}
+	})
+}
+
+func TestOIDCClient_Network(t *testing.T) {
+	server := oauthtest.NewServer(testClientID, testClientSecret)
+	defer server.Close()
+
+	t.Run("recusa issuer sem https", func(t *testing.T) {
+		if err := NewOIDCClient(OIDCConfig{}).Discover(context.Background(), server.URL); err == nil {
+			t.Error("esperava erro para issuer http")
+		}
+	})
+
+	t.Run("recusa issuer que resolve para a rede interna", func(t *testing.T) {
+		for _, issuer := range []string{
+			"https://" + server.Listener.Addr().String(),
+			"https://localhost",
+			"https://169.254.169.254",
+		} {
+			err := NewOIDCClient(OIDCConfig{}).Discover(context.Background(), issuer)
+			if !errors.Is(err, errUnsafeAddress) {
+				t.Errorf("%s: esperava errUnsafeAddress, obteve %v", issuer, err)
+			}
+		}
+	})
+
+	t.Run("classifica os endereços", func(t *testing.T) {
+		for ip, public := range map[string]bool{
+			"8.8.8.8":         true,
+			"2001:4860::8888": true,
+			"10.0.0.1":        false,
+			"172.16.0.1":      false,
+			"192.168.1.1":     false,
+			"100.64.0.1":      false,
+			"169.254.169.254": false,
+			"0.0.0.0":         false,
+			"::1":             false,
+			"fe80::1":         false,
+			"fd00::1":         false,
+			"::ffff:10.0.0.1": false,
+		} {
+			if got := isPublicIP(net.ParseIP(ip)); got != public {
+				t.Errorf("%s: esperava público %v, obteve %v", ip, public, got)
+			}
+		}
+	})
+
+	t.Run("cache de descoberta é limitado", func(t *testing.T) {
+		client := NewOIDCClient(localOIDC)
+		now := time.Now()
+		for i := 0; i < oidcMaxProviders; i++ {
+			client.providers[fmt.Sprintf("https://idp-%d.example.com", i)] = cachedProvider{expiresAt: now.Add(time.Duration(i+1) * time.Minute)}
+		}
+
+		if err := client.Discover(context.Background(), server.URL); err != nil {
+			t.Fatalf("esperava descoberta com sucesso, obteve %v", err)
+		}
+
+		if len(client.providers) != oidcMaxProviders {
+			t.Errorf("esperava %d documentos em cache, obteve %d", oidcMaxProviders, len(client.providers))
+		}
+		if _, ok := client.providers["https://idp-0.example.com"]; ok {
+			t.Error("esperava descartado o documento que expira primeiro")
+		}
+	})
+}
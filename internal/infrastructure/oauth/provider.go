@@ -0,0 +1,108 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+// Config contém as credenciais de um provedor OAuth2
+// As URLs vazias usam os endpoints públicos do provedor; preenchidas, apontam
+// para outro servidor (ex: oauthtest nos testes)
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+
+	AuthURL    string
+	TokenURL   string
+	APIBaseURL string
+}
+
+// fetchIdentity consulta a API do provedor com o cliente já autenticado
+type fetchIdentity func(ctx context.Context, client *http.Client) (*domain.OAuthIdentity, error)
+
+// provider implementa domain.OAuthProvider sobre golang.org/x/oauth2
+type provider struct {
+	name   string
+	config *oauth2.Config
+	fetch  fetchIdentity
+}
+
+func newProvider(name string, cfg Config, endpoint oauth2.Endpoint, scopes []string, fetch fetchIdentity) *provider {
+	if cfg.AuthURL != "" {
+		endpoint.AuthURL = cfg.AuthURL
+	}
+	if cfg.TokenURL != "" {
+		endpoint.TokenURL = cfg.TokenURL
+	}
+
+	return &provider{
+		name: name,
+		config: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     endpoint,
+			Scopes:       scopes,
+		},
+		fetch: fetch,
+	}
+}
+
+func (p *provider) Name() string {
+	return p.name
+}
+
+func (p *provider) AuthCodeURL(state, codeVerifier string) string {
+	return p.config.AuthCodeURL(state, oauth2.S256ChallengeOption(codeVerifier))
+}
+
+func (p *provider) Exchange(ctx context.Context, code, codeVerifier string) (*domain.OAuthIdentity, error) {
+	token, err := p.config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to exchange code: %w", p.name, err)
+	}
+
+	identity, err := p.fetch(ctx, p.config.Client(ctx, token))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to fetch user: %w", p.name, err)
+	}
+
+	identity.Provider = p.name
+	return identity, nil
+}
+
+// getJSON faz um GET autenticado e decodifica a resposta JSON em v
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// orDefault retorna value, ou fallback quando value está vazio
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
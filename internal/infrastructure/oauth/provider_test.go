@@ -0,0 +1,155 @@
+package oauth
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/oauth/oauthtest"
+)
+
+const (
+	testClientID     = "client-id"
+	testClientSecret = "client-secret"
+	testVerifier     = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+// newTestConfig aponta o provedor para o servidor falso
+func newTestConfig(server *oauthtest.Server, name string) Config {
+	return Config{
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  "http://localhost:8080/api/v1/auth/oauth/" + name + "/callback",
+		AuthURL:      server.URL + "/authorize",
+		TokenURL:     server.URL + "/token",
+		APIBaseURL:   server.URL,
+	}
+}
+
+// login percorre o fluxo completo: autorização, redirect e troca do código
+func login(t *testing.T, server *oauthtest.Server, provider domain.OAuthProvider, verifier string) (*domain.OAuthIdentity, error) {
+	t.Helper()
+
+	authURL := provider.AuthCodeURL("state-123", testVerifier)
+	code, state, err := server.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("falha na autorização: %v", err)
+	}
+	if state != "state-123" {
+		t.Errorf("esperava state devolvido no redirect, obteve '%s'", state)
+	}
+
+	return provider.Exchange(context.Background(), code, verifier)
+}
+
+func TestGoogleProvider(t *testing.T) {
+	server := oauthtest.NewServer(testClientID, testClientSecret)
+	defer server.Close()
+
+	server.SetUser(oauthtest.User{
+		Subject:       "1122334455",
+		Email:         "maria@gmail.com",
+		EmailVerified: true,
+		Name:          "Maria Silva",
+		AvatarURL:     "https://example.com/maria.png",
+	})
+	provider := NewGoogleProvider(newTestConfig(server, ProviderGoogle))
+
+	t.Run("monta a URL de autorização com PKCE S256", func(t *testing.T) {
+		authURL, err := url.Parse(provider.AuthCodeURL("state-123", testVerifier))
+		if err != nil {
+			t.Fatalf("URL inválida: %v", err)
+		}
+
+		q := authURL.Query()
+		if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+			t.Errorf("esperava desafio PKCE S256, obteve %v", q)
+		}
+		if q.Get("code_challenge") == testVerifier {
+			t.Error("o verifier não deveria ir na URL")
+		}
+		if !strings.Contains(q.Get("scope"), "email") {
+			t.Errorf("esperava escopo de email, obteve '%s'", q.Get("scope"))
+		}
+	})
+
+	t.Run("troca o código pela identidade", func(t *testing.T) {
+		identity, err := login(t, server, provider, testVerifier)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		want := domain.OAuthIdentity{
+			Provider:      ProviderGoogle,
+			Subject:       "1122334455",
+			Email:         "maria@gmail.com",
+			EmailVerified: true,
+			Name:          "Maria Silva",
+			AvatarURL:     "https://example.com/maria.png",
+		}
+		if *identity != want {
+			t.Errorf("identidade inesperada: %+v", identity)
+		}
+	})
+
+	t.Run("verifier diferente é rejeitado", func(t *testing.T) {
+		if _, err := login(t, server, provider, "outro-verifier-com-tamanho-suficiente-para-pkce"); err == nil {
+			t.Error("esperava erro com verifier incorreto")
+		}
+	})
+
+	t.Run("credenciais do cliente inválidas", func(t *testing.T) {
+		cfg := newTestConfig(server, ProviderGoogle)
+		cfg.ClientSecret = "errado"
+
+		if _, err := login(t, server, NewGoogleProvider(cfg), testVerifier); err == nil {
+			t.Error("esperava erro com client secret incorreto")
+		}
+	})
+}
+
+func TestGitHubProvider(t *testing.T) {
+	server := oauthtest.NewServer(testClientID, testClientSecret)
+	defer server.Close()
+
+	provider := NewGitHubProvider(newTestConfig(server, ProviderGitHub))
+
+	t.Run("usa o email primário e o id numérico", func(t *testing.T) {
+		server.SetUser(oauthtest.User{
+			Subject:       "583231",
+			Login:         "octocat",
+			Email:         "octocat@example.com",
+			EmailVerified: true,
+			AvatarURL:     "https://example.com/octocat.png",
+		})
+
+		identity, err := login(t, server, provider, testVerifier)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		if identity.Provider != ProviderGitHub || identity.Subject != "583231" {
+			t.Errorf("identificação inesperada: %+v", identity)
+		}
+		if identity.Email != "octocat@example.com" || !identity.EmailVerified {
+			t.Errorf("esperava email primário verificado, obteve %+v", identity)
+		}
+		if identity.Name != "octocat" {
+			t.Errorf("esperava login como nome na falta do nome, obteve '%s'", identity.Name)
+		}
+	})
+
+	t.Run("email primário não verificado", func(t *testing.T) {
+		server.SetUser(oauthtest.User{Subject: "42", Login: "dev", Email: "dev@example.com"})
+
+		identity, err := login(t, server, provider, testVerifier)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if identity.EmailVerified {
+			t.Error("esperava email não verificado")
+		}
+	})
+}
@@ -0,0 +1,222 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+const (
+	// pollInterval é o intervalo entre buscas quando não há mensagens pendentes
+	pollInterval = 2 * time.Second
+	// batchSize é o máximo de mensagens reservadas por busca
+	batchSize = 20
+	// claimLease é por quanto tempo uma mensagem reservada fica invisível para
+	// outras instâncias; se o processo cair durante a entrega, ela volta a ser
+	// entregue depois disso
+	claimLease = 5 * time.Minute
+	// deliveryTimeout limita cada tentativa de entrega
+	deliveryTimeout = 30 * time.Second
+	// maxAttempts é o total de tentativas antes de desistir da mensagem
+	maxAttempts = 10
+	// A espera entre tentativas dobra a cada falha, de retryBaseDelay até retryMaxDelay
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = time.Hour
+)
+
+// Handler entrega uma mensagem do outbox a partir do seu payload JSON
+type Handler func(ctx context.Context, payload []byte) error
+
+// Dispatcher entrega as mensagens do outbox em segundo plano, com novas
+// tentativas e backoff exponencial
+type Dispatcher struct {
+	repo     repositories.OutboxRepository
+	logger   domain.Logger
+	handlers map[string]Handler
+	now      func() time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+}
+
+// NewDispatcher cria um novo Dispatcher
+func NewDispatcher(repo repositories.OutboxRepository, logger domain.Logger) *Dispatcher {
+	return &Dispatcher{
+		repo:     repo,
+		logger:   logger,
+		handlers: make(map[string]Handler),
+		now:      time.Now,
+	}
+}
+
+// Handle registra o handler de um tipo de mensagem
+// Deve ser chamado antes de Start
+func (d *Dispatcher) Handle(msgType string, handler Handler) {
+	d.handlers[msgType] = handler
+}
+
+// RegisterNotifier registra os handlers dos emails gravados pelo Writer,
+// entregando-os com o notifier informado
+func RegisterNotifier(d *Dispatcher, notifier domain.AccountNotifier) {
+	d.Handle(MessageActivationEmail, decode(notifier.SendActivation))
+	d.Handle(MessageInviteEmail, decode(notifier.SendInvite))
+	d.Handle(MessageSignupAttemptEmail, decode(notifier.SendSignupAttempt))
+	d.Handle(MessagePasswordResetEmail, decode(notifier.SendPasswordReset))
+	d.Handle(MessageAccountLockedEmail, decode(notifier.SendAccountLocked))
+}
+
+// decode adapta uma função tipada para Handler, decodificando o payload
+func decode[T any](fn func(context.Context, T) error) Handler {
+	return func(ctx context.Context, payload []byte) error {
+		var v T
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return err
+		}
+		return fn(ctx, v)
+	}
+}
+
+// Start inicia a goroutine de entrega, que roda até Stop ou o cancelamento do contexto
+func (d *Dispatcher) Start(ctx context.Context) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.done != nil {
+		return
+	}
+
+	ctx, d.cancel = context.WithCancel(ctx)
+	d.done = make(chan struct{})
+
+	go d.run(ctx, d.done)
+	d.logger.Info("outbox dispatcher started")
+}
+
+// Stop interrompe a goroutine de entrega e espera a tentativa em andamento terminar
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.done == nil {
+		return
+	}
+
+	d.cancel()
+	<-d.done
+	d.done = nil
+	d.logger.Info("outbox dispatcher stopped")
+}
+
+func (d *Dispatcher) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	for {
+		// Um lote cheio indica que pode haver mais mensagens: busca de novo sem esperar
+		n, err := d.dispatchBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			d.logger.Error("failed to claim outbox messages", "error", err)
+		}
+		if err == nil && n == batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// dispatchBatch reserva e entrega um lote de mensagens, retornando quantas foram reservadas
+func (d *Dispatcher) dispatchBatch(ctx context.Context) (int, error) {
+	messages, err := d.repo.ClaimPending(ctx, d.now(), claimLease, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, msg := range messages {
+		if ctx.Err() != nil {
+			// As mensagens restantes voltam a ficar disponíveis após o lease
+			break
+		}
+		d.deliver(ctx, msg)
+	}
+
+	return len(messages), nil
+}
+
+// deliver entrega uma mensagem e registra o resultado
+// Falhas ao registrar o resultado só são logadas: a mensagem volta após o lease
+func (d *Dispatcher) deliver(ctx context.Context, msg *entities.OutboxMessage) {
+	deliveryCtx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	// O resultado é registrado mesmo durante o Stop, para não repetir uma entrega já feita
+	ctx = context.WithoutCancel(ctx)
+
+	handler, ok := d.handlers[msg.Type]
+	if !ok {
+		// Eventos sem assinantes são apenas marcados como processados
+		d.logger.Debug("no outbox handler registered", "type", msg.Type, "message_id", msg.ID)
+		d.markProcessed(ctx, msg)
+		return
+	}
+
+	err := handler(deliveryCtx, msg.Payload)
+
+	if err == nil {
+		d.markProcessed(ctx, msg)
+		return
+	}
+
+	attempts := msg.Attempts + 1
+	if attempts >= maxAttempts {
+		d.logger.Error("outbox message failed permanently",
+			"type", msg.Type,
+			"message_id", msg.ID,
+			"attempts", attempts,
+			"error", err,
+		)
+		if markErr := d.repo.MarkFailed(ctx, msg.ID, attempts, d.now(), err.Error()); markErr != nil {
+			d.logger.Error("failed to mark outbox message as failed", "message_id", msg.ID, "error", markErr)
+		}
+		return
+	}
+
+	next := d.now().Add(retryDelay(attempts))
+	d.logger.Warn("outbox delivery failed, will retry",
+		"type", msg.Type,
+		"message_id", msg.ID,
+		"attempts", attempts,
+		"next_attempt_at", next,
+		"error", err,
+	)
+	if markErr := d.repo.MarkRetry(ctx, msg.ID, attempts, next, err.Error()); markErr != nil {
+		d.logger.Error("failed to reschedule outbox message", "message_id", msg.ID, "error", markErr)
+	}
+}
+
+func (d *Dispatcher) markProcessed(ctx context.Context, msg *entities.OutboxMessage) {
+	if err := d.repo.MarkProcessed(ctx, msg.ID, d.now()); err != nil {
+		d.logger.Error("failed to mark outbox message as processed", "message_id", msg.ID, "error", err)
+	}
+}
+
+// retryDelay retorna a espera antes da próxima tentativa, dada a quantidade de falhas
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,352 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+// nopLogger descarta todas as mensagens de log nos testes
+type nopLogger struct{}
+
+func (nopLogger) Info(string, ...any)         {}
+func (nopLogger) Error(string, ...any)        {}
+func (nopLogger) Debug(string, ...any)        {}
+func (nopLogger) Warn(string, ...any)         {}
+func (l nopLogger) With(...any) domain.Logger { return l }
+
+// fakeOutboxRepository é um outbox em memória
+type fakeOutboxRepository struct {
+	mu       sync.Mutex
+	messages []*entities.OutboxMessage
+}
+
+func (r *fakeOutboxRepository) Create(_ context.Context, msg *entities.OutboxMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	msg.CreatedAt = time.Now()
+	r.messages = append(r.messages, msg)
+	return nil
+}
+
+func (r *fakeOutboxRepository) ClaimPending(_ context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.OutboxMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var claimed []*entities.OutboxMessage
+	for _, m := range r.messages {
+		if len(claimed) == limit {
+			break
+		}
+		if !m.IsProcessed() && !m.IsFailed() && !m.NextAttemptAt.After(now) {
+			m.NextAttemptAt = now.Add(lease)
+			copied := *m
+			claimed = append(claimed, &copied)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *fakeOutboxRepository) find(id string) *entities.OutboxMessage {
+	for _, m := range r.messages {
+		if m.ID == id {
+			return m
+		}
+	}
+	return nil
+}
+
+func (r *fakeOutboxRepository) MarkProcessed(_ context.Context, id string, processedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := r.find(id)
+	m.ProcessedAt = &processedAt
+	m.Payload = []byte("{}")
+	return nil
+}
+
+func (r *fakeOutboxRepository) MarkRetry(_ context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := r.find(id)
+	m.Attempts = attempts
+	m.NextAttemptAt = nextAttemptAt
+	m.LastError = &lastError
+	return nil
+}
+
+func (r *fakeOutboxRepository) MarkFailed(_ context.Context, id string, attempts int, failedAt time.Time, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := r.find(id)
+	m.Attempts = attempts
+	m.FailedAt = &failedAt
+	m.LastError = &lastError
+	return nil
+}
+
+// recordingNotifier guarda os emails entregues e falha enquanto failures > 0
+type recordingNotifier struct {
+	mu          sync.Mutex
+	failures    int
+	activations []domain.ActivationNotice
+	invites     []domain.InviteNotice
+	resets      []domain.PasswordResetNotice
+	locks       []domain.AccountLockedNotice
+}
+
+func (n *recordingNotifier) fail() error {
+	if n.failures > 0 {
+		n.failures--
+		return errors.New("smtp unavailable")
+	}
+	return nil
+}
+
+func (n *recordingNotifier) SendActivation(_ context.Context, notice domain.ActivationNotice) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err := n.fail(); err != nil {
+		return err
+	}
+	n.activations = append(n.activations, notice)
+	return nil
+}
+
+func (n *recordingNotifier) SendInvite(_ context.Context, notice domain.InviteNotice) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err := n.fail(); err != nil {
+		return err
+	}
+	n.invites = append(n.invites, notice)
+	return nil
+}
+
+func (n *recordingNotifier) SendSignupAttempt(context.Context, domain.SignupAttemptNotice) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.fail()
+}
+
+func (n *recordingNotifier) SendPasswordReset(_ context.Context, notice domain.PasswordResetNotice) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err := n.fail(); err != nil {
+		return err
+	}
+	n.resets = append(n.resets, notice)
+	return nil
+}
+
+func (n *recordingNotifier) SendAccountLocked(_ context.Context, notice domain.AccountLockedNotice) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err := n.fail(); err != nil {
+		return err
+	}
+	n.locks = append(n.locks, notice)
+	return nil
+}
+
+// newTestDispatcher cria um dispatcher com relógio controlado pelo teste
+func newTestDispatcher(repo *fakeOutboxRepository, notifier domain.AccountNotifier, now *time.Time) *Dispatcher {
+	d := NewDispatcher(repo, nopLogger{})
+	d.now = func() time.Time { return *now }
+	RegisterNotifier(d, notifier)
+	return d
+}
+
+func TestDispatcher_DeliversWrittenMessages(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeOutboxRepository{}
+	notifier := &recordingNotifier{}
+	now := time.Now()
+	d := newTestDispatcher(repo, notifier, &now)
+
+	writer := NewWriter(repo)
+	expiresAt := now.Add(48 * time.Hour).Truncate(time.Second)
+	if err := writer.SendActivation(ctx, domain.ActivationNotice{Email: "joao@email.com", Locale: "pt-BR", Token: "tok-1"}); err != nil {
+		t.Fatalf("falha ao gravar ativação: %v", err)
+	}
+	if err := writer.SendInvite(ctx, domain.InviteNotice{Email: "maria@email.com", Role: "user", Token: "tok-2", ExpiresAt: expiresAt}); err != nil {
+		t.Fatalf("falha ao gravar convite: %v", err)
+	}
+	if err := writer.SendPasswordReset(ctx, domain.PasswordResetNotice{Email: "joao@email.com", Locale: "pt-BR", Token: "tok-3", ExpiresAt: expiresAt}); err != nil {
+		t.Fatalf("falha ao gravar redefinição de senha: %v", err)
+	}
+	if err := writer.SendAccountLocked(ctx, domain.AccountLockedNotice{Email: "joao@email.com", IPAddress: "203.0.113.7", LockedUntil: expiresAt}); err != nil {
+		t.Fatalf("falha ao gravar aviso de bloqueio: %v", err)
+	}
+	now = time.Now()
+
+	if _, err := d.dispatchBatch(ctx); err != nil {
+		t.Fatalf("falha ao despachar: %v", err)
+	}
+
+	if len(notifier.activations) != 1 || notifier.activations[0].Token != "tok-1" || notifier.activations[0].Locale != "pt-BR" {
+		t.Errorf("ativação entregue incorretamente: %+v", notifier.activations)
+	}
+	if len(notifier.invites) != 1 || !notifier.invites[0].ExpiresAt.Equal(expiresAt) {
+		t.Errorf("convite entregue incorretamente: %+v", notifier.invites)
+	}
+	if len(notifier.resets) != 1 || notifier.resets[0].Token != "tok-3" || !notifier.resets[0].ExpiresAt.Equal(expiresAt) {
+		t.Errorf("redefinição de senha entregue incorretamente: %+v", notifier.resets)
+	}
+	if len(notifier.locks) != 1 || notifier.locks[0].IPAddress != "203.0.113.7" || !notifier.locks[0].LockedUntil.Equal(expiresAt) {
+		t.Errorf("aviso de bloqueio entregue incorretamente: %+v", notifier.locks)
+	}
+
+	for _, m := range repo.messages {
+		if !m.IsProcessed() {
+			t.Errorf("esperava mensagem %s processada", m.Type)
+		}
+		if string(m.Payload) != "{}" {
+			t.Errorf("esperava payload descartado após a entrega, obteve %s", m.Payload)
+		}
+	}
+
+	// Nada pendente: um novo ciclo não entrega de novo
+	if _, err := d.dispatchBatch(ctx); err != nil {
+		t.Fatalf("falha ao despachar: %v", err)
+	}
+	if len(notifier.activations) != 1 {
+		t.Errorf("não esperava reentrega, obteve %d ativações", len(notifier.activations))
+	}
+}
+
+func TestDispatcher_RetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeOutboxRepository{}
+	notifier := &recordingNotifier{failures: 2}
+	now := time.Now()
+	d := newTestDispatcher(repo, notifier, &now)
+
+	if err := NewWriter(repo).SendActivation(ctx, domain.ActivationNotice{Email: "joao@email.com", Token: "tok"}); err != nil {
+		t.Fatalf("falha ao gravar ativação: %v", err)
+	}
+	msg := repo.messages[0]
+	now = time.Now()
+
+	_, _ = d.dispatchBatch(ctx)
+	if msg.Attempts != 1 || msg.LastError == nil {
+		t.Fatalf("esperava 1 tentativa com erro registrado, obteve %d", msg.Attempts)
+	}
+	if !msg.NextAttemptAt.Equal(now.Add(retryBaseDelay)) {
+		t.Errorf("esperava próxima tentativa em %v, obteve %v", retryBaseDelay, msg.NextAttemptAt.Sub(now))
+	}
+
+	// Antes do backoff vencer, a mensagem não é entregue
+	_, _ = d.dispatchBatch(ctx)
+	if msg.Attempts != 1 {
+		t.Errorf("não esperava nova tentativa antes do backoff, obteve %d", msg.Attempts)
+	}
+
+	now = now.Add(retryBaseDelay)
+	_, _ = d.dispatchBatch(ctx)
+	if msg.Attempts != 2 || !msg.NextAttemptAt.Equal(now.Add(2*retryBaseDelay)) {
+		t.Errorf("esperava backoff dobrado na segunda falha, obteve %v", msg.NextAttemptAt.Sub(now))
+	}
+
+	now = now.Add(2 * retryBaseDelay)
+	_, _ = d.dispatchBatch(ctx)
+	if !msg.IsProcessed() || len(notifier.activations) != 1 {
+		t.Errorf("esperava entrega na terceira tentativa")
+	}
+}
+
+func TestDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeOutboxRepository{}
+	notifier := &recordingNotifier{failures: maxAttempts}
+	now := time.Now()
+	d := newTestDispatcher(repo, notifier, &now)
+
+	if err := NewWriter(repo).SendSignupAttempt(ctx, domain.SignupAttemptNotice{Email: "joao@email.com"}); err != nil {
+		t.Fatalf("falha ao gravar aviso: %v", err)
+	}
+	msg := repo.messages[0]
+	now = time.Now()
+
+	for i := 0; i < maxAttempts; i++ {
+		_, _ = d.dispatchBatch(ctx)
+		now = now.Add(retryMaxDelay)
+	}
+
+	if !msg.IsFailed() || msg.Attempts != maxAttempts {
+		t.Errorf("esperava falha definitiva após %d tentativas, obteve %d (failed=%v)", maxAttempts, msg.Attempts, msg.IsFailed())
+	}
+}
+
+func TestDispatcher_EventsWithoutHandlerAreProcessed(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeOutboxRepository{}
+	now := time.Now()
+	d := newTestDispatcher(repo, &recordingNotifier{}, &now)
+
+	event := domain.Event{Type: domain.EventUserActivated, Payload: domain.UserActivatedEvent{UserID: "u1"}}
+	if err := NewWriter(repo).Publish(ctx, event); err != nil {
+		t.Fatalf("falha ao publicar evento: %v", err)
+	}
+	if string(repo.messages[0].Payload) != `{"user_id":"u1"}` {
+		t.Errorf("payload inesperado: %s", repo.messages[0].Payload)
+	}
+	now = time.Now()
+
+	_, _ = d.dispatchBatch(ctx)
+	if !repo.messages[0].IsProcessed() {
+		t.Error("esperava evento sem assinante marcado como processado")
+	}
+}
+
+func TestDispatcher_StartStop(t *testing.T) {
+	repo := &fakeOutboxRepository{}
+	notifier := &recordingNotifier{}
+	d := NewDispatcher(repo, nopLogger{})
+	RegisterNotifier(d, notifier)
+
+	if err := NewWriter(repo).SendActivation(context.Background(), domain.ActivationNotice{Email: "joao@email.com"}); err != nil {
+		t.Fatalf("falha ao gravar ativação: %v", err)
+	}
+
+	d.Start(context.Background())
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		notifier.mu.Lock()
+		delivered := len(notifier.activations)
+		notifier.mu.Unlock()
+		if delivered == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("esperava entrega pela goroutine do dispatcher")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	d.Stop()
+	d.Stop() // idempotente
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, retryBaseDelay},
+		{2, 2 * retryBaseDelay},
+		{3, 4 * retryBaseDelay},
+		{20, retryMaxDelay},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, esperava %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+// Tipos das mensagens de email gravadas no outbox
+const (
+	MessageActivationEmail    = "email.activation"
+	MessageInviteEmail        = "email.invite"
+	MessageSignupAttemptEmail = "email.signup_attempt"
+	MessagePasswordResetEmail = "email.password_reset"
+	MessageAccountLockedEmail = "email.account_locked"
+)
+
+// Writer grava emails e eventos de domínio no outbox em vez de entregá-los
+// Com o contexto de UnitOfWork.WithTransaction a mensagem entra na mesma
+// transação da alteração de negócio: ou as duas são confirmadas, ou nenhuma
+type Writer struct {
+	repo repositories.OutboxRepository
+}
+
+// NewWriter cria um novo Writer
+func NewWriter(repo repositories.OutboxRepository) *Writer {
+	return &Writer{repo: repo}
+}
+
+func (w *Writer) SendActivation(ctx context.Context, notice domain.ActivationNotice) error {
+	return w.enqueue(ctx, MessageActivationEmail, notice)
+}
+
+func (w *Writer) SendInvite(ctx context.Context, notice domain.InviteNotice) error {
+	return w.enqueue(ctx, MessageInviteEmail, notice)
+}
+
+func (w *Writer) SendSignupAttempt(ctx context.Context, notice domain.SignupAttemptNotice) error {
+	return w.enqueue(ctx, MessageSignupAttemptEmail, notice)
+}
+
+func (w *Writer) SendPasswordReset(ctx context.Context, notice domain.PasswordResetNotice) error {
+	return w.enqueue(ctx, MessagePasswordResetEmail, notice)
+}
+
+func (w *Writer) SendAccountLocked(ctx context.Context, notice domain.AccountLockedNotice) error {
+	return w.enqueue(ctx, MessageAccountLockedEmail, notice)
+}
+
+func (w *Writer) Publish(ctx context.Context, event domain.Event) error {
+	return w.enqueue(ctx, event.Type, event.Payload)
+}
+
+func (w *Writer) enqueue(ctx context.Context, msgType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s outbox message: %w", msgType, err)
+	}
+
+	return w.repo.Create(ctx, &entities.OutboxMessage{
+		ID:            uuid.New().String(),
+		Type:          msgType,
+		Payload:       data,
+		NextAttemptAt: time.Now(),
+	})
+}
@@ -0,0 +1,3 @@
+-- Migration: create_users_table
+
+DROP TABLE IF EXISTS users CASCADE;
@@ -0,0 +1,3 @@
+-- Migration: create_refresh_tokens_table
+
+DROP TABLE IF EXISTS refresh_tokens CASCADE;
@@ -0,0 +1,23 @@
+-- Migration: create_refresh_tokens_table
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at BIGINT NOT NULL,
+    used_at BIGINT,
+    revoked_at BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+-- Índices
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
+
+-- Comentários
+COMMENT ON TABLE refresh_tokens IS 'Issued refresh tokens (hashed), grouped by login family';
+COMMENT ON COLUMN refresh_tokens.token_hash IS 'SHA-256 hex digest of the refresh token';
+COMMENT ON COLUMN refresh_tokens.family_id IS 'Shared by all tokens rotated from the same login';
+COMMENT ON COLUMN refresh_tokens.used_at IS 'Set when the token is rotated; reuse revokes the family';
@@ -0,0 +1,3 @@
+-- Migration: create_organizations_table
+
+DROP TABLE IF EXISTS organizations CASCADE;
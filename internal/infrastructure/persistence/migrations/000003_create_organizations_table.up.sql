@@ -0,0 +1,18 @@
+-- Migration: create_organizations_table
+
+CREATE TABLE IF NOT EXISTS organizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'active',
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    deleted_at BIGINT
+);
+
+-- Índices
+CREATE INDEX idx_organizations_status ON organizations(status);
+CREATE INDEX idx_organizations_deleted_at ON organizations(deleted_at);
+
+-- Comentários
+COMMENT ON TABLE organizations IS 'Customer organizations (tenant root)';
+COMMENT ON COLUMN organizations.status IS 'Organization status: active, suspended, canceled';
@@ -0,0 +1,3 @@
+-- Migration: create_organization_members_table
+
+DROP TABLE IF EXISTS organization_members CASCADE;
@@ -0,0 +1,26 @@
+-- Migration: create_organization_members_table
+
+CREATE TABLE IF NOT EXISTS organization_members (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL,
+    invited_by UUID REFERENCES users(id),
+    invited_at BIGINT NOT NULL,
+    joined_at BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    deleted_at BIGINT
+);
+
+-- Um usuário só pode ser membro ativo uma vez por organização
+CREATE UNIQUE INDEX idx_organization_members_org_user ON organization_members(organization_id, user_id)
+    WHERE deleted_at IS NULL;
+
+-- Índices
+CREATE INDEX idx_organization_members_org ON organization_members(organization_id);
+CREATE INDEX idx_organization_members_user ON organization_members(user_id);
+CREATE INDEX idx_organization_members_deleted_at ON organization_members(deleted_at);
+
+-- Comentários
+COMMENT ON TABLE organization_members IS 'N:N membership between users and organizations';
+COMMENT ON COLUMN organization_members.role IS 'Role of the user in this organization: admin, user, guest';
@@ -0,0 +1,5 @@
+-- Migration: create_tenant_rls_functions
+
+DROP FUNCTION IF EXISTS disable_tenant_rls(REGCLASS);
+DROP FUNCTION IF EXISTS enable_tenant_rls(REGCLASS);
+DROP FUNCTION IF EXISTS app_current_org();
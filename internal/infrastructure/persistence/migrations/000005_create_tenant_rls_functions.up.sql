@@ -0,0 +1,43 @@
+-- Migration: create_tenant_rls_functions
+
+-- Organização da transação atual
+-- Definida pelo UnitOfWork com set_config('app.current_org', <id>, true) (equivalente a SET LOCAL)
+-- Retorna NULL quando não definida, o que faz as policies não retornarem nenhuma linha
+CREATE OR REPLACE FUNCTION app_current_org() RETURNS UUID
+    LANGUAGE sql STABLE
+AS $$
+    SELECT NULLIF(current_setting('app.current_org', true), '')::uuid
+$$;
+
+-- Habilita RLS em uma tabela de negócio (com coluna organization_id)
+-- FORCE aplica as policies também ao owner da tabela (usuário da aplicação)
+-- Uso nas migrations de tabelas de negócio: SELECT enable_tenant_rls('subscriptions');
+CREATE OR REPLACE FUNCTION enable_tenant_rls(target REGCLASS) RETURNS void
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    EXECUTE format('ALTER TABLE %s ENABLE ROW LEVEL SECURITY', target);
+    EXECUTE format('ALTER TABLE %s FORCE ROW LEVEL SECURITY', target);
+    EXECUTE format(
+        'CREATE POLICY tenant_isolation ON %s
+            USING (organization_id = app_current_org())
+            WITH CHECK (organization_id = app_current_org())',
+        target
+    );
+END;
+$$;
+
+-- Reverte enable_tenant_rls (usado nas migrations down)
+CREATE OR REPLACE FUNCTION disable_tenant_rls(target REGCLASS) RETURNS void
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %s', target);
+    EXECUTE format('ALTER TABLE %s NO FORCE ROW LEVEL SECURITY', target);
+    EXECUTE format('ALTER TABLE %s DISABLE ROW LEVEL SECURITY', target);
+END;
+$$;
+
+-- Comentários
+COMMENT ON FUNCTION app_current_org() IS 'Organization of the current transaction (app.current_org)';
+COMMENT ON FUNCTION enable_tenant_rls(REGCLASS) IS 'Enables the tenant_isolation RLS policy on a business table';
@@ -0,0 +1,6 @@
+-- Migration: add_status_to_users
+
+DROP INDEX IF EXISTS idx_users_status;
+ALTER TABLE users ALTER COLUMN name DROP DEFAULT;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+ALTER TABLE users DROP COLUMN IF EXISTS status;
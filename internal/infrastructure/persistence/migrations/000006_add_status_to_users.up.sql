@@ -0,0 +1,16 @@
+-- Migration: add_status_to_users
+
+-- Usuários existentes continuam ativos; novos cadastros nascem inativos
+ALTER TABLE users ADD COLUMN status VARCHAR(50) NOT NULL DEFAULT 'active';
+ALTER TABLE users ALTER COLUMN status SET DEFAULT 'inactive';
+ALTER TABLE users ADD COLUMN email_verified_at BIGINT;
+
+-- O cadastro self-service não pede nome (fica em user_accounts.full_name)
+ALTER TABLE users ALTER COLUMN name SET DEFAULT '';
+
+-- Índices
+CREATE INDEX idx_users_status ON users(status);
+
+-- Comentários
+COMMENT ON COLUMN users.status IS 'Account status: inactive, active, suspended';
+COMMENT ON COLUMN users.email_verified_at IS 'Unix timestamp of email verification (null if not verified)';
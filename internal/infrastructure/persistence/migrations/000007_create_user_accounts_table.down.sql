@@ -0,0 +1,3 @@
+-- Migration: create_user_accounts_table
+
+DROP TABLE IF EXISTS user_accounts CASCADE;
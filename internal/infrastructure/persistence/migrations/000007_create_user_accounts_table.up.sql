@@ -0,0 +1,26 @@
+-- Migration: create_user_accounts_table
+
+CREATE TABLE IF NOT EXISTS user_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    full_name VARCHAR(255),
+    avatar_url VARCHAR(500),
+    phone VARCHAR(50),
+    locale VARCHAR(10) NOT NULL DEFAULT 'pt-BR',
+    timezone VARCHAR(50) NOT NULL DEFAULT 'America/Sao_Paulo',
+    theme VARCHAR(20) NOT NULL DEFAULT 'light',
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    deleted_at BIGINT
+);
+
+-- Uma conta ativa por usuário
+CREATE UNIQUE INDEX idx_user_accounts_user_id ON user_accounts(user_id)
+    WHERE deleted_at IS NULL;
+
+-- Índices
+CREATE INDEX idx_user_accounts_deleted_at ON user_accounts(deleted_at);
+
+-- Comentários
+COMMENT ON TABLE user_accounts IS 'User profile and preferences (1:1 with users)';
+COMMENT ON COLUMN user_accounts.full_name IS 'Optional full name, never collected at signup';
@@ -0,0 +1,3 @@
+-- Migration: create_activation_tokens_table
+
+DROP TABLE IF EXISTS activation_tokens CASCADE;
@@ -0,0 +1,21 @@
+-- Migration: create_activation_tokens_table
+
+CREATE TABLE IF NOT EXISTS activation_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at BIGINT NOT NULL,
+    used_at BIGINT,
+    revoked_at BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+-- Índices
+CREATE INDEX idx_activation_tokens_user_id ON activation_tokens(user_id);
+CREATE INDEX idx_activation_tokens_created_at ON activation_tokens(created_at);
+
+-- Comentários
+COMMENT ON TABLE activation_tokens IS 'Single-use account activation tokens (hashed)';
+COMMENT ON COLUMN activation_tokens.token_hash IS 'SHA-256 hex digest of the activation token';
+COMMENT ON COLUMN activation_tokens.used_at IS 'Set when the token activates the account';
+COMMENT ON COLUMN activation_tokens.revoked_at IS 'Set when a newer token is resent; only the latest token is valid';
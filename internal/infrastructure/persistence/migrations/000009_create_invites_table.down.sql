@@ -0,0 +1,3 @@
+-- Migration: create_invites_table
+
+DROP TABLE IF EXISTS invites CASCADE;
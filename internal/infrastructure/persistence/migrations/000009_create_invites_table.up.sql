@@ -0,0 +1,34 @@
+-- Migration: create_invites_table
+
+CREATE TABLE IF NOT EXISTS invites (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    invited_by UUID NOT NULL REFERENCES users(id),
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    expires_at BIGINT NOT NULL,
+    accepted_at BIGINT,
+    accepted_by UUID REFERENCES users(id),
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    deleted_at BIGINT
+);
+
+-- Um convite pendente por email em cada organização
+CREATE UNIQUE INDEX idx_invites_org_email_pending ON invites(organization_id, email)
+    WHERE status = 'pending' AND deleted_at IS NULL;
+
+-- Índices
+CREATE INDEX idx_invites_organization ON invites(organization_id);
+CREATE INDEX idx_invites_email ON invites(email);
+CREATE INDEX idx_invites_deleted_at ON invites(deleted_at);
+
+-- Isolamento por organização
+SELECT enable_tenant_rls('invites');
+
+-- Comentários
+COMMENT ON TABLE invites IS 'Invitations to join an organization';
+COMMENT ON COLUMN invites.token_hash IS 'SHA-256 hex digest of the invite token';
+COMMENT ON COLUMN invites.status IS 'Invite status: pending, accepted, revoked, expired';
@@ -0,0 +1,3 @@
+-- Migration: create_outbox_table
+
+DROP TABLE IF EXISTS outbox CASCADE;
@@ -0,0 +1,23 @@
+-- Migration: create_outbox_table
+
+CREATE TABLE IF NOT EXISTS outbox (
+    id UUID PRIMARY KEY,
+    type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at BIGINT NOT NULL,
+    last_error TEXT,
+    processed_at BIGINT,
+    failed_at BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+-- Mensagens pendentes, na ordem em que o dispatcher as busca
+CREATE INDEX idx_outbox_pending ON outbox(next_attempt_at)
+    WHERE processed_at IS NULL AND failed_at IS NULL;
+
+-- Comentários
+COMMENT ON TABLE outbox IS 'Transactional outbox: emails and domain events written with the business change';
+COMMENT ON COLUMN outbox.payload IS 'JSON payload; cleared after delivery since emails carry tokens';
+COMMENT ON COLUMN outbox.next_attempt_at IS 'Earliest delivery time; pushed forward while a dispatcher holds the message';
+COMMENT ON COLUMN outbox.failed_at IS 'Set when all delivery attempts are exhausted';
@@ -0,0 +1,4 @@
+-- Migration: create_oauth_tables
+
+DROP TABLE IF EXISTS user_identities CASCADE;
+DROP TABLE IF EXISTS oauth_states CASCADE;
@@ -0,0 +1,33 @@
+-- Migration: create_oauth_tables
+
+CREATE TABLE IF NOT EXISTS oauth_states (
+    id UUID PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    state_hash VARCHAR(64) NOT NULL UNIQUE,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at BIGINT NOT NULL,
+    used_at BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+CREATE INDEX idx_oauth_states_expires_at ON oauth_states(expires_at);
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+-- Uma conta do provedor pertence a um único usuário
+CREATE UNIQUE INDEX idx_user_identities_provider_subject ON user_identities(provider, subject);
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+
+-- Comentários
+COMMENT ON TABLE oauth_states IS 'Pending OAuth2 logins: single-use state and PKCE verifier';
+COMMENT ON COLUMN oauth_states.state_hash IS 'SHA-256 hex digest of the state parameter';
+COMMENT ON TABLE user_identities IS 'Links between users and OAuth2 provider accounts';
+COMMENT ON COLUMN user_identities.subject IS 'Stable user identifier at the provider';
+COMMENT ON COLUMN user_identities.email IS 'Email reported by the provider when the link was created';
@@ -0,0 +1,3 @@
+-- Migration: create_sso_configs_table
+
+DROP TABLE IF EXISTS sso_configs CASCADE;
@@ -0,0 +1,23 @@
+-- Migration: create_sso_configs_table
+
+CREATE TABLE IF NOT EXISTS sso_configs (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL UNIQUE REFERENCES organizations(id) ON DELETE CASCADE,
+    issuer VARCHAR(255) NOT NULL,
+    client_id VARCHAR(255) NOT NULL,
+    client_secret VARCHAR(512) NOT NULL,
+    allowed_domains JSONB NOT NULL DEFAULT '[]',
+    default_role VARCHAR(50) NOT NULL DEFAULT 'user',
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+-- Isolamento por organização
+SELECT enable_tenant_rls('sso_configs');
+
+-- Comentários
+COMMENT ON TABLE sso_configs IS 'Per-organization OpenID Connect identity provider (enterprise SSO)';
+COMMENT ON COLUMN sso_configs.issuer IS 'OIDC issuer URL; endpoints and keys come from its discovery document';
+COMMENT ON COLUMN sso_configs.allowed_domains IS 'Lowercase email domains allowed to sign in through the provider';
+COMMENT ON COLUMN sso_configs.default_role IS 'Role of members provisioned on first login';
@@ -0,0 +1,3 @@
+-- Migration: create_password_reset_tokens_table
+
+DROP TABLE IF EXISTS password_reset_tokens CASCADE;
@@ -0,0 +1,21 @@
+-- Migration: create_password_reset_tokens_table
+
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at BIGINT NOT NULL,
+    used_at BIGINT,
+    revoked_at BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+-- Índices
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+CREATE INDEX idx_password_reset_tokens_created_at ON password_reset_tokens(created_at);
+
+-- Comentários
+COMMENT ON TABLE password_reset_tokens IS 'Single-use password reset tokens (hashed)';
+COMMENT ON COLUMN password_reset_tokens.token_hash IS 'SHA-256 hex digest of the password reset token';
+COMMENT ON COLUMN password_reset_tokens.used_at IS 'Set when the token resets the password';
+COMMENT ON COLUMN password_reset_tokens.revoked_at IS 'Set when a newer reset is requested; only the latest token is valid';
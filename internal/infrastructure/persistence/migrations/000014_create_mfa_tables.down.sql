@@ -0,0 +1,5 @@
+-- Migration: create_mfa_tables
+
+ALTER TABLE organizations DROP COLUMN IF EXISTS require_mfa;
+DROP TABLE IF EXISTS mfa_recovery_codes CASCADE;
+DROP TABLE IF EXISTS user_totp CASCADE;
@@ -0,0 +1,34 @@
+-- Migration: create_mfa_tables
+
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    confirmed_at BIGINT,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+-- Organizações existentes não passam a exigir 2FA
+ALTER TABLE organizations ADD COLUMN require_mfa BOOLEAN NOT NULL DEFAULT false;
+
+-- Índices
+CREATE INDEX idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
+CREATE UNIQUE INDEX idx_mfa_recovery_codes_user_code ON mfa_recovery_codes(user_id, code_hash);
+
+-- Comentários
+COMMENT ON TABLE user_totp IS 'TOTP (RFC 6238) second factor of each user';
+COMMENT ON COLUMN user_totp.secret IS 'Base32 shared secret read by the authenticator app';
+COMMENT ON COLUMN user_totp.confirmed_at IS 'Set when the user proves the enrollment with a valid code; only confirmed enrollments are enforced';
+COMMENT ON COLUMN user_totp.last_used_step IS 'Last accepted 30-second step, prevents replaying a code';
+COMMENT ON TABLE mfa_recovery_codes IS 'Single-use recovery codes (hashed) for users who lost their authenticator';
+COMMENT ON COLUMN mfa_recovery_codes.code_hash IS 'SHA-256 hex digest of the recovery code';
+COMMENT ON COLUMN organizations.require_mfa IS 'Members must sign in with a second factor to access the organization';
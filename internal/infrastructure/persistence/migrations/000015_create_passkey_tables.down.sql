@@ -0,0 +1,4 @@
+-- Migration: create_passkey_tables
+
+DROP TABLE IF EXISTS passkey_challenges CASCADE;
+DROP TABLE IF EXISTS passkeys CASCADE;
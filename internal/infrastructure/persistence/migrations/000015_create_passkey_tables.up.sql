@@ -0,0 +1,41 @@
+-- Migration: create_passkey_tables
+
+CREATE TABLE IF NOT EXISTS passkeys (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    credential_id VARCHAR(1400) NOT NULL UNIQUE,
+    public_key BYTEA NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    aaguid VARCHAR(36) NOT NULL,
+    transports JSONB NOT NULL DEFAULT '[]',
+    name VARCHAR(100) NOT NULL,
+    backup_eligible BOOLEAN NOT NULL DEFAULT false,
+    backup_state BOOLEAN NOT NULL DEFAULT false,
+    last_used_at BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+CREATE TABLE IF NOT EXISTS passkey_challenges (
+    id UUID PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    ceremony VARCHAR(20) NOT NULL,
+    challenge_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at BIGINT NOT NULL,
+    used_at BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+-- Índices
+CREATE INDEX idx_passkeys_user_id ON passkeys(user_id);
+CREATE INDEX idx_passkey_challenges_expires_at ON passkey_challenges(expires_at);
+
+-- Comentários
+COMMENT ON TABLE passkeys IS 'WebAuthn credentials (passkeys); a user may have several';
+COMMENT ON COLUMN passkeys.credential_id IS 'Base64url credential ID assigned by the authenticator';
+COMMENT ON COLUMN passkeys.public_key IS 'COSE_Key public key registered by the authenticator';
+COMMENT ON COLUMN passkeys.sign_count IS 'Last signature counter seen; a counter that does not increase flags a cloned authenticator';
+COMMENT ON COLUMN passkeys.aaguid IS 'Authenticator model identifier';
+COMMENT ON COLUMN passkeys.backup_state IS 'Whether the credential is synced to other devices';
+COMMENT ON TABLE passkey_challenges IS 'Pending WebAuthn ceremonies: single-use challenges';
+COMMENT ON COLUMN passkey_challenges.user_id IS 'User registering a passkey; NULL for sign-in ceremonies';
+COMMENT ON COLUMN passkey_challenges.challenge_hash IS 'SHA-256 hex digest of the challenge';
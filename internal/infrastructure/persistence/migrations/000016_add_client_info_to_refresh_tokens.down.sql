@@ -0,0 +1,4 @@
+-- Migration: add_client_info_to_refresh_tokens
+
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS user_agent;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS ip_address;
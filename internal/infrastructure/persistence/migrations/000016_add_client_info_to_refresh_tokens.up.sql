@@ -0,0 +1,10 @@
+-- Migration: add_client_info_to_refresh_tokens
+
+-- Cada família de refresh tokens é uma sessão; o token mais recente guarda
+-- o cliente que fez o último login ou rotação
+ALTER TABLE refresh_tokens ADD COLUMN ip_address VARCHAR(45) NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN user_agent VARCHAR(512) NOT NULL DEFAULT '';
+
+-- Comentários
+COMMENT ON COLUMN refresh_tokens.ip_address IS 'Client IP that received the token';
+COMMENT ON COLUMN refresh_tokens.user_agent IS 'Client User-Agent that received the token';
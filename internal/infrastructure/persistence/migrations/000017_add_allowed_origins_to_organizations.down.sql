@@ -0,0 +1,4 @@
+-- Migration: add_allowed_origins_to_organizations
+
+DROP INDEX IF EXISTS idx_organizations_allowed_origins;
+ALTER TABLE organizations DROP COLUMN IF EXISTS allowed_origins;
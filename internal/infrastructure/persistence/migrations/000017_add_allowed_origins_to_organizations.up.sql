@@ -0,0 +1,11 @@
+-- Migration: add_allowed_origins_to_organizations
+
+-- Origins CORS extras da organização (ex: domínio próprio do frontend)
+ALTER TABLE organizations ADD COLUMN allowed_origins JSONB NOT NULL DEFAULT '[]';
+
+-- Índices
+-- O middleware CORS procura as organizações que aceitam um origin (allowed_origins @> '["origin"]')
+CREATE INDEX idx_organizations_allowed_origins ON organizations USING GIN (allowed_origins);
+
+-- Comentários
+COMMENT ON COLUMN organizations.allowed_origins IS 'Extra CORS origins (scheme://host[:port]) accepted for the organization';
@@ -0,0 +1,5 @@
+-- Migration: enable_rls_on_organization_members
+
+DROP POLICY IF EXISTS member_self ON organization_members;
+SELECT disable_tenant_rls('organization_members');
+DROP FUNCTION IF EXISTS app_current_user();
@@ -0,0 +1,22 @@
+-- Migration: enable_rls_on_organization_members
+
+-- Usuário da transação atual
+-- Definido com set_config('app.current_user', <id>, true) nas consultas que
+-- atravessam organizações, como a lista de organizações do usuário
+CREATE OR REPLACE FUNCTION app_current_user() RETURNS UUID
+    LANGUAGE sql STABLE
+AS $$
+    SELECT NULLIF(current_setting('app.current_user', true), '')::uuid
+$$;
+
+-- Isolamento por organização
+SELECT enable_tenant_rls('organization_members');
+
+-- Cada usuário também enxerga as próprias associações em todas as organizações
+-- Policies permissivas se somam: a leitura vale pela organização OU pelo usuário
+CREATE POLICY member_self ON organization_members
+    FOR SELECT
+    USING (user_id = app_current_user());
+
+-- Comentários
+COMMENT ON FUNCTION app_current_user() IS 'User of the current transaction (app.current_user)';
@@ -0,0 +1,4 @@
+-- Migration: add_domain_verification_to_sso_configs
+
+ALTER TABLE sso_configs DROP COLUMN IF EXISTS domain_verification_token;
+ALTER TABLE sso_configs DROP COLUMN IF EXISTS verified_domains;
@@ -0,0 +1,11 @@
+-- Migration: add_domain_verification_to_sso_configs
+
+-- Os domínios do SSO só valem depois que a organização comprova a posse por
+-- um registro TXT. As configurações existentes começam sem domínios comprovados
+-- e recebem o token na primeira verificação
+ALTER TABLE sso_configs ADD COLUMN verified_domains JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE sso_configs ADD COLUMN domain_verification_token VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Comentários
+COMMENT ON COLUMN sso_configs.verified_domains IS 'Allowed domains whose ownership was proven through the TXT record';
+COMMENT ON COLUMN sso_configs.domain_verification_token IS 'Token published in the _avantpro-verification TXT record of each domain';
@@ -0,0 +1,4 @@
+-- Migration: scrub_tokens_from_failed_outbox
+
+-- Os tokens removidos não podem ser restaurados
+COMMENT ON COLUMN outbox.payload IS 'JSON payload; cleared after delivery since emails carry tokens';
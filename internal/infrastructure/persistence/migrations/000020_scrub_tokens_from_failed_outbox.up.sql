@@ -0,0 +1,8 @@
+-- Migration: scrub_tokens_from_failed_outbox
+
+-- Mensagens que falharam de vez não serão entregues: o token do email
+-- (ativação, redefinição de senha, convite) não precisa ficar no banco
+UPDATE outbox SET payload = payload - 'Token' WHERE failed_at IS NOT NULL;
+
+-- Comentários
+COMMENT ON COLUMN outbox.payload IS 'JSON payload; cleared after delivery and stripped of the email token after permanent failure';
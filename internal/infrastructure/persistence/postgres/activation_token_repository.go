@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+// ActivationTokenRepository implementa repositories.ActivationTokenRepository usando GORM
+type ActivationTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewActivationTokenRepository cria um novo ActivationTokenRepository
+func NewActivationTokenRepository(db *gorm.DB) repositories.ActivationTokenRepository {
+	return &ActivationTokenRepository{db: db}
+}
+
+func (r *ActivationTokenRepository) Create(ctx context.Context, token *entities.ActivationToken) error {
+	model := ActivationTokenModel{
+		ID:        token.ID,
+		UserID:    token.UserID,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt.Unix(),
+	}
+
+	if err := dbFromContext(ctx, r.db).Create(&model).Error; err != nil {
+		return err
+	}
+
+	token.CreatedAt = time.Unix(model.CreatedAt, 0)
+	return nil
+}
+
+func (r *ActivationTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entities.ActivationToken, error) {
+	var model ActivationTokenModel
+
+	err := dbFromContext(ctx, r.db).
+		Where("token_hash = ?", tokenHash).
+		First(&model).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrInvalidActivationToken
+		}
+		return nil, err
+	}
+
+	return toActivationTokenEntity(&model), nil
+}
+
+func (r *ActivationTokenRepository) MarkAsUsed(ctx context.Context, id string) error {
+	// O filtro por used_at/revoked_at garante que o token ative a conta uma única vez
+	result := dbFromContext(ctx, r.db).
+		Model(&ActivationTokenModel{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", time.Now().Unix())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrInvalidActivationToken
+	}
+
+	return nil
+}
+
+func (r *ActivationTokenRepository) RevokeByUser(ctx context.Context, userID string) error {
+	return dbFromContext(ctx, r.db).
+		Model(&ActivationTokenModel{}).
+		Where("user_id = ? AND used_at IS NULL AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now().Unix()).
+		Error
+}
+
+func (r *ActivationTokenRepository) CountCreatedSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	var count int64
+
+	err := dbFromContext(ctx, r.db).
+		Model(&ActivationTokenModel{}).
+		Where("user_id = ? AND created_at >= ?", userID, since.Unix()).
+		Count(&count).
+		Error
+
+	return count, err
+}
+
+// toActivationTokenEntity converte o model GORM para a entidade de domínio
+func toActivationTokenEntity(model *ActivationTokenModel) *entities.ActivationToken {
+	return &entities.ActivationToken{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		TokenHash: model.TokenHash,
+		ExpiresAt: time.Unix(model.ExpiresAt, 0),
+		UsedAt:    unixToTimePtr(model.UsedAt),
+		RevokedAt: unixToTimePtr(model.RevokedAt),
+		CreatedAt: time.Unix(model.CreatedAt, 0),
+	}
+}
@@ -21,6 +21,8 @@ func NewDatabaseConnection(cfg *config.DatabaseConfig, log domain.Logger) (*gorm
 			return time.Now().UTC()
 		},
 		PrepareStmt: false,
+		// Traduzir erros do driver (ex: unique violation -> gorm.ErrDuplicatedKey)
+		TranslateError: true,
 	}
 
 	// Conectar
@@ -29,6 +31,11 @@ func NewDatabaseConnection(cfg *config.DatabaseConfig, log domain.Logger) (*gorm
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Isolamento automático de tabelas de negócio por organização
+	if err := db.Use(TenantPlugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register tenant plugin: %w", err)
+	}
+
 	// Configurar connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
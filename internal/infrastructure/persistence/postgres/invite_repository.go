@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+)
+
+// InviteRepository implementa repositories.InviteRepository usando GORM
+// InviteModel é um TenantModel: o TenantPlugin aplica o filtro de organização
+type InviteRepository struct {
+	db *gorm.DB
+}
+
+// NewInviteRepository cria um novo InviteRepository
+func NewInviteRepository(db *gorm.DB) repositories.InviteRepository {
+	return &InviteRepository{db: db}
+}
+
+func (r *InviteRepository) Create(ctx context.Context, invite *entities.Invite) error {
+	model := toInviteModel(invite)
+
+	if err := dbFromContext(ctx, r.db).Create(model).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return domainerrors.ErrInviteAlreadyPending
+		}
+		return err
+	}
+
+	invite.OrganizationID = model.OrganizationID
+	invite.CreatedAt = time.Unix(model.CreatedAt, 0)
+	invite.UpdatedAt = time.Unix(model.UpdatedAt, 0)
+	return nil
+}
+
+func (r *InviteRepository) FindByID(ctx context.Context, id string) (*entities.Invite, error) {
+	var model InviteModel
+
+	err := dbFromContext(ctx, r.db).
+		Where("id = ? AND deleted_at IS NULL", id).
+		First(&model).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrInviteNotFound
+		}
+		return nil, err
+	}
+
+	return toInviteEntity(&model)
+}
+
+func (r *InviteRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entities.Invite, error) {
+	var model InviteModel
+
+	err := dbFromContext(ctx, r.db).
+		Where("token_hash = ? AND deleted_at IS NULL", tokenHash).
+		First(&model).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrInvalidInviteToken
+		}
+		return nil, err
+	}
+
+	return toInviteEntity(&model)
+}
+
+func (r *InviteRepository) List(ctx context.Context) ([]*entities.Invite, error) {
+	var models []InviteModel
+
+	err := dbFromContext(ctx, r.db).
+		Where("deleted_at IS NULL").
+		Order("created_at DESC").
+		Find(&models).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	invites := make([]*entities.Invite, 0, len(models))
+	for i := range models {
+		invite, err := toInviteEntity(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		invites = append(invites, invite)
+	}
+
+	return invites, nil
+}
+
+func (r *InviteRepository) ExpirePending(ctx context.Context, email string, now time.Time) error {
+	return dbFromContext(ctx, r.db).
+		Model(&InviteModel{}).
+		Where("email = ? AND status = ? AND expires_at <= ? AND deleted_at IS NULL",
+			email, string(entities.InviteStatusPending), now.Unix()).
+		Updates(map[string]interface{}{
+			"status":     string(entities.InviteStatusExpired),
+			"updated_at": now.Unix(),
+		}).
+		Error
+}
+
+func (r *InviteRepository) Revoke(ctx context.Context, id string) error {
+	result := dbFromContext(ctx, r.db).
+		Model(&InviteModel{}).
+		Where("id = ? AND status = ? AND deleted_at IS NULL", id, string(entities.InviteStatusPending)).
+		Updates(map[string]interface{}{
+			"status":     string(entities.InviteStatusRevoked),
+			"updated_at": time.Now().Unix(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrInviteNotFound
+	}
+
+	return nil
+}
+
+func (r *InviteRepository) MarkAccepted(ctx context.Context, id, userID string, acceptedAt time.Time) error {
+	// O filtro por status garante que o convite seja aceito uma única vez
+	result := dbFromContext(ctx, r.db).
+		Model(&InviteModel{}).
+		Where("id = ? AND status = ? AND deleted_at IS NULL", id, string(entities.InviteStatusPending)).
+		Updates(map[string]interface{}{
+			"status":      string(entities.InviteStatusAccepted),
+			"accepted_at": acceptedAt.Unix(),
+			"accepted_by": userID,
+			"updated_at":  acceptedAt.Unix(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrInvalidInviteToken
+	}
+
+	return nil
+}
+
+// toInviteModel converte a entidade de domínio para o model GORM
+func toInviteModel(invite *entities.Invite) *InviteModel {
+	return &InviteModel{
+		TenantModel: TenantModel{OrganizationID: invite.OrganizationID},
+		ID:          invite.ID,
+		Email:       invite.Email.String(),
+		Role:        string(invite.Role),
+		TokenHash:   invite.TokenHash,
+		InvitedBy:   invite.InvitedBy,
+		Status:      string(invite.Status),
+		ExpiresAt:   invite.ExpiresAt.Unix(),
+		AcceptedAt:  timePtrToUnix(invite.AcceptedAt),
+		AcceptedBy:  invite.AcceptedBy,
+	}
+}
+
+// toInviteEntity converte o model GORM para a entidade de domínio
+func toInviteEntity(model *InviteModel) (*entities.Invite, error) {
+	email, err := valueobjects.NewEmail(model.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.Invite{
+		ID:             model.ID,
+		OrganizationID: model.OrganizationID,
+		Email:          email,
+		Role:           entities.Role(model.Role),
+		TokenHash:      model.TokenHash,
+		InvitedBy:      model.InvitedBy,
+		Status:         entities.InviteStatus(model.Status),
+		ExpiresAt:      time.Unix(model.ExpiresAt, 0),
+		AcceptedAt:     unixToTimePtr(model.AcceptedAt),
+		AcceptedBy:     model.AcceptedBy,
+		CreatedAt:      time.Unix(model.CreatedAt, 0),
+		UpdatedAt:      time.Unix(model.UpdatedAt, 0),
+	}, nil
+}
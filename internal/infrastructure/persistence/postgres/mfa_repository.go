@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+// MFARepository implementa repositories.MFARepository usando GORM
+type MFARepository struct {
+	db *gorm.DB
+}
+
+// NewMFARepository cria um novo MFARepository
+func NewMFARepository(db *gorm.DB) repositories.MFARepository {
+	return &MFARepository{db: db}
+}
+
+func (r *MFARepository) FindTOTP(ctx context.Context, userID string) (*entities.TOTPEnrollment, error) {
+	var model UserTOTPModel
+
+	err := dbFromContext(ctx, r.db).
+		Where("user_id = ?", userID).
+		First(&model).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrMFANotEnrolled
+		}
+		return nil, err
+	}
+
+	return toTOTPEnrollmentEntity(&model), nil
+}
+
+func (r *MFARepository) SaveTOTP(ctx context.Context, enrollment *entities.TOTPEnrollment) error {
+	model := UserTOTPModel{
+		UserID: enrollment.UserID,
+		Secret: enrollment.Secret,
+	}
+
+	// Um novo cadastro substitui o anterior ainda não confirmado
+	err := dbFromContext(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"secret":         model.Secret,
+				"confirmed_at":   nil,
+				"last_used_step": 0,
+				"updated_at":     time.Now().Unix(),
+			}),
+		}).
+		Create(&model).
+		Error
+	if err != nil {
+		return err
+	}
+
+	enrollment.ConfirmedAt = nil
+	enrollment.LastUsedStep = 0
+	enrollment.CreatedAt = time.Unix(model.CreatedAt, 0)
+	enrollment.UpdatedAt = time.Unix(model.UpdatedAt, 0)
+	return nil
+}
+
+func (r *MFARepository) ConfirmTOTP(ctx context.Context, userID string, step int64) error {
+	now := time.Now().Unix()
+
+	result := dbFromContext(ctx, r.db).
+		Model(&UserTOTPModel{}).
+		Where("user_id = ? AND confirmed_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"confirmed_at":   now,
+			"last_used_step": step,
+			"updated_at":     now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrMFAAlreadyEnabled
+	}
+
+	return nil
+}
+
+func (r *MFARepository) UseTOTPStep(ctx context.Context, userID string, step int64) error {
+	// A condição no UPDATE impede que duas requisições aceitem o mesmo código
+	result := dbFromContext(ctx, r.db).
+		Model(&UserTOTPModel{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Updates(map[string]interface{}{
+			"last_used_step": step,
+			"updated_at":     time.Now().Unix(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func (r *MFARepository) DeleteTOTP(ctx context.Context, userID string) error {
+	db := dbFromContext(ctx, r.db)
+
+	if err := db.Where("user_id = ?", userID).Delete(&RecoveryCodeModel{}).Error; err != nil {
+		return err
+	}
+
+	result := db.Where("user_id = ?", userID).Delete(&UserTOTPModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrMFANotEnrolled
+	}
+
+	return nil
+}
+
+func (r *MFARepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codes []*entities.RecoveryCode) error {
+	db := dbFromContext(ctx, r.db)
+
+	if err := db.Where("user_id = ?", userID).Delete(&RecoveryCodeModel{}).Error; err != nil {
+		return err
+	}
+
+	models := make([]RecoveryCodeModel, 0, len(codes))
+	for _, code := range codes {
+		models = append(models, RecoveryCodeModel{
+			ID:       code.ID,
+			UserID:   userID,
+			CodeHash: code.CodeHash,
+		})
+	}
+
+	if len(models) == 0 {
+		return nil
+	}
+
+	return db.Create(&models).Error
+}
+
+func (r *MFARepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	// O filtro por used_at garante que cada código seja aceito uma única vez
+	result := dbFromContext(ctx, r.db).
+		Model(&RecoveryCodeModel{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now().Unix())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func (r *MFARepository) CountRecoveryCodes(ctx context.Context, userID string) (int64, error) {
+	var count int64
+
+	err := dbFromContext(ctx, r.db).
+		Model(&RecoveryCodeModel{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).
+		Error
+
+	return count, err
+}
+
+// toTOTPEnrollmentEntity converte o model GORM para a entidade de domínio
+func toTOTPEnrollmentEntity(model *UserTOTPModel) *entities.TOTPEnrollment {
+	return &entities.TOTPEnrollment{
+		UserID:       model.UserID,
+		Secret:       model.Secret,
+		ConfirmedAt:  unixToTimePtr(model.ConfirmedAt),
+		LastUsedStep: model.LastUsedStep,
+		CreatedAt:    time.Unix(model.CreatedAt, 0),
+		UpdatedAt:    time.Unix(model.UpdatedAt, 0),
+	}
+}
@@ -2,17 +2,273 @@ package postgres
 
 // UserModel é o model GORM para usuários
 type UserModel struct {
-	ID           string  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Email        string  `gorm:"type:varchar(255);uniqueIndex;not null"`
-	Name         string  `gorm:"type:varchar(500);not null"`
-	PasswordHash string  `gorm:"type:varchar(255);not null"`
-	Role         string  `gorm:"type:varchar(50);not null;index"`
-	AvatarURL    *string `gorm:"type:varchar(500)"`
-	CreatedAt    int64   `gorm:"autoCreateTime;index"`
-	UpdatedAt    int64   `gorm:"autoUpdateTime"`
-	DeletedAt    *int64  `gorm:"index"` // Soft delete
+	ID              string  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Email           string  `gorm:"type:varchar(255);uniqueIndex;not null"`
+	Name            string  `gorm:"type:varchar(500);not null"`
+	PasswordHash    string  `gorm:"type:varchar(255);not null"`
+	Role            string  `gorm:"type:varchar(50);not null;index"`
+	Status          string  `gorm:"type:varchar(50);not null;index"`
+	AvatarURL       *string `gorm:"type:varchar(500)"`
+	EmailVerifiedAt *int64
+	CreatedAt       int64  `gorm:"autoCreateTime;index"`
+	UpdatedAt       int64  `gorm:"autoUpdateTime"`
+	DeletedAt       *int64 `gorm:"index"` // Soft delete
 }
 
 func (UserModel) TableName() string {
 	return "users"
 }
+
+// UserAccountModel é o model GORM para o perfil do usuário
+type UserAccountModel struct {
+	ID        string  `gorm:"type:uuid;primary_key"`
+	UserID    string  `gorm:"type:uuid;not null;uniqueIndex"`
+	FullName  *string `gorm:"type:varchar(255)"`
+	AvatarURL *string `gorm:"type:varchar(500)"`
+	Phone     *string `gorm:"type:varchar(50)"`
+	Locale    string  `gorm:"type:varchar(10);not null"`
+	Timezone  string  `gorm:"type:varchar(50);not null"`
+	Theme     string  `gorm:"type:varchar(20);not null"`
+	CreatedAt int64   `gorm:"autoCreateTime"`
+	UpdatedAt int64   `gorm:"autoUpdateTime"`
+	DeletedAt *int64  `gorm:"index"` // Soft delete
+}
+
+func (UserAccountModel) TableName() string {
+	return "user_accounts"
+}
+
+// RefreshTokenModel é o model GORM para refresh tokens
+type RefreshTokenModel struct {
+	ID        string `gorm:"type:uuid;primary_key"`
+	UserID    string `gorm:"type:uuid;not null;index"`
+	FamilyID  string `gorm:"type:uuid;not null;index"`
+	TokenHash string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	IPAddress string `gorm:"type:varchar(45);not null;default:''"`
+	UserAgent string `gorm:"type:varchar(512);not null;default:''"`
+	ExpiresAt int64  `gorm:"not null;index"`
+	UsedAt    *int64
+	RevokedAt *int64
+	CreatedAt int64 `gorm:"autoCreateTime"`
+}
+
+func (RefreshTokenModel) TableName() string {
+	return "refresh_tokens"
+}
+
+// ActivationTokenModel é o model GORM para tokens de ativação de conta
+type ActivationTokenModel struct {
+	ID        string `gorm:"type:uuid;primary_key"`
+	UserID    string `gorm:"type:uuid;not null;index"`
+	TokenHash string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	ExpiresAt int64  `gorm:"not null"`
+	UsedAt    *int64
+	RevokedAt *int64
+	CreatedAt int64 `gorm:"autoCreateTime;index"`
+}
+
+func (ActivationTokenModel) TableName() string {
+	return "activation_tokens"
+}
+
+// PasswordResetTokenModel é o model GORM para tokens de redefinição de senha
+type PasswordResetTokenModel struct {
+	ID        string `gorm:"type:uuid;primary_key"`
+	UserID    string `gorm:"type:uuid;not null;index"`
+	TokenHash string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	ExpiresAt int64  `gorm:"not null"`
+	UsedAt    *int64
+	RevokedAt *int64
+	CreatedAt int64 `gorm:"autoCreateTime;index"`
+}
+
+func (PasswordResetTokenModel) TableName() string {
+	return "password_reset_tokens"
+}
+
+// UserTOTPModel é o model GORM para o cadastro TOTP do usuário
+type UserTOTPModel struct {
+	UserID       string `gorm:"type:uuid;primary_key"`
+	Secret       string `gorm:"type:varchar(64);not null"`
+	ConfirmedAt  *int64
+	LastUsedStep int64 `gorm:"not null;default:0"`
+	CreatedAt    int64 `gorm:"autoCreateTime"`
+	UpdatedAt    int64 `gorm:"autoUpdateTime"`
+}
+
+func (UserTOTPModel) TableName() string {
+	return "user_totp"
+}
+
+// RecoveryCodeModel é o model GORM para códigos de recuperação do segundo fator
+type RecoveryCodeModel struct {
+	ID        string `gorm:"type:uuid;primary_key"`
+	UserID    string `gorm:"type:uuid;not null;index"`
+	CodeHash  string `gorm:"type:varchar(64);not null"`
+	UsedAt    *int64
+	CreatedAt int64 `gorm:"autoCreateTime"`
+}
+
+func (RecoveryCodeModel) TableName() string {
+	return "mfa_recovery_codes"
+}
+
+// OrganizationModel é o model GORM para organizações
+type OrganizationModel struct {
+	ID             string `gorm:"type:uuid;primary_key"`
+	Name           string `gorm:"type:varchar(255);not null"`
+	Status         string `gorm:"type:varchar(50);not null;index"`
+	RequireMFA     bool   `gorm:"not null;default:false"`
+	AllowedOrigins string `gorm:"type:jsonb;not null"`
+	CreatedAt      int64  `gorm:"autoCreateTime"`
+	UpdatedAt      int64  `gorm:"autoUpdateTime"`
+	DeletedAt      *int64 `gorm:"index"` // Soft delete
+}
+
+func (OrganizationModel) TableName() string {
+	return "organizations"
+}
+
+// OrganizationMemberModel é o model GORM para a associação N:N entre usuários e organizações
+// É um TenantModel: o TenantPlugin aplica o filtro de organização
+type OrganizationMemberModel struct {
+	TenantModel
+	ID        string  `gorm:"type:uuid;primary_key"`
+	UserID    string  `gorm:"type:uuid;not null;index"`
+	Role      string  `gorm:"type:varchar(50);not null"`
+	InvitedBy *string `gorm:"type:uuid"`
+	InvitedAt int64   `gorm:"not null"`
+	JoinedAt  *int64
+	CreatedAt int64  `gorm:"autoCreateTime"`
+	DeletedAt *int64 `gorm:"index"` // Soft delete
+
+	// Relacionamentos para eager loading
+	Organization *OrganizationModel `gorm:"foreignKey:OrganizationID"`
+	User         *UserModel         `gorm:"foreignKey:UserID"`
+}
+
+func (OrganizationMemberModel) TableName() string {
+	return "organization_members"
+}
+
+// InviteModel é o model GORM para convites de organização
+type InviteModel struct {
+	TenantModel
+	ID         string `gorm:"type:uuid;primary_key"`
+	Email      string `gorm:"type:varchar(255);not null;index"`
+	Role       string `gorm:"type:varchar(50);not null"`
+	TokenHash  string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	InvitedBy  string `gorm:"type:uuid;not null"`
+	Status     string `gorm:"type:varchar(50);not null"`
+	ExpiresAt  int64  `gorm:"not null"`
+	AcceptedAt *int64
+	AcceptedBy *string `gorm:"type:uuid"`
+	CreatedAt  int64   `gorm:"autoCreateTime"`
+	UpdatedAt  int64   `gorm:"autoUpdateTime"`
+	DeletedAt  *int64  `gorm:"index"` // Soft delete
+}
+
+func (InviteModel) TableName() string {
+	return "invites"
+}
+
+// OutboxModel é o model GORM para mensagens do outbox transacional
+type OutboxModel struct {
+	ID            string `gorm:"type:uuid;primary_key"`
+	Type          string `gorm:"type:varchar(100);not null"`
+	Payload       string `gorm:"type:jsonb;not null"`
+	Attempts      int    `gorm:"not null;default:0"`
+	NextAttemptAt int64  `gorm:"not null"`
+	LastError     *string
+	ProcessedAt   *int64
+	FailedAt      *int64
+	CreatedAt     int64 `gorm:"autoCreateTime"`
+}
+
+func (OutboxModel) TableName() string {
+	return "outbox"
+}
+
+// OAuthStateModel é o model GORM para logins OAuth2 em andamento
+type OAuthStateModel struct {
+	ID           string `gorm:"type:uuid;primary_key"`
+	Provider     string `gorm:"type:varchar(50);not null"`
+	StateHash    string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	CodeVerifier string `gorm:"type:varchar(128);not null"`
+	ExpiresAt    int64  `gorm:"not null;index"`
+	UsedAt       *int64
+	CreatedAt    int64 `gorm:"autoCreateTime"`
+}
+
+func (OAuthStateModel) TableName() string {
+	return "oauth_states"
+}
+
+// UserIdentityModel é o model GORM para vínculos entre usuários e provedores OAuth2
+type UserIdentityModel struct {
+	ID        string `gorm:"type:uuid;primary_key"`
+	UserID    string `gorm:"type:uuid;not null;index"`
+	Provider  string `gorm:"type:varchar(50);not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject   string `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Email     string `gorm:"type:varchar(255);not null"`
+	CreatedAt int64  `gorm:"autoCreateTime"`
+}
+
+func (UserIdentityModel) TableName() string {
+	return "user_identities"
+}
+
+// SSOConfigModel é o model GORM para o provedor OIDC de uma organização
+type SSOConfigModel struct {
+	TenantModel
+	ID                      string `gorm:"type:uuid;primary_key"`
+	Issuer                  string `gorm:"type:varchar(255);not null"`
+	ClientID                string `gorm:"type:varchar(255);not null"`
+	ClientSecret            string `gorm:"type:varchar(512);not null"`
+	AllowedDomains          string `gorm:"type:jsonb;not null"`
+	VerifiedDomains         string `gorm:"type:jsonb;not null;default:'[]'"`
+	DomainVerificationToken string `gorm:"type:varchar(64);not null;default:''"`
+	DefaultRole             string `gorm:"type:varchar(50);not null"`
+	Enabled                 bool   `gorm:"not null"`
+	CreatedAt               int64  `gorm:"autoCreateTime"`
+	UpdatedAt               int64  `gorm:"autoUpdateTime"`
+}
+
+func (SSOConfigModel) TableName() string {
+	return "sso_configs"
+}
+
+// PasskeyModel é o model GORM para credenciais WebAuthn
+type PasskeyModel struct {
+	ID             string `gorm:"type:uuid;primary_key"`
+	UserID         string `gorm:"type:uuid;not null;index"`
+	CredentialID   string `gorm:"type:varchar(1400);uniqueIndex;not null"`
+	PublicKey      []byte `gorm:"type:bytea;not null"`
+	SignCount      int64  `gorm:"not null;default:0"`
+	AAGUID         string `gorm:"type:varchar(36);not null"`
+	Transports     string `gorm:"type:jsonb;not null"`
+	Name           string `gorm:"type:varchar(100);not null"`
+	BackupEligible bool   `gorm:"not null;default:false"`
+	BackupState    bool   `gorm:"not null;default:false"`
+	LastUsedAt     *int64
+	CreatedAt      int64 `gorm:"autoCreateTime"`
+}
+
+func (PasskeyModel) TableName() string {
+	return "passkeys"
+}
+
+// PasskeyChallengeModel é o model GORM para cerimônias WebAuthn em andamento
+type PasskeyChallengeModel struct {
+	ID            string  `gorm:"type:uuid;primary_key"`
+	UserID        *string `gorm:"type:uuid"`
+	Ceremony      string  `gorm:"type:varchar(20);not null"`
+	ChallengeHash string  `gorm:"type:varchar(64);uniqueIndex;not null"`
+	ExpiresAt     int64   `gorm:"not null;index"`
+	UsedAt        *int64
+	CreatedAt     int64 `gorm:"autoCreateTime"`
+}
+
+func (PasskeyChallengeModel) TableName() string {
+	return "passkey_challenges"
+}
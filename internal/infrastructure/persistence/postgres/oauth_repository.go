@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+// OAuthStateRepository implementa repositories.OAuthStateRepository usando GORM
+type OAuthStateRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthStateRepository cria um novo OAuthStateRepository
+func NewOAuthStateRepository(db *gorm.DB) repositories.OAuthStateRepository {
+	return &OAuthStateRepository{db: db}
+}
+
+func (r *OAuthStateRepository) Create(ctx context.Context, state *entities.OAuthState) error {
+	model := OAuthStateModel{
+		ID:           state.ID,
+		Provider:     state.Provider,
+		StateHash:    state.StateHash,
+		CodeVerifier: state.CodeVerifier,
+		ExpiresAt:    state.ExpiresAt.Unix(),
+	}
+
+	if err := dbFromContext(ctx, r.db).Create(&model).Error; err != nil {
+		return err
+	}
+
+	state.CreatedAt = time.Unix(model.CreatedAt, 0)
+	return nil
+}
+
+func (r *OAuthStateRepository) Consume(ctx context.Context, provider, stateHash string) (*entities.OAuthState, error) {
+	var models []OAuthStateModel
+
+	// O UPDATE condicional garante que dois callbacks com o mesmo state não passem juntos
+	result := dbFromContext(ctx, r.db).
+		Model(&models).
+		Clauses(clause.Returning{}).
+		Where("provider = ? AND state_hash = ? AND used_at IS NULL", provider, stateHash).
+		Update("used_at", time.Now().Unix())
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if len(models) == 0 {
+		return nil, domainerrors.ErrInvalidOAuthState
+	}
+
+	return &entities.OAuthState{
+		ID:           models[0].ID,
+		Provider:     models[0].Provider,
+		StateHash:    models[0].StateHash,
+		CodeVerifier: models[0].CodeVerifier,
+		ExpiresAt:    time.Unix(models[0].ExpiresAt, 0),
+		UsedAt:       unixToTimePtr(models[0].UsedAt),
+		CreatedAt:    time.Unix(models[0].CreatedAt, 0),
+	}, nil
+}
+
+// UserIdentityRepository implementa repositories.UserIdentityRepository usando GORM
+type UserIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewUserIdentityRepository cria um novo UserIdentityRepository
+func NewUserIdentityRepository(db *gorm.DB) repositories.UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *entities.UserIdentity) error {
+	model := UserIdentityModel{
+		ID:       identity.ID,
+		UserID:   identity.UserID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+
+	if err := dbFromContext(ctx, r.db).Create(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return domainerrors.ErrIdentityAlreadyLinked
+		}
+		return err
+	}
+
+	identity.CreatedAt = time.Unix(model.CreatedAt, 0)
+	return nil
+}
+
+func (r *UserIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*entities.UserIdentity, error) {
+	var model UserIdentityModel
+
+	err := dbFromContext(ctx, r.db).
+		Where("provider = ? AND subject = ?", provider, subject).
+		First(&model).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrIdentityNotFound
+		}
+		return nil, err
+	}
+
+	return &entities.UserIdentity{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		Provider:  model.Provider,
+		Subject:   model.Subject,
+		Email:     model.Email,
+		CreatedAt: time.Unix(model.CreatedAt, 0),
+	}, nil
+}
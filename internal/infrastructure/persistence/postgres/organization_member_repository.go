@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+// activeOrganizationJoin restringe consultas de membros a organizações não removidas
+const activeOrganizationJoin = "JOIN organizations ON organizations.id = organization_members.organization_id AND organizations.deleted_at IS NULL"
+
+// OrganizationMemberRepository implementa repositories.OrganizationMemberRepository usando GORM
+// OrganizationMemberModel é um TenantModel com RLS: as consultas rodam em
+// transação (a do UnitOfWork ou uma própria) com app.current_org definido
+type OrganizationMemberRepository struct {
+	db *gorm.DB
+}
+
+// NewOrganizationMemberRepository cria um novo OrganizationMemberRepository
+func NewOrganizationMemberRepository(db *gorm.DB) repositories.OrganizationMemberRepository {
+	return &OrganizationMemberRepository{db: db}
+}
+
+func (r *OrganizationMemberRepository) Create(ctx context.Context, member *entities.OrganizationMember) error {
+	model := toOrganizationMemberModel(member)
+
+	err := withTenantTransaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.Create(model).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return domainerrors.ErrMemberAlreadyExists
+		}
+		return err
+	}
+
+	member.CreatedAt = time.Unix(model.CreatedAt, 0)
+	return nil
+}
+
+// FindByUserID retorna TODAS as organizações de um usuário
+// Query cross-organization: sem o filtro do TenantPlugin e, no RLS, liberada
+// pela policy member_self com app.current_user
+func (r *OrganizationMemberRepository) FindByUserID(ctx context.Context, userID string) ([]*entities.OrganizationMember, error) {
+	var models []OrganizationMemberModel
+
+	err := withTenantTransaction(ctx, r.db, func(tx *gorm.DB) error {
+		if err := setCurrentUser(tx, userID); err != nil {
+			return err
+		}
+
+		return crossTenant(tx).
+			Joins(activeOrganizationJoin).
+			Where("organization_members.user_id = ? AND organization_members.deleted_at IS NULL", userID).
+			Preload("Organization").
+			Order("organization_members.created_at").
+			Find(&models).
+			Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrganizationMemberEntities(models)
+}
+
+func (r *OrganizationMemberRepository) FindByUserAndOrganization(ctx context.Context, userID, organizationID string) (*entities.OrganizationMember, error) {
+	var model OrganizationMemberModel
+
+	err := withTenantTransaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Joins(activeOrganizationJoin).
+			Where("organization_members.user_id = ? AND organization_members.organization_id = ? AND organization_members.deleted_at IS NULL", userID, organizationID).
+			Preload("Organization").
+			First(&model).
+			Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrMemberNotFound
+		}
+		return nil, err
+	}
+
+	return toOrganizationMemberEntity(&model)
+}
+
+func (r *OrganizationMemberRepository) FindByOrganization(ctx context.Context, organizationID string) ([]*entities.OrganizationMember, error) {
+	var models []OrganizationMemberModel
+
+	err := withTenantTransaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Joins(activeOrganizationJoin).
+			Where("organization_members.organization_id = ? AND organization_members.deleted_at IS NULL", organizationID).
+			Preload("User").
+			Order("organization_members.created_at").
+			Find(&models).
+			Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrganizationMemberEntities(models)
+}
+
+func (r *OrganizationMemberRepository) CountByRole(ctx context.Context, organizationID string, role entities.Role) (int64, error) {
+	var count int64
+
+	err := withTenantTransaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Model(&OrganizationMemberModel{}).
+			Where("organization_id = ? AND role = ? AND deleted_at IS NULL", organizationID, string(role)).
+			Count(&count).
+			Error
+	})
+
+	return count, err
+}
+
+func (r *OrganizationMemberRepository) UpdateRole(ctx context.Context, id string, role entities.Role) error {
+	return r.update(ctx, id, "role", string(role))
+}
+
+func (r *OrganizationMemberRepository) Delete(ctx context.Context, id string) error {
+	return r.update(ctx, id, "deleted_at", time.Now().Unix())
+}
+
+// update altera uma coluna do membro; ErrMemberNotFound quando ele não existe
+func (r *OrganizationMemberRepository) update(ctx context.Context, id, column string, value interface{}) error {
+	return withTenantTransaction(ctx, r.db, func(tx *gorm.DB) error {
+		result := tx.
+			Model(&OrganizationMemberModel{}).
+			Where("id = ? AND deleted_at IS NULL", id).
+			Update(column, value)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domainerrors.ErrMemberNotFound
+		}
+
+		return nil
+	})
+}
+
+// toOrganizationMemberModel converte a entidade de domínio para o model GORM
+func toOrganizationMemberModel(member *entities.OrganizationMember) *OrganizationMemberModel {
+	return &OrganizationMemberModel{
+		TenantModel: TenantModel{OrganizationID: member.OrganizationID},
+		ID:          member.ID,
+		UserID:      member.UserID,
+		Role:        member.Role.String(),
+		InvitedBy:   member.InvitedBy,
+		InvitedAt:   member.InvitedAt.Unix(),
+		JoinedAt:    timePtrToUnix(member.JoinedAt),
+	}
+}
+
+// toOrganizationMemberEntity converte o model GORM para a entidade de domínio
+func toOrganizationMemberEntity(model *OrganizationMemberModel) (*entities.OrganizationMember, error) {
+	member := &entities.OrganizationMember{
+		ID:             model.ID,
+		OrganizationID: model.OrganizationID,
+		UserID:         model.UserID,
+		Role:           entities.Role(model.Role),
+		InvitedBy:      model.InvitedBy,
+		InvitedAt:      time.Unix(model.InvitedAt, 0),
+		JoinedAt:       unixToTimePtr(model.JoinedAt),
+		CreatedAt:      time.Unix(model.CreatedAt, 0),
+	}
+
+	if model.Organization != nil {
+		organization, err := toOrganizationEntity(model.Organization)
+		if err != nil {
+			return nil, err
+		}
+		member.Organization = organization
+	}
+
+	if model.User != nil {
+		user, err := toUserEntity(model.User)
+		if err != nil {
+			return nil, err
+		}
+		member.User = user
+	}
+
+	return member, nil
+}
+
+func toOrganizationMemberEntities(models []OrganizationMemberModel) ([]*entities.OrganizationMember, error) {
+	members := make([]*entities.OrganizationMember, 0, len(models))
+	for i := range models {
+		member, err := toOrganizationMemberEntity(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// timePtrToUnix converte um *time.Time opcional em timestamp
+func timePtrToUnix(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	ts := t.Unix()
+	return &ts
+}
@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+// OrganizationRepository implementa repositories.OrganizationRepository usando GORM
+type OrganizationRepository struct {
+	db *gorm.DB
+}
+
+// NewOrganizationRepository cria um novo OrganizationRepository
+func NewOrganizationRepository(db *gorm.DB) repositories.OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+func (r *OrganizationRepository) Create(ctx context.Context, organization *entities.Organization) error {
+	model, err := toOrganizationModel(organization)
+	if err != nil {
+		return err
+	}
+
+	if err := dbFromContext(ctx, r.db).Create(model).Error; err != nil {
+		return err
+	}
+
+	organization.CreatedAt = time.Unix(model.CreatedAt, 0)
+	organization.UpdatedAt = time.Unix(model.UpdatedAt, 0)
+	return nil
+}
+
+func (r *OrganizationRepository) FindByID(ctx context.Context, id string) (*entities.Organization, error) {
+	var model OrganizationModel
+
+	err := dbFromContext(ctx, r.db).
+		Where("id = ? AND deleted_at IS NULL", id).
+		First(&model).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+
+	return toOrganizationEntity(&model)
+}
+
+func (r *OrganizationRepository) Update(ctx context.Context, organization *entities.Organization) error {
+	origins, err := marshalOrigins(organization.AllowedOrigins)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	result := dbFromContext(ctx, r.db).
+		Model(&OrganizationModel{}).
+		Where("id = ? AND deleted_at IS NULL", organization.ID).
+		Updates(map[string]interface{}{
+			"name":            organization.Name,
+			"status":          string(organization.Status),
+			"require_mfa":     organization.RequireMFA,
+			"allowed_origins": origins,
+			"updated_at":      now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrOrganizationNotFound
+	}
+
+	organization.UpdatedAt = time.Unix(now, 0)
+	return nil
+}
+
+func (r *OrganizationRepository) Delete(ctx context.Context, id string) error {
+	result := dbFromContext(ctx, r.db).
+		Model(&OrganizationModel{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Update("deleted_at", time.Now().Unix())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrOrganizationNotFound
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepository) FindIDsByAllowedOrigin(ctx context.Context, origin string) ([]string, error) {
+	origins, err := marshalOrigins([]string{origin})
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	err = dbFromContext(ctx, r.db).
+		Model(&OrganizationModel{}).
+		Where("allowed_origins @> ?::jsonb AND status = ? AND deleted_at IS NULL", origins, string(entities.OrganizationStatusActive)).
+		Pluck("id", &ids).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// toOrganizationModel converte a entidade de domínio para o model GORM
+func toOrganizationModel(organization *entities.Organization) (*OrganizationModel, error) {
+	origins, err := marshalOrigins(organization.AllowedOrigins)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrganizationModel{
+		ID:             organization.ID,
+		Name:           organization.Name,
+		Status:         string(organization.Status),
+		RequireMFA:     organization.RequireMFA,
+		AllowedOrigins: origins,
+	}, nil
+}
+
+// toOrganizationEntity converte o model GORM para a entidade de domínio
+func toOrganizationEntity(model *OrganizationModel) (*entities.Organization, error) {
+	var origins []string
+	if model.AllowedOrigins != "" {
+		if err := json.Unmarshal([]byte(model.AllowedOrigins), &origins); err != nil {
+			return nil, err
+		}
+	}
+
+	return &entities.Organization{
+		ID:             model.ID,
+		Name:           model.Name,
+		Status:         entities.OrganizationStatus(model.Status),
+		RequireMFA:     model.RequireMFA,
+		AllowedOrigins: origins,
+		CreatedAt:      time.Unix(model.CreatedAt, 0),
+		UpdatedAt:      time.Unix(model.UpdatedAt, 0),
+	}, nil
+}
+
+// marshalOrigins serializa os origins para a coluna JSONB; nil vira lista vazia
+func marshalOrigins(origins []string) (string, error) {
+	if origins == nil {
+		origins = []string{}
+	}
+	data, err := json.Marshal(origins)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
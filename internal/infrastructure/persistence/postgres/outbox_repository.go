@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+// outboxTokenField é o campo dos emails com o token de ativação, redefinição
+// de senha ou convite, removido do payload das mensagens que falham de vez
+const outboxTokenField = "Token"
+
+// OutboxRepository implementa repositories.OutboxRepository usando GORM
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository cria um novo OutboxRepository
+func NewOutboxRepository(db *gorm.DB) repositories.OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+func (r *OutboxRepository) Create(ctx context.Context, msg *entities.OutboxMessage) error {
+	model := OutboxModel{
+		ID:            msg.ID,
+		Type:          msg.Type,
+		Payload:       string(msg.Payload),
+		NextAttemptAt: msg.NextAttemptAt.Unix(),
+	}
+
+	if err := dbFromContext(ctx, r.db).Create(&model).Error; err != nil {
+		return err
+	}
+
+	msg.CreatedAt = time.Unix(model.CreatedAt, 0)
+	return nil
+}
+
+func (r *OutboxRepository) ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.OutboxMessage, error) {
+	var models []OutboxModel
+
+	// SKIP LOCKED evita que instâncias concorrentes reservem as mesmas mensagens
+	err := dbFromContext(ctx, r.db).
+		Raw(`UPDATE outbox SET next_attempt_at = ?
+			WHERE id IN (
+				SELECT id FROM outbox
+				WHERE processed_at IS NULL AND failed_at IS NULL AND next_attempt_at <= ?
+				ORDER BY next_attempt_at
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *`,
+			now.Add(lease).Unix(), now.Unix(), limit,
+		).
+		Scan(&models).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*entities.OutboxMessage, len(models))
+	for i := range models {
+		messages[i] = toOutboxMessageEntity(&models[i])
+	}
+
+	return messages, nil
+}
+
+func (r *OutboxRepository) MarkProcessed(ctx context.Context, id string, processedAt time.Time) error {
+	return dbFromContext(ctx, r.db).
+		Model(&OutboxModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"processed_at": processedAt.Unix(),
+			"payload":      "{}",
+		}).
+		Error
+}
+
+func (r *OutboxRepository) MarkRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return dbFromContext(ctx, r.db).
+		Model(&OutboxModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        attempts,
+			"next_attempt_at": nextAttemptAt.Unix(),
+			"last_error":      lastError,
+		}).
+		Error
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id string, attempts int, failedAt time.Time, lastError string) error {
+	return dbFromContext(ctx, r.db).
+		Model(&OutboxModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":   attempts,
+			"failed_at":  failedAt.Unix(),
+			"last_error": lastError,
+			"payload":    gorm.Expr("payload - ?::text", outboxTokenField),
+		}).
+		Error
+}
+
+// toOutboxMessageEntity converte o model GORM para a entidade de domínio
+func toOutboxMessageEntity(model *OutboxModel) *entities.OutboxMessage {
+	return &entities.OutboxMessage{
+		ID:            model.ID,
+		Type:          model.Type,
+		Payload:       []byte(model.Payload),
+		Attempts:      model.Attempts,
+		NextAttemptAt: time.Unix(model.NextAttemptAt, 0),
+		LastError:     model.LastError,
+		ProcessedAt:   unixToTimePtr(model.ProcessedAt),
+		FailedAt:      unixToTimePtr(model.FailedAt),
+		CreatedAt:     time.Unix(model.CreatedAt, 0),
+	}
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestOutboxRepository_MarkFailed(t *testing.T) {
+	db := setupDryRunDB(t)
+
+	// Captura o SQL dos updates
+	var executed []*gorm.Statement
+	if err := db.Callback().Update().After("gorm:update").Register("test:capture", func(d *gorm.DB) {
+		executed = append(executed, d.Statement)
+	}); err != nil {
+		t.Fatalf("failed to register capture callback: %v", err)
+	}
+
+	repo := NewOutboxRepository(db)
+
+	t.Run("remove o token do payload na falha definitiva", func(t *testing.T) {
+		if err := repo.MarkFailed(context.Background(), "msg-1", 5, time.Now(), "smtp down"); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		if len(executed) != 1 {
+			t.Fatalf("esperava 1 comando, obteve %d", len(executed))
+		}
+
+		sql := executed[0].SQL.String()
+		if !strings.Contains(sql, `"payload"=payload - $`) {
+			t.Errorf("esperava o token removido do payload, SQL: %s", sql)
+		}
+
+		found := false
+		for _, v := range executed[0].Vars {
+			if v == outboxTokenField {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("esperava o campo '%s' nos parâmetros, obteve %v", outboxTokenField, executed[0].Vars)
+		}
+	})
+}
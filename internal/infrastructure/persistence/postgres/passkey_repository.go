@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+// PasskeyRepository implementa repositories.PasskeyRepository usando GORM
+type PasskeyRepository struct {
+	db *gorm.DB
+}
+
+// NewPasskeyRepository cria um novo PasskeyRepository
+func NewPasskeyRepository(db *gorm.DB) repositories.PasskeyRepository {
+	return &PasskeyRepository{db: db}
+}
+
+func (r *PasskeyRepository) Create(ctx context.Context, passkey *entities.Passkey) error {
+	transports, err := json.Marshal(passkey.Transports)
+	if err != nil {
+		return err
+	}
+
+	model := PasskeyModel{
+		ID:             passkey.ID,
+		UserID:         passkey.UserID,
+		CredentialID:   passkey.CredentialID,
+		PublicKey:      passkey.PublicKey,
+		SignCount:      int64(passkey.SignCount),
+		AAGUID:         passkey.AAGUID,
+		Transports:     string(transports),
+		Name:           passkey.Name,
+		BackupEligible: passkey.BackupEligible,
+		BackupState:    passkey.BackupState,
+	}
+
+	if err := dbFromContext(ctx, r.db).Create(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return domainerrors.ErrPasskeyAlreadyRegistered
+		}
+		return err
+	}
+
+	passkey.CreatedAt = time.Unix(model.CreatedAt, 0)
+	return nil
+}
+
+func (r *PasskeyRepository) FindByCredentialID(ctx context.Context, credentialID string) (*entities.Passkey, error) {
+	var model PasskeyModel
+
+	err := dbFromContext(ctx, r.db).
+		Where("credential_id = ?", credentialID).
+		First(&model).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrPasskeyNotFound
+		}
+		return nil, err
+	}
+
+	return toPasskeyEntity(&model)
+}
+
+func (r *PasskeyRepository) ListByUser(ctx context.Context, userID string) ([]*entities.Passkey, error) {
+	var models []PasskeyModel
+
+	err := dbFromContext(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&models).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	passkeys := make([]*entities.Passkey, 0, len(models))
+	for i := range models {
+		passkey, err := toPasskeyEntity(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		passkeys = append(passkeys, passkey)
+	}
+
+	return passkeys, nil
+}
+
+func (r *PasskeyRepository) UpdateUsage(ctx context.Context, id string, previousSignCount, signCount uint32, backupState bool) error {
+	// O filtro pelo contador anterior impede que a mesma assinatura seja aceita duas vezes
+	result := dbFromContext(ctx, r.db).
+		Model(&PasskeyModel{}).
+		Where("id = ? AND sign_count = ?", id, int64(previousSignCount)).
+		Updates(map[string]interface{}{
+			"sign_count":   int64(signCount),
+			"backup_state": backupState,
+			"last_used_at": time.Now().Unix(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrInvalidPasskey
+	}
+
+	return nil
+}
+
+func (r *PasskeyRepository) Delete(ctx context.Context, userID, id string) error {
+	result := dbFromContext(ctx, r.db).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&PasskeyModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrPasskeyNotFound
+	}
+
+	return nil
+}
+
+// toPasskeyEntity converte o model GORM para a entidade de domínio
+func toPasskeyEntity(model *PasskeyModel) (*entities.Passkey, error) {
+	var transports []string
+	if err := json.Unmarshal([]byte(model.Transports), &transports); err != nil {
+		return nil, err
+	}
+
+	return &entities.Passkey{
+		ID:             model.ID,
+		UserID:         model.UserID,
+		CredentialID:   model.CredentialID,
+		PublicKey:      model.PublicKey,
+		SignCount:      uint32(model.SignCount),
+		AAGUID:         model.AAGUID,
+		Transports:     transports,
+		Name:           model.Name,
+		BackupEligible: model.BackupEligible,
+		BackupState:    model.BackupState,
+		LastUsedAt:     unixToTimePtr(model.LastUsedAt),
+		CreatedAt:      time.Unix(model.CreatedAt, 0),
+	}, nil
+}
+
+// PasskeyChallengeRepository implementa repositories.PasskeyChallengeRepository usando GORM
+type PasskeyChallengeRepository struct {
+	db *gorm.DB
+}
+
+// NewPasskeyChallengeRepository cria um novo PasskeyChallengeRepository
+func NewPasskeyChallengeRepository(db *gorm.DB) repositories.PasskeyChallengeRepository {
+	return &PasskeyChallengeRepository{db: db}
+}
+
+func (r *PasskeyChallengeRepository) Create(ctx context.Context, challenge *entities.PasskeyChallenge) error {
+	model := PasskeyChallengeModel{
+		ID:            challenge.ID,
+		Ceremony:      challenge.Ceremony,
+		ChallengeHash: challenge.ChallengeHash,
+		ExpiresAt:     challenge.ExpiresAt.Unix(),
+	}
+	if challenge.UserID != "" {
+		model.UserID = &challenge.UserID
+	}
+
+	if err := dbFromContext(ctx, r.db).Create(&model).Error; err != nil {
+		return err
+	}
+
+	challenge.CreatedAt = time.Unix(model.CreatedAt, 0)
+	return nil
+}
+
+func (r *PasskeyChallengeRepository) Consume(ctx context.Context, ceremony, challengeHash string) (*entities.PasskeyChallenge, error) {
+	var models []PasskeyChallengeModel
+
+	// O UPDATE condicional garante que duas respostas com o mesmo challenge não passem juntas
+	result := dbFromContext(ctx, r.db).
+		Model(&models).
+		Clauses(clause.Returning{}).
+		Where("ceremony = ? AND challenge_hash = ? AND used_at IS NULL", ceremony, challengeHash).
+		Update("used_at", time.Now().Unix())
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if len(models) == 0 {
+		return nil, domainerrors.ErrInvalidPasskey
+	}
+
+	challenge := &entities.PasskeyChallenge{
+		ID:            models[0].ID,
+		Ceremony:      models[0].Ceremony,
+		ChallengeHash: models[0].ChallengeHash,
+		ExpiresAt:     time.Unix(models[0].ExpiresAt, 0),
+		UsedAt:        unixToTimePtr(models[0].UsedAt),
+		CreatedAt:     time.Unix(models[0].CreatedAt, 0),
+	}
+	if models[0].UserID != nil {
+		challenge.UserID = *models[0].UserID
+	}
+
+	return challenge, nil
+}
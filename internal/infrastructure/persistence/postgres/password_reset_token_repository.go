@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+// PasswordResetTokenRepository implementa repositories.PasswordResetTokenRepository usando GORM
+type PasswordResetTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetTokenRepository cria um novo PasswordResetTokenRepository
+func NewPasswordResetTokenRepository(db *gorm.DB) repositories.PasswordResetTokenRepository {
+	return &PasswordResetTokenRepository{db: db}
+}
+
+func (r *PasswordResetTokenRepository) Create(ctx context.Context, token *entities.PasswordResetToken) error {
+	model := PasswordResetTokenModel{
+		ID:        token.ID,
+		UserID:    token.UserID,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt.Unix(),
+	}
+
+	if err := dbFromContext(ctx, r.db).Create(&model).Error; err != nil {
+		return err
+	}
+
+	token.CreatedAt = time.Unix(model.CreatedAt, 0)
+	return nil
+}
+
+func (r *PasswordResetTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entities.PasswordResetToken, error) {
+	var model PasswordResetTokenModel
+
+	err := dbFromContext(ctx, r.db).
+		Where("token_hash = ?", tokenHash).
+		First(&model).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrInvalidPasswordResetToken
+		}
+		return nil, err
+	}
+
+	return toPasswordResetTokenEntity(&model), nil
+}
+
+func (r *PasswordResetTokenRepository) MarkAsUsed(ctx context.Context, id string) error {
+	// O filtro por used_at/revoked_at garante que o token redefina a senha uma única vez
+	result := dbFromContext(ctx, r.db).
+		Model(&PasswordResetTokenModel{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", time.Now().Unix())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrInvalidPasswordResetToken
+	}
+
+	return nil
+}
+
+func (r *PasswordResetTokenRepository) RevokeByUser(ctx context.Context, userID string) error {
+	return dbFromContext(ctx, r.db).
+		Model(&PasswordResetTokenModel{}).
+		Where("user_id = ? AND used_at IS NULL AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now().Unix()).
+		Error
+}
+
+func (r *PasswordResetTokenRepository) CountCreatedSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	var count int64
+
+	err := dbFromContext(ctx, r.db).
+		Model(&PasswordResetTokenModel{}).
+		Where("user_id = ? AND created_at >= ?", userID, since.Unix()).
+		Count(&count).
+		Error
+
+	return count, err
+}
+
+// toPasswordResetTokenEntity converte o model GORM para a entidade de domínio
+func toPasswordResetTokenEntity(model *PasswordResetTokenModel) *entities.PasswordResetToken {
+	return &entities.PasswordResetToken{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		TokenHash: model.TokenHash,
+		ExpiresAt: time.Unix(model.ExpiresAt, 0),
+		UsedAt:    unixToTimePtr(model.UsedAt),
+		RevokedAt: unixToTimePtr(model.RevokedAt),
+		CreatedAt: time.Unix(model.CreatedAt, 0),
+	}
+}
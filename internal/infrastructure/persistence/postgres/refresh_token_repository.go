@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+// RefreshTokenRepository implementa repositories.RefreshTokenRepository usando GORM
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenRepository cria um novo RefreshTokenRepository
+func NewRefreshTokenRepository(db *gorm.DB) repositories.RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *entities.RefreshToken) error {
+	model := RefreshTokenModel{
+		ID:        token.ID,
+		UserID:    token.UserID,
+		FamilyID:  token.FamilyID,
+		TokenHash: token.TokenHash,
+		IPAddress: token.IPAddress,
+		UserAgent: token.UserAgent,
+		ExpiresAt: token.ExpiresAt.Unix(),
+	}
+
+	if err := dbFromContext(ctx, r.db).Create(&model).Error; err != nil {
+		return err
+	}
+
+	token.CreatedAt = time.Unix(model.CreatedAt, 0)
+	return nil
+}
+
+func (r *RefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	var model RefreshTokenModel
+
+	err := dbFromContext(ctx, r.db).
+		Where("token_hash = ?", tokenHash).
+		First(&model).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	return toRefreshTokenEntity(&model), nil
+}
+
+func (r *RefreshTokenRepository) MarkAsUsed(ctx context.Context, id string) error {
+	// O filtro por used_at/revoked_at garante que apenas uma requisição
+	// concorrente consiga consumir o token
+	result := dbFromContext(ctx, r.db).
+		Model(&RefreshTokenModel{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", time.Now().Unix())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrRefreshTokenReused
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	return dbFromContext(ctx, r.db).
+		Model(&RefreshTokenModel{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now().Unix()).
+		Error
+}
+
+func (r *RefreshTokenRepository) RevokeByUser(ctx context.Context, userID string) error {
+	return dbFromContext(ctx, r.db).
+		Model(&RefreshTokenModel{}).
+		Where("user_id = ? AND used_at IS NULL AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now().Unix()).
+		Error
+}
+
+// sessionRow é o resultado da consulta de sessões ativas
+type sessionRow struct {
+	FamilyID   string
+	IPAddress  string
+	UserAgent  string
+	CreatedAt  int64
+	LastUsedAt int64
+	ExpiresAt  int64
+}
+
+func (r *RefreshTokenRepository) ListSessions(ctx context.Context, userID string) ([]*entities.Session, error) {
+	var rows []sessionRow
+
+	// Cada família ativa tem exatamente um token não usado e não revogado;
+	// o início da sessão é o token mais antigo da família
+	err := dbFromContext(ctx, r.db).
+		Table("refresh_tokens AS t").
+		Select(`t.family_id, t.ip_address, t.user_agent, t.expires_at,
+			t.created_at AS last_used_at,
+			(SELECT MIN(f.created_at) FROM refresh_tokens f WHERE f.family_id = t.family_id) AS created_at`).
+		Where("t.user_id = ? AND t.used_at IS NULL AND t.revoked_at IS NULL AND t.expires_at > ?", userID, time.Now().Unix()).
+		Order("t.created_at DESC").
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*entities.Session, 0, len(rows))
+	for _, row := range rows {
+		sessions = append(sessions, &entities.Session{
+			ID:         row.FamilyID,
+			UserID:     userID,
+			IPAddress:  row.IPAddress,
+			UserAgent:  row.UserAgent,
+			CreatedAt:  time.Unix(row.CreatedAt, 0),
+			LastUsedAt: time.Unix(row.LastUsedAt, 0),
+			ExpiresAt:  time.Unix(row.ExpiresAt, 0),
+		})
+	}
+
+	return sessions, nil
+}
+
+func (r *RefreshTokenRepository) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	// Tokens já usados não são aceitos novamente; basta revogar o token ativo
+	result := dbFromContext(ctx, r.db).
+		Model(&RefreshTokenModel{}).
+		Where("user_id = ? AND family_id = ? AND used_at IS NULL AND revoked_at IS NULL AND expires_at > ?",
+			userID, sessionID, time.Now().Unix()).
+		Update("revoked_at", time.Now().Unix())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// toRefreshTokenEntity converte o model GORM para a entidade de domínio
+func toRefreshTokenEntity(model *RefreshTokenModel) *entities.RefreshToken {
+	return &entities.RefreshToken{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		FamilyID:  model.FamilyID,
+		TokenHash: model.TokenHash,
+		IPAddress: model.IPAddress,
+		UserAgent: model.UserAgent,
+		ExpiresAt: time.Unix(model.ExpiresAt, 0),
+		UsedAt:    unixToTimePtr(model.UsedAt),
+		RevokedAt: unixToTimePtr(model.RevokedAt),
+		CreatedAt: time.Unix(model.CreatedAt, 0),
+	}
+}
+
+// unixToTimePtr converte um timestamp opcional em *time.Time
+func unixToTimePtr(ts *int64) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := time.Unix(*ts, 0)
+	return &t
+}
@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+// SSOConfigRepository implementa repositories.SSOConfigRepository usando GORM
+// SSOConfigModel é um TenantModel: o TenantPlugin aplica o filtro de organização
+type SSOConfigRepository struct {
+	db *gorm.DB
+}
+
+// NewSSOConfigRepository cria um novo SSOConfigRepository
+func NewSSOConfigRepository(db *gorm.DB) repositories.SSOConfigRepository {
+	return &SSOConfigRepository{db: db}
+}
+
+func (r *SSOConfigRepository) Find(ctx context.Context) (*entities.SSOConfig, error) {
+	var model SSOConfigModel
+
+	if err := dbFromContext(ctx, r.db).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrSSONotConfigured
+		}
+		return nil, err
+	}
+
+	return toSSOConfigEntity(&model)
+}
+
+func (r *SSOConfigRepository) Save(ctx context.Context, config *entities.SSOConfig) error {
+	model, err := toSSOConfigModel(config)
+	if err != nil {
+		return err
+	}
+
+	// Uma configuração por organização: salvar de novo substitui a anterior
+	err = dbFromContext(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "organization_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"issuer", "client_id", "client_secret", "allowed_domains", "verified_domains",
+				"domain_verification_token", "default_role", "enabled", "updated_at",
+			}),
+		}).
+		Create(model).
+		Error
+	if err != nil {
+		return err
+	}
+
+	config.OrganizationID = model.OrganizationID
+	config.UpdatedAt = time.Unix(model.UpdatedAt, 0)
+	if config.CreatedAt.IsZero() {
+		config.CreatedAt = time.Unix(model.CreatedAt, 0)
+	}
+	return nil
+}
+
+func (r *SSOConfigRepository) Delete(ctx context.Context) error {
+	// O TenantPlugin restringe o DELETE à organização do contexto
+	result := dbFromContext(ctx, r.db).Delete(&SSOConfigModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrSSONotConfigured
+	}
+
+	return nil
+}
+
+// toSSOConfigModel converte a entidade de domínio para o model GORM
+func toSSOConfigModel(config *entities.SSOConfig) (*SSOConfigModel, error) {
+	domains, err := json.Marshal(config.AllowedDomains)
+	if err != nil {
+		return nil, err
+	}
+
+	verified := config.VerifiedDomains
+	if verified == nil {
+		verified = []string{}
+	}
+	verifiedDomains, err := json.Marshal(verified)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SSOConfigModel{
+		TenantModel:             TenantModel{OrganizationID: config.OrganizationID},
+		ID:                      config.ID,
+		Issuer:                  config.Issuer,
+		ClientID:                config.ClientID,
+		ClientSecret:            config.ClientSecret,
+		AllowedDomains:          string(domains),
+		VerifiedDomains:         string(verifiedDomains),
+		DomainVerificationToken: config.DomainVerificationToken,
+		DefaultRole:             string(config.DefaultRole),
+		Enabled:                 config.Enabled,
+	}, nil
+}
+
+// toSSOConfigEntity converte o model GORM para a entidade de domínio
+func toSSOConfigEntity(model *SSOConfigModel) (*entities.SSOConfig, error) {
+	var domains, verified []string
+	if err := json.Unmarshal([]byte(model.AllowedDomains), &domains); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(model.VerifiedDomains), &verified); err != nil {
+		return nil, err
+	}
+
+	return &entities.SSOConfig{
+		ID:                      model.ID,
+		OrganizationID:          model.OrganizationID,
+		Issuer:                  model.Issuer,
+		ClientID:                model.ClientID,
+		ClientSecret:            model.ClientSecret,
+		AllowedDomains:          domains,
+		VerifiedDomains:         verified,
+		DomainVerificationToken: model.DomainVerificationToken,
+		DefaultRole:             entities.Role(model.DefaultRole),
+		Enabled:                 model.Enabled,
+		CreatedAt:               time.Unix(model.CreatedAt, 0),
+		UpdatedAt:               time.Unix(model.UpdatedAt, 0),
+	}, nil
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+)
+
+// TenantModel deve ser embutido nos models de tabelas de negócio (com organization_id)
+// Models que o embutem são isolados automaticamente pelo TenantPlugin
+type TenantModel struct {
+	OrganizationID string `gorm:"type:uuid;not null;index"`
+}
+
+func (TenantModel) tenantScoped() {}
+
+// tenantScoped identifica models isolados por organização
+type tenantScoped interface {
+	tenantScoped()
+}
+
+// TenantPlugin isola por organização todas as operações em models TenantModel
+//
+// A organização vem de domain.OrganizationIDFromContext, usando o contexto da
+// query (dbFromContext propaga o contexto também para transações do UnitOfWork):
+//   - SELECT, UPDATE e DELETE recebem "organization_id = ?" automaticamente
+//   - CREATE preenche organization_id e rejeita registros de outra organização
+//   - sem organização no contexto, a operação falha com ErrMissingOrganization
+//
+// Queries Raw/Exec não passam pelo plugin e não devem ser usadas em tabelas de negócio.
+// Como segunda linha de defesa, tabelas de negócio também têm RLS no Postgres
+// (enable_tenant_rls), que depende do app.current_org definido pelo UnitOfWork:
+// acesse essas tabelas dentro de UnitOfWork.WithTransaction.
+type TenantPlugin struct{}
+
+// Name implementa gorm.Plugin
+func (TenantPlugin) Name() string {
+	return "avantpro:tenant"
+}
+
+// Initialize implementa gorm.Plugin
+func (TenantPlugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("tenant:create", assignTenant); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("tenant:query", scopeTenant); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("tenant:update", scopeTenant); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("tenant:delete", scopeTenant); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("tenant:row", scopeTenant); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// scopeTenant adiciona o filtro de organização em queries de models isolados
+func scopeTenant(db *gorm.DB) {
+	field, ok := tenantField(db)
+	if !ok {
+		return
+	}
+
+	organizationID, ok := domain.OrganizationIDFromContext(db.Statement.Context)
+	if !ok {
+		_ = db.AddError(domainerrors.ErrMissingOrganization)
+		return
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: organizationID},
+	}})
+}
+
+// assignTenant preenche organization_id em inserts de models isolados
+func assignTenant(db *gorm.DB) {
+	field, ok := tenantField(db)
+	if !ok {
+		return
+	}
+
+	organizationID, ok := domain.OrganizationIDFromContext(db.Statement.Context)
+	if !ok {
+		_ = db.AddError(domainerrors.ErrMissingOrganization)
+		return
+	}
+
+	ctx := db.Statement.Context
+	assign := func(rv reflect.Value) {
+		value, zero := field.ValueOf(ctx, rv)
+		if zero {
+			if err := field.Set(ctx, rv, organizationID); err != nil {
+				_ = db.AddError(err)
+			}
+			return
+		}
+		if value != organizationID {
+			_ = db.AddError(domainerrors.ErrCrossTenantAccess)
+		}
+	}
+
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			assign(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		assign(rv)
+	}
+}
+
+// crossTenantSetting marca queries que atravessam organizações de propósito
+const crossTenantSetting = "tenant:cross"
+
+// crossTenant libera a query do filtro de organização do TenantPlugin
+// Use só em consultas já restritas de outra forma (ex: pelo usuário); o RLS
+// continua valendo no Postgres
+func crossTenant(db *gorm.DB) *gorm.DB {
+	return db.Set(crossTenantSetting, true)
+}
+
+// tenantField retorna o campo organization_id quando o model da query é isolado
+func tenantField(db *gorm.DB) (*schema.Field, bool) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return nil, false
+	}
+
+	if cross, _ := db.Get(crossTenantSetting); cross == true {
+		return nil, false
+	}
+
+	if _, ok := reflect.New(db.Statement.Schema.ModelType).Interface().(tenantScoped); !ok {
+		return nil, false
+	}
+
+	field := db.Statement.Schema.LookUpField("OrganizationID")
+	return field, field != nil
+}
@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+)
+
+// noteModel é um model de negócio usado apenas nos testes de isolamento
+type noteModel struct {
+	ID string `gorm:"type:uuid;primary_key"`
+	TenantModel
+	Title string
+}
+
+func (noteModel) TableName() string {
+	return "notes"
+}
+
+// setupDryRunDB cria um *gorm.DB que gera o SQL sem executá-lo
+func setupDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true, // writes não abrem transação (e conexão) no dry run
+		Logger:                 logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("failed to open dry run db: %v", err)
+	}
+
+	if err := db.Use(TenantPlugin{}); err != nil {
+		t.Fatalf("failed to register tenant plugin: %v", err)
+	}
+
+	return db
+}
+
+// assertScopedTo verifica se a query gerada filtra pela organização informada
+func assertScopedTo(t *testing.T, stmt *gorm.Statement, organizationID string) {
+	t.Helper()
+
+	sql := stmt.SQL.String()
+	if !strings.Contains(sql, `"notes"."organization_id" = $`) {
+		t.Fatalf("esperava filtro por organization_id, SQL: %s", sql)
+	}
+
+	for _, v := range stmt.Vars {
+		if v == organizationID {
+			return
+		}
+	}
+	t.Errorf("esperava organização '%s' nos parâmetros, obteve %v", organizationID, stmt.Vars)
+}
+
+func TestTenantPlugin_Query(t *testing.T) {
+	db := setupDryRunDB(t)
+	orgA := domain.WithOrganizationID(context.Background(), "org-a")
+
+	t.Run("filtra leituras pela organização do contexto", func(t *testing.T) {
+		var notes []noteModel
+		result := dbFromContext(orgA, db).Where("title = ?", "x").Find(&notes)
+		if result.Error != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", result.Error)
+		}
+
+		assertScopedTo(t, result.Statement, "org-a")
+	})
+
+	t.Run("busca por ID de outra organização continua filtrada", func(t *testing.T) {
+		var note noteModel
+		result := dbFromContext(orgA, db).Where("id = ?", "note-of-org-b").First(&note)
+
+		assertScopedTo(t, result.Statement, "org-a")
+	})
+
+	t.Run("recusa leitura sem organização no contexto", func(t *testing.T) {
+		var notes []noteModel
+		err := dbFromContext(context.Background(), db).Find(&notes).Error
+		if !errors.Is(err, domainerrors.ErrMissingOrganization) {
+			t.Errorf("esperava ErrMissingOrganization, obteve %v", err)
+		}
+	})
+
+	t.Run("recusa contagem sem organização no contexto", func(t *testing.T) {
+		var count int64
+		err := dbFromContext(context.Background(), db).Model(&noteModel{}).Count(&count).Error
+		if !errors.Is(err, domainerrors.ErrMissingOrganization) {
+			t.Errorf("esperava ErrMissingOrganization, obteve %v", err)
+		}
+	})
+
+	t.Run("consulta cross-organization dispensa o filtro", func(t *testing.T) {
+		var notes []noteModel
+		result := crossTenant(dbFromContext(orgA, db)).Where("title = ?", "x").Find(&notes)
+		if result.Error != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", result.Error)
+		}
+
+		if strings.Contains(result.Statement.SQL.String(), "organization_id") {
+			t.Errorf("não esperava filtro de organização, SQL: %s", result.Statement.SQL.String())
+		}
+	})
+
+	t.Run("não altera models globais", func(t *testing.T) {
+		var users []UserModel
+		result := dbFromContext(context.Background(), db).Find(&users)
+		if result.Error != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", result.Error)
+		}
+
+		if strings.Contains(result.Statement.SQL.String(), "organization_id") {
+			t.Errorf("não esperava filtro de organização em users, SQL: %s", result.Statement.SQL.String())
+		}
+	})
+}
+
+func TestTenantPlugin_Write(t *testing.T) {
+	db := setupDryRunDB(t)
+	orgA := domain.WithOrganizationID(context.Background(), "org-a")
+
+	t.Run("preenche organization_id no insert", func(t *testing.T) {
+		note := noteModel{ID: "n1", Title: "x"}
+		if err := dbFromContext(orgA, db).Create(&note).Error; err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		if note.OrganizationID != "org-a" {
+			t.Errorf("esperava organization_id 'org-a', obteve '%s'", note.OrganizationID)
+		}
+	})
+
+	t.Run("recusa insert para outra organização", func(t *testing.T) {
+		notes := []noteModel{
+			{ID: "n1", Title: "x"},
+			{ID: "n2", Title: "y", TenantModel: TenantModel{OrganizationID: "org-b"}},
+		}
+
+		err := dbFromContext(orgA, db).Create(&notes).Error
+		if !errors.Is(err, domainerrors.ErrCrossTenantAccess) {
+			t.Errorf("esperava ErrCrossTenantAccess, obteve %v", err)
+		}
+	})
+
+	t.Run("filtra updates pela organização do contexto", func(t *testing.T) {
+		result := dbFromContext(orgA, db).
+			Model(&noteModel{}).
+			Where("id = ?", "note-of-org-b").
+			Update("title", "hijacked")
+
+		assertScopedTo(t, result.Statement, "org-a")
+	})
+
+	t.Run("filtra deletes pela organização do contexto", func(t *testing.T) {
+		result := dbFromContext(orgA, db).Where("id = ?", "note-of-org-b").Delete(&noteModel{})
+
+		assertScopedTo(t, result.Statement, "org-a")
+	})
+
+	t.Run("recusa update sem organização no contexto", func(t *testing.T) {
+		err := dbFromContext(context.Background(), db).
+			Model(&noteModel{}).
+			Where("id = ?", "n1").
+			Update("title", "x").
+			Error
+		if !errors.Is(err, domainerrors.ErrMissingOrganization) {
+			t.Errorf("esperava ErrMissingOrganization, obteve %v", err)
+		}
+	})
+}
+
+func TestTenantPlugin_Transaction(t *testing.T) {
+	db := setupDryRunDB(t)
+
+	// Simula a transação que o UnitOfWork armazena no contexto
+	tx := db.Session(&gorm.Session{})
+	ctx := context.WithValue(context.Background(), txKey, tx)
+
+	t.Run("aplica o isolamento na transação do contexto", func(t *testing.T) {
+		var notes []noteModel
+		result := dbFromContext(domain.WithOrganizationID(ctx, "org-a"), nil).Find(&notes)
+		if result.Error != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", result.Error)
+		}
+
+		assertScopedTo(t, result.Statement, "org-a")
+	})
+
+	t.Run("recusa query na transação sem organização", func(t *testing.T) {
+		var notes []noteModel
+		err := dbFromContext(ctx, nil).Find(&notes).Error
+		if !errors.Is(err, domainerrors.ErrMissingOrganization) {
+			t.Errorf("esperava ErrMissingOrganization, obteve %v", err)
+		}
+	})
+}
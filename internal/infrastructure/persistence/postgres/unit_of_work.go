@@ -24,7 +24,16 @@ func NewUnitOfWork(db *gorm.DB) domain.UnitOfWork {
 }
 
 func (uow *UnitOfWork) Begin(ctx context.Context) (context.Context, error) {
-	tx := uow.db.Begin()
+	tx := uow.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return ctx, tx.Error
+	}
+
+	if err := setCurrentOrganization(ctx, tx); err != nil {
+		tx.Rollback()
+		return ctx, err
+	}
+
 	return context.WithValue(ctx, txKey, tx), nil
 }
 
@@ -45,7 +54,15 @@ func (uow *UnitOfWork) Rollback(ctx context.Context) error {
 }
 
 func (uow *UnitOfWork) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
-	tx := uow.db.Begin()
+	tx := uow.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := setCurrentOrganization(ctx, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
 
 	txCtx := context.WithValue(ctx, txKey, tx)
 
@@ -58,3 +75,44 @@ func (uow *UnitOfWork) WithTransaction(ctx context.Context, fn func(context.Cont
 
 	return tx.Commit().Error
 }
+
+// dbFromContext retorna a transação armazenada no contexto ou a conexão padrão
+func dbFromContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// withTenantTransaction executa fn na transação do contexto ou, fora do
+// UnitOfWork, numa transação própria com app.current_org definido: tabelas com
+// RLS não retornam nenhuma linha sem ele
+func withTenantTransaction(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if tx, ok := ctx.Value(txKey).(*gorm.DB); ok {
+		return fn(tx.WithContext(ctx))
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setCurrentOrganization(ctx, tx); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// setCurrentUser define app.current_user na transação, usado pela policy que
+// libera ao usuário as próprias associações em todas as organizações
+func setCurrentUser(tx *gorm.DB, userID string) error {
+	return tx.Exec("SELECT set_config('app.current_user', ?, true)", userID).Error
+}
+
+// setCurrentOrganization define app.current_org na transação, usado pelas policies de RLS
+// set_config(..., true) equivale a SET LOCAL, mas aceita o valor como parâmetro
+func setCurrentOrganization(ctx context.Context, tx *gorm.DB) error {
+	organizationID, ok := domain.OrganizationIDFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	return tx.Exec("SELECT set_config('app.current_org', ?, true)", organizationID).Error
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+func TestSetCurrentOrganization(t *testing.T) {
+	db := setupDryRunDB(t)
+
+	// Captura o SQL executado via Exec
+	var executed []*gorm.Statement
+	if err := db.Callback().Raw().After("gorm:raw").Register("test:capture", func(d *gorm.DB) {
+		executed = append(executed, d.Statement)
+	}); err != nil {
+		t.Fatalf("failed to register capture callback: %v", err)
+	}
+
+	t.Run("define app.current_org com a organização do contexto", func(t *testing.T) {
+		executed = nil
+		ctx := domain.WithOrganizationID(context.Background(), "org-a")
+
+		if err := setCurrentOrganization(ctx, db.Session(&gorm.Session{})); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		if len(executed) != 1 {
+			t.Fatalf("esperava 1 comando, obteve %d", len(executed))
+		}
+
+		sql := executed[0].SQL.String()
+		if !strings.Contains(sql, "set_config('app.current_org', $1, true)") {
+			t.Errorf("esperava set_config local de app.current_org, SQL: %s", sql)
+		}
+		if len(executed[0].Vars) != 1 || executed[0].Vars[0] != "org-a" {
+			t.Errorf("esperava parâmetro 'org-a', obteve %v", executed[0].Vars)
+		}
+	})
+
+	t.Run("não altera a sessão sem organização no contexto", func(t *testing.T) {
+		executed = nil
+
+		if err := setCurrentOrganization(context.Background(), db.Session(&gorm.Session{})); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		if len(executed) != 0 {
+			t.Errorf("não esperava comandos, obteve %d", len(executed))
+		}
+	})
+}
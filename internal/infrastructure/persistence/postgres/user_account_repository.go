@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+// UserAccountRepository implementa repositories.UserAccountRepository usando GORM
+type UserAccountRepository struct {
+	db *gorm.DB
+}
+
+// NewUserAccountRepository cria um novo UserAccountRepository
+func NewUserAccountRepository(db *gorm.DB) repositories.UserAccountRepository {
+	return &UserAccountRepository{db: db}
+}
+
+func (r *UserAccountRepository) Create(ctx context.Context, account *entities.UserAccount) error {
+	model := toUserAccountModel(account)
+
+	if err := dbFromContext(ctx, r.db).Create(model).Error; err != nil {
+		return err
+	}
+
+	account.CreatedAt = time.Unix(model.CreatedAt, 0)
+	account.UpdatedAt = time.Unix(model.UpdatedAt, 0)
+	return nil
+}
+
+func (r *UserAccountRepository) FindByUserID(ctx context.Context, userID string) (*entities.UserAccount, error) {
+	var model UserAccountModel
+
+	err := dbFromContext(ctx, r.db).
+		Where("user_id = ? AND deleted_at IS NULL", userID).
+		First(&model).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrUserAccountNotFound
+		}
+		return nil, err
+	}
+
+	return toUserAccountEntity(&model), nil
+}
+
+// toUserAccountModel converte a entidade de domínio para o model GORM
+func toUserAccountModel(account *entities.UserAccount) *UserAccountModel {
+	return &UserAccountModel{
+		ID:        account.ID,
+		UserID:    account.UserID,
+		FullName:  account.FullName,
+		AvatarURL: account.AvatarURL,
+		Phone:     account.Phone,
+		Locale:    account.Locale,
+		Timezone:  account.Timezone,
+		Theme:     account.Theme,
+	}
+}
+
+// toUserAccountEntity converte o model GORM para a entidade de domínio
+func toUserAccountEntity(model *UserAccountModel) *entities.UserAccount {
+	return &entities.UserAccount{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		FullName:  model.FullName,
+		AvatarURL: model.AvatarURL,
+		Phone:     model.Phone,
+		Locale:    model.Locale,
+		Timezone:  model.Timezone,
+		Theme:     model.Theme,
+		CreatedAt: time.Unix(model.CreatedAt, 0),
+		UpdatedAt: time.Unix(model.UpdatedAt, 0),
+	}
+}
@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+)
+
+// UserRepository implementa repositories.UserRepository usando GORM
+type UserRepository struct {
+	db *gorm.DB
+}
+
+// NewUserRepository cria um novo UserRepository
+func NewUserRepository(db *gorm.DB) repositories.UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	model := toUserModel(user)
+
+	if err := dbFromContext(ctx, r.db).Create(model).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return domainerrors.ErrEmailAlreadyExists
+		}
+		return err
+	}
+
+	user.CreatedAt = time.Unix(model.CreatedAt, 0)
+	user.UpdatedAt = time.Unix(model.UpdatedAt, 0)
+	return nil
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id string) (*entities.User, error) {
+	var model UserModel
+
+	err := dbFromContext(ctx, r.db).
+		Where("id = ? AND deleted_at IS NULL", id).
+		First(&model).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return toUserEntity(&model)
+}
+
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entities.User, error) {
+	var model UserModel
+
+	err := dbFromContext(ctx, r.db).
+		Where("email = ? AND deleted_at IS NULL", email).
+		First(&model).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return toUserEntity(&model)
+}
+
+func (r *UserRepository) Activate(ctx context.Context, id string, verifiedAt time.Time) error {
+	result := dbFromContext(ctx, r.db).
+		Model(&UserModel{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"status":            string(entities.UserStatusActive),
+			"email_verified_at": verifiedAt.Unix(),
+			"updated_at":        time.Now().Unix(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) UpdatePasswordHash(ctx context.Context, id, passwordHash string) error {
+	result := dbFromContext(ctx, r.db).
+		Model(&UserModel{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"password_hash": passwordHash,
+			"updated_at":    time.Now().Unix(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// toUserModel converte a entidade de domínio para o model GORM
+func toUserModel(user *entities.User) *UserModel {
+	return &UserModel{
+		ID:              user.ID,
+		Email:           user.Email.String(),
+		Name:            user.Name,
+		PasswordHash:    user.PasswordHash,
+		Role:            string(user.Role),
+		Status:          string(user.Status),
+		AvatarURL:       user.AvatarURL,
+		EmailVerifiedAt: timePtrToUnix(user.EmailVerifiedAt),
+	}
+}
+
+// toUserEntity converte o model GORM para a entidade de domínio
+func toUserEntity(model *UserModel) (*entities.User, error) {
+	email, err := valueobjects.NewEmail(model.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.User{
+		ID:              model.ID,
+		Email:           email,
+		Name:            model.Name,
+		PasswordHash:    model.PasswordHash,
+		Role:            entities.Role(model.Role),
+		Status:          entities.UserStatus(model.Status),
+		AvatarURL:       model.AvatarURL,
+		EmailVerifiedAt: unixToTimePtr(model.EmailVerifiedAt),
+		CreatedAt:       time.Unix(model.CreatedAt, 0),
+		UpdatedAt:       time.Unix(model.UpdatedAt, 0),
+	}, nil
+}
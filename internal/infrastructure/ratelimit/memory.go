@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+// sweepInterval é o número de chamadas entre as limpezas de chaves antigas
+const sweepInterval = 1000
+
+// counter são os contadores em memória de uma chave
+type counter struct {
+	window   time.Duration
+	index    int64
+	previous int64
+	current  int64
+}
+
+// MemoryLimiter implementa domain.RateLimiter em memória
+// Serve para testes e para instâncias únicas; com várias réplicas, cada uma
+// aplicaria o limite separadamente
+type MemoryLimiter struct {
+	mu       sync.Mutex
+	counters map[string]*counter
+	calls    int
+	now      func() time.Time
+}
+
+// NewMemoryLimiter cria um novo MemoryLimiter
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		counters: make(map[string]*counter),
+		now:      time.Now,
+	}
+}
+
+func (l *MemoryLimiter) Allow(_ context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	index, elapsed := windowIndex(now, limit.Window)
+	c, ok := l.counters[key]
+	if !ok || c.window != limit.Window {
+		c = &counter{window: limit.Window, index: index}
+		l.counters[key] = c
+	}
+
+	switch c.index {
+	case index:
+	case index - 1:
+		c.previous, c.current = c.current, 0
+	default:
+		c.previous, c.current = 0, 0
+	}
+	c.index = index
+
+	c.current++
+	result := decide(windowState{previous: c.previous, current: c.current, elapsed: elapsed}, limit)
+	if !result.Allowed {
+		c.current--
+	}
+
+	return result, nil
+}
+
+// sweep remove periodicamente as chaves sem requisições nas duas últimas janelas
+func (l *MemoryLimiter) sweep(now time.Time) {
+	l.calls++
+	if l.calls < sweepInterval {
+		return
+	}
+	l.calls = 0
+
+	for key, c := range l.counters {
+		if index, _ := windowIndex(now, c.window); index > c.index+1 {
+			delete(l.counters, key)
+		}
+	}
+}
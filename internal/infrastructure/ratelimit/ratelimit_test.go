@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+// testLimiter verifica o contrato comum às implementações
+// setNow controla o relógio do limitador; o teste parte do início de uma janela
+func testLimiter(t *testing.T, limiter domain.RateLimiter, setNow func(time.Time)) {
+	ctx := context.Background()
+	limit := domain.RateLimit{Limit: 3, Window: time.Hour}
+	start := time.Now().Truncate(time.Hour).Add(2 * time.Hour)
+
+	t.Run("aceita até o limite e informa o restante", func(t *testing.T) {
+		setNow(start)
+
+		for want := 2; want >= 0; want-- {
+			result, err := limiter.Allow(ctx, "ip:1", limit)
+			if err != nil {
+				t.Fatalf("esperava sucesso, obteve erro: %v", err)
+			}
+			if !result.Allowed || result.Remaining != want {
+				t.Errorf("esperava permitida com %d restantes, obteve %+v", want, result)
+			}
+		}
+	})
+
+	t.Run("recusa acima do limite com tempo de espera", func(t *testing.T) {
+		setNow(start.Add(15 * time.Minute))
+
+		result, _ := limiter.Allow(ctx, "ip:1", limit)
+		if result.Allowed {
+			t.Fatal("esperava requisição recusada")
+		}
+		// Na próxima janela, as 3 requisições ainda pesam 3*(1-g); cabe uma nova com g >= 1/3
+		want := 45*time.Minute + 20*time.Minute
+		if result.RetryAfter != want {
+			t.Errorf("esperava espera de %v, obteve %v", want, result.RetryAfter)
+		}
+		if result.ResetAfter != 45*time.Minute {
+			t.Errorf("esperava reset em 45m, obteve %v", result.ResetAfter)
+		}
+	})
+
+	t.Run("requisições recusadas não consomem o limite", func(t *testing.T) {
+		setNow(start.Add(time.Hour + 20*time.Minute))
+
+		result, _ := limiter.Allow(ctx, "ip:1", limit)
+		if !result.Allowed {
+			t.Errorf("esperava requisição permitida após a espera, obteve %+v", result)
+		}
+	})
+
+	t.Run("chaves são independentes", func(t *testing.T) {
+		setNow(start.Add(15 * time.Minute))
+
+		result, _ := limiter.Allow(ctx, "ip:2", limit)
+		if !result.Allowed || result.Remaining != 2 {
+			t.Errorf("esperava chave nova com 2 restantes, obteve %+v", result)
+		}
+	})
+
+	t.Run("janela anterior deixa de contar", func(t *testing.T) {
+		setNow(start.Add(3 * time.Hour))
+
+		result, _ := limiter.Allow(ctx, "ip:1", limit)
+		if !result.Allowed || result.Remaining != 2 {
+			t.Errorf("esperava limite renovado, obteve %+v", result)
+		}
+	})
+}
+
+func TestMemoryLimiter(t *testing.T) {
+	limiter := NewMemoryLimiter()
+	testLimiter(t, limiter, func(now time.Time) { limiter.now = func() time.Time { return now } })
+}
+
+func TestRedisLimiter(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	limiter := NewRedisLimiter(client)
+	testLimiter(t, limiter, func(now time.Time) { limiter.now = func() time.Time { return now } })
+}
+
+func TestDecide(t *testing.T) {
+	limit := domain.RateLimit{Limit: 5, Window: time.Minute}
+
+	t.Run("pondera a janela anterior", func(t *testing.T) {
+		// 4 na janela anterior, metade dela ainda conta: 2 + 2 atuais = 4
+		result := decide(windowState{previous: 4, current: 2, elapsed: 30 * time.Second}, limit)
+		if !result.Allowed || result.Remaining != 1 {
+			t.Errorf("esperava permitida com 1 restante, obteve %+v", result)
+		}
+	})
+
+	t.Run("espera a janela anterior perder peso", func(t *testing.T) {
+		// 6 anteriores + 2 atuais; a próxima cabe quando 6*(1-f) + 2 <= 4, f >= 2/3
+		result := decide(windowState{previous: 6, current: 3, elapsed: 30 * time.Second}, limit)
+		if result.Allowed {
+			t.Fatal("esperava requisição recusada")
+		}
+		if result.RetryAfter != 10*time.Second {
+			t.Errorf("esperava espera de 10s, obteve %v", result.RetryAfter)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+// keyPrefix separa as chaves do limitador das demais chaves do Redis
+const keyPrefix = "ratelimit:"
+
+// RedisLimiter implementa domain.RateLimiter no Redis
+// O contador da janela atual é incrementado com INCR, que é atômico entre as
+// réplicas; uma requisição recusada desfaz o próprio incremento
+type RedisLimiter struct {
+	client *redis.Client
+	now    func() time.Time
+}
+
+// NewRedisLimiter cria um novo RedisLimiter
+func NewRedisLimiter(client *redis.Client) *RedisLimiter {
+	return &RedisLimiter{client: client, now: time.Now}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error) {
+	index, elapsed := windowIndex(l.now(), limit.Window)
+	window := strconv.FormatInt(limit.Window.Milliseconds(), 10)
+	currentKey := keyPrefix + key + ":" + window + ":" + strconv.FormatInt(index, 10)
+	previousKey := keyPrefix + key + ":" + window + ":" + strconv.FormatInt(index-1, 10)
+
+	current, err := l.client.Incr(ctx, currentKey).Result()
+	if err != nil {
+		return domain.RateLimitResult{}, err
+	}
+
+	// O contador precisa sobreviver à janela seguinte, onde vira a janela anterior
+	if err := l.client.PExpire(ctx, currentKey, 2*limit.Window).Err(); err != nil {
+		return domain.RateLimitResult{}, err
+	}
+
+	previous, err := l.client.Get(ctx, previousKey).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return domain.RateLimitResult{}, err
+	}
+
+	result := decide(windowState{previous: previous, current: current, elapsed: elapsed}, limit)
+	if !result.Allowed {
+		if err := l.client.Decr(ctx, currentKey).Err(); err != nil {
+			return domain.RateLimitResult{}, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,77 @@
+// Package ratelimit implementa domain.RateLimiter com janela deslizante,
+// em memória e no Redis
+//
+// O algoritmo é o sliding window counter: cada chave tem um contador por
+// janela fixa e a contagem estimada é a janela atual somada à anterior,
+// ponderada pela fração da janela anterior que ainda cai nos últimos
+// Window. Com dois contadores por chave, evita a rajada de 2x o limite que
+// janelas fixas permitem na virada.
+package ratelimit
+
+import (
+	"math"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+)
+
+// windowState são os contadores de uma chave no instante da decisão
+type windowState struct {
+	previous int64
+	current  int64 // Já inclui a requisição avaliada
+	elapsed  time.Duration
+}
+
+// windowIndex retorna a janela fixa do instante e o tempo decorrido nela
+func windowIndex(now time.Time, window time.Duration) (int64, time.Duration) {
+	nanos := now.UnixNano()
+	return nanos / int64(window), time.Duration(nanos % int64(window))
+}
+
+// decide aplica o limite aos contadores
+func decide(state windowState, limit domain.RateLimit) domain.RateLimitResult {
+	weight := 1 - float64(state.elapsed)/float64(limit.Window)
+	estimate := float64(state.previous)*weight + float64(state.current)
+
+	result := domain.RateLimitResult{
+		Allowed:    estimate <= float64(limit.Limit),
+		Limit:      limit.Limit,
+		ResetAfter: limit.Window - state.elapsed,
+	}
+
+	if result.Allowed {
+		result.Remaining = int(math.Floor(float64(limit.Limit) - estimate))
+		return result
+	}
+
+	result.RetryAfter = retryAfter(state.previous, state.current-1, state.elapsed, limit)
+	return result
+}
+
+// retryAfter calcula quando uma nova requisição caberia no limite, dado que
+// current não inclui a requisição recusada
+func retryAfter(previous, current int64, elapsed time.Duration, limit domain.RateLimit) time.Duration {
+	free := int64(limit.Limit - 1)
+	window := int64(limit.Window)
+
+	// Ainda na janela atual: a parcela da anterior precisa cair o suficiente,
+	// previous*(1-f) + current <= free
+	if previous > 0 && current <= free {
+		at := time.Duration(ceilDiv(window*(previous-free+current), previous))
+		if at < limit.Window {
+			return max(at-elapsed, 0)
+		}
+	}
+
+	// Na próxima janela, a atual passa a ser a anterior: current*(1-g) <= free
+	wait := limit.Window - elapsed
+	if current > free {
+		wait += time.Duration(ceilDiv(window*(current-free), current))
+	}
+	return wait
+}
+
+// ceilDiv divide arredondando para cima (operandos positivos)
+func ceilDiv(a, b int64) int64 {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,74 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+// Algoritmos COSE aceitos (registro IANA), na ordem de preferência anunciada
+// aos autenticadores em pubKeyCredParams
+const (
+	AlgES256 = int64(webauthncose.AlgES256)
+	AlgEdDSA = int64(webauthncose.AlgEdDSA)
+	AlgRS256 = int64(webauthncose.AlgRS256)
+)
+
+// SupportedAlgorithms lista os algoritmos aceitos no cadastro
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+const rsaMinBits = 2048
+
+// publicKey é uma chave pública COSE já decodificada
+type publicKey struct {
+	alg int64
+	key any
+}
+
+// parsePublicKey decodifica uma COSE_Key e recusa combinações de tipo e
+// algoritmo fora de SupportedAlgorithms e chaves que o go-webauthn aceitaria
+// sem conferir, como pontos fora da curva ou RSA curta
+func parsePublicKey(data []byte) (*publicKey, error) {
+	key, err := webauthncose.ParsePublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("webauthn: invalid cose key: %w", err)
+	}
+
+	switch k := key.(type) {
+	case webauthncose.EC2PublicKeyData:
+		if k.Algorithm != AlgES256 || k.Curve != int64(webauthncose.P256) || len(k.XCoord) != 32 || len(k.YCoord) != 32 {
+			return nil, errors.New("webauthn: invalid ec2 key")
+		}
+		point := append(append([]byte{0x04}, k.XCoord...), k.YCoord...)
+		if _, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point); err != nil {
+			return nil, fmt.Errorf("webauthn: invalid ec2 key: %w", err)
+		}
+		return &publicKey{alg: k.Algorithm, key: key}, nil
+	case webauthncose.OKPPublicKeyData:
+		if k.Algorithm != AlgEdDSA || len(k.XCoord) != ed25519.PublicKeySize {
+			return nil, errors.New("webauthn: invalid okp key")
+		}
+		return &publicKey{alg: k.Algorithm, key: key}, nil
+	case webauthncose.RSAPublicKeyData:
+		if k.Algorithm != AlgRS256 || new(big.Int).SetBytes(k.Modulus).BitLen() < rsaMinBits {
+			return nil, errors.New("webauthn: invalid rsa key")
+		}
+		return &publicKey{alg: k.Algorithm, key: key}, nil
+	default:
+		return nil, errors.New("webauthn: unsupported cose key")
+	}
+}
+
+// verify confere a assinatura de data com o algoritmo da chave
+func (k *publicKey) verify(data, signature []byte) error {
+	valid, err := webauthncose.VerifySignature(k.key, data, signature)
+	if err != nil || !valid {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+// Package webauthn verifica as cerimônias WebAuthn (passkeys) do lado do
+// relying party: cadastro (navigator.credentials.create) e login
+// (navigator.credentials.get)
+//
+// A decodificação CBOR, as chaves COSE e os formatos de atestação ficam com o
+// github.com/go-webauthn/webauthn. As opções pedem attestation "none", que os
+// provedores de passkeys sincronizados entregam sem declaração; quando o
+// autenticador envia outro formato, a declaração é verificada antes do
+// cadastro. A presença e a verificação do usuário (UP e UV) são sempre
+// exigidas, então um passkey vale como dois fatores.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/config"
+)
+
+// Tipos do clientDataJSON de cada cerimônia
+const (
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+)
+
+const defaultRPName = "AvantPro"
+
+var (
+	ErrInvalidClientData        = errors.New("webauthn: invalid client data")
+	ErrChallengeMismatch        = errors.New("webauthn: challenge mismatch")
+	ErrOriginNotAllowed         = errors.New("webauthn: origin not allowed")
+	ErrInvalidAuthenticatorData = errors.New("webauthn: invalid authenticator data")
+	ErrInvalidAttestation       = errors.New("webauthn: invalid attestation statement")
+	ErrRPIDMismatch             = errors.New("webauthn: rp id hash mismatch")
+	ErrUserNotVerified          = errors.New("webauthn: user presence and verification are required")
+	ErrInvalidSignature         = errors.New("webauthn: invalid signature")
+	// ErrSignCountRegressed indica um contador que não avançou, possível sinal
+	// de que o autenticador foi clonado
+	ErrSignCountRegressed = errors.New("webauthn: sign count did not increase")
+)
+
+// RelyingParty verifica as respostas dos autenticadores para um RP ID
+type RelyingParty struct {
+	id      string
+	name    string
+	origins []string
+	idHash  [32]byte
+}
+
+// NewRelyingParty cria um RelyingParty a partir da configuração
+// Cada origem precisa estar no domínio do RP ID ou num subdomínio dele
+func NewRelyingParty(cfg *config.WebAuthnConfig) (*RelyingParty, error) {
+	id := strings.ToLower(strings.TrimSpace(cfg.RPID))
+	if id == "" {
+		return nil, errors.New("WEBAUTHN_RP_ID is required")
+	}
+
+	name := strings.TrimSpace(cfg.RPName)
+	if name == "" {
+		name = defaultRPName
+	}
+
+	var origins []string
+	for _, origin := range strings.Split(cfg.Origins, ",") {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if origin == "" {
+			continue
+		}
+		parsed, err := url.Parse(origin)
+		if err != nil || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid WEBAUTHN_ORIGINS entry %q", origin)
+		}
+		host := strings.ToLower(parsed.Hostname())
+		if host != id && !strings.HasSuffix(host, "."+id) {
+			return nil, fmt.Errorf("WEBAUTHN_ORIGINS entry %q is outside WEBAUTHN_RP_ID %q", origin, id)
+		}
+		origins = append(origins, origin)
+	}
+	if len(origins) == 0 {
+		return nil, errors.New("WEBAUTHN_ORIGINS is required")
+	}
+
+	return &RelyingParty{
+		id:      id,
+		name:    name,
+		origins: origins,
+		idHash:  sha256.Sum256([]byte(id)),
+	}, nil
+}
+
+// ID retorna o RP ID enviado nas opções das cerimônias
+func (rp *RelyingParty) ID() string {
+	return rp.id
+}
+
+// Name retorna o nome do RP exibido pelo autenticador
+func (rp *RelyingParty) Name() string {
+	return rp.name
+}
+
+// Credential é a credencial criada no cadastro
+type Credential struct {
+	ID             []byte
+	PublicKey      []byte // COSE_Key na codificação canônica do CTAP2
+	Algorithm      int64
+	SignCount      uint32
+	AAGUID         []byte
+	BackupEligible bool
+	BackupState    bool
+}
+
+// Assertion é o resultado de um login verificado
+type Assertion struct {
+	SignCount   uint32
+	BackupState bool
+}
+
+// clientData são os campos usados do clientDataJSON
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// ClientDataChallenge extrai o challenge do clientDataJSON, para que a
+// cerimônia pendente seja localizada antes da verificação completa
+func ClientDataChallenge(clientDataJSON []byte) (string, error) {
+	var data clientData
+	if err := json.Unmarshal(clientDataJSON, &data); err != nil || data.Challenge == "" {
+		return "", ErrInvalidClientData
+	}
+	return data.Challenge, nil
+}
+
+// VerifyRegistration verifica a resposta de navigator.credentials.create
+// para o challenge emitido e retorna a credencial criada
+func (rp *RelyingParty) VerifyRegistration(challenge string, clientDataJSON, attestationObject []byte) (*Credential, error) {
+	if err := rp.verifyClientData(clientDataJSON, ceremonyCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	var object protocol.AttestationObject
+	if err := webauthncbor.Unmarshal(attestationObject, &object); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAuthenticatorData, err)
+	}
+	if err := rp.parseAuthenticatorData(&object.AuthData, object.RawAuthData); err != nil {
+		return nil, err
+	}
+	if !object.AuthData.Flags.HasAttestedCredentialData() {
+		return nil, ErrInvalidAuthenticatorData
+	}
+
+	key, err := parsePublicKey(object.AuthData.AttData.CredentialPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if err := object.VerifyAttestation(clientDataHash[:], nil); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAttestation, describe(err))
+	}
+
+	attested := object.AuthData.AttData
+	return &Credential{
+		ID:             attested.CredentialID,
+		PublicKey:      attested.CredentialPublicKey,
+		Algorithm:      key.alg,
+		SignCount:      object.AuthData.Counter,
+		AAGUID:         attested.AAGUID,
+		BackupEligible: object.AuthData.Flags.HasBackupEligible(),
+		BackupState:    object.AuthData.Flags.HasBackupState(),
+	}, nil
+}
+
+// VerifyAssertion verifica a resposta de navigator.credentials.get com a
+// chave pública e o contador de assinaturas guardados no cadastro
+//
+// Autenticadores que não mantêm contador enviam sempre zero; quando um dos
+// lados é diferente de zero, o novo valor precisa ser maior que o guardado
+func (rp *RelyingParty) VerifyAssertion(challenge string, clientDataJSON, rawAuthData, signature, publicKey []byte, storedSignCount uint32) (*Assertion, error) {
+	if err := rp.verifyClientData(clientDataJSON, ceremonyGet, challenge); err != nil {
+		return nil, err
+	}
+
+	var authData protocol.AuthenticatorData
+	if err := rp.parseAuthenticatorData(&authData, rawAuthData); err != nil {
+		return nil, err
+	}
+	// A credencial já foi cadastrada: o login não traz dados atestados
+	if authData.Flags.HasAttestedCredentialData() {
+		return nil, ErrInvalidAuthenticatorData
+	}
+
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), rawAuthData...), clientDataHash[:]...)
+	if err := key.verify(signed, signature); err != nil {
+		return nil, err
+	}
+
+	if (authData.Counter != 0 || storedSignCount != 0) && authData.Counter <= storedSignCount {
+		return nil, ErrSignCountRegressed
+	}
+
+	return &Assertion{
+		SignCount:   authData.Counter,
+		BackupState: authData.Flags.HasBackupState(),
+	}, nil
+}
+
+// verifyClientData confere o tipo da cerimônia, o challenge e a origem
+func (rp *RelyingParty) verifyClientData(clientDataJSON []byte, ceremony, challenge string) error {
+	var data clientData
+	if err := json.Unmarshal(clientDataJSON, &data); err != nil || data.Type != ceremony {
+		return ErrInvalidClientData
+	}
+
+	if challenge == "" || subtle.ConstantTimeCompare([]byte(data.Challenge), []byte(challenge)) != 1 {
+		return ErrChallengeMismatch
+	}
+
+	// Cerimônias iniciadas dentro de iframes de terceiros não são aceitas
+	if data.CrossOrigin {
+		return ErrOriginNotAllowed
+	}
+	for _, origin := range rp.origins {
+		if data.Origin == origin {
+			return nil
+		}
+	}
+	return ErrOriginNotAllowed
+}
+
+// parseAuthenticatorData decodifica os dados do autenticador e confere o
+// hash do RP ID e as flags de presença e verificação do usuário
+func (rp *RelyingParty) parseAuthenticatorData(authData *protocol.AuthenticatorData, raw []byte) error {
+	if err := authData.Unmarshal(raw); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAuthenticatorData, describe(err))
+	}
+
+	if !bytes.Equal(authData.RPIDHash, rp.idHash[:]) {
+		return ErrRPIDMismatch
+	}
+	if !authData.Flags.HasUserPresent() || !authData.Flags.HasUserVerified() {
+		return ErrUserNotVerified
+	}
+	if authData.Flags.HasBackupState() && !authData.Flags.HasBackupEligible() {
+		return ErrInvalidAuthenticatorData
+	}
+	return nil
+}
+
+// describe inclui o detalhe de depuração dos erros do go-webauthn, que
+// costuma dizer qual verificação falhou
+func describe(err error) string {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) && protocolErr.DevInfo != "" {
+		return protocolErr.Details + " (" + protocolErr.DevInfo + ")"
+	}
+	return err.Error()
+}
@@ -0,0 +1,332 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/config"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/webauthn/webauthntest"
+)
+
+const (
+	testRPID   = "localhost"
+	testOrigin = "http://localhost:3000"
+)
+
+func newTestRelyingParty(t *testing.T) *RelyingParty {
+	t.Helper()
+
+	rp, err := NewRelyingParty(&config.WebAuthnConfig{RPID: testRPID, Origins: testOrigin + ", https://app.localhost/"})
+	if err != nil {
+		t.Fatalf("falha ao criar relying party: %v", err)
+	}
+	return rp
+}
+
+// register cadastra uma credencial e retorna o resultado verificado
+func register(t *testing.T, rp *RelyingParty, authenticator *webauthntest.Authenticator) *Credential {
+	t.Helper()
+
+	attestation, err := authenticator.Register("desafio-cadastro", []byte("user-1"))
+	if err != nil {
+		t.Fatalf("falha no autenticador: %v", err)
+	}
+
+	credential, err := rp.VerifyRegistration("desafio-cadastro", attestation.ClientDataJSON, attestation.AttestationObject)
+	if err != nil {
+		t.Fatalf("esperava cadastro válido, obteve erro: %v", err)
+	}
+	return credential
+}
+
+func TestNewRelyingParty(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.WebAuthnConfig
+		wantErr bool
+	}{
+		{"configuração válida", config.WebAuthnConfig{RPID: "avantpro.com", Origins: "https://avantpro.com,https://app.avantpro.com"}, false},
+		{"RP ID ausente", config.WebAuthnConfig{Origins: "https://avantpro.com"}, true},
+		{"origens ausentes", config.WebAuthnConfig{RPID: "avantpro.com"}, true},
+		{"origem fora do domínio", config.WebAuthnConfig{RPID: "avantpro.com", Origins: "https://avantpro.com.evil.io"}, true},
+		{"origem sem esquema", config.WebAuthnConfig{RPID: "avantpro.com", Origins: "avantpro.com"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp, err := NewRelyingParty(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("esperava erro=%v, obteve %v", tt.wantErr, err)
+			}
+			if err == nil && rp.Name() != defaultRPName {
+				t.Errorf("esperava nome padrão '%s', obteve '%s'", defaultRPName, rp.Name())
+			}
+		})
+	}
+}
+
+func TestRelyingParty_VerifyRegistration(t *testing.T) {
+	rp := newTestRelyingParty(t)
+
+	t.Run("cadastro válido retorna a credencial", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(testRPID, testOrigin)
+		attestation, _ := authenticator.Register("desafio", []byte("user-1"))
+
+		credential, err := rp.VerifyRegistration("desafio", attestation.ClientDataJSON, attestation.AttestationObject)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if !bytes.Equal(credential.ID, attestation.CredentialID) {
+			t.Error("ID da credencial diferente do criado pelo autenticador")
+		}
+		if credential.Algorithm != AlgES256 || !bytes.Equal(credential.AAGUID, webauthntest.AAGUID) {
+			t.Errorf("credencial inesperada: %+v", credential)
+		}
+		if !credential.BackupEligible || !credential.BackupState {
+			t.Error("esperava flags de backup do passkey sincronizado")
+		}
+	})
+
+	t.Run("rejeita respostas inválidas", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			configure func(a *webauthntest.Authenticator)
+			challenge string
+			wantErr   error
+		}{
+			{"challenge diferente", nil, "outro", ErrChallengeMismatch},
+			{"origem não permitida", func(a *webauthntest.Authenticator) { a.Origin = "https://evil.io" }, "desafio", ErrOriginNotAllowed},
+			{"RP ID diferente", func(a *webauthntest.Authenticator) { a.RPID = "evil.io" }, "desafio", ErrRPIDMismatch},
+			{"sem verificação do usuário", func(a *webauthntest.Authenticator) { a.SkipUserVerification = true }, "desafio", ErrUserNotVerified},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				authenticator := webauthntest.NewAuthenticator(testRPID, testOrigin)
+				if tt.configure != nil {
+					tt.configure(authenticator)
+				}
+				attestation, _ := authenticator.Register("desafio", []byte("user-1"))
+
+				_, err := rp.VerifyRegistration(tt.challenge, attestation.ClientDataJSON, attestation.AttestationObject)
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("esperava %v, obteve %v", tt.wantErr, err)
+				}
+			})
+		}
+	})
+
+	t.Run("atestação none é aceita", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(testRPID, testOrigin)
+		authenticator.NoneAttestation = true
+		attestation, _ := authenticator.Register("desafio", []byte("user-1"))
+
+		if _, err := rp.VerifyRegistration("desafio", attestation.ClientDataJSON, attestation.AttestationObject); err != nil {
+			t.Errorf("esperava sucesso, obteve erro: %v", err)
+		}
+	})
+
+	t.Run("rejeita atestação assinada por outra chave", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(testRPID, testOrigin)
+		authenticator.ForgedAttestation = true
+		attestation, _ := authenticator.Register("desafio", []byte("user-1"))
+
+		if _, err := rp.VerifyRegistration("desafio", attestation.ClientDataJSON, attestation.AttestationObject); !errors.Is(err, ErrInvalidAttestation) {
+			t.Errorf("esperava ErrInvalidAttestation, obteve %v", err)
+		}
+	})
+
+	t.Run("rejeita resposta de login no cadastro", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(testRPID, testOrigin)
+		attestation, _ := authenticator.Register("desafio", []byte("user-1"))
+		assertion, _ := authenticator.Assert("desafio", attestation.CredentialID)
+
+		if _, err := rp.VerifyRegistration("desafio", assertion.ClientDataJSON, attestation.AttestationObject); !errors.Is(err, ErrInvalidClientData) {
+			t.Errorf("esperava ErrInvalidClientData, obteve %v", err)
+		}
+	})
+
+	t.Run("rejeita attestation object truncado", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(testRPID, testOrigin)
+		attestation, _ := authenticator.Register("desafio", []byte("user-1"))
+		truncated := attestation.AttestationObject[:len(attestation.AttestationObject)-10]
+
+		if _, err := rp.VerifyRegistration("desafio", attestation.ClientDataJSON, truncated); !errors.Is(err, ErrInvalidAuthenticatorData) {
+			t.Errorf("esperava ErrInvalidAuthenticatorData, obteve %v", err)
+		}
+	})
+
+	t.Run("rejeita CBOR fora da forma canônica do CTAP2", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(testRPID, testOrigin)
+		attestation, _ := authenticator.Register("desafio", []byte("user-1"))
+
+		tests := map[string][]byte{
+			"vazio":                  {},
+			"chave duplicada":        {0xa2, 0x63, 'f', 'm', 't', 0x64, 'n', 'o', 'n', 'e', 0x63, 'f', 'm', 't', 0x64, 'n', 'o', 'n', 'e'},
+			"comprimento indefinido": {0xbf, 0x63, 'f', 'm', 't', 0x64, 'n', 'o', 'n', 'e', 0xff},
+		}
+		for name, data := range tests {
+			t.Run(name, func(t *testing.T) {
+				if _, err := rp.VerifyRegistration("desafio", attestation.ClientDataJSON, data); !errors.Is(err, ErrInvalidAuthenticatorData) {
+					t.Errorf("esperava ErrInvalidAuthenticatorData, obteve %v", err)
+				}
+			})
+		}
+	})
+}
+
+func TestRelyingParty_VerifyAssertion(t *testing.T) {
+	rp := newTestRelyingParty(t)
+
+	t.Run("login válido avança o contador", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(testRPID, testOrigin)
+		credential := register(t, rp, authenticator)
+
+		signCount := credential.SignCount
+		for i := 0; i < 2; i++ {
+			assertion, _ := authenticator.Assert("desafio-login", credential.ID)
+			result, err := rp.VerifyAssertion("desafio-login", assertion.ClientDataJSON, assertion.AuthenticatorData, assertion.Signature, credential.PublicKey, signCount)
+			if err != nil {
+				t.Fatalf("esperava sucesso, obteve erro: %v", err)
+			}
+			if result.SignCount <= signCount {
+				t.Fatalf("esperava contador maior que %d, obteve %d", signCount, result.SignCount)
+			}
+			signCount = result.SignCount
+		}
+	})
+
+	t.Run("origem alternativa configurada é aceita", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(testRPID, "https://app.localhost")
+		credential := register(t, rp, authenticator)
+
+		assertion, _ := authenticator.Assert("desafio", credential.ID)
+		if _, err := rp.VerifyAssertion("desafio", assertion.ClientDataJSON, assertion.AuthenticatorData, assertion.Signature, credential.PublicKey, 0); err != nil {
+			t.Errorf("esperava sucesso, obteve erro: %v", err)
+		}
+	})
+
+	t.Run("contador que não avança indica clonagem", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(testRPID, testOrigin)
+		credential := register(t, rp, authenticator)
+
+		assertion, _ := authenticator.Assert("desafio", credential.ID)
+		if _, err := rp.VerifyAssertion("desafio", assertion.ClientDataJSON, assertion.AuthenticatorData, assertion.Signature, credential.PublicKey, 5); !errors.Is(err, ErrSignCountRegressed) {
+			t.Errorf("esperava ErrSignCountRegressed, obteve %v", err)
+		}
+	})
+
+	t.Run("autenticador sem contador é aceito", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(testRPID, testOrigin)
+		authenticator.FrozenSignCount = true
+		credential := register(t, rp, authenticator)
+
+		for i := 0; i < 2; i++ {
+			assertion, _ := authenticator.Assert("desafio", credential.ID)
+			if _, err := rp.VerifyAssertion("desafio", assertion.ClientDataJSON, assertion.AuthenticatorData, assertion.Signature, credential.PublicKey, 0); err != nil {
+				t.Fatalf("esperava sucesso, obteve erro: %v", err)
+			}
+		}
+	})
+
+	t.Run("assinatura de outra chave é rejeitada", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(testRPID, testOrigin)
+		credential := register(t, rp, authenticator)
+		other := register(t, rp, authenticator)
+
+		assertion, _ := authenticator.Assert("desafio", other.ID)
+		if _, err := rp.VerifyAssertion("desafio", assertion.ClientDataJSON, assertion.AuthenticatorData, assertion.Signature, credential.PublicKey, 0); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("esperava ErrInvalidSignature, obteve %v", err)
+		}
+	})
+
+	t.Run("clientDataJSON adulterado invalida a assinatura", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(testRPID, testOrigin)
+		credential := register(t, rp, authenticator)
+
+		assertion, _ := authenticator.Assert("desafio", credential.ID)
+		tampered := bytes.Replace(assertion.ClientDataJSON, []byte(`"crossOrigin":false`), []byte(`"crossOrigin":false `), 1)
+		if _, err := rp.VerifyAssertion("desafio", tampered, assertion.AuthenticatorData, assertion.Signature, credential.PublicKey, 0); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("esperava ErrInvalidSignature, obteve %v", err)
+		}
+	})
+
+	t.Run("sem verificação do usuário é rejeitado", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(testRPID, testOrigin)
+		credential := register(t, rp, authenticator)
+		authenticator.SkipUserVerification = true
+
+		assertion, _ := authenticator.Assert("desafio", credential.ID)
+		if _, err := rp.VerifyAssertion("desafio", assertion.ClientDataJSON, assertion.AuthenticatorData, assertion.Signature, credential.PublicKey, 0); !errors.Is(err, ErrUserNotVerified) {
+			t.Errorf("esperava ErrUserNotVerified, obteve %v", err)
+		}
+	})
+}
+
+func TestClientDataChallenge(t *testing.T) {
+	t.Run("extrai o challenge", func(t *testing.T) {
+		challenge, err := ClientDataChallenge([]byte(`{"type":"webauthn.get","challenge":"abc","origin":"http://localhost:3000"}`))
+		if err != nil || challenge != "abc" {
+			t.Errorf("esperava 'abc', obteve '%s' (%v)", challenge, err)
+		}
+	})
+
+	t.Run("rejeita JSON inválido ou sem challenge", func(t *testing.T) {
+		for _, raw := range []string{"", "{", `{"type":"webauthn.get"}`} {
+			if _, err := ClientDataChallenge([]byte(raw)); !errors.Is(err, ErrInvalidClientData) {
+				t.Errorf("esperava ErrInvalidClientData para %q, obteve %v", raw, err)
+			}
+		}
+	})
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	point, _ := key.PublicKey.Bytes()
+
+	ec2 := func(alg int64, curve webauthncose.COSEEllipticCurve, x, y []byte) []byte {
+		data, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+			PublicKeyData: webauthncose.PublicKeyData{KeyType: int64(webauthncose.EllipticKey), Algorithm: alg},
+			Curve:         int64(curve),
+			XCoord:        x,
+			YCoord:        y,
+		})
+		if err != nil {
+			t.Fatalf("falha ao codificar a chave: %v", err)
+		}
+		return data
+	}
+
+	t.Run("aceita chave ES256", func(t *testing.T) {
+		parsed, err := parsePublicKey(ec2(AlgES256, webauthncose.P256, point[1:33], point[33:]))
+		if err != nil || parsed.alg != AlgES256 {
+			t.Errorf("esperava chave ES256, obteve %+v (%v)", parsed, err)
+		}
+	})
+
+	t.Run("rejeita chaves inválidas", func(t *testing.T) {
+		offCurve := bytes.Repeat([]byte{0x01}, 32)
+		tests := map[string][]byte{
+			"não é CBOR":               {0xff},
+			"algoritmo do tipo errado": ec2(AlgRS256, webauthncose.P256, point[1:33], point[33:]),
+			"outra curva":              ec2(AlgES256, webauthncose.P384, point[1:33], point[33:]),
+			"ponto fora da curva":      ec2(AlgES256, webauthncose.P256, offCurve, offCurve),
+			"coordenada curta":         ec2(AlgES256, webauthncose.P256, point[1:32], point[33:]),
+		}
+
+		for name, data := range tests {
+			t.Run(name, func(t *testing.T) {
+				if _, err := parsePublicKey(data); err == nil {
+					t.Error("esperava erro")
+				}
+			})
+		}
+	})
+}
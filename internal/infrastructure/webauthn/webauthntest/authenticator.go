@@ -0,0 +1,238 @@
+// Package webauthntest fornece um autenticador WebAuthn em software para
+// testes, no mesmo espírito do net/http/httptest
+//
+// O autenticador cria credenciais ES256 residentes, sempre com presença e
+// verificação do usuário, e monta as respostas exatamente como o navegador
+// as entregaria ao relying party (clientDataJSON, attestationObject com
+// autoatestação "packed", authenticatorData e assinatura).
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+// Flags dos dados do autenticador (WebAuthn §6.1)
+const (
+	flagUserPresent    = 0x01
+	flagUserVerified   = 0x04
+	flagBackupEligible = 0x08
+	flagBackupState    = 0x10
+	flagAttestedData   = 0x40
+)
+
+// AAGUID identifica o modelo do autenticador falso nas credenciais criadas
+var AAGUID = []byte("avantpro-webauth")
+
+// Authenticator é um autenticador de plataforma em software
+// Os campos exportados permitem simular clientes e autenticadores defeituosos
+type Authenticator struct {
+	// Origin é a origem informada no clientDataJSON
+	Origin string
+	// RPID é o domínio cujo hash vai nos dados do autenticador
+	RPID string
+	// SkipUserVerification omite a flag UV, como um autenticador sem PIN ou biometria
+	SkipUserVerification bool
+	// FrozenSignCount mantém o contador parado, como um autenticador clonado
+	FrozenSignCount bool
+	// NoneAttestation envia o formato "none", como os passkeys sincronizados
+	NoneAttestation bool
+	// ForgedAttestation assina a declaração "packed" com outra chave
+	ForgedAttestation bool
+
+	mu          sync.Mutex
+	credentials map[string]*credential
+}
+
+// credential é uma credencial residente no autenticador
+type credential struct {
+	id         []byte
+	key        *ecdsa.PrivateKey
+	userHandle []byte
+	signCount  uint32
+}
+
+// attestationObject é o objeto CBOR devolvido no cadastro
+type attestationObject struct {
+	Format       string         `cbor:"fmt"`
+	AttStatement map[string]any `cbor:"attStmt"`
+	AuthData     []byte         `cbor:"authData"`
+}
+
+// Attestation é a resposta de navigator.credentials.create
+type Attestation struct {
+	CredentialID      []byte
+	ClientDataJSON    []byte
+	AttestationObject []byte
+}
+
+// Assertion é a resposta de navigator.credentials.get
+type Assertion struct {
+	CredentialID      []byte
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+	UserHandle        []byte
+}
+
+// NewAuthenticator cria um autenticador para o RP ID e a origem informados
+func NewAuthenticator(rpID, origin string) *Authenticator {
+	return &Authenticator{
+		Origin:      origin,
+		RPID:        rpID,
+		credentials: make(map[string]*credential),
+	}
+}
+
+// Register cria uma credencial para o usuário e responde ao challenge do cadastro
+func (a *Authenticator) Register(challenge string, userHandle []byte) (*Attestation, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	cred := &credential{id: id, key: key, userHandle: append([]byte(nil), userHandle...)}
+
+	a.mu.Lock()
+	a.credentials[string(id)] = cred
+	a.mu.Unlock()
+
+	publicKey, err := coseKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	authData := a.authenticatorData(flagAttestedData, 0)
+	authData = append(authData, AAGUID...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(id)))
+	authData = append(authData, id...)
+	authData = append(authData, publicKey...)
+
+	clientDataJSON := a.clientData("webauthn.create", challenge)
+	object := attestationObject{Format: "none", AttStatement: map[string]any{}, AuthData: authData}
+	if !a.NoneAttestation {
+		// Autoatestação: a própria chave da credencial assina authData || hash(clientDataJSON)
+		signer := key
+		if a.ForgedAttestation {
+			if signer, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+				return nil, err
+			}
+		}
+		signature, err := sign(signer, authData, clientDataJSON)
+		if err != nil {
+			return nil, err
+		}
+		object.Format = "packed"
+		object.AttStatement = map[string]any{"alg": int64(webauthncose.AlgES256), "sig": signature}
+	}
+
+	encoded, err := webauthncbor.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Attestation{
+		CredentialID:      id,
+		ClientDataJSON:    clientDataJSON,
+		AttestationObject: encoded,
+	}, nil
+}
+
+// Assert assina o challenge do login com a credencial informada, avançando o contador
+func (a *Authenticator) Assert(challenge string, credentialID []byte) (*Assertion, error) {
+	a.mu.Lock()
+	cred, ok := a.credentials[string(credentialID)]
+	if ok && !a.FrozenSignCount {
+		cred.signCount++
+	}
+	var signCount uint32
+	if ok {
+		signCount = cred.signCount
+	}
+	a.mu.Unlock()
+
+	if !ok {
+		return nil, errors.New("webauthntest: unknown credential")
+	}
+
+	clientDataJSON := a.clientData("webauthn.get", challenge)
+	authData := a.authenticatorData(0, signCount)
+
+	signature, err := sign(cred.key, authData, clientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Assertion{
+		CredentialID:      cred.id,
+		ClientDataJSON:    clientDataJSON,
+		AuthenticatorData: authData,
+		Signature:         signature,
+		UserHandle:        cred.userHandle,
+	}, nil
+}
+
+// clientData monta o clientDataJSON como o navegador
+func (a *Authenticator) clientData(ceremony, challenge string) []byte {
+	data, _ := json.Marshal(map[string]any{
+		"type":        ceremony,
+		"challenge":   challenge,
+		"origin":      a.Origin,
+		"crossOrigin": false,
+	})
+	return data
+}
+
+// authenticatorData monta o cabeçalho dos dados do autenticador
+// As credenciais são tratadas como passkeys sincronizados (BE e BS)
+func (a *Authenticator) authenticatorData(extraFlags byte, signCount uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.RPID))
+
+	flags := flagUserPresent | flagBackupEligible | flagBackupState | extraFlags
+	if !a.SkipUserVerification {
+		flags |= flagUserVerified
+	}
+
+	data := append([]byte(nil), rpIDHash[:]...)
+	data = append(data, flags)
+	return binary.BigEndian.AppendUint32(data, signCount)
+}
+
+// sign assina authData || SHA-256(clientDataJSON), o conteúdo assinado nas
+// duas cerimônias
+func sign(key *ecdsa.PrivateKey, authData, clientDataJSON []byte) ([]byte, error) {
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte(nil), authData...), clientDataHash[:]...))
+	return ecdsa.SignASN1(rand.Reader, key, digest[:])
+}
+
+// coseKey codifica a chave pública P-256 como COSE_Key (ES256)
+func coseKey(key *ecdsa.PublicKey) ([]byte, error) {
+	point, err := key.Bytes() // 0x04 || x || y
+	if err != nil {
+		return nil, err
+	}
+	return webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: point[1:33],
+		YCoord: point[33:65],
+	})
+}
@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
+)
+
+// AuthService implementa os casos de uso de autenticação
+type AuthService struct {
+	userRepo         repositories.UserRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	mfaRepo          repositories.MFARepository
+	uow              domain.UnitOfWork
+	jwtService       *auth.JWTService
+	hasher           domain.PasswordHasher
+	lockout          *LockoutService
+	logger           domain.Logger
+}
+
+// NewAuthService cria um novo AuthService
+func NewAuthService(
+	userRepo repositories.UserRepository,
+	refreshTokenRepo repositories.RefreshTokenRepository,
+	mfaRepo repositories.MFARepository,
+	uow domain.UnitOfWork,
+	jwtService *auth.JWTService,
+	hasher domain.PasswordHasher,
+	lockout *LockoutService,
+	logger domain.Logger,
+) *AuthService {
+	return &AuthService{
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		mfaRepo:          mfaRepo,
+		uow:              uow,
+		jwtService:       jwtService,
+		hasher:           hasher,
+		lockout:          lockout,
+		logger:           logger,
+	}
+}
+
+// AuthResult contém os tokens emitidos após uma autenticação bem-sucedida
+// Quando o usuário tem segundo fator, contém apenas o MFAToken do desafio
+type AuthResult struct {
+	AccessToken  string
+	RefreshToken string
+	MFAToken     string
+	ExpiresIn    time.Duration
+	User         *entities.User
+}
+
+// MFARequired indica que a autenticação aguarda o segundo fator
+func (r *AuthResult) MFARequired() bool {
+	return r.MFAToken != ""
+}
+
+// Login autentica um usuário por email e senha
+// Após falhas seguidas, a conta ou o IP ficam bloqueados e o login retorna
+// *domainerrors.LockedError sem verificar a senha
+func (s *AuthService) Login(ctx context.Context, email, password string) (*AuthResult, error) {
+	normalized, err := valueobjects.NewEmail(email)
+	if err != nil {
+		s.hasher.SimulateVerify(password)
+		return nil, domainerrors.ErrInvalidCredentials
+	}
+
+	if err := s.lockout.Check(ctx, normalized.String()); err != nil {
+		s.logger.Info("login rejected while locked", "ip_address", domain.ClientInfoFromContext(ctx).IPAddress)
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, normalized.String())
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			s.hasher.SimulateVerify(password)
+			s.lockout.RecordFailure(ctx, normalized.String(), nil)
+			return nil, domainerrors.ErrInvalidCredentials
+		}
+		s.logger.Error("failed to find user", "error", err)
+		return nil, err
+	}
+
+	if !s.VerifyPassword(ctx, user, password) {
+		s.logger.Info("login failed", "user_id", user.ID)
+		s.lockout.RecordFailure(ctx, normalized.String(), user)
+		return nil, domainerrors.ErrInvalidCredentials
+	}
+
+	s.lockout.RecordSuccess(ctx, normalized.String())
+
+	// Verificado só após a senha para não revelar o status de contas alheias
+	if !user.IsActive() {
+		s.logger.Info("login rejected for inactive account", "user_id", user.ID, "status", user.Status)
+		return nil, domainerrors.ErrAccountNotActive
+	}
+
+	result, err := s.StartSession(ctx, user)
+	if err != nil {
+		s.logger.Error("failed to issue tokens", "user_id", user.ID, "error", err)
+		return nil, err
+	}
+
+	if result.MFARequired() {
+		s.logger.Info("login awaiting second factor", "user_id", user.ID)
+		return result, nil
+	}
+
+	s.logger.Info("user logged in", "user_id", user.ID)
+	return result, nil
+}
+
+// StartSession conclui uma autenticação primária (senha, OAuth, SSO)
+// Usuários com TOTP confirmado recebem um desafio, trocado por tokens em
+// VerifyMFA; os demais recebem os tokens diretamente
+func (s *AuthService) StartSession(ctx context.Context, user *entities.User) (*AuthResult, error) {
+	enrollment, err := s.mfaRepo.FindTOTP(ctx, user.ID)
+	if err != nil && !errors.Is(err, domainerrors.ErrMFANotEnrolled) {
+		return nil, err
+	}
+
+	if err != nil || !enrollment.IsConfirmed() {
+		return s.IssueTokens(ctx, user)
+	}
+
+	mfaToken, err := s.jwtService.GenerateMFAToken(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthResult{
+		MFAToken:  mfaToken,
+		ExpiresIn: s.jwtService.MFAExpiry(),
+		User:      user,
+	}, nil
+}
+
+// VerifyMFA troca o desafio do login e um código TOTP ou de recuperação
+// por tokens de uma sessão autenticada com o segundo fator
+// Após códigos errados seguidos, o segundo fator do usuário fica bloqueado e
+// VerifyMFA retorna *domainerrors.LockedError sem verificar o código, mesmo
+// com desafios novos
+func (s *AuthService) VerifyMFA(ctx context.Context, mfaToken, code string) (*AuthResult, error) {
+	claims, err := s.jwtService.ValidateMFAToken(mfaToken)
+	if err != nil {
+		return nil, domainerrors.ErrInvalidMFAToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, claims.Subject)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, domainerrors.ErrInvalidMFAToken
+		}
+		s.logger.Error("failed to find user", "user_id", claims.Subject, "error", err)
+		return nil, err
+	}
+
+	if !user.IsActive() {
+		return nil, domainerrors.ErrAccountNotActive
+	}
+
+	if err := s.lockout.CheckSecondFactor(ctx, user.ID); err != nil {
+		s.logger.Info("second factor rejected while locked", "user_id", user.ID)
+		return nil, err
+	}
+
+	recovery, err := verifySecondFactor(ctx, s.mfaRepo, user.ID, code)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrInvalidMFACode):
+			s.logger.Info("second factor rejected", "user_id", user.ID)
+			s.lockout.RecordSecondFactorFailure(ctx, user)
+		case errors.Is(err, domainerrors.ErrMFANotEnrolled):
+			// O segundo fator foi desativado depois da emissão do desafio
+			return nil, domainerrors.ErrInvalidMFAToken
+		default:
+			s.logger.Error("failed to verify second factor", "user_id", user.ID, "error", err)
+		}
+		return nil, err
+	}
+
+	s.lockout.RecordSecondFactorSuccess(ctx, user.ID)
+
+	result, err := s.issueTokens(ctx, user, uuid.New().String(), true)
+	if err != nil {
+		s.logger.Error("failed to issue tokens", "user_id", user.ID, "error", err)
+		return nil, err
+	}
+
+	if recovery {
+		s.logger.Warn("recovery code used to sign in", "user_id", user.ID)
+	}
+	s.logger.Info("user logged in", "user_id", user.ID, "mfa", true)
+	return result, nil
+}
+
+// HashPassword gera o hash de uma senha com o algoritmo e o custo atuais
+func (s *AuthService) HashPassword(password string) (string, error) {
+	return s.hasher.Hash(password)
+}
+
+// CheckPassword confere a senha de um usuário já identificado fora do login
+// (ex: aceite de convite), com o mesmo bloqueio por falhas seguidas do Login
+// Retorna *domainerrors.LockedError sem verificar a senha quando a conta ou o
+// IP estão bloqueados, e ErrInvalidCredentials quando a senha não confere
+func (s *AuthService) CheckPassword(ctx context.Context, user *entities.User, password string) error {
+	email := user.Email.String()
+
+	if err := s.lockout.Check(ctx, email); err != nil {
+		s.logger.Info("password check rejected while locked", "user_id", user.ID)
+		return err
+	}
+
+	if !s.VerifyPassword(ctx, user, password) {
+		s.lockout.RecordFailure(ctx, email, user)
+		return domainerrors.ErrInvalidCredentials
+	}
+
+	s.lockout.RecordSuccess(ctx, email)
+	return nil
+}
+
+// VerifyPassword confere a senha do usuário e, quando o hash usa um algoritmo
+// legado ou parâmetros antigos, o regrava de forma transparente
+// Falhas ao regravar não impedem o login: o hash antigo continua válido
+func (s *AuthService) VerifyPassword(ctx context.Context, user *entities.User, password string) bool {
+	ok, needsRehash := s.hasher.Verify(user.PasswordHash, password)
+	if !ok || !needsRehash {
+		return ok
+	}
+
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		s.logger.Error("failed to rehash password", "user_id", user.ID, "error", err)
+		return true
+	}
+
+	if err := s.userRepo.UpdatePasswordHash(ctx, user.ID, hash); err != nil {
+		s.logger.Error("failed to store rehashed password", "user_id", user.ID, "error", err)
+		return true
+	}
+
+	user.PasswordHash = hash
+	s.logger.Info("password rehashed", "user_id", user.ID)
+	return true
+}
+
+// Refresh troca um refresh token válido por um novo par de tokens
+// O token apresentado é consumido; reapresentá-lo revoga toda a família
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*AuthResult, error) {
+	claims, err := s.jwtService.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return nil, domainerrors.ErrInvalidRefreshToken
+	}
+
+	stored, err := s.refreshTokenRepo.FindByHash(ctx, auth.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrInvalidRefreshToken) {
+			return nil, err
+		}
+		s.logger.Error("failed to find refresh token", "error", err)
+		return nil, err
+	}
+
+	if stored.UserID != claims.Subject {
+		return nil, domainerrors.ErrInvalidRefreshToken
+	}
+
+	if stored.IsUsed() || stored.IsRevoked() {
+		s.revokeFamily(ctx, stored)
+		return nil, domainerrors.ErrInvalidRefreshToken
+	}
+
+	if stored.IsExpired(time.Now()) {
+		return nil, domainerrors.ErrInvalidRefreshToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, domainerrors.ErrInvalidRefreshToken
+		}
+		s.logger.Error("failed to find user", "user_id", stored.UserID, "error", err)
+		return nil, err
+	}
+
+	if !user.IsActive() {
+		return nil, domainerrors.ErrInvalidRefreshToken
+	}
+
+	var result *AuthResult
+	err = s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.refreshTokenRepo.MarkAsUsed(txCtx, stored.ID); err != nil {
+			return err
+		}
+
+		// A rotação preserva o segundo fator comprovado no login
+		issued, err := s.issueTokens(txCtx, user, stored.FamilyID, claims.MFA)
+		if err != nil {
+			return err
+		}
+
+		result = issued
+		return nil
+	})
+	if err != nil {
+		// Outra requisição consumiu o token primeiro
+		if errors.Is(err, domainerrors.ErrRefreshTokenReused) {
+			s.revokeFamily(ctx, stored)
+			return nil, domainerrors.ErrInvalidRefreshToken
+		}
+		s.logger.Error("failed to rotate refresh token", "user_id", user.ID, "error", err)
+		return nil, err
+	}
+
+	s.logger.Info("refresh token rotated", "user_id", user.ID, "family_id", stored.FamilyID)
+	return result, nil
+}
+
+// revokeFamily revoga todos os tokens da família após detecção de reuso
+func (s *AuthService) revokeFamily(ctx context.Context, token *entities.RefreshToken) {
+	s.logger.Warn("refresh token reuse detected, revoking family",
+		"user_id", token.UserID,
+		"family_id", token.FamilyID,
+	)
+
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID); err != nil {
+		s.logger.Error("failed to revoke refresh token family", "family_id", token.FamilyID, "error", err)
+	}
+}
+
+// IssueTokens emite um par de tokens iniciando uma nova família de refresh tokens
+// Usado por fluxos que autenticam o usuário sem senha (ex: ativação de conta)
+func (s *AuthService) IssueTokens(ctx context.Context, user *entities.User) (*AuthResult, error) {
+	return s.issueTokens(ctx, user, uuid.New().String(), false)
+}
+
+// IssueMFATokens emite tokens de uma sessão autenticada com dois fatores
+// Usado por métodos que já verificam posse e identidade juntos (ex: passkeys)
+func (s *AuthService) IssueMFATokens(ctx context.Context, user *entities.User) (*AuthResult, error) {
+	return s.issueTokens(ctx, user, uuid.New().String(), true)
+}
+
+// issueTokens gera o par access/refresh token para o usuário e persiste
+// o hash do refresh token na família informada, junto com o cliente da requisição
+func (s *AuthService) issueTokens(ctx context.Context, user *entities.User, familyID string, mfa bool) (*AuthResult, error) {
+	accessToken, err := s.jwtService.GenerateAccessToken(user.ID, user.Email.String(), user.Role.String(), familyID, mfa)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := s.jwtService.GenerateRefreshToken(user.ID, familyID, mfa)
+	if err != nil {
+		return nil, err
+	}
+
+	client := domain.ClientInfoFromContext(ctx)
+	stored := &entities.RefreshToken{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: auth.HashToken(refreshToken),
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		ExpiresAt: time.Now().Add(s.jwtService.RefreshExpiry()),
+	}
+	if err := s.refreshTokenRepo.Create(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	return &AuthResult{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    s.jwtService.AccessExpiry(),
+		User:         user,
+	}, nil
+}
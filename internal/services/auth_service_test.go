@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/config"
+)
+
+func newTestUser(t *testing.T, id, email, password string) *entities.User {
+	t.Helper()
+
+	mail, err := valueobjects.NewEmail(email)
+	if err != nil {
+		t.Fatalf("email inválido: %v", err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("falha ao gerar hash: %v", err)
+	}
+
+	return &entities.User{
+		ID:           id,
+		Email:        mail,
+		Name:         "Test User",
+		PasswordHash: string(hash),
+		Role:         entities.RoleUser,
+		Status:       entities.UserStatusActive,
+	}
+}
+
+func newTestJWTService(t *testing.T) *auth.JWTService {
+	t.Helper()
+
+	jwtService, err := auth.NewJWTService(&config.JWTConfig{
+		Secret:        "test-secret",
+		AccessExpiry:  "15m",
+		RefreshExpiry: "168h",
+	})
+	if err != nil {
+		t.Fatalf("falha ao criar JWTService: %v", err)
+	}
+
+	return jwtService
+}
+
+// newTestPasswordHasher cria um hasher argon2id com custo mínimo para os testes
+func newTestPasswordHasher(t *testing.T) *auth.PasswordHasher {
+	t.Helper()
+
+	hasher, err := auth.NewPasswordHasher(&config.PasswordConfig{Memory: 64, Iterations: 1, Parallelism: 1})
+	if err != nil {
+		t.Fatalf("falha ao criar PasswordHasher: %v", err)
+	}
+
+	return hasher
+}
+
+func TestAuthService_Login(t *testing.T) {
+	jwtService := newTestJWTService(t)
+	user := newTestUser(t, "user-1", "user@example.com", "Senha123")
+	refreshRepo := newFakeRefreshTokenRepository()
+	service := NewAuthService(newFakeUserRepository(user), refreshRepo, newFakeMFARepository(), fakeUnitOfWork{}, jwtService, newTestPasswordHasher(t), newTestLockoutService(), nopLogger{})
+
+	t.Run("retorna tokens com credenciais válidas", func(t *testing.T) {
+		result, err := service.Login(context.Background(), "User@Example.com", "Senha123")
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		claims, err := jwtService.ValidateAccessToken(result.AccessToken)
+		if err != nil {
+			t.Fatalf("access token inválido: %v", err)
+		}
+		if claims.Subject != user.ID {
+			t.Errorf("esperava subject '%s', obteve '%s'", user.ID, claims.Subject)
+		}
+
+		if _, err := jwtService.ValidateRefreshToken(result.RefreshToken); err != nil {
+			t.Errorf("refresh token inválido: %v", err)
+		}
+
+		if _, err := refreshRepo.FindByHash(context.Background(), auth.HashToken(result.RefreshToken)); err != nil {
+			t.Errorf("esperava refresh token persistido, obteve %v", err)
+		}
+	})
+
+	t.Run("senha incorreta retorna ErrInvalidCredentials", func(t *testing.T) {
+		_, err := service.Login(context.Background(), "user@example.com", "errada123")
+		if !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+			t.Errorf("esperava ErrInvalidCredentials, obteve %v", err)
+		}
+	})
+
+	t.Run("email inexistente retorna ErrInvalidCredentials", func(t *testing.T) {
+		_, err := service.Login(context.Background(), "ninguem@example.com", "Senha123")
+		if !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+			t.Errorf("esperava ErrInvalidCredentials, obteve %v", err)
+		}
+	})
+
+	t.Run("regrava hash bcrypt legado como argon2id", func(t *testing.T) {
+		legacy := newTestUser(t, "user-3", "legacy@example.com", "Senha123")
+		service := NewAuthService(newFakeUserRepository(legacy), newFakeRefreshTokenRepository(), newFakeMFARepository(), fakeUnitOfWork{}, jwtService, newTestPasswordHasher(t), newTestLockoutService(), nopLogger{})
+
+		if _, err := service.Login(context.Background(), "legacy@example.com", "Senha123"); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if !strings.HasPrefix(legacy.PasswordHash, "$argon2id$") {
+			t.Fatalf("esperava hash argon2id, obteve '%s'", legacy.PasswordHash)
+		}
+
+		if _, err := service.Login(context.Background(), "legacy@example.com", "Senha123"); err != nil {
+			t.Errorf("esperava login com o novo hash, obteve erro: %v", err)
+		}
+	})
+
+	t.Run("conta inativa retorna ErrAccountNotActive", func(t *testing.T) {
+		inactive := newTestUser(t, "user-2", "inactive@example.com", "Senha123")
+		inactive.Status = entities.UserStatusInactive
+		service := NewAuthService(newFakeUserRepository(inactive), refreshRepo, newFakeMFARepository(), fakeUnitOfWork{}, jwtService, newTestPasswordHasher(t), newTestLockoutService(), nopLogger{})
+
+		_, err := service.Login(context.Background(), "inactive@example.com", "Senha123")
+		if !errors.Is(err, domainerrors.ErrAccountNotActive) {
+			t.Errorf("esperava ErrAccountNotActive, obteve %v", err)
+		}
+	})
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	jwtService := newTestJWTService(t)
+	user := newTestUser(t, "user-1", "user@example.com", "Senha123")
+
+	newService := func() (*AuthService, *fakeRefreshTokenRepository) {
+		refreshRepo := newFakeRefreshTokenRepository()
+		return NewAuthService(newFakeUserRepository(user), refreshRepo, newFakeMFARepository(), fakeUnitOfWork{}, jwtService, newTestPasswordHasher(t), newTestLockoutService(), nopLogger{}), refreshRepo
+	}
+
+	t.Run("rotaciona o refresh token na mesma família", func(t *testing.T) {
+		service, refreshRepo := newService()
+		login, _ := service.Login(context.Background(), "user@example.com", "Senha123")
+
+		result, err := service.Refresh(context.Background(), login.RefreshToken)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		if result.RefreshToken == login.RefreshToken {
+			t.Error("esperava um novo refresh token")
+		}
+
+		old, _ := refreshRepo.FindByHash(context.Background(), auth.HashToken(login.RefreshToken))
+		rotated, _ := refreshRepo.FindByHash(context.Background(), auth.HashToken(result.RefreshToken))
+		if !old.IsUsed() {
+			t.Error("esperava token antigo marcado como usado")
+		}
+		if rotated.FamilyID != old.FamilyID {
+			t.Errorf("esperava família '%s', obteve '%s'", old.FamilyID, rotated.FamilyID)
+		}
+	})
+
+	t.Run("reuso revoga toda a família", func(t *testing.T) {
+		service, refreshRepo := newService()
+		login, _ := service.Login(context.Background(), "user@example.com", "Senha123")
+		rotated, _ := service.Refresh(context.Background(), login.RefreshToken)
+
+		_, err := service.Refresh(context.Background(), login.RefreshToken)
+		if !errors.Is(err, domainerrors.ErrInvalidRefreshToken) {
+			t.Fatalf("esperava ErrInvalidRefreshToken, obteve %v", err)
+		}
+
+		current, _ := refreshRepo.FindByHash(context.Background(), auth.HashToken(rotated.RefreshToken))
+		if !current.IsRevoked() {
+			t.Error("esperava token mais recente da família revogado")
+		}
+
+		_, err = service.Refresh(context.Background(), rotated.RefreshToken)
+		if !errors.Is(err, domainerrors.ErrInvalidRefreshToken) {
+			t.Errorf("esperava ErrInvalidRefreshToken, obteve %v", err)
+		}
+	})
+
+	t.Run("token não persistido é rejeitado", func(t *testing.T) {
+		service, _ := newService()
+		token, _ := jwtService.GenerateRefreshToken(user.ID, "session-1", false)
+
+		_, err := service.Refresh(context.Background(), token)
+		if !errors.Is(err, domainerrors.ErrInvalidRefreshToken) {
+			t.Errorf("esperava ErrInvalidRefreshToken, obteve %v", err)
+		}
+	})
+
+	t.Run("access token é rejeitado", func(t *testing.T) {
+		service, _ := newService()
+		login, _ := service.Login(context.Background(), "user@example.com", "Senha123")
+
+		_, err := service.Refresh(context.Background(), login.AccessToken)
+		if !errors.Is(err, domainerrors.ErrInvalidRefreshToken) {
+			t.Errorf("esperava ErrInvalidRefreshToken, obteve %v", err)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+const (
+	// originCacheTTL é por quanto tempo a consulta de um origin fica em cache
+	// Mudanças nos origins de uma organização levam até esse tempo para valer
+	originCacheTTL = time.Minute
+	// maxCachedOrigins limita o cache, já que o header Origin vem do cliente
+	maxCachedOrigins = 10000
+)
+
+// cachedOrigin é o resultado em cache da consulta de um origin
+type cachedOrigin struct {
+	organizationIDs []string
+	expiresAt       time.Time
+}
+
+// CORSOriginService implementa domain.OrganizationOrigins
+// O middleware CORS consulta o origin de toda requisição cross-origin, então
+// os resultados, inclusive os vazios, ficam em cache na instância
+type CORSOriginService struct {
+	orgRepo repositories.OrganizationRepository
+	logger  domain.Logger
+	now     func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedOrigin
+}
+
+// NewCORSOriginService cria um novo CORSOriginService
+func NewCORSOriginService(orgRepo repositories.OrganizationRepository, logger domain.Logger) *CORSOriginService {
+	return &CORSOriginService{
+		orgRepo: orgRepo,
+		logger:  logger,
+		now:     time.Now,
+		cache:   make(map[string]cachedOrigin),
+	}
+}
+
+// OrganizationsAllowingOrigin retorna as organizações ativas que aceitam o origin
+func (s *CORSOriginService) OrganizationsAllowingOrigin(ctx context.Context, origin string) ([]string, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	cached, ok := s.cache[origin]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.organizationIDs, nil
+	}
+
+	ids, err := s.orgRepo.FindIDsByAllowedOrigin(ctx, origin)
+	if err != nil {
+		s.logger.Error("failed to find organizations by origin", "origin", origin, "error", err)
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if len(s.cache) >= maxCachedOrigins {
+		s.cache = make(map[string]cachedOrigin)
+	}
+	s.cache[origin] = cachedOrigin{organizationIDs: ids, expiresAt: now.Add(originCacheTTL)}
+	s.mu.Unlock()
+
+	return ids, nil
+}
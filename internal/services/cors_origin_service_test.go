@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+)
+
+func TestCORSOriginService(t *testing.T) {
+	ctx := context.Background()
+
+	newService := func() (*CORSOriginService, *fakeOrganizationRepository, *time.Time) {
+		orgs := newFakeOrganizationRepository()
+		_ = orgs.Create(ctx, &entities.Organization{ID: "org-a", Status: entities.OrganizationStatusActive, AllowedOrigins: []string{"https://app.empresa.com"}})
+		_ = orgs.Create(ctx, &entities.Organization{ID: "org-b", Status: entities.OrganizationStatusSuspended, AllowedOrigins: []string{"https://app.empresa.com"}})
+
+		now := time.Now()
+		service := NewCORSOriginService(orgs, nopLogger{})
+		service.now = func() time.Time { return now }
+		return service, orgs, &now
+	}
+
+	t.Run("retorna só organizações ativas", func(t *testing.T) {
+		service, _, _ := newService()
+
+		ids, err := service.OrganizationsAllowingOrigin(ctx, "https://app.empresa.com")
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if len(ids) != 1 || ids[0] != "org-a" {
+			t.Errorf("esperava [org-a], obteve %v", ids)
+		}
+	})
+
+	t.Run("guarda consultas em cache, inclusive vazias", func(t *testing.T) {
+		service, orgs, now := newService()
+
+		for i := 0; i < 3; i++ {
+			_, _ = service.OrganizationsAllowingOrigin(ctx, "https://app.empresa.com")
+			_, _ = service.OrganizationsAllowingOrigin(ctx, "https://outro.com")
+		}
+		if orgs.originLookups != 2 {
+			t.Errorf("esperava 2 consultas ao repositório, obteve %d", orgs.originLookups)
+		}
+
+		*now = now.Add(originCacheTTL)
+		_, _ = service.OrganizationsAllowingOrigin(ctx, "https://app.empresa.com")
+		if orgs.originLookups != 3 {
+			t.Errorf("esperava nova consulta após o TTL, obteve %d", orgs.originLookups)
+		}
+	})
+}
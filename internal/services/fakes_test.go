@@ -0,0 +1,925 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+)
+
+// nopLogger descarta todas as mensagens de log nos testes
+type nopLogger struct{}
+
+func (nopLogger) Info(string, ...any)         {}
+func (nopLogger) Error(string, ...any)        {}
+func (nopLogger) Debug(string, ...any)        {}
+func (nopLogger) Warn(string, ...any)         {}
+func (l nopLogger) With(...any) domain.Logger { return l }
+
+// fakeUserRepository é um repositório em memória para testes
+type fakeUserRepository struct {
+	users map[string]*entities.User
+}
+
+func newFakeUserRepository(users ...*entities.User) *fakeUserRepository {
+	repo := &fakeUserRepository{users: make(map[string]*entities.User)}
+	for _, u := range users {
+		repo.users[u.ID] = u
+	}
+	return repo
+}
+
+func (r *fakeUserRepository) Create(_ context.Context, user *entities.User) error {
+	for _, u := range r.users {
+		if u.Email == user.Email {
+			return domainerrors.ErrEmailAlreadyExists
+		}
+	}
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeUserRepository) FindByID(_ context.Context, id string) (*entities.User, error) {
+	if u, ok := r.users[id]; ok {
+		return u, nil
+	}
+	return nil, domainerrors.ErrUserNotFound
+}
+
+func (r *fakeUserRepository) FindByEmail(_ context.Context, email string) (*entities.User, error) {
+	for _, u := range r.users {
+		if u.Email.String() == email {
+			return u, nil
+		}
+	}
+	return nil, domainerrors.ErrUserNotFound
+}
+
+func (r *fakeUserRepository) Activate(_ context.Context, id string, verifiedAt time.Time) error {
+	u, ok := r.users[id]
+	if !ok {
+		return domainerrors.ErrUserNotFound
+	}
+	u.Status = entities.UserStatusActive
+	u.EmailVerifiedAt = &verifiedAt
+	return nil
+}
+
+func (r *fakeUserRepository) UpdatePasswordHash(_ context.Context, id, passwordHash string) error {
+	u, ok := r.users[id]
+	if !ok {
+		return domainerrors.ErrUserNotFound
+	}
+	u.PasswordHash = passwordHash
+	return nil
+}
+
+// fakeUserAccountRepository é um repositório de perfis em memória
+type fakeUserAccountRepository struct {
+	accounts map[string]*entities.UserAccount
+}
+
+func newFakeUserAccountRepository() *fakeUserAccountRepository {
+	return &fakeUserAccountRepository{accounts: make(map[string]*entities.UserAccount)}
+}
+
+func (r *fakeUserAccountRepository) Create(_ context.Context, account *entities.UserAccount) error {
+	r.accounts[account.UserID] = account
+	return nil
+}
+
+func (r *fakeUserAccountRepository) FindByUserID(_ context.Context, userID string) (*entities.UserAccount, error) {
+	if a, ok := r.accounts[userID]; ok {
+		return a, nil
+	}
+	return nil, domainerrors.ErrUserAccountNotFound
+}
+
+// fakeActivationTokenRepository é um repositório de tokens de ativação em memória
+type fakeActivationTokenRepository struct {
+	tokens map[string]*entities.ActivationToken
+}
+
+func newFakeActivationTokenRepository() *fakeActivationTokenRepository {
+	return &fakeActivationTokenRepository{tokens: make(map[string]*entities.ActivationToken)}
+}
+
+func (r *fakeActivationTokenRepository) Create(_ context.Context, token *entities.ActivationToken) error {
+	token.CreatedAt = time.Now()
+	r.tokens[token.ID] = token
+	return nil
+}
+
+func (r *fakeActivationTokenRepository) FindByHash(_ context.Context, tokenHash string) (*entities.ActivationToken, error) {
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, domainerrors.ErrInvalidActivationToken
+}
+
+func (r *fakeActivationTokenRepository) MarkAsUsed(_ context.Context, id string) error {
+	t, ok := r.tokens[id]
+	if !ok || t.IsUsed() || t.IsRevoked() {
+		return domainerrors.ErrInvalidActivationToken
+	}
+	now := time.Now()
+	t.UsedAt = &now
+	return nil
+}
+
+func (r *fakeActivationTokenRepository) RevokeByUser(_ context.Context, userID string) error {
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.UserID == userID && !t.IsUsed() && !t.IsRevoked() {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeActivationTokenRepository) CountCreatedSince(_ context.Context, userID string, since time.Time) (int64, error) {
+	var count int64
+	for _, t := range r.tokens {
+		if t.UserID == userID && !t.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// fakePasswordResetTokenRepository é um repositório de tokens de redefinição de senha em memória
+type fakePasswordResetTokenRepository struct {
+	tokens map[string]*entities.PasswordResetToken
+}
+
+func newFakePasswordResetTokenRepository() *fakePasswordResetTokenRepository {
+	return &fakePasswordResetTokenRepository{tokens: make(map[string]*entities.PasswordResetToken)}
+}
+
+func (r *fakePasswordResetTokenRepository) Create(_ context.Context, token *entities.PasswordResetToken) error {
+	token.CreatedAt = time.Now()
+	r.tokens[token.ID] = token
+	return nil
+}
+
+func (r *fakePasswordResetTokenRepository) FindByHash(_ context.Context, tokenHash string) (*entities.PasswordResetToken, error) {
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, domainerrors.ErrInvalidPasswordResetToken
+}
+
+func (r *fakePasswordResetTokenRepository) MarkAsUsed(_ context.Context, id string) error {
+	t, ok := r.tokens[id]
+	if !ok || t.IsUsed() || t.IsRevoked() {
+		return domainerrors.ErrInvalidPasswordResetToken
+	}
+	now := time.Now()
+	t.UsedAt = &now
+	return nil
+}
+
+func (r *fakePasswordResetTokenRepository) RevokeByUser(_ context.Context, userID string) error {
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.UserID == userID && !t.IsUsed() && !t.IsRevoked() {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakePasswordResetTokenRepository) CountCreatedSince(_ context.Context, userID string, since time.Time) (int64, error) {
+	var count int64
+	for _, t := range r.tokens {
+		if t.UserID == userID && !t.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// fakeNotifier guarda os tokens enviados, indexados por email
+// Com err definido, todo envio falha, como um outbox indisponível
+type fakeNotifier struct {
+	activations    map[string]string
+	invites        map[string]string
+	signupAttempts map[string]int
+	passwordResets map[string]string
+	accountLocks   map[string]domain.AccountLockedNotice
+	err            error
+}
+
+func newFakeNotifier() *fakeNotifier {
+	return &fakeNotifier{
+		activations:    make(map[string]string),
+		invites:        make(map[string]string),
+		signupAttempts: make(map[string]int),
+		passwordResets: make(map[string]string),
+		accountLocks:   make(map[string]domain.AccountLockedNotice),
+	}
+}
+
+func (n *fakeNotifier) SendActivation(_ context.Context, notice domain.ActivationNotice) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.activations[notice.Email] = notice.Token
+	return nil
+}
+
+func (n *fakeNotifier) SendInvite(_ context.Context, notice domain.InviteNotice) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.invites[notice.Email] = notice.Token
+	return nil
+}
+
+func (n *fakeNotifier) SendSignupAttempt(_ context.Context, notice domain.SignupAttemptNotice) error {
+	n.signupAttempts[notice.Email]++
+	return nil
+}
+
+func (n *fakeNotifier) SendPasswordReset(_ context.Context, notice domain.PasswordResetNotice) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.passwordResets[notice.Email] = notice.Token
+	return nil
+}
+
+func (n *fakeNotifier) SendAccountLocked(_ context.Context, notice domain.AccountLockedNotice) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.accountLocks[notice.Email] = notice
+	return nil
+}
+
+// fakeEventPublisher guarda os eventos publicados, na ordem
+type fakeEventPublisher struct {
+	events []domain.Event
+}
+
+func (p *fakeEventPublisher) Publish(_ context.Context, event domain.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+// types retorna os tipos dos eventos publicados
+func (p *fakeEventPublisher) types() []string {
+	types := make([]string, len(p.events))
+	for i, e := range p.events {
+		types[i] = e.Type
+	}
+	return types
+}
+
+// fakeInviteRepository é um repositório de convites em memória
+// Assim como o TenantPlugin, filtra pela organização do contexto
+type fakeInviteRepository struct {
+	invites map[string]*entities.Invite
+}
+
+func newFakeInviteRepository() *fakeInviteRepository {
+	return &fakeInviteRepository{invites: make(map[string]*entities.Invite)}
+}
+
+func (r *fakeInviteRepository) scoped(ctx context.Context) ([]*entities.Invite, error) {
+	organizationID, ok := domain.OrganizationIDFromContext(ctx)
+	if !ok {
+		return nil, domainerrors.ErrMissingOrganization
+	}
+
+	var invites []*entities.Invite
+	for _, i := range r.invites {
+		if i.OrganizationID == organizationID {
+			invites = append(invites, i)
+		}
+	}
+	return invites, nil
+}
+
+func (r *fakeInviteRepository) Create(ctx context.Context, invite *entities.Invite) error {
+	invites, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	for _, i := range invites {
+		if i.Email == invite.Email && i.Status == entities.InviteStatusPending {
+			return domainerrors.ErrInviteAlreadyPending
+		}
+	}
+	invite.CreatedAt = time.Now()
+	r.invites[invite.ID] = invite
+	return nil
+}
+
+func (r *fakeInviteRepository) FindByID(ctx context.Context, id string) (*entities.Invite, error) {
+	invites, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range invites {
+		if i.ID == id {
+			return i, nil
+		}
+	}
+	return nil, domainerrors.ErrInviteNotFound
+}
+
+func (r *fakeInviteRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entities.Invite, error) {
+	invites, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range invites {
+		if i.TokenHash == tokenHash {
+			copied := *i
+			return &copied, nil
+		}
+	}
+	return nil, domainerrors.ErrInvalidInviteToken
+}
+
+func (r *fakeInviteRepository) List(ctx context.Context) ([]*entities.Invite, error) {
+	return r.scoped(ctx)
+}
+
+func (r *fakeInviteRepository) ExpirePending(ctx context.Context, email string, now time.Time) error {
+	invites, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	for _, i := range invites {
+		if i.Email.String() == email && i.Status == entities.InviteStatusPending && i.IsExpired(now) {
+			i.Status = entities.InviteStatusExpired
+		}
+	}
+	return nil
+}
+
+func (r *fakeInviteRepository) Revoke(ctx context.Context, id string) error {
+	invite, err := r.FindByID(ctx, id)
+	if err != nil || invite.Status != entities.InviteStatusPending {
+		return domainerrors.ErrInviteNotFound
+	}
+	invite.Status = entities.InviteStatusRevoked
+	return nil
+}
+
+func (r *fakeInviteRepository) MarkAccepted(ctx context.Context, id, userID string, acceptedAt time.Time) error {
+	invite, err := r.FindByID(ctx, id)
+	if err != nil || invite.Status != entities.InviteStatusPending {
+		return domainerrors.ErrInvalidInviteToken
+	}
+	invite.Status = entities.InviteStatusAccepted
+	invite.AcceptedAt = &acceptedAt
+	invite.AcceptedBy = &userID
+	return nil
+}
+
+// fakeRefreshTokenRepository é um repositório de refresh tokens em memória
+type fakeRefreshTokenRepository struct {
+	tokens map[string]*entities.RefreshToken
+}
+
+func newFakeRefreshTokenRepository() *fakeRefreshTokenRepository {
+	return &fakeRefreshTokenRepository{tokens: make(map[string]*entities.RefreshToken)}
+}
+
+func (r *fakeRefreshTokenRepository) Create(_ context.Context, token *entities.RefreshToken) error {
+	token.CreatedAt = time.Now()
+	r.tokens[token.ID] = token
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) FindByHash(_ context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, domainerrors.ErrInvalidRefreshToken
+}
+
+func (r *fakeRefreshTokenRepository) MarkAsUsed(_ context.Context, id string) error {
+	t, ok := r.tokens[id]
+	if !ok || t.IsUsed() || t.IsRevoked() {
+		return domainerrors.ErrRefreshTokenReused
+	}
+	now := time.Now()
+	t.UsedAt = &now
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeFamily(_ context.Context, familyID string) error {
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.FamilyID == familyID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeByUser(_ context.Context, userID string) error {
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.UserID == userID && !t.IsUsed() && !t.IsRevoked() {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) ListSessions(_ context.Context, userID string) ([]*entities.Session, error) {
+	now := time.Now()
+	var sessions []*entities.Session
+	for _, t := range r.tokens {
+		if t.UserID != userID || t.IsUsed() || t.IsRevoked() || t.IsExpired(now) {
+			continue
+		}
+		session := &entities.Session{
+			ID:         t.FamilyID,
+			UserID:     userID,
+			IPAddress:  t.IPAddress,
+			UserAgent:  t.UserAgent,
+			CreatedAt:  t.CreatedAt,
+			LastUsedAt: t.CreatedAt,
+			ExpiresAt:  t.ExpiresAt,
+		}
+		for _, f := range r.tokens {
+			if f.FamilyID == t.FamilyID && f.CreatedAt.Before(session.CreatedAt) {
+				session.CreatedAt = f.CreatedAt
+			}
+		}
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt) })
+	return sessions, nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeSession(_ context.Context, userID, sessionID string) error {
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.FamilyID == sessionID && !t.IsUsed() && !t.IsRevoked() && !t.IsExpired(now) {
+			t.RevokedAt = &now
+			return nil
+		}
+	}
+	return domainerrors.ErrSessionNotFound
+}
+
+// fakeMFARepository é um repositório do segundo fator em memória
+type fakeMFARepository struct {
+	totp  map[string]*entities.TOTPEnrollment
+	codes map[string][]*entities.RecoveryCode
+}
+
+func newFakeMFARepository() *fakeMFARepository {
+	return &fakeMFARepository{
+		totp:  make(map[string]*entities.TOTPEnrollment),
+		codes: make(map[string][]*entities.RecoveryCode),
+	}
+}
+
+func (r *fakeMFARepository) FindTOTP(_ context.Context, userID string) (*entities.TOTPEnrollment, error) {
+	e, ok := r.totp[userID]
+	if !ok {
+		return nil, domainerrors.ErrMFANotEnrolled
+	}
+	copied := *e
+	return &copied, nil
+}
+
+func (r *fakeMFARepository) SaveTOTP(_ context.Context, enrollment *entities.TOTPEnrollment) error {
+	enrollment.ConfirmedAt = nil
+	enrollment.LastUsedStep = 0
+	enrollment.CreatedAt = time.Now()
+	copied := *enrollment
+	r.totp[enrollment.UserID] = &copied
+	return nil
+}
+
+func (r *fakeMFARepository) ConfirmTOTP(_ context.Context, userID string, step int64) error {
+	e, ok := r.totp[userID]
+	if !ok || e.IsConfirmed() {
+		return domainerrors.ErrMFAAlreadyEnabled
+	}
+	now := time.Now()
+	e.ConfirmedAt = &now
+	e.LastUsedStep = step
+	return nil
+}
+
+func (r *fakeMFARepository) UseTOTPStep(_ context.Context, userID string, step int64) error {
+	e, ok := r.totp[userID]
+	if !ok || e.LastUsedStep >= step {
+		return domainerrors.ErrInvalidMFACode
+	}
+	e.LastUsedStep = step
+	return nil
+}
+
+func (r *fakeMFARepository) DeleteTOTP(_ context.Context, userID string) error {
+	if _, ok := r.totp[userID]; !ok {
+		return domainerrors.ErrMFANotEnrolled
+	}
+	delete(r.totp, userID)
+	delete(r.codes, userID)
+	return nil
+}
+
+func (r *fakeMFARepository) ReplaceRecoveryCodes(_ context.Context, userID string, codes []*entities.RecoveryCode) error {
+	r.codes[userID] = codes
+	return nil
+}
+
+func (r *fakeMFARepository) UseRecoveryCode(_ context.Context, userID, codeHash string) error {
+	for _, c := range r.codes[userID] {
+		if c.CodeHash == codeHash && c.UsedAt == nil {
+			now := time.Now()
+			c.UsedAt = &now
+			return nil
+		}
+	}
+	return domainerrors.ErrInvalidMFACode
+}
+
+func (r *fakeMFARepository) CountRecoveryCodes(_ context.Context, userID string) (int64, error) {
+	var count int64
+	for _, c := range r.codes[userID] {
+		if c.UsedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// fakePasskeyRepository é um repositório de passkeys em memória
+type fakePasskeyRepository struct {
+	passkeys []*entities.Passkey
+}
+
+func (r *fakePasskeyRepository) Create(_ context.Context, passkey *entities.Passkey) error {
+	for _, p := range r.passkeys {
+		if p.CredentialID == passkey.CredentialID {
+			return domainerrors.ErrPasskeyAlreadyRegistered
+		}
+	}
+	passkey.CreatedAt = time.Now()
+	copied := *passkey
+	r.passkeys = append(r.passkeys, &copied)
+	return nil
+}
+
+func (r *fakePasskeyRepository) FindByCredentialID(_ context.Context, credentialID string) (*entities.Passkey, error) {
+	for _, p := range r.passkeys {
+		if p.CredentialID == credentialID {
+			copied := *p
+			return &copied, nil
+		}
+	}
+	return nil, domainerrors.ErrPasskeyNotFound
+}
+
+func (r *fakePasskeyRepository) ListByUser(_ context.Context, userID string) ([]*entities.Passkey, error) {
+	var result []*entities.Passkey
+	for _, p := range r.passkeys {
+		if p.UserID == userID {
+			copied := *p
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakePasskeyRepository) UpdateUsage(_ context.Context, id string, previousSignCount, signCount uint32, backupState bool) error {
+	for _, p := range r.passkeys {
+		if p.ID == id && p.SignCount == previousSignCount {
+			now := time.Now()
+			p.SignCount = signCount
+			p.BackupState = backupState
+			p.LastUsedAt = &now
+			return nil
+		}
+	}
+	return domainerrors.ErrInvalidPasskey
+}
+
+func (r *fakePasskeyRepository) Delete(_ context.Context, userID, id string) error {
+	for i, p := range r.passkeys {
+		if p.ID == id && p.UserID == userID {
+			r.passkeys = append(r.passkeys[:i], r.passkeys[i+1:]...)
+			return nil
+		}
+	}
+	return domainerrors.ErrPasskeyNotFound
+}
+
+// fakePasskeyChallengeRepository é um repositório de challenges WebAuthn em memória
+type fakePasskeyChallengeRepository struct {
+	challenges []*entities.PasskeyChallenge
+}
+
+func (r *fakePasskeyChallengeRepository) Create(_ context.Context, challenge *entities.PasskeyChallenge) error {
+	challenge.CreatedAt = time.Now()
+	copied := *challenge
+	r.challenges = append(r.challenges, &copied)
+	return nil
+}
+
+func (r *fakePasskeyChallengeRepository) Consume(_ context.Context, ceremony, challengeHash string) (*entities.PasskeyChallenge, error) {
+	for _, c := range r.challenges {
+		if c.Ceremony == ceremony && c.ChallengeHash == challengeHash && c.UsedAt == nil {
+			now := time.Now()
+			c.UsedAt = &now
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, domainerrors.ErrInvalidPasskey
+}
+
+// fakeUnitOfWork executa a função diretamente, sem transação
+type fakeUnitOfWork struct{}
+
+func (fakeUnitOfWork) Begin(ctx context.Context) (context.Context, error) { return ctx, nil }
+func (fakeUnitOfWork) Commit(context.Context) error                       { return nil }
+func (fakeUnitOfWork) Rollback(context.Context) error                     { return nil }
+func (fakeUnitOfWork) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+// fakeOrganizationRepository é um repositório de organizações em memória
+type fakeOrganizationRepository struct {
+	orgs          map[string]*entities.Organization
+	originLookups int
+}
+
+func newFakeOrganizationRepository() *fakeOrganizationRepository {
+	return &fakeOrganizationRepository{orgs: make(map[string]*entities.Organization)}
+}
+
+func (r *fakeOrganizationRepository) Create(_ context.Context, org *entities.Organization) error {
+	r.orgs[org.ID] = org
+	return nil
+}
+
+func (r *fakeOrganizationRepository) FindByID(_ context.Context, id string) (*entities.Organization, error) {
+	if org, ok := r.orgs[id]; ok {
+		return org, nil
+	}
+	return nil, domainerrors.ErrOrganizationNotFound
+}
+
+func (r *fakeOrganizationRepository) Update(_ context.Context, org *entities.Organization) error {
+	if _, ok := r.orgs[org.ID]; !ok {
+		return domainerrors.ErrOrganizationNotFound
+	}
+	r.orgs[org.ID] = org
+	return nil
+}
+
+func (r *fakeOrganizationRepository) Delete(_ context.Context, id string) error {
+	if _, ok := r.orgs[id]; !ok {
+		return domainerrors.ErrOrganizationNotFound
+	}
+	delete(r.orgs, id)
+	return nil
+}
+
+func (r *fakeOrganizationRepository) FindIDsByAllowedOrigin(_ context.Context, origin string) ([]string, error) {
+	r.originLookups++
+
+	var ids []string
+	for _, org := range r.orgs {
+		if !org.IsActive() {
+			continue
+		}
+		for _, o := range org.AllowedOrigins {
+			if o == origin {
+				ids = append(ids, org.ID)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
+// fakeOrganizationMemberRepository é um repositório de membros em memória
+// Membros de organizações removidas do fakeOrganizationRepository são ignorados
+type fakeOrganizationMemberRepository struct {
+	orgs    *fakeOrganizationRepository
+	members map[string]*entities.OrganizationMember
+}
+
+func newFakeOrganizationMemberRepository(orgs *fakeOrganizationRepository) *fakeOrganizationMemberRepository {
+	return &fakeOrganizationMemberRepository{orgs: orgs, members: make(map[string]*entities.OrganizationMember)}
+}
+
+func (r *fakeOrganizationMemberRepository) active(m *entities.OrganizationMember) bool {
+	_, ok := r.orgs.orgs[m.OrganizationID]
+	return ok
+}
+
+func (r *fakeOrganizationMemberRepository) withOrganization(m *entities.OrganizationMember) *entities.OrganizationMember {
+	copied := *m
+	copied.Organization = r.orgs.orgs[m.OrganizationID]
+	return &copied
+}
+
+func (r *fakeOrganizationMemberRepository) Create(_ context.Context, member *entities.OrganizationMember) error {
+	for _, m := range r.members {
+		if m.UserID == member.UserID && m.OrganizationID == member.OrganizationID {
+			return domainerrors.ErrMemberAlreadyExists
+		}
+	}
+	r.members[member.ID] = member
+	return nil
+}
+
+func (r *fakeOrganizationMemberRepository) FindByUserID(_ context.Context, userID string) ([]*entities.OrganizationMember, error) {
+	var result []*entities.OrganizationMember
+	for _, m := range r.members {
+		if m.UserID == userID && r.active(m) {
+			result = append(result, r.withOrganization(m))
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeOrganizationMemberRepository) FindByUserAndOrganization(_ context.Context, userID, organizationID string) (*entities.OrganizationMember, error) {
+	for _, m := range r.members {
+		if m.UserID == userID && m.OrganizationID == organizationID && r.active(m) {
+			return r.withOrganization(m), nil
+		}
+	}
+	return nil, domainerrors.ErrMemberNotFound
+}
+
+func (r *fakeOrganizationMemberRepository) FindByOrganization(_ context.Context, organizationID string) ([]*entities.OrganizationMember, error) {
+	var result []*entities.OrganizationMember
+	for _, m := range r.members {
+		if m.OrganizationID == organizationID && r.active(m) {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeOrganizationMemberRepository) CountByRole(_ context.Context, organizationID string, role entities.Role) (int64, error) {
+	var count int64
+	for _, m := range r.members {
+		if m.OrganizationID == organizationID && m.Role == role {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeOrganizationMemberRepository) UpdateRole(_ context.Context, id string, role entities.Role) error {
+	m, ok := r.members[id]
+	if !ok {
+		return domainerrors.ErrMemberNotFound
+	}
+	m.Role = role
+	return nil
+}
+
+func (r *fakeOrganizationMemberRepository) Delete(_ context.Context, id string) error {
+	if _, ok := r.members[id]; !ok {
+		return domainerrors.ErrMemberNotFound
+	}
+	delete(r.members, id)
+	return nil
+}
+
+// fakeOAuthStateRepository é um repositório de states OAuth2 em memória
+type fakeOAuthStateRepository struct {
+	states map[string]*entities.OAuthState
+}
+
+func newFakeOAuthStateRepository() *fakeOAuthStateRepository {
+	return &fakeOAuthStateRepository{states: make(map[string]*entities.OAuthState)}
+}
+
+func (r *fakeOAuthStateRepository) Create(_ context.Context, state *entities.OAuthState) error {
+	state.CreatedAt = time.Now()
+	r.states[state.StateHash] = state
+	return nil
+}
+
+func (r *fakeOAuthStateRepository) Consume(_ context.Context, provider, stateHash string) (*entities.OAuthState, error) {
+	s, ok := r.states[stateHash]
+	if !ok || s.Provider != provider || s.UsedAt != nil {
+		return nil, domainerrors.ErrInvalidOAuthState
+	}
+	now := time.Now()
+	s.UsedAt = &now
+	copied := *s
+	return &copied, nil
+}
+
+// fakeUserIdentityRepository é um repositório de identidades OAuth2 em memória
+type fakeUserIdentityRepository struct {
+	identities []*entities.UserIdentity
+}
+
+func (r *fakeUserIdentityRepository) Create(_ context.Context, identity *entities.UserIdentity) error {
+	for _, i := range r.identities {
+		if i.Provider == identity.Provider && i.Subject == identity.Subject {
+			return domainerrors.ErrIdentityAlreadyLinked
+		}
+	}
+	identity.CreatedAt = time.Now()
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *fakeUserIdentityRepository) FindByProviderSubject(_ context.Context, provider, subject string) (*entities.UserIdentity, error) {
+	for _, i := range r.identities {
+		if i.Provider == provider && i.Subject == subject {
+			return i, nil
+		}
+	}
+	return nil, domainerrors.ErrIdentityNotFound
+}
+
+// fakeSSOConfigRepository é um repositório de configurações de SSO em memória
+// Assim como o TenantPlugin, usa a organização do contexto
+type fakeSSOConfigRepository struct {
+	configs map[string]*entities.SSOConfig
+}
+
+func newFakeSSOConfigRepository() *fakeSSOConfigRepository {
+	return &fakeSSOConfigRepository{configs: make(map[string]*entities.SSOConfig)}
+}
+
+func (r *fakeSSOConfigRepository) Find(ctx context.Context) (*entities.SSOConfig, error) {
+	organizationID, ok := domain.OrganizationIDFromContext(ctx)
+	if !ok {
+		return nil, domainerrors.ErrMissingOrganization
+	}
+	config, ok := r.configs[organizationID]
+	if !ok {
+		return nil, domainerrors.ErrSSONotConfigured
+	}
+	return config, nil
+}
+
+func (r *fakeSSOConfigRepository) Save(ctx context.Context, config *entities.SSOConfig) error {
+	organizationID, ok := domain.OrganizationIDFromContext(ctx)
+	if !ok {
+		return domainerrors.ErrMissingOrganization
+	}
+	if config.CreatedAt.IsZero() {
+		config.CreatedAt = time.Now()
+	}
+	config.OrganizationID = organizationID
+	config.UpdatedAt = time.Now()
+	r.configs[organizationID] = config
+	return nil
+}
+
+func (r *fakeSSOConfigRepository) Delete(ctx context.Context) error {
+	organizationID, ok := domain.OrganizationIDFromContext(ctx)
+	if !ok {
+		return domainerrors.ErrMissingOrganization
+	}
+	if _, ok := r.configs[organizationID]; !ok {
+		return domainerrors.ErrSSONotConfigured
+	}
+	delete(r.configs, organizationID)
+	return nil
+}
+
+// fakeTXTResolver é um DNS em memória com os registros TXT publicados
+type fakeTXTResolver struct {
+	records map[string][]string
+}
+
+func newFakeTXTResolver() *fakeTXTResolver {
+	return &fakeTXTResolver{records: make(map[string][]string)}
+}
+
+func (r *fakeTXTResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	records, ok := r.records[name]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return records, nil
+}
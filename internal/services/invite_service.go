@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
+)
+
+// inviteTTL é a validade de um convite
+const inviteTTL = 7 * 24 * time.Hour
+
+// InviteService implementa os casos de uso de convites de organização
+//
+// A tabela invites é isolada por organização (TenantModel + RLS), então todo
+// acesso acontece dentro de UnitOfWork.WithTransaction com a organização no
+// contexto. O token do convite tem o formato "<organization_id>.<segredo>",
+// o que permite ao fluxo público de aceite selecionar a organização antes de
+// procurar o hash.
+type InviteService struct {
+	inviteRepo  repositories.InviteRepository
+	memberRepo  repositories.OrganizationMemberRepository
+	userRepo    repositories.UserRepository
+	accountRepo repositories.UserAccountRepository
+	authService *AuthService
+	notifier    domain.AccountNotifier
+	events      domain.EventPublisher
+	uow         domain.UnitOfWork
+	logger      domain.Logger
+}
+
+// NewInviteService cria um novo InviteService
+func NewInviteService(
+	inviteRepo repositories.InviteRepository,
+	memberRepo repositories.OrganizationMemberRepository,
+	userRepo repositories.UserRepository,
+	accountRepo repositories.UserAccountRepository,
+	authService *AuthService,
+	notifier domain.AccountNotifier,
+	events domain.EventPublisher,
+	uow domain.UnitOfWork,
+	logger domain.Logger,
+) *InviteService {
+	return &InviteService{
+		inviteRepo:  inviteRepo,
+		memberRepo:  memberRepo,
+		userRepo:    userRepo,
+		accountRepo: accountRepo,
+		authService: authService,
+		notifier:    notifier,
+		events:      events,
+		uow:         uow,
+		logger:      logger,
+	}
+}
+
+// CreateInviteInput contém os dados de um novo convite
+type CreateInviteInput struct {
+	Email  string
+	Role   entities.Role
+	Locale string
+}
+
+// AcceptInviteInput contém os dados do aceite de um convite
+// FullName só é usado quando o convite cria um novo usuário
+type AcceptInviteInput struct {
+	Token    string
+	Password string
+	FullName string
+}
+
+// Create convida um email para a organização e envia o token por email
+// O convite e o email são gravados na mesma transação
+func (s *InviteService) Create(ctx context.Context, userID, organizationID string, input CreateInviteInput) (*entities.Invite, error) {
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	member, err := authorizeMember(ctx, s.memberRepo, userID, organizationID, entities.PermissionInvitesWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	email, err := valueobjects.NewEmail(input.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convidar quem já é membro não faz sentido
+	if existing, err := s.userRepo.FindByEmail(ctx, email.String()); err == nil {
+		if _, err := s.memberRepo.FindByUserAndOrganization(ctx, existing.ID, organizationID); err == nil {
+			return nil, domainerrors.ErrMemberAlreadyExists
+		} else if !errors.Is(err, domainerrors.ErrMemberNotFound) {
+			return nil, err
+		}
+	} else if !errors.Is(err, domainerrors.ErrUserNotFound) {
+		return nil, err
+	}
+
+	secret, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	token := organizationID + "." + secret
+
+	now := time.Now()
+	invite := &entities.Invite{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+		Email:          email,
+		Role:           input.Role,
+		TokenHash:      auth.HashToken(token),
+		InvitedBy:      userID,
+		Status:         entities.InviteStatusPending,
+		ExpiresAt:      now.Add(inviteTTL),
+	}
+
+	notice := domain.InviteNotice{
+		Email:            email.String(),
+		Locale:           input.Locale,
+		OrganizationName: member.Organization.Name,
+		InviterName:      s.displayName(ctx, userID),
+		Role:             invite.Role.String(),
+		Token:            token,
+		ExpiresAt:        invite.ExpiresAt,
+	}
+
+	err = s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		// Convites vencidos não devem bloquear um novo convite para o mesmo email
+		if err := s.inviteRepo.ExpirePending(txCtx, email.String(), now); err != nil {
+			return err
+		}
+		if err := s.inviteRepo.Create(txCtx, invite); err != nil {
+			return err
+		}
+		return s.notifier.SendInvite(txCtx, notice)
+	})
+	if err != nil {
+		if !errors.Is(err, domainerrors.ErrInviteAlreadyPending) {
+			s.logger.Error("failed to create invite", "organization_id", organizationID, "error", err)
+		}
+		return nil, err
+	}
+
+	s.logger.Info("invite created",
+		"invite_id", invite.ID,
+		"organization_id", organizationID,
+		"role", invite.Role,
+		"user_id", userID,
+	)
+
+	return invite, nil
+}
+
+// displayName retorna o nome do perfil do usuário ou, na falta dele, o email
+func (s *InviteService) displayName(ctx context.Context, userID string) string {
+	if account, err := s.accountRepo.FindByUserID(ctx, userID); err == nil && account.FullName != nil && *account.FullName != "" {
+		return *account.FullName
+	}
+	if user, err := s.userRepo.FindByID(ctx, userID); err == nil {
+		return user.Email.String()
+	}
+	return ""
+}
+
+// List retorna os convites da organização, do mais recente ao mais antigo
+func (s *InviteService) List(ctx context.Context, userID, organizationID string) ([]*entities.Invite, error) {
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	if _, err := authorizeMember(ctx, s.memberRepo, userID, organizationID, entities.PermissionInvitesRead); err != nil {
+		return nil, err
+	}
+
+	var invites []*entities.Invite
+	err := s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		found, err := s.inviteRepo.List(txCtx)
+		if err != nil {
+			return err
+		}
+
+		invites = found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invites, nil
+}
+
+// Revoke revoga um convite pendente
+func (s *InviteService) Revoke(ctx context.Context, userID, organizationID, inviteID string) error {
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	if _, err := authorizeMember(ctx, s.memberRepo, userID, organizationID, entities.PermissionInvitesWrite); err != nil {
+		return err
+	}
+
+	err := s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		return s.inviteRepo.Revoke(txCtx, inviteID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("invite revoked", "invite_id", inviteID, "organization_id", organizationID, "user_id", userID)
+	return nil
+}
+
+// Accept aceita o convite e abre uma sessão para o convidado
+// Se o email já tem conta, a senha informada precisa conferir e o usuário é
+// apenas associado à organização; caso contrário o usuário é criado já ativo,
+// pois o convite comprova a posse do email. Tudo acontece numa única transação.
+func (s *InviteService) Accept(ctx context.Context, input AcceptInviteInput) (*ActivationResult, error) {
+	organizationID, _, ok := strings.Cut(input.Token, ".")
+	if !ok || uuid.Validate(organizationID) != nil {
+		return nil, domainerrors.ErrInvalidInviteToken
+	}
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	result := &ActivationResult{}
+	err := s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		invite, err := s.inviteRepo.FindByTokenHash(txCtx, auth.HashToken(input.Token))
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		switch invite.CurrentStatus(now) {
+		case entities.InviteStatusPending:
+		case entities.InviteStatusExpired:
+			return domainerrors.ErrInviteExpired
+		default:
+			return domainerrors.ErrInvalidInviteToken
+		}
+
+		user, err := s.findOrCreateInvitee(txCtx, invite, input, now)
+		if err != nil {
+			return err
+		}
+
+		member := &entities.OrganizationMember{
+			ID:             uuid.New().String(),
+			OrganizationID: invite.OrganizationID,
+			UserID:         user.ID,
+			Role:           invite.Role,
+			InvitedBy:      &invite.InvitedBy,
+			InvitedAt:      invite.CreatedAt,
+			JoinedAt:       &now,
+		}
+		if err := s.memberRepo.Create(txCtx, member); err != nil {
+			return err
+		}
+
+		if err := s.inviteRepo.MarkAccepted(txCtx, invite.ID, user.ID, now); err != nil {
+			return err
+		}
+
+		membership, err := s.memberRepo.FindByUserAndOrganization(txCtx, user.ID, invite.OrganizationID)
+		if err != nil {
+			return err
+		}
+
+		issued, err := s.authService.StartSession(txCtx, user)
+		if err != nil {
+			return err
+		}
+
+		result.Auth = issued
+		result.Membership = membership
+		return s.events.Publish(txCtx, domain.Event{
+			Type: domain.EventInviteAccepted,
+			Payload: domain.InviteAcceptedEvent{
+				InviteID:       invite.ID,
+				OrganizationID: invite.OrganizationID,
+				UserID:         user.ID,
+			},
+		})
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrInvalidInviteToken),
+			errors.Is(err, domainerrors.ErrInviteExpired),
+			errors.Is(err, domainerrors.ErrInvalidCredentials),
+			errors.Is(err, domainerrors.ErrAccountLocked),
+			errors.Is(err, domainerrors.ErrMemberAlreadyExists):
+		default:
+			s.logger.Error("failed to accept invite", "organization_id", organizationID, "error", err)
+		}
+		return nil, err
+	}
+
+	s.logger.Info("invite accepted", "organization_id", organizationID, "user_id", result.Auth.User.ID)
+	return result, nil
+}
+
+// findOrCreateInvitee retorna o usuário do email convidado, criando-o se necessário
+func (s *InviteService) findOrCreateInvitee(ctx context.Context, invite *entities.Invite, input AcceptInviteInput, now time.Time) (*entities.User, error) {
+	user, err := s.userRepo.FindByEmail(ctx, invite.Email.String())
+	if err == nil {
+		if err := s.authService.CheckPassword(ctx, user, input.Password); err != nil {
+			return nil, err
+		}
+
+		// O convite comprova a posse do email de uma conta ainda não ativada
+		if user.Status == entities.UserStatusInactive {
+			if err := s.userRepo.Activate(ctx, user.ID, now); err != nil {
+				return nil, err
+			}
+			user.Status = entities.UserStatusActive
+			user.EmailVerifiedAt = &now
+		}
+
+		return user, nil
+	}
+	if !errors.Is(err, domainerrors.ErrUserNotFound) {
+		return nil, err
+	}
+
+	if errs := valueobjects.ValidatePassword(input.Password); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	hash, err := s.authService.HashPassword(input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	fullName := strings.TrimSpace(input.FullName)
+
+	user = &entities.User{
+		ID:              uuid.New().String(),
+		Email:           invite.Email,
+		Name:            fullName,
+		PasswordHash:    hash,
+		Role:            entities.RoleUser,
+		Status:          entities.UserStatusActive,
+		EmailVerifiedAt: &now,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	account := &entities.UserAccount{
+		ID:       uuid.New().String(),
+		UserID:   user.ID,
+		Locale:   entities.DefaultAccountLocale,
+		Timezone: entities.DefaultAccountTimezone,
+		Theme:    entities.DefaultAccountTheme,
+	}
+	if fullName != "" {
+		account.FullName = &fullName
+	}
+	if err := s.accountRepo.Create(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+)
+
+type inviteFixture struct {
+	service  *InviteService
+	users    *fakeUserRepository
+	accounts *fakeUserAccountRepository
+	invites  *fakeInviteRepository
+	members  *fakeOrganizationMemberRepository
+	notifier *fakeNotifier
+	events   *fakeEventPublisher
+	admin    *entities.User
+	bob      *entities.User
+	orgID    string
+}
+
+func newInviteFixture(t *testing.T) *inviteFixture {
+	t.Helper()
+
+	admin := newTestUser(t, "admin", "admin@example.com", "Senha123")
+	bob := newTestUser(t, "bob", "bob@example.com", "Senha123")
+	users := newFakeUserRepository(admin, bob)
+	accounts := newFakeUserAccountRepository()
+	orgs := newFakeOrganizationRepository()
+	members := newFakeOrganizationMemberRepository(orgs)
+	invites := newFakeInviteRepository()
+	notifier := newFakeNotifier()
+	events := &fakeEventPublisher{}
+	authService := NewAuthService(users, newFakeRefreshTokenRepository(), newFakeMFARepository(), fakeUnitOfWork{}, newTestJWTService(t), newTestPasswordHasher(t), newTestLockoutService(), nopLogger{})
+
+	org, err := NewOrganizationService(orgs, members, users, newTestLockoutService(), fakeUnitOfWork{}, nopLogger{}).
+		Create(context.Background(), admin.ID, "Empresa ABC")
+	if err != nil {
+		t.Fatalf("falha ao criar organização: %v", err)
+	}
+
+	return &inviteFixture{
+		service:  NewInviteService(invites, members, users, accounts, authService, notifier, events, fakeUnitOfWork{}, nopLogger{}),
+		users:    users,
+		accounts: accounts,
+		invites:  invites,
+		members:  members,
+		notifier: notifier,
+		events:   events,
+		admin:    admin,
+		bob:      bob,
+		orgID:    org.OrganizationID,
+	}
+}
+
+// invite convida o email como user e retorna o token enviado
+func (f *inviteFixture) invite(t *testing.T, email string) string {
+	t.Helper()
+
+	_, err := f.service.Create(context.Background(), f.admin.ID, f.orgID, CreateInviteInput{Email: email, Role: entities.RoleUser})
+	if err != nil {
+		t.Fatalf("falha ao convidar: %v", err)
+	}
+
+	token, ok := f.notifier.invites[email]
+	if !ok {
+		t.Fatal("esperava convite enviado")
+	}
+	return token
+}
+
+func TestInviteService_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("apenas admins convidam", func(t *testing.T) {
+		f := newInviteFixture(t)
+		token := f.invite(t, "bob@example.com")
+		if _, err := f.service.Accept(ctx, AcceptInviteInput{Token: token, Password: "Senha123"}); err != nil {
+			t.Fatalf("falha ao aceitar convite: %v", err)
+		}
+
+		_, err := f.service.Create(ctx, f.bob.ID, f.orgID, CreateInviteInput{Email: "maria@example.com", Role: entities.RoleUser})
+		if !errors.Is(err, domainerrors.ErrForbidden) {
+			t.Errorf("esperava ErrForbidden, obteve %v", err)
+		}
+	})
+
+	t.Run("não membro não enxerga a organização", func(t *testing.T) {
+		f := newInviteFixture(t)
+
+		_, err := f.service.Create(ctx, f.bob.ID, f.orgID, CreateInviteInput{Email: "maria@example.com", Role: entities.RoleUser})
+		if !errors.Is(err, domainerrors.ErrOrganizationNotFound) {
+			t.Errorf("esperava ErrOrganizationNotFound, obteve %v", err)
+		}
+	})
+
+	t.Run("um convite pendente por email", func(t *testing.T) {
+		f := newInviteFixture(t)
+		f.invite(t, "maria@example.com")
+
+		_, err := f.service.Create(ctx, f.admin.ID, f.orgID, CreateInviteInput{Email: "maria@example.com", Role: entities.RoleUser})
+		if !errors.Is(err, domainerrors.ErrInviteAlreadyPending) {
+			t.Errorf("esperava ErrInviteAlreadyPending, obteve %v", err)
+		}
+	})
+
+	t.Run("convite vencido não bloqueia um novo", func(t *testing.T) {
+		f := newInviteFixture(t)
+		f.invite(t, "maria@example.com")
+		for _, i := range f.invites.invites {
+			i.ExpiresAt = time.Now().Add(-time.Minute)
+		}
+
+		if _, err := f.service.Create(ctx, f.admin.ID, f.orgID, CreateInviteInput{Email: "maria@example.com", Role: entities.RoleUser}); err != nil {
+			t.Errorf("esperava novo convite, obteve erro: %v", err)
+		}
+	})
+
+	t.Run("membro existente não pode ser convidado", func(t *testing.T) {
+		f := newInviteFixture(t)
+
+		_, err := f.service.Create(ctx, f.admin.ID, f.orgID, CreateInviteInput{Email: "admin@example.com", Role: entities.RoleUser})
+		if !errors.Is(err, domainerrors.ErrMemberAlreadyExists) {
+			t.Errorf("esperava ErrMemberAlreadyExists, obteve %v", err)
+		}
+	})
+}
+
+func TestInviteService_Accept(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cria usuário ativo com perfil e associação", func(t *testing.T) {
+		f := newInviteFixture(t)
+		token := f.invite(t, "maria@example.com")
+
+		result, err := f.service.Accept(ctx, AcceptInviteInput{Token: token, Password: "Senha123", FullName: "Maria Silva"})
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if result.Auth.AccessToken == "" {
+			t.Error("esperava sessão aberta")
+		}
+		if result.Membership.Role != entities.RoleUser || result.Membership.Organization.Name != "Empresa ABC" {
+			t.Errorf("associação inesperada: %+v", result.Membership)
+		}
+
+		user, err := f.users.FindByEmail(ctx, "maria@example.com")
+		if err != nil {
+			t.Fatalf("esperava usuário criado, obteve erro: %v", err)
+		}
+		if !user.IsActive() || user.EmailVerifiedAt == nil {
+			t.Error("esperava usuário ativo com email verificado")
+		}
+
+		account, err := f.accounts.FindByUserID(ctx, user.ID)
+		if err != nil || account.FullName == nil || *account.FullName != "Maria Silva" {
+			t.Errorf("esperava perfil com nome completo, obteve %+v (erro: %v)", account, err)
+		}
+
+		if len(f.events.events) != 1 || f.events.events[0].Type != domain.EventInviteAccepted {
+			t.Fatalf("esperava evento %s, obteve %v", domain.EventInviteAccepted, f.events.types())
+		}
+		if payload := f.events.events[0].Payload.(domain.InviteAcceptedEvent); payload.UserID != user.ID || payload.OrganizationID != f.orgID {
+			t.Errorf("payload inesperado: %+v", payload)
+		}
+	})
+
+	t.Run("usuário existente precisa confirmar a senha", func(t *testing.T) {
+		f := newInviteFixture(t)
+		token := f.invite(t, "bob@example.com")
+
+		if _, err := f.service.Accept(ctx, AcceptInviteInput{Token: token, Password: "errada123"}); !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+			t.Fatalf("esperava ErrInvalidCredentials, obteve %v", err)
+		}
+
+		result, err := f.service.Accept(ctx, AcceptInviteInput{Token: token, Password: "Senha123"})
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if result.Auth.User.ID != f.bob.ID {
+			t.Errorf("esperava associar o usuário existente, obteve '%s'", result.Auth.User.ID)
+		}
+	})
+
+	t.Run("senhas erradas no aceite bloqueiam a conta como no login", func(t *testing.T) {
+		f := newInviteFixture(t)
+		token := f.invite(t, "bob@example.com")
+
+		for i := 0; i < accountLockoutPolicy.delayAfter; i++ {
+			if _, err := f.service.Accept(ctx, AcceptInviteInput{Token: token, Password: "errada123"}); !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+				t.Fatalf("tentativa %d: esperava ErrInvalidCredentials, obteve %v", i+1, err)
+			}
+		}
+
+		var locked *domainerrors.LockedError
+		if _, err := f.service.Accept(ctx, AcceptInviteInput{Token: token, Password: "Senha123"}); !errors.As(err, &locked) {
+			t.Errorf("esperava LockedError sem verificar a senha, obteve %v", err)
+		}
+	})
+
+	t.Run("convite de uso único", func(t *testing.T) {
+		f := newInviteFixture(t)
+		token := f.invite(t, "maria@example.com")
+
+		if _, err := f.service.Accept(ctx, AcceptInviteInput{Token: token, Password: "Senha123"}); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if _, err := f.service.Accept(ctx, AcceptInviteInput{Token: token, Password: "Senha123"}); !errors.Is(err, domainerrors.ErrInvalidInviteToken) {
+			t.Errorf("esperava ErrInvalidInviteToken, obteve %v", err)
+		}
+	})
+
+	t.Run("convite revogado", func(t *testing.T) {
+		f := newInviteFixture(t)
+		token := f.invite(t, "maria@example.com")
+
+		invites, _ := f.service.List(ctx, f.admin.ID, f.orgID)
+		if err := f.service.Revoke(ctx, f.admin.ID, f.orgID, invites[0].ID); err != nil {
+			t.Fatalf("falha ao revogar: %v", err)
+		}
+
+		if _, err := f.service.Accept(ctx, AcceptInviteInput{Token: token, Password: "Senha123"}); !errors.Is(err, domainerrors.ErrInvalidInviteToken) {
+			t.Errorf("esperava ErrInvalidInviteToken, obteve %v", err)
+		}
+	})
+
+	t.Run("convite expirado", func(t *testing.T) {
+		f := newInviteFixture(t)
+		token := f.invite(t, "maria@example.com")
+		for _, i := range f.invites.invites {
+			i.ExpiresAt = time.Now().Add(-time.Minute)
+		}
+
+		if _, err := f.service.Accept(ctx, AcceptInviteInput{Token: token, Password: "Senha123"}); !errors.Is(err, domainerrors.ErrInviteExpired) {
+			t.Errorf("esperava ErrInviteExpired, obteve %v", err)
+		}
+	})
+
+	t.Run("token malformado", func(t *testing.T) {
+		f := newInviteFixture(t)
+
+		if _, err := f.service.Accept(ctx, AcceptInviteInput{Token: "sem-organizacao", Password: "Senha123"}); !errors.Is(err, domainerrors.ErrInvalidInviteToken) {
+			t.Errorf("esperava ErrInvalidInviteToken, obteve %v", err)
+		}
+	})
+
+	t.Run("senha fora da política para novo usuário", func(t *testing.T) {
+		f := newInviteFixture(t)
+		token := f.invite(t, "maria@example.com")
+
+		if _, err := f.service.Accept(ctx, AcceptInviteInput{Token: token, Password: "abc"}); !errors.Is(err, domainerrors.ErrPasswordLength) {
+			t.Errorf("esperava ErrPasswordLength, obteve %v", err)
+		}
+	})
+}
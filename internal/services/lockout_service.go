@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+// lockoutPolicy define os atrasos e bloqueios aplicados após falhas de login seguidas
+// A partir da falha delayAfter, cada falha bloqueia a chave por delay, que dobra
+// a cada nova falha; a partir de lockAfter, o bloqueio passa a ser lockout,
+// também dobrando, até maxLockout. As falhas são esquecidas após window sem novas falhas
+type lockoutPolicy struct {
+	delayAfter int
+	delay      time.Duration
+	lockAfter  int
+	lockout    time.Duration
+	maxLockout time.Duration
+	window     time.Duration
+}
+
+var (
+	// accountLockoutPolicy protege cada conta: atrasos a partir da 3ª falha
+	// e bloqueio de 15 minutos na 5ª (RN-05)
+	accountLockoutPolicy = lockoutPolicy{
+		delayAfter: 3,
+		delay:      time.Second,
+		lockAfter:  5,
+		lockout:    15 * time.Minute,
+		maxLockout: 24 * time.Hour,
+		window:     24 * time.Hour,
+	}
+	// secondFactorLockoutPolicy protege o segundo fator de cada usuário
+	// Tem contagem própria: a senha correta zera as falhas da conta, e quem
+	// conhece a senha poderia intercalar logins para testar códigos sem limite
+	secondFactorLockoutPolicy = lockoutPolicy{
+		delayAfter: 3,
+		delay:      time.Second,
+		lockAfter:  5,
+		lockout:    15 * time.Minute,
+		maxLockout: 24 * time.Hour,
+		window:     24 * time.Hour,
+	}
+	// ipLockoutPolicy contém quem testa senhas de várias contas a partir do mesmo IP
+	// É mais tolerante porque usuários atrás de um mesmo NAT compartilham o IP
+	ipLockoutPolicy = lockoutPolicy{
+		delayAfter: 10,
+		delay:      time.Second,
+		lockAfter:  20,
+		lockout:    15 * time.Minute,
+		maxLockout: time.Hour,
+		window:     24 * time.Hour,
+	}
+)
+
+// duration retorna por quanto tempo a chave fica bloqueada após a falha de número failures
+func (p lockoutPolicy) duration(failures int) time.Duration {
+	switch {
+	case failures < p.delayAfter:
+		return 0
+	case failures < p.lockAfter:
+		return doubled(p.delay, failures-p.delayAfter, p.lockout)
+	default:
+		return doubled(p.lockout, failures-p.lockAfter, p.maxLockout)
+	}
+}
+
+// doubled dobra base o número de vezes informado, limitado a limit
+func doubled(base time.Duration, times int, limit time.Duration) time.Duration {
+	d := base
+	for i := 0; i < times && d < limit; i++ {
+		d *= 2
+	}
+	return min(d, limit)
+}
+
+// LockoutService protege o login por senha e o segundo fator contra força bruta
+// Conta as falhas seguidas por conta (pelo email, para que emails cadastrados
+// e desconhecidos se comportem igual), por segundo fator (pelo usuário) e por
+// IP, aplicando atrasos progressivos
+// e bloqueios temporários. Falhas do armazenamento só são logadas: o login
+// continua funcionando, protegido apenas pelo rate limiting
+type LockoutService struct {
+	store        domain.LoginAttemptStore
+	accountRepo  repositories.UserAccountRepository
+	notifier     domain.AccountNotifier
+	logger       domain.Logger
+	account      lockoutPolicy
+	secondFactor lockoutPolicy
+	ip           lockoutPolicy
+	now          func() time.Time
+}
+
+// NewLockoutService cria um novo LockoutService
+func NewLockoutService(
+	store domain.LoginAttemptStore,
+	accountRepo repositories.UserAccountRepository,
+	notifier domain.AccountNotifier,
+	logger domain.Logger,
+) *LockoutService {
+	return &LockoutService{
+		store:        store,
+		accountRepo:  accountRepo,
+		notifier:     notifier,
+		logger:       logger,
+		account:      accountLockoutPolicy,
+		secondFactor: secondFactorLockoutPolicy,
+		ip:           ipLockoutPolicy,
+		now:          time.Now,
+	}
+}
+
+// Check retorna um *domainerrors.LockedError quando a conta do email ou o IP
+// da requisição estão bloqueados; o maior dos bloqueios define a espera
+func (s *LockoutService) Check(ctx context.Context, email string) error {
+	return s.check(ctx, s.keys(ctx, accountKey(email)))
+}
+
+// CheckSecondFactor é o Check do segundo fator do usuário
+func (s *LockoutService) CheckSecondFactor(ctx context.Context, userID string) error {
+	return s.check(ctx, s.keys(ctx, secondFactorKey(userID)))
+}
+
+// check retorna o maior dos bloqueios em vigor entre as chaves
+func (s *LockoutService) check(ctx context.Context, keys []string) error {
+	now := s.now()
+
+	var lockedUntil time.Time
+	for _, key := range keys {
+		failures, err := s.store.Failures(ctx, key)
+		if err != nil {
+			s.logger.Error("failed to check login failures", "error", err)
+			continue
+		}
+		if failures.LockedAt(now) && failures.LockedUntil.After(lockedUntil) {
+			lockedUntil = failures.LockedUntil
+		}
+	}
+
+	if lockedUntil.IsZero() {
+		return nil
+	}
+	return &domainerrors.LockedError{RetryAfter: lockedUntil.Sub(now)}
+}
+
+// RecordFailure registra uma falha de login para o email e o IP da requisição
+// user é nil quando o email não está cadastrado; quando a falha bloqueia a
+// conta pela primeira vez, o dono é avisado por email
+func (s *LockoutService) RecordFailure(ctx context.Context, email string, user *entities.User) {
+	client := domain.ClientInfoFromContext(ctx)
+
+	count, until := s.recordFailure(ctx, accountKey(email), s.account)
+	if count == s.account.lockAfter && user != nil {
+		s.logger.Warn("account locked after failed logins", "user_id", user.ID, "ip_address", client.IPAddress, "locked_until", until)
+		s.notifyLocked(ctx, user, client.IPAddress, until)
+	}
+
+	s.recordIPFailure(ctx, client.IPAddress)
+}
+
+// RecordSecondFactorFailure registra um código de segundo fator recusado para
+// o usuário e o IP da requisição
+// Chegar aqui exige a senha (ou outro fator primário) correta, então o dono é
+// avisado quando o bloqueio começa
+func (s *LockoutService) RecordSecondFactorFailure(ctx context.Context, user *entities.User) {
+	client := domain.ClientInfoFromContext(ctx)
+
+	count, until := s.recordFailure(ctx, secondFactorKey(user.ID), s.secondFactor)
+	if count == s.secondFactor.lockAfter {
+		s.logger.Warn("second factor locked after failed codes", "user_id", user.ID, "ip_address", client.IPAddress, "locked_until", until)
+		s.notifyLocked(ctx, user, client.IPAddress, until)
+	}
+
+	s.recordIPFailure(ctx, client.IPAddress)
+}
+
+// RecordSecondFactorSuccess esquece as falhas do segundo fator do usuário
+func (s *LockoutService) RecordSecondFactorSuccess(ctx context.Context, userID string) {
+	if err := s.store.Reset(ctx, secondFactorKey(userID)); err != nil {
+		s.logger.Error("failed to reset second factor failures", "error", err)
+	}
+}
+
+// recordIPFailure soma a falha ao IP da requisição, quando conhecido
+func (s *LockoutService) recordIPFailure(ctx context.Context, ipAddress string) {
+	if ipAddress == "" {
+		return
+	}
+	if count, until := s.recordFailure(ctx, ipKey(ipAddress), s.ip); count == s.ip.lockAfter {
+		s.logger.Warn("ip locked after failed logins", "ip_address", ipAddress, "locked_until", until)
+	}
+}
+
+// RecordSuccess esquece as falhas da conta após um login com a senha correta
+// As falhas do IP continuam valendo: uma conta própria não libera o IP
+func (s *LockoutService) RecordSuccess(ctx context.Context, email string) {
+	if err := s.store.Reset(ctx, accountKey(email)); err != nil {
+		s.logger.Error("failed to reset login failures", "error", err)
+	}
+}
+
+// Unlock remove o bloqueio e as falhas da conta e do segundo fator do usuário
+func (s *LockoutService) Unlock(ctx context.Context, user *entities.User) error {
+	for _, key := range []string{accountKey(user.Email.String()), secondFactorKey(user.ID)} {
+		if err := s.store.Reset(ctx, key); err != nil {
+			s.logger.Error("failed to unlock account", "user_id", user.ID, "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// recordFailure soma a falha à chave e aplica o bloqueio da política
+// Retorna o novo total de falhas e o fim do bloqueio, zero quando não há
+func (s *LockoutService) recordFailure(ctx context.Context, key string, policy lockoutPolicy) (int, time.Time) {
+	count, err := s.store.RecordFailure(ctx, key, policy.window)
+	if err != nil {
+		s.logger.Error("failed to record login failure", "error", err)
+		return 0, time.Time{}
+	}
+
+	d := policy.duration(count)
+	if d == 0 {
+		return count, time.Time{}
+	}
+
+	until := s.now().Add(d)
+	if err := s.store.Lock(ctx, key, until); err != nil {
+		s.logger.Error("failed to lock login", "error", err)
+		return count, time.Time{}
+	}
+	return count, until
+}
+
+// notifyLocked avisa o dono da conta sobre o bloqueio
+func (s *LockoutService) notifyLocked(ctx context.Context, user *entities.User, ipAddress string, until time.Time) {
+	locale := entities.DefaultAccountLocale
+	if account, err := s.accountRepo.FindByUserID(ctx, user.ID); err == nil {
+		locale = account.Locale
+	}
+
+	err := s.notifier.SendAccountLocked(ctx, domain.AccountLockedNotice{
+		Email:       user.Email.String(),
+		Locale:      locale,
+		IPAddress:   ipAddress,
+		LockedUntil: until,
+	})
+	if err != nil {
+		s.logger.Error("failed to send account locked notice", "user_id", user.ID, "error", err)
+	}
+}
+
+// keys retorna as chaves verificadas: a chave informada e, quando conhecido, o IP
+func (s *LockoutService) keys(ctx context.Context, key string) []string {
+	keys := []string{key}
+	if ip := domain.ClientInfoFromContext(ctx).IPAddress; ip != "" {
+		keys = append(keys, ipKey(ip))
+	}
+	return keys
+}
+
+// accountKey identifica a conta pelo hash do email normalizado, sem guardar o email
+func accountKey(email string) string {
+	sum := sha256.Sum256([]byte(email))
+	return "account:" + hex.EncodeToString(sum[:])
+}
+
+// secondFactorKey identifica o segundo fator do usuário
+func secondFactorKey(userID string) string {
+	return "mfa:" + userID
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/lockout"
+)
+
+// newTestLockoutService cria um LockoutService com armazenamento em memória
+func newTestLockoutService() *LockoutService {
+	return NewLockoutService(lockout.NewMemoryStore(), newFakeUserAccountRepository(), newFakeNotifier(), nopLogger{})
+}
+
+func TestLockoutPolicy_Duration(t *testing.T) {
+	policy := accountLockoutPolicy
+
+	for _, tc := range []struct {
+		failures int
+		want     time.Duration
+	}{
+		{2, 0},
+		{3, time.Second},
+		{4, 2 * time.Second},
+		{5, 15 * time.Minute},
+		{6, 30 * time.Minute},
+		{12, 24 * time.Hour},
+		{100, 24 * time.Hour},
+	} {
+		if got := policy.duration(tc.failures); got != tc.want {
+			t.Errorf("após %d falhas esperava %v, obteve %v", tc.failures, tc.want, got)
+		}
+	}
+}
+
+type lockoutFixture struct {
+	service  *AuthService
+	lockout  *LockoutService
+	notifier *fakeNotifier
+	now      *time.Time
+}
+
+func newLockoutFixture(t *testing.T, users ...*entities.User) *lockoutFixture {
+	t.Helper()
+
+	notifier := newFakeNotifier()
+	now := time.Now()
+
+	lockoutService := NewLockoutService(lockout.NewMemoryStore(), newFakeUserAccountRepository(), notifier, nopLogger{})
+	lockoutService.now = func() time.Time { return now }
+
+	return &lockoutFixture{
+		service:  NewAuthService(newFakeUserRepository(users...), newFakeRefreshTokenRepository(), newFakeMFARepository(), fakeUnitOfWork{}, newTestJWTService(t), newTestPasswordHasher(t), lockoutService, nopLogger{}),
+		lockout:  lockoutService,
+		notifier: notifier,
+		now:      &now,
+	}
+}
+
+// fail faz n tentativas com a senha errada, avançando o relógio além de cada atraso
+func (f *lockoutFixture) fail(ctx context.Context, email string, n int) error {
+	var err error
+	for i := 0; i < n; i++ {
+		_, err = f.service.Login(ctx, email, "errada123")
+		*f.now = f.now.Add(2 * time.Second)
+	}
+	return err
+}
+
+func TestAuthService_Lockout(t *testing.T) {
+	clientCtx := func(ip string) context.Context {
+		return domain.WithClientInfo(context.Background(), domain.ClientInfo{IPAddress: ip})
+	}
+
+	t.Run("atrasa o login a partir da 3ª falha", func(t *testing.T) {
+		f := newLockoutFixture(t, newTestUser(t, "user-1", "user@example.com", "Senha123"))
+		ctx := clientCtx("203.0.113.1")
+
+		for i := 0; i < 3; i++ {
+			if _, err := f.service.Login(ctx, "user@example.com", "errada123"); !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+				t.Fatalf("falha %d: esperava ErrInvalidCredentials, obteve %v", i+1, err)
+			}
+		}
+
+		_, err := f.service.Login(ctx, "user@example.com", "Senha123")
+		var locked *domainerrors.LockedError
+		if !errors.As(err, &locked) || !errors.Is(err, domainerrors.ErrAccountLocked) {
+			t.Fatalf("esperava LockedError, obteve %v", err)
+		}
+		if locked.RetryAfter != time.Second {
+			t.Errorf("esperava espera de 1s, obteve %v", locked.RetryAfter)
+		}
+
+		*f.now = f.now.Add(time.Second)
+		if _, err := f.service.Login(ctx, "user@example.com", "Senha123"); err != nil {
+			t.Errorf("esperava login após o atraso, obteve %v", err)
+		}
+	})
+
+	t.Run("bloqueia a conta na 5ª falha e avisa o dono", func(t *testing.T) {
+		f := newLockoutFixture(t, newTestUser(t, "user-1", "user@example.com", "Senha123"))
+		ctx := clientCtx("203.0.113.1")
+
+		_ = f.fail(ctx, "user@example.com", 5)
+
+		// Nem a senha correta é aceita durante o bloqueio, mesmo de outro IP
+		_, err := f.service.Login(clientCtx("198.51.100.9"), "user@example.com", "Senha123")
+		var locked *domainerrors.LockedError
+		if !errors.As(err, &locked) {
+			t.Fatalf("esperava LockedError, obteve %v", err)
+		}
+		if locked.RetryAfter <= 14*time.Minute || locked.RetryAfter > 15*time.Minute {
+			t.Errorf("esperava espera de ~15m, obteve %v", locked.RetryAfter)
+		}
+
+		notice, ok := f.notifier.accountLocks["user@example.com"]
+		if !ok {
+			t.Fatal("esperava aviso de bloqueio por email")
+		}
+		if notice.IPAddress != "203.0.113.1" || notice.Locale != entities.DefaultAccountLocale {
+			t.Errorf("aviso de bloqueio inesperado: %+v", notice)
+		}
+
+		*f.now = f.now.Add(15 * time.Minute)
+		if _, err := f.service.Login(ctx, "user@example.com", "Senha123"); err != nil {
+			t.Errorf("esperava login após o bloqueio, obteve %v", err)
+		}
+	})
+
+	t.Run("login bem-sucedido zera as falhas da conta", func(t *testing.T) {
+		f := newLockoutFixture(t, newTestUser(t, "user-1", "user@example.com", "Senha123"))
+		ctx := clientCtx("203.0.113.1")
+
+		_ = f.fail(ctx, "user@example.com", 4)
+		if _, err := f.service.Login(ctx, "user@example.com", "Senha123"); err != nil {
+			t.Fatalf("esperava sucesso, obteve %v", err)
+		}
+
+		if err := f.fail(ctx, "user@example.com", 1); !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+			t.Errorf("esperava contagem reiniciada, obteve %v", err)
+		}
+	})
+
+	t.Run("emails desconhecidos também são bloqueados", func(t *testing.T) {
+		f := newLockoutFixture(t)
+		ctx := clientCtx("203.0.113.1")
+
+		_ = f.fail(ctx, "ninguem@example.com", 5)
+
+		if _, err := f.service.Login(ctx, "ninguem@example.com", "Senha123"); !errors.Is(err, domainerrors.ErrAccountLocked) {
+			t.Errorf("esperava ErrAccountLocked, obteve %v", err)
+		}
+		if len(f.notifier.accountLocks) != 0 {
+			t.Errorf("não esperava aviso para email desconhecido, obteve %+v", f.notifier.accountLocks)
+		}
+	})
+
+	t.Run("bloqueia o IP após falhas em várias contas", func(t *testing.T) {
+		user := newTestUser(t, "user-1", "user@example.com", "Senha123")
+		f := newLockoutFixture(t, user)
+		ctx := clientCtx("203.0.113.1")
+
+		// Cada falha atrasa o IP um pouco mais; o relógio avança além do atraso
+		for i := 0; i < ipLockoutPolicy.lockAfter; i++ {
+			if _, err := f.service.Login(ctx, fmt.Sprintf("vitima%d@example.com", i), "errada123"); !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+				t.Fatalf("falha %d: esperava ErrInvalidCredentials, obteve %v", i+1, err)
+			}
+			*f.now = f.now.Add(10 * time.Minute)
+		}
+
+		if _, err := f.service.Login(ctx, "user@example.com", "Senha123"); !errors.Is(err, domainerrors.ErrAccountLocked) {
+			t.Errorf("esperava IP bloqueado, obteve %v", err)
+		}
+		if _, err := f.service.Login(clientCtx("198.51.100.9"), "user@example.com", "Senha123"); err != nil {
+			t.Errorf("esperava login de outro IP, obteve %v", err)
+		}
+	})
+
+	t.Run("desbloqueio libera a conta", func(t *testing.T) {
+		user := newTestUser(t, "user-1", "user@example.com", "Senha123")
+		f := newLockoutFixture(t, user)
+		ctx := clientCtx("203.0.113.1")
+
+		_ = f.fail(ctx, "user@example.com", 5)
+		if err := f.lockout.Unlock(ctx, user); err != nil {
+			t.Fatalf("esperava sucesso, obteve %v", err)
+		}
+
+		if _, err := f.service.Login(ctx, "user@example.com", "Senha123"); err != nil {
+			t.Errorf("esperava login após o desbloqueio, obteve %v", err)
+		}
+	})
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
+)
+
+const (
+	// totpIssuer é o nome exibido pelos apps autenticadores
+	totpIssuer = "AvantPro"
+	// mfaMethodTOTP identifica o segundo fator nos eventos
+	mfaMethodTOTP = "totp"
+)
+
+// MFAService implementa o cadastro do segundo fator (TOTP e códigos de recuperação)
+type MFAService struct {
+	userRepo repositories.UserRepository
+	mfaRepo  repositories.MFARepository
+	events   domain.EventPublisher
+	uow      domain.UnitOfWork
+	logger   domain.Logger
+}
+
+// NewMFAService cria um novo MFAService
+func NewMFAService(
+	userRepo repositories.UserRepository,
+	mfaRepo repositories.MFARepository,
+	events domain.EventPublisher,
+	uow domain.UnitOfWork,
+	logger domain.Logger,
+) *MFAService {
+	return &MFAService{
+		userRepo: userRepo,
+		mfaRepo:  mfaRepo,
+		events:   events,
+		uow:      uow,
+		logger:   logger,
+	}
+}
+
+// MFAStatus resume o segundo fator do usuário
+type MFAStatus struct {
+	TOTPEnabled            bool
+	RecoveryCodesRemaining int64
+}
+
+// TOTPSetup contém o que o app autenticador precisa para o cadastro
+type TOTPSetup struct {
+	Secret string
+	URI    string
+}
+
+// Status informa se o usuário tem TOTP ativo e quantos códigos de recuperação restam
+func (s *MFAService) Status(ctx context.Context, userID string) (*MFAStatus, error) {
+	enrollment, err := s.mfaRepo.FindTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrMFANotEnrolled) {
+			return &MFAStatus{}, nil
+		}
+		return nil, err
+	}
+
+	if !enrollment.IsConfirmed() {
+		return &MFAStatus{}, nil
+	}
+
+	remaining, err := s.mfaRepo.CountRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MFAStatus{TOTPEnabled: true, RecoveryCodesRemaining: remaining}, nil
+}
+
+// EnrollTOTP gera um novo segredo TOTP, ainda não exigido no login
+// Chamar de novo antes da confirmação substitui o segredo anterior
+func (s *MFAService) EnrollTOTP(ctx context.Context, userID string) (*TOTPSetup, error) {
+	if enrollment, err := s.mfaRepo.FindTOTP(ctx, userID); err == nil && enrollment.IsConfirmed() {
+		return nil, domainerrors.ErrMFAAlreadyEnabled
+	} else if err != nil && !errors.Is(err, domainerrors.ErrMFANotEnrolled) {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaRepo.SaveTOTP(ctx, &entities.TOTPEnrollment{UserID: userID, Secret: secret}); err != nil {
+		s.logger.Error("failed to save totp enrollment", "user_id", userID, "error", err)
+		return nil, err
+	}
+
+	s.logger.Info("totp enrollment started", "user_id", userID)
+	return &TOTPSetup{
+		Secret: secret,
+		URI:    auth.TOTPURI(totpIssuer, user.Email.String(), secret),
+	}, nil
+}
+
+// ConfirmTOTP ativa o TOTP com o primeiro código do app autenticador e
+// retorna os códigos de recuperação, exibidos uma única vez
+func (s *MFAService) ConfirmTOTP(ctx context.Context, userID, code string) ([]string, error) {
+	enrollment, err := s.mfaRepo.FindTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if enrollment.IsConfirmed() {
+		return nil, domainerrors.ErrMFAAlreadyEnabled
+	}
+
+	step, ok := auth.ValidateTOTP(enrollment.Secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return nil, domainerrors.ErrInvalidMFACode
+	}
+
+	codes, stored, err := newRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.mfaRepo.ConfirmTOTP(txCtx, userID, step); err != nil {
+			return err
+		}
+		if err := s.mfaRepo.ReplaceRecoveryCodes(txCtx, userID, stored); err != nil {
+			return err
+		}
+
+		return s.events.Publish(txCtx, domain.Event{
+			Type:    domain.EventMFAEnabled,
+			Payload: domain.MFAEvent{UserID: userID, Method: mfaMethodTOTP},
+		})
+	})
+	if err != nil {
+		if !errors.Is(err, domainerrors.ErrMFAAlreadyEnabled) {
+			s.logger.Error("failed to confirm totp", "user_id", userID, "error", err)
+		}
+		return nil, err
+	}
+
+	s.logger.Info("totp enabled", "user_id", userID)
+	return codes, nil
+}
+
+// DisableTOTP desativa o segundo fator mediante um código TOTP ou de recuperação
+func (s *MFAService) DisableTOTP(ctx context.Context, userID, code string) error {
+	if _, err := verifySecondFactor(ctx, s.mfaRepo, userID, code); err != nil {
+		return err
+	}
+
+	err := s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.mfaRepo.DeleteTOTP(txCtx, userID); err != nil {
+			return err
+		}
+
+		return s.events.Publish(txCtx, domain.Event{
+			Type:    domain.EventMFADisabled,
+			Payload: domain.MFAEvent{UserID: userID, Method: mfaMethodTOTP},
+		})
+	})
+	if err != nil {
+		s.logger.Error("failed to disable totp", "user_id", userID, "error", err)
+		return err
+	}
+
+	s.logger.Info("totp disabled", "user_id", userID)
+	return nil
+}
+
+// RegenerateRecoveryCodes substitui os códigos de recuperação mediante um código TOTP
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	if _, err := verifySecondFactor(ctx, s.mfaRepo, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, stored, err := newRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userID, stored); err != nil {
+		s.logger.Error("failed to replace recovery codes", "user_id", userID, "error", err)
+		return nil, err
+	}
+
+	s.logger.Info("recovery codes regenerated", "user_id", userID)
+	return codes, nil
+}
+
+// newRecoveryCodes gera os códigos em texto plano e as entidades com o hash de cada um
+func newRecoveryCodes(userID string) ([]string, []*entities.RecoveryCode, error) {
+	codes, err := auth.GenerateRecoveryCodes(entities.RecoveryCodeCount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stored := make([]*entities.RecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		stored = append(stored, &entities.RecoveryCode{
+			ID:       uuid.New().String(),
+			UserID:   userID,
+			CodeHash: auth.HashToken(code),
+		})
+	}
+
+	return codes, stored, nil
+}
+
+// verifySecondFactor confere um código TOTP ou, na falta dele, um código de
+// recuperação, que é consumido. Retorna se o código usado foi de recuperação
+// Cada passo TOTP é aceito uma única vez, então um código interceptado não
+// pode ser reapresentado
+func verifySecondFactor(ctx context.Context, mfaRepo repositories.MFARepository, userID, code string) (bool, error) {
+	enrollment, err := mfaRepo.FindTOTP(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	if !enrollment.IsConfirmed() {
+		return false, domainerrors.ErrMFANotEnrolled
+	}
+
+	code = strings.TrimSpace(code)
+	if step, ok := auth.ValidateTOTP(enrollment.Secret, code, time.Now()); ok {
+		if step <= enrollment.LastUsedStep {
+			return false, domainerrors.ErrInvalidMFACode
+		}
+		return false, mfaRepo.UseTOTPStep(ctx, userID, step)
+	}
+
+	if err := mfaRepo.UseRecoveryCode(ctx, userID, auth.HashToken(auth.NormalizeRecoveryCode(code))); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
+)
+
+type mfaFixture struct {
+	service *MFAService
+	auth    *AuthService
+	jwt     *auth.JWTService
+	user    *entities.User
+	mfa     *fakeMFARepository
+	events  *fakeEventPublisher
+}
+
+func newMFAFixture(t *testing.T) *mfaFixture {
+	t.Helper()
+
+	user := newTestUser(t, "user-1", "joao@email.com", "Senha123")
+	userRepo := newFakeUserRepository(user)
+	mfa := newFakeMFARepository()
+	events := &fakeEventPublisher{}
+	jwtService := newTestJWTService(t)
+
+	return &mfaFixture{
+		service: NewMFAService(userRepo, mfa, events, fakeUnitOfWork{}, nopLogger{}),
+		auth:    NewAuthService(userRepo, newFakeRefreshTokenRepository(), mfa, fakeUnitOfWork{}, jwtService, newTestPasswordHasher(t), newTestLockoutService(), nopLogger{}),
+		jwt:     jwtService,
+		user:    user,
+		mfa:     mfa,
+		events:  events,
+	}
+}
+
+// totpCode gera o código do instante atual deslocado por offset
+// Cada passo só é aceito uma vez, então os testes avançam 30s a cada uso
+func totpCode(t *testing.T, secret string, offset time.Duration) string {
+	t.Helper()
+
+	code, err := auth.TOTPCode(secret, time.Now().Add(offset))
+	if err != nil {
+		t.Fatalf("falha ao gerar código: %v", err)
+	}
+	return code
+}
+
+// enable cadastra e confirma o TOTP, retornando o segredo e os códigos de recuperação
+func (f *mfaFixture) enable(t *testing.T) (string, []string) {
+	t.Helper()
+
+	setup, err := f.service.EnrollTOTP(context.Background(), f.user.ID)
+	if err != nil {
+		t.Fatalf("falha ao iniciar cadastro: %v", err)
+	}
+
+	codes, err := f.service.ConfirmTOTP(context.Background(), f.user.ID, totpCode(t, setup.Secret, 0))
+	if err != nil {
+		t.Fatalf("falha ao confirmar cadastro: %v", err)
+	}
+	return setup.Secret, codes
+}
+
+func TestMFAService_Enrollment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cadastro não confirmado não é exigido no login", func(t *testing.T) {
+		f := newMFAFixture(t)
+		setup, err := f.service.EnrollTOTP(ctx, f.user.ID)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if !strings.HasPrefix(setup.URI, "otpauth://totp/AvantPro:joao@email.com?") || !strings.Contains(setup.URI, "secret="+setup.Secret) {
+			t.Errorf("URI inesperada: '%s'", setup.URI)
+		}
+
+		result, err := f.auth.Login(ctx, f.user.Email.String(), "Senha123")
+		if err != nil || result.MFARequired() {
+			t.Errorf("esperava tokens sem desafio, obteve %+v (%v)", result, err)
+		}
+	})
+
+	t.Run("confirmação ativa o TOTP e gera códigos de recuperação", func(t *testing.T) {
+		f := newMFAFixture(t)
+		_, codes := f.enable(t)
+
+		if len(codes) != entities.RecoveryCodeCount {
+			t.Errorf("esperava %d códigos, obteve %d", entities.RecoveryCodeCount, len(codes))
+		}
+		for _, stored := range f.mfa.codes[f.user.ID] {
+			for _, code := range codes {
+				if stored.CodeHash == code {
+					t.Fatal("não esperava código em texto plano")
+				}
+			}
+		}
+
+		status, _ := f.service.Status(ctx, f.user.ID)
+		if !status.TOTPEnabled || status.RecoveryCodesRemaining != int64(entities.RecoveryCodeCount) {
+			t.Errorf("status inesperado: %+v", status)
+		}
+		if types := f.events.types(); len(types) != 1 || types[0] != domain.EventMFAEnabled {
+			t.Errorf("esperava evento %s, obteve %v", domain.EventMFAEnabled, types)
+		}
+	})
+
+	t.Run("código errado não confirma o cadastro", func(t *testing.T) {
+		f := newMFAFixture(t)
+		setup, _ := f.service.EnrollTOTP(ctx, f.user.ID)
+
+		wrong := totpCode(t, setup.Secret, 5*time.Minute)
+		if _, err := f.service.ConfirmTOTP(ctx, f.user.ID, wrong); !errors.Is(err, domainerrors.ErrInvalidMFACode) {
+			t.Errorf("esperava ErrInvalidMFACode, obteve %v", err)
+		}
+	})
+
+	t.Run("não reinicia cadastro já ativo", func(t *testing.T) {
+		f := newMFAFixture(t)
+		f.enable(t)
+
+		if _, err := f.service.EnrollTOTP(ctx, f.user.ID); !errors.Is(err, domainerrors.ErrMFAAlreadyEnabled) {
+			t.Errorf("esperava ErrMFAAlreadyEnabled, obteve %v", err)
+		}
+	})
+
+	t.Run("desativar exige código válido", func(t *testing.T) {
+		f := newMFAFixture(t)
+		secret, _ := f.enable(t)
+
+		if err := f.service.DisableTOTP(ctx, f.user.ID, "000000"); !errors.Is(err, domainerrors.ErrInvalidMFACode) {
+			t.Errorf("esperava ErrInvalidMFACode, obteve %v", err)
+		}
+		if err := f.service.DisableTOTP(ctx, f.user.ID, totpCode(t, secret, 30*time.Second)); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		status, _ := f.service.Status(ctx, f.user.ID)
+		if status.TOTPEnabled || status.RecoveryCodesRemaining != 0 {
+			t.Errorf("esperava 2FA desativado, obteve %+v", status)
+		}
+		if types := f.events.types(); types[len(types)-1] != domain.EventMFADisabled {
+			t.Errorf("esperava evento %s, obteve %v", domain.EventMFADisabled, types)
+		}
+	})
+
+	t.Run("regenerar invalida os códigos anteriores", func(t *testing.T) {
+		f := newMFAFixture(t)
+		secret, old := f.enable(t)
+
+		codes, err := f.service.RegenerateRecoveryCodes(ctx, f.user.ID, totpCode(t, secret, 30*time.Second))
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if codes[0] == old[0] {
+			t.Error("esperava novos códigos")
+		}
+		if err := f.service.DisableTOTP(ctx, f.user.ID, old[0]); !errors.Is(err, domainerrors.ErrInvalidMFACode) {
+			t.Errorf("esperava código antigo rejeitado, obteve %v", err)
+		}
+	})
+}
+
+func TestAuthService_VerifyMFA(t *testing.T) {
+	ctx := context.Background()
+
+	// challenge faz login com senha e retorna o token de desafio
+	challenge := func(t *testing.T, f *mfaFixture) string {
+		t.Helper()
+
+		result, err := f.auth.Login(ctx, f.user.Email.String(), "Senha123")
+		if err != nil {
+			t.Fatalf("falha no login: %v", err)
+		}
+		if !result.MFARequired() || result.AccessToken != "" || result.RefreshToken != "" {
+			t.Fatalf("esperava apenas o desafio, obteve %+v", result)
+		}
+		return result.MFAToken
+	}
+
+	t.Run("código TOTP conclui o login com sessão mfa", func(t *testing.T) {
+		f := newMFAFixture(t)
+		secret, _ := f.enable(t)
+
+		result, err := f.auth.VerifyMFA(ctx, challenge(t, f), totpCode(t, secret, 30*time.Second))
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		claims, err := f.jwt.ValidateAccessToken(result.AccessToken)
+		if err != nil || !claims.MFA {
+			t.Fatalf("esperava access token com mfa, obteve %+v (%v)", claims, err)
+		}
+
+		refreshed, err := f.auth.Refresh(ctx, result.RefreshToken)
+		if err != nil {
+			t.Fatalf("falha no refresh: %v", err)
+		}
+		if claims, _ := f.jwt.ValidateAccessToken(refreshed.AccessToken); !claims.MFA {
+			t.Error("esperava mfa preservado na rotação")
+		}
+	})
+
+	t.Run("código TOTP não pode ser reapresentado", func(t *testing.T) {
+		f := newMFAFixture(t)
+		secret, _ := f.enable(t)
+		code := totpCode(t, secret, 30*time.Second)
+
+		if _, err := f.auth.VerifyMFA(ctx, challenge(t, f), code); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if _, err := f.auth.VerifyMFA(ctx, challenge(t, f), code); !errors.Is(err, domainerrors.ErrInvalidMFACode) {
+			t.Errorf("esperava ErrInvalidMFACode, obteve %v", err)
+		}
+	})
+
+	t.Run("códigos errados bloqueiam o segundo fator mesmo com desafios novos", func(t *testing.T) {
+		f := newMFAFixture(t)
+		secret, _ := f.enable(t)
+
+		// Cada login com a senha correta emite um desafio novo e zera as falhas da conta
+		for i := 0; i < secondFactorLockoutPolicy.delayAfter; i++ {
+			if _, err := f.auth.VerifyMFA(ctx, challenge(t, f), "000000"); !errors.Is(err, domainerrors.ErrInvalidMFACode) {
+				t.Fatalf("tentativa %d: esperava ErrInvalidMFACode, obteve %v", i+1, err)
+			}
+		}
+
+		var locked *domainerrors.LockedError
+		if _, err := f.auth.VerifyMFA(ctx, challenge(t, f), totpCode(t, secret, 30*time.Second)); !errors.As(err, &locked) {
+			t.Errorf("esperava LockedError sem verificar o código, obteve %v", err)
+		}
+	})
+
+	t.Run("código de recuperação vale uma única vez", func(t *testing.T) {
+		f := newMFAFixture(t)
+		_, codes := f.enable(t)
+		typed := strings.ToUpper(codes[0])
+
+		if _, err := f.auth.VerifyMFA(ctx, challenge(t, f), typed); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if _, err := f.auth.VerifyMFA(ctx, challenge(t, f), typed); !errors.Is(err, domainerrors.ErrInvalidMFACode) {
+			t.Errorf("esperava ErrInvalidMFACode, obteve %v", err)
+		}
+
+		status, _ := f.service.Status(ctx, f.user.ID)
+		if status.RecoveryCodesRemaining != int64(entities.RecoveryCodeCount-1) {
+			t.Errorf("esperava %d códigos restantes, obteve %d", entities.RecoveryCodeCount-1, status.RecoveryCodesRemaining)
+		}
+	})
+
+	t.Run("desafio inválido retorna ErrInvalidMFAToken", func(t *testing.T) {
+		f := newMFAFixture(t)
+		secret, _ := f.enable(t)
+		login, _ := f.auth.IssueTokens(ctx, f.user)
+
+		for _, token := range []string{"invalido", login.AccessToken} {
+			if _, err := f.auth.VerifyMFA(ctx, token, totpCode(t, secret, 30*time.Second)); !errors.Is(err, domainerrors.ErrInvalidMFAToken) {
+				t.Errorf("esperava ErrInvalidMFAToken, obteve %v", err)
+			}
+		}
+	})
+
+	t.Run("desafio emitido antes de desativar o 2FA é inválido", func(t *testing.T) {
+		f := newMFAFixture(t)
+		secret, _ := f.enable(t)
+		token := challenge(t, f)
+
+		if err := f.service.DisableTOTP(ctx, f.user.ID, totpCode(t, secret, 30*time.Second)); err != nil {
+			t.Fatalf("falha ao desativar: %v", err)
+		}
+		if _, err := f.auth.VerifyMFA(ctx, token, totpCode(t, secret, 30*time.Second)); !errors.Is(err, domainerrors.ErrInvalidMFAToken) {
+			t.Errorf("esperava ErrInvalidMFAToken, obteve %v", err)
+		}
+	})
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
+)
+
+// oauthStateTTL é o tempo que o usuário tem para concluir o login no provedor
+const oauthStateTTL = 10 * time.Minute
+
+// OAuthService implementa o login social com provedores OAuth2
+//
+// O state e o verifier PKCE ficam no banco (apenas o hash do state) e são
+// consumidos uma única vez no callback. A identidade devolvida pelo provedor é
+// vinculada ao usuário com o mesmo email verificado ou a um novo usuário, já
+// ativo, sem senha.
+type OAuthService struct {
+	providers    map[string]domain.OAuthProvider
+	stateRepo    repositories.OAuthStateRepository
+	identityRepo repositories.UserIdentityRepository
+	userRepo     repositories.UserRepository
+	accountRepo  repositories.UserAccountRepository
+	authService  *AuthService
+	uow          domain.UnitOfWork
+	logger       domain.Logger
+}
+
+// NewOAuthService cria um novo OAuthService com os provedores configurados
+func NewOAuthService(
+	providers []domain.OAuthProvider,
+	stateRepo repositories.OAuthStateRepository,
+	identityRepo repositories.UserIdentityRepository,
+	userRepo repositories.UserRepository,
+	accountRepo repositories.UserAccountRepository,
+	authService *AuthService,
+	uow domain.UnitOfWork,
+	logger domain.Logger,
+) *OAuthService {
+	byName := make(map[string]domain.OAuthProvider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+
+	return &OAuthService{
+		providers:    byName,
+		stateRepo:    stateRepo,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		accountRepo:  accountRepo,
+		authService:  authService,
+		uow:          uow,
+		logger:       logger,
+	}
+}
+
+// OAuthStart contém a URL de autorização do provedor e o state emitido
+type OAuthStart struct {
+	AuthURL string
+	State   string
+}
+
+// OAuthCallbackInput contém os parâmetros devolvidos pelo provedor
+// Locale só é usado quando o login cria um novo usuário
+type OAuthCallbackInput struct {
+	Provider string
+	State    string
+	Code     string
+	Locale   string
+}
+
+// Start emite o state e o verifier PKCE e retorna a URL de autorização
+func (s *OAuthService) Start(ctx context.Context, providerName string) (*OAuthStart, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, domainerrors.ErrOAuthProviderNotSupported
+	}
+
+	state, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	// 32 bytes em base64url resultam num verifier de 43 caracteres, o mínimo da RFC 7636
+	verifier, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &entities.OAuthState{
+		ID:           uuid.New().String(),
+		Provider:     providerName,
+		StateHash:    auth.HashToken(state),
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(oauthStateTTL),
+	}
+	if err := s.stateRepo.Create(ctx, stored); err != nil {
+		s.logger.Error("failed to store oauth state", "provider", providerName, "error", err)
+		return nil, err
+	}
+
+	return &OAuthStart{
+		AuthURL: provider.AuthCodeURL(state, verifier),
+		State:   state,
+	}, nil
+}
+
+// Callback consome o state, troca o código pela identidade do provedor e abre uma sessão
+func (s *OAuthService) Callback(ctx context.Context, input OAuthCallbackInput) (*AuthResult, error) {
+	provider, ok := s.providers[input.Provider]
+	if !ok {
+		return nil, domainerrors.ErrOAuthProviderNotSupported
+	}
+
+	stored, err := s.stateRepo.Consume(ctx, input.Provider, auth.HashToken(input.State))
+	if err != nil {
+		if !errors.Is(err, domainerrors.ErrInvalidOAuthState) {
+			s.logger.Error("failed to consume oauth state", "provider", input.Provider, "error", err)
+		}
+		return nil, err
+	}
+	if stored.IsExpired(time.Now()) {
+		return nil, domainerrors.ErrInvalidOAuthState
+	}
+
+	identity, err := provider.Exchange(ctx, input.Code, stored.CodeVerifier)
+	if err != nil {
+		s.logger.Warn("oauth exchange failed", "provider", input.Provider, "error", err)
+		return nil, domainerrors.ErrOAuthExchangeFailed
+	}
+
+	var result *AuthResult
+	err = s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		user, err := s.resolveUser(txCtx, identity, input.Locale)
+		if err != nil {
+			return err
+		}
+
+		if !user.IsActive() {
+			return domainerrors.ErrAccountNotActive
+		}
+
+		issued, err := s.authService.StartSession(txCtx, user)
+		if err != nil {
+			return err
+		}
+
+		result = issued
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrOAuthEmailNotVerified),
+			errors.Is(err, domainerrors.ErrAccountNotActive):
+			s.logger.Info("oauth login rejected", "provider", input.Provider, "reason", err.Error())
+		default:
+			s.logger.Error("failed to complete oauth login", "provider", input.Provider, "error", err)
+		}
+		return nil, err
+	}
+
+	s.logger.Info("user logged in", "user_id", result.User.ID, "provider", input.Provider)
+	return result, nil
+}
+
+// resolveUser retorna o usuário vinculado à identidade, vinculando-a ao
+// usuário com o mesmo email ou criando um novo usuário quando necessário
+func (s *OAuthService) resolveUser(ctx context.Context, identity *domain.OAuthIdentity, locale string) (*entities.User, error) {
+	linked, err := s.identityRepo.FindByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return s.userRepo.FindByID(ctx, linked.UserID)
+	}
+	if !errors.Is(err, domainerrors.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	// Sem email verificado, vincular por email permitiria tomar a conta de outra pessoa
+	if !identity.EmailVerified {
+		return nil, domainerrors.ErrOAuthEmailNotVerified
+	}
+
+	email, err := valueobjects.NewEmail(identity.Email)
+	if err != nil {
+		return nil, domainerrors.ErrOAuthEmailNotVerified
+	}
+
+	now := time.Now()
+
+	user, err := s.userRepo.FindByEmail(ctx, email.String())
+	switch {
+	case err == nil:
+		if user.Status == entities.UserStatusInactive {
+			if err := activateExternalUser(ctx, s.userRepo, user, now); err != nil {
+				return nil, err
+			}
+		}
+	case errors.Is(err, domainerrors.ErrUserNotFound):
+		user, err = createExternalUser(ctx, s.userRepo, s.accountRepo, email, identity, locale, now)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	link := &entities.UserIdentity{
+		ID:       uuid.New().String(),
+		UserID:   user.ID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    email.String(),
+	}
+	if err := s.identityRepo.Create(ctx, link); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("oauth identity linked", "user_id", user.ID, "provider", identity.Provider)
+	return user, nil
+}
+
+// activateExternalUser ativa a conta pendente cujo email o provedor comprovou
+// A senha é descartada: quem fez o cadastro não comprovou a posse do email e
+// pode ser outra pessoa tentando tomar a conta antes do dono (pré-sequestro);
+// o dono define uma senha pela recuperação de senha, se quiser
+func activateExternalUser(ctx context.Context, userRepo repositories.UserRepository, user *entities.User, now time.Time) error {
+	if err := userRepo.Activate(ctx, user.ID, now); err != nil {
+		return err
+	}
+	if err := userRepo.UpdatePasswordHash(ctx, user.ID, ""); err != nil {
+		return err
+	}
+
+	user.Status = entities.UserStatusActive
+	user.EmailVerifiedAt = &now
+	user.PasswordHash = ""
+	return nil
+}
+
+// createExternalUser cria um usuário ativo, sem senha, com o perfil vindo do provedor
+// Usado pelos logins OAuth2 e SSO, em que o provedor comprova a posse do email
+func createExternalUser(
+	ctx context.Context,
+	userRepo repositories.UserRepository,
+	accountRepo repositories.UserAccountRepository,
+	email valueobjects.Email,
+	identity *domain.OAuthIdentity,
+	locale string,
+	now time.Time,
+) (*entities.User, error) {
+	name := strings.TrimSpace(identity.Name)
+
+	user := &entities.User{
+		ID:              uuid.New().String(),
+		Email:           email,
+		Name:            name,
+		Role:            entities.RoleUser,
+		Status:          entities.UserStatusActive,
+		EmailVerifiedAt: &now,
+	}
+	if identity.AvatarURL != "" {
+		user.AvatarURL = &identity.AvatarURL
+	}
+	if err := userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if locale == "" {
+		locale = entities.DefaultAccountLocale
+	}
+
+	account := &entities.UserAccount{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		AvatarURL: user.AvatarURL,
+		Locale:    locale,
+		Timezone:  entities.DefaultAccountTimezone,
+		Theme:     entities.DefaultAccountTheme,
+	}
+	if name != "" {
+		account.FullName = &name
+	}
+	if err := accountRepo.Create(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
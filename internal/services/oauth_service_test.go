@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/oauth"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/oauth/oauthtest"
+)
+
+type oauthFixture struct {
+	service    *OAuthService
+	server     *oauthtest.Server
+	users      *fakeUserRepository
+	accounts   *fakeUserAccountRepository
+	states     *fakeOAuthStateRepository
+	identities *fakeUserIdentityRepository
+}
+
+// newOAuthFixture configura o provedor Google contra um servidor de autorização falso
+func newOAuthFixture(t *testing.T, users ...*entities.User) *oauthFixture {
+	t.Helper()
+
+	server := oauthtest.NewServer("client-id", "client-secret")
+	t.Cleanup(server.Close)
+
+	google := oauth.NewGoogleProvider(oauth.Config{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://localhost:8080/api/v1/auth/oauth/google/callback",
+		AuthURL:      server.URL + "/authorize",
+		TokenURL:     server.URL + "/token",
+		APIBaseURL:   server.URL,
+	})
+
+	userRepo := newFakeUserRepository(users...)
+	accounts := newFakeUserAccountRepository()
+	states := newFakeOAuthStateRepository()
+	identities := &fakeUserIdentityRepository{}
+	authService := NewAuthService(userRepo, newFakeRefreshTokenRepository(), newFakeMFARepository(), fakeUnitOfWork{}, newTestJWTService(t), newTestPasswordHasher(t), newTestLockoutService(), nopLogger{})
+
+	return &oauthFixture{
+		service: NewOAuthService(
+			[]domain.OAuthProvider{google}, states, identities, userRepo, accounts,
+			authService, fakeUnitOfWork{}, nopLogger{},
+		),
+		server:     server,
+		users:      userRepo,
+		accounts:   accounts,
+		states:     states,
+		identities: identities,
+	}
+}
+
+// authorize inicia o login e autoriza no provedor falso, retornando o callback
+func (f *oauthFixture) authorize(t *testing.T) OAuthCallbackInput {
+	t.Helper()
+
+	start, err := f.service.Start(context.Background(), oauth.ProviderGoogle)
+	if err != nil {
+		t.Fatalf("falha ao iniciar login: %v", err)
+	}
+
+	code, state, err := f.server.Authorize(start.AuthURL)
+	if err != nil {
+		t.Fatalf("falha na autorização: %v", err)
+	}
+	if state != start.State {
+		t.Fatalf("esperava state '%s' no callback, obteve '%s'", start.State, state)
+	}
+
+	return OAuthCallbackInput{Provider: oauth.ProviderGoogle, State: state, Code: code, Locale: "en"}
+}
+
+func TestOAuthService_Start(t *testing.T) {
+	f := newOAuthFixture(t)
+
+	t.Run("persiste apenas o hash do state", func(t *testing.T) {
+		start, err := f.service.Start(context.Background(), oauth.ProviderGoogle)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if _, ok := f.states.states[start.State]; ok {
+			t.Error("o state não deveria ser armazenado em texto plano")
+		}
+		if len(f.states.states) != 1 {
+			t.Errorf("esperava 1 state armazenado, obteve %d", len(f.states.states))
+		}
+	})
+
+	t.Run("provedor desconhecido", func(t *testing.T) {
+		if _, err := f.service.Start(context.Background(), "myspace"); !errors.Is(err, domainerrors.ErrOAuthProviderNotSupported) {
+			t.Errorf("esperava ErrOAuthProviderNotSupported, obteve %v", err)
+		}
+	})
+}
+
+func TestOAuthService_Callback(t *testing.T) {
+	ctx := context.Background()
+	maria := oauthtest.User{
+		Subject:       "g-123",
+		Email:         "Maria@Gmail.com",
+		EmailVerified: true,
+		Name:          "Maria Silva",
+		AvatarURL:     "https://example.com/maria.png",
+	}
+
+	t.Run("cria usuário ativo sem senha no primeiro login", func(t *testing.T) {
+		f := newOAuthFixture(t)
+		f.server.SetUser(maria)
+
+		result, err := f.service.Callback(ctx, f.authorize(t))
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if result.AccessToken == "" || result.RefreshToken == "" {
+			t.Error("esperava tokens emitidos")
+		}
+
+		user, err := f.users.FindByEmail(ctx, "maria@gmail.com")
+		if err != nil {
+			t.Fatalf("esperava usuário criado, obteve erro: %v", err)
+		}
+		if !user.IsActive() || user.EmailVerifiedAt == nil || user.PasswordHash != "" {
+			t.Errorf("esperava usuário ativo, verificado e sem senha: %+v", user)
+		}
+
+		account, err := f.accounts.FindByUserID(ctx, user.ID)
+		if err != nil || account.FullName == nil || *account.FullName != "Maria Silva" || account.Locale != "en" {
+			t.Errorf("perfil inesperado: %+v (erro: %v)", account, err)
+		}
+
+		if len(f.identities.identities) != 1 || f.identities.identities[0].UserID != user.ID {
+			t.Errorf("esperava identidade vinculada ao usuário, obteve %+v", f.identities.identities)
+		}
+	})
+
+	t.Run("logins seguintes usam o vínculo existente", func(t *testing.T) {
+		f := newOAuthFixture(t)
+		f.server.SetUser(maria)
+
+		first, err := f.service.Callback(ctx, f.authorize(t))
+		if err != nil {
+			t.Fatalf("falha no primeiro login: %v", err)
+		}
+
+		// O email no provedor mudou, mas o subject continua o mesmo
+		changed := maria
+		changed.Email = "maria.silva@gmail.com"
+		f.server.SetUser(changed)
+
+		second, err := f.service.Callback(ctx, f.authorize(t))
+		if err != nil {
+			t.Fatalf("falha no segundo login: %v", err)
+		}
+		if second.User.ID != first.User.ID || len(f.users.users) != 1 {
+			t.Error("esperava o mesmo usuário nos dois logins")
+		}
+	})
+
+	t.Run("vincula ao usuário existente com o mesmo email", func(t *testing.T) {
+		existing := newTestUser(t, "maria", "maria@gmail.com", "Senha123")
+		existing.Status = entities.UserStatusInactive
+		f := newOAuthFixture(t, existing)
+		f.server.SetUser(maria)
+
+		result, err := f.service.Callback(ctx, f.authorize(t))
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if result.User.ID != "maria" {
+			t.Errorf("esperava o usuário existente, obteve '%s'", result.User.ID)
+		}
+		if !existing.IsActive() || existing.EmailVerifiedAt == nil {
+			t.Error("esperava conta pendente ativada pelo email verificado no provedor")
+		}
+	})
+
+	t.Run("descarta a senha da conta pendente cadastrada por outra pessoa", func(t *testing.T) {
+		// Alguém se cadastrou antes com o email da Maria e definiu a própria senha
+		preRegistered := newTestUser(t, "maria", "maria@gmail.com", "SenhaDoAtacante1")
+		preRegistered.Status = entities.UserStatusInactive
+		f := newOAuthFixture(t, preRegistered)
+		f.server.SetUser(maria)
+
+		if _, err := f.service.Callback(ctx, f.authorize(t)); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		user, _ := f.users.FindByID(ctx, "maria")
+		if user.PasswordHash != "" {
+			t.Error("esperava a senha do cadastro pendente descartada")
+		}
+	})
+
+	t.Run("conta já ativa mantém a senha", func(t *testing.T) {
+		existing := newTestUser(t, "maria", "maria@gmail.com", "Senha123")
+		hash := existing.PasswordHash
+		f := newOAuthFixture(t, existing)
+		f.server.SetUser(maria)
+
+		if _, err := f.service.Callback(ctx, f.authorize(t)); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if existing.PasswordHash != hash {
+			t.Error("não esperava alteração na senha de conta ativa")
+		}
+	})
+
+	t.Run("email não verificado não vincula nem cria usuário", func(t *testing.T) {
+		existing := newTestUser(t, "maria", "maria@gmail.com", "Senha123")
+		f := newOAuthFixture(t, existing)
+		unverified := maria
+		unverified.EmailVerified = false
+		f.server.SetUser(unverified)
+
+		if _, err := f.service.Callback(ctx, f.authorize(t)); !errors.Is(err, domainerrors.ErrOAuthEmailNotVerified) {
+			t.Errorf("esperava ErrOAuthEmailNotVerified, obteve %v", err)
+		}
+		if len(f.identities.identities) != 0 {
+			t.Error("não esperava identidade vinculada")
+		}
+	})
+
+	t.Run("conta suspensa não abre sessão", func(t *testing.T) {
+		existing := newTestUser(t, "maria", "maria@gmail.com", "Senha123")
+		existing.Status = entities.UserStatusSuspended
+		f := newOAuthFixture(t, existing)
+		f.server.SetUser(maria)
+
+		if _, err := f.service.Callback(ctx, f.authorize(t)); !errors.Is(err, domainerrors.ErrAccountNotActive) {
+			t.Errorf("esperava ErrAccountNotActive, obteve %v", err)
+		}
+	})
+
+	t.Run("state de uso único", func(t *testing.T) {
+		f := newOAuthFixture(t)
+		f.server.SetUser(maria)
+		input := f.authorize(t)
+
+		if _, err := f.service.Callback(ctx, input); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if _, err := f.service.Callback(ctx, input); !errors.Is(err, domainerrors.ErrInvalidOAuthState) {
+			t.Errorf("esperava ErrInvalidOAuthState, obteve %v", err)
+		}
+	})
+
+	t.Run("state desconhecido", func(t *testing.T) {
+		f := newOAuthFixture(t)
+		f.server.SetUser(maria)
+		input := f.authorize(t)
+		input.State = "forjado"
+
+		if _, err := f.service.Callback(ctx, input); !errors.Is(err, domainerrors.ErrInvalidOAuthState) {
+			t.Errorf("esperava ErrInvalidOAuthState, obteve %v", err)
+		}
+	})
+
+	t.Run("state expirado", func(t *testing.T) {
+		f := newOAuthFixture(t)
+		f.server.SetUser(maria)
+		input := f.authorize(t)
+		for _, s := range f.states.states {
+			s.ExpiresAt = time.Now().Add(-time.Minute)
+		}
+
+		if _, err := f.service.Callback(ctx, input); !errors.Is(err, domainerrors.ErrInvalidOAuthState) {
+			t.Errorf("esperava ErrInvalidOAuthState, obteve %v", err)
+		}
+	})
+
+	t.Run("código recusado pelo provedor", func(t *testing.T) {
+		f := newOAuthFixture(t)
+		f.server.SetUser(maria)
+		input := f.authorize(t)
+		input.Code = "codigo-invalido"
+
+		if _, err := f.service.Callback(ctx, input); !errors.Is(err, domainerrors.ErrOAuthExchangeFailed) {
+			t.Errorf("esperava ErrOAuthExchangeFailed, obteve %v", err)
+		}
+	})
+}
@@ -0,0 +1,400 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+)
+
+// OrganizationService implementa os casos de uso de organizações e membros
+// Todas as operações recebem o ID do usuário autenticado e validam a role
+// dele na organização alvo
+type OrganizationService struct {
+	orgRepo    repositories.OrganizationRepository
+	memberRepo repositories.OrganizationMemberRepository
+	userRepo   repositories.UserRepository
+	lockout    *LockoutService
+	uow        domain.UnitOfWork
+	logger     domain.Logger
+}
+
+// NewOrganizationService cria um novo OrganizationService
+func NewOrganizationService(
+	orgRepo repositories.OrganizationRepository,
+	memberRepo repositories.OrganizationMemberRepository,
+	userRepo repositories.UserRepository,
+	lockout *LockoutService,
+	uow domain.UnitOfWork,
+	logger domain.Logger,
+) *OrganizationService {
+	return &OrganizationService{
+		orgRepo:    orgRepo,
+		memberRepo: memberRepo,
+		userRepo:   userRepo,
+		lockout:    lockout,
+		uow:        uow,
+		logger:     logger,
+	}
+}
+
+// Create cria uma organização e adiciona o criador como admin
+func (s *OrganizationService) Create(ctx context.Context, userID, name string) (*entities.OrganizationMember, error) {
+	now := time.Now()
+
+	org := &entities.Organization{
+		ID:     uuid.New().String(),
+		Name:   strings.TrimSpace(name),
+		Status: entities.OrganizationStatusActive,
+	}
+
+	member := &entities.OrganizationMember{
+		ID:             uuid.New().String(),
+		OrganizationID: org.ID,
+		UserID:         userID,
+		Role:           entities.RoleAdmin,
+		InvitedAt:      now,
+		JoinedAt:       &now,
+		Organization:   org,
+	}
+
+	ctx = domain.WithOrganizationID(ctx, org.ID)
+	err := s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.orgRepo.Create(txCtx, org); err != nil {
+			return err
+		}
+		return s.memberRepo.Create(txCtx, member)
+	})
+	if err != nil {
+		s.logger.Error("failed to create organization", "user_id", userID, "error", err)
+		return nil, err
+	}
+
+	s.logger.Info("organization created", "organization_id", org.ID, "user_id", userID)
+	return member, nil
+}
+
+// List retorna as organizações do usuário com a role dele em cada uma
+func (s *OrganizationService) List(ctx context.Context, userID string) ([]*entities.OrganizationMember, error) {
+	return s.memberRepo.FindByUserID(ctx, userID)
+}
+
+// Get retorna a organização junto com a role do usuário nela
+func (s *OrganizationService) Get(ctx context.Context, userID, organizationID string) (*entities.OrganizationMember, error) {
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	return s.authorize(ctx, userID, organizationID, entities.PermissionOrganizationsRead)
+}
+
+// maxAllowedOrigins é o máximo de origins CORS extras por organização
+const maxAllowedOrigins = 10
+
+// UpdateOrganizationInput contém os dados alteráveis de uma organização
+// RequireMFA nil mantém a exigência de segundo fator como está, e
+// AllowedOrigins nil mantém os origins (uma lista vazia remove todos)
+type UpdateOrganizationInput struct {
+	Name           string
+	RequireMFA     *bool
+	AllowedOrigins []string
+}
+
+// Update altera o nome da organização, a exigência de segundo fator e os origins CORS
+func (s *OrganizationService) Update(ctx context.Context, userID, organizationID string, input UpdateOrganizationInput) (*entities.OrganizationMember, error) {
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	member, err := s.authorize(ctx, userID, organizationID, entities.PermissionOrganizationsWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.RequireMFA != nil {
+		// Quem liga a exigência sem segundo fator perderia o próprio acesso
+		if *input.RequireMFA && !domain.MFAVerifiedFromContext(ctx) {
+			return nil, domainerrors.ErrMFASessionRequired
+		}
+		member.Organization.RequireMFA = *input.RequireMFA
+	}
+
+	if input.AllowedOrigins != nil {
+		origins, err := normalizeOrigins(input.AllowedOrigins)
+		if err != nil {
+			return nil, err
+		}
+		member.Organization.AllowedOrigins = origins
+	}
+
+	member.Organization.Name = strings.TrimSpace(input.Name)
+	if err := s.orgRepo.Update(ctx, member.Organization); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("organization updated", "organization_id", organizationID, "user_id", userID)
+	return member, nil
+}
+
+// Delete remove (soft delete) a organização
+func (s *OrganizationService) Delete(ctx context.Context, userID, organizationID string) error {
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	if _, err := s.authorize(ctx, userID, organizationID, entities.PermissionOrganizationsDelete); err != nil {
+		return err
+	}
+
+	if err := s.orgRepo.Delete(ctx, organizationID); err != nil {
+		return err
+	}
+
+	s.logger.Info("organization deleted", "organization_id", organizationID, "user_id", userID)
+	return nil
+}
+
+// ListMembers lista os membros da organização
+func (s *OrganizationService) ListMembers(ctx context.Context, userID, organizationID string) ([]*entities.OrganizationMember, error) {
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	if _, err := s.authorize(ctx, userID, organizationID, entities.PermissionMembersRead); err != nil {
+		return nil, err
+	}
+
+	return s.memberRepo.FindByOrganization(ctx, organizationID)
+}
+
+// AddMember adiciona um usuário existente à organização com a role informada
+func (s *OrganizationService) AddMember(ctx context.Context, userID, organizationID, email string, role entities.Role) (*entities.OrganizationMember, error) {
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	if _, err := s.authorize(ctx, userID, organizationID, entities.PermissionMembersWrite); err != nil {
+		return nil, err
+	}
+
+	normalized, err := valueobjects.NewEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, normalized.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.memberRepo.FindByUserAndOrganization(ctx, user.ID, organizationID); err == nil {
+		return nil, domainerrors.ErrMemberAlreadyExists
+	} else if !errors.Is(err, domainerrors.ErrMemberNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	member := &entities.OrganizationMember{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+		UserID:         user.ID,
+		Role:           role,
+		InvitedBy:      &userID,
+		InvitedAt:      now,
+		JoinedAt:       &now,
+		User:           user,
+	}
+
+	if err := s.memberRepo.Create(ctx, member); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("organization member added",
+		"organization_id", organizationID,
+		"member_user_id", user.ID,
+		"role", role,
+		"user_id", userID,
+	)
+	return member, nil
+}
+
+// UpdateMemberRole altera a role de um membro na organização
+func (s *OrganizationService) UpdateMemberRole(ctx context.Context, userID, organizationID, memberUserID string, role entities.Role) (*entities.OrganizationMember, error) {
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	if _, err := s.authorize(ctx, userID, organizationID, entities.PermissionMembersWrite); err != nil {
+		return nil, err
+	}
+
+	member, err := s.memberRepo.FindByUserAndOrganization(ctx, memberUserID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if member.Role == entities.RoleAdmin && role != entities.RoleAdmin {
+		if err := s.ensureAnotherAdmin(ctx, organizationID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.memberRepo.UpdateRole(ctx, member.ID, role); err != nil {
+		return nil, err
+	}
+	member.Role = role
+
+	s.logger.Info("organization member role updated",
+		"organization_id", organizationID,
+		"member_user_id", memberUserID,
+		"role", role,
+		"user_id", userID,
+	)
+	return member, nil
+}
+
+// RemoveMember remove um membro da organização
+func (s *OrganizationService) RemoveMember(ctx context.Context, userID, organizationID, memberUserID string) error {
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	if _, err := s.authorize(ctx, userID, organizationID, entities.PermissionMembersWrite); err != nil {
+		return err
+	}
+
+	member, err := s.memberRepo.FindByUserAndOrganization(ctx, memberUserID, organizationID)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == entities.RoleAdmin {
+		if err := s.ensureAnotherAdmin(ctx, organizationID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.memberRepo.Delete(ctx, member.ID); err != nil {
+		return err
+	}
+
+	s.logger.Info("organization member removed",
+		"organization_id", organizationID,
+		"member_user_id", memberUserID,
+		"user_id", userID,
+	)
+	return nil
+}
+
+// UnlockMember remove o bloqueio de login de um membro da organização
+// O bloqueio vale para a conta inteira: o admin só desbloqueia membros que não
+// pertencem a outras organizações, para não liberar tentativas de senha contra
+// quem ele apenas adicionou à sua (ErrMemberInOtherOrganizations)
+// Sem efeito quando a conta não está bloqueada
+func (s *OrganizationService) UnlockMember(ctx context.Context, userID, organizationID, memberUserID string) error {
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	if _, err := s.authorize(ctx, userID, organizationID, entities.PermissionMembersWrite); err != nil {
+		return err
+	}
+
+	if _, err := s.memberRepo.FindByUserAndOrganization(ctx, memberUserID, organizationID); err != nil {
+		return err
+	}
+
+	memberships, err := s.memberRepo.FindByUserID(ctx, memberUserID)
+	if err != nil {
+		return err
+	}
+	for _, membership := range memberships {
+		if membership.OrganizationID != organizationID {
+			return domainerrors.ErrMemberInOtherOrganizations
+		}
+	}
+
+	user, err := s.userRepo.FindByID(ctx, memberUserID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.lockout.Unlock(ctx, user); err != nil {
+		return err
+	}
+
+	s.logger.Info("organization member unlocked",
+		"organization_id", organizationID,
+		"member_user_id", memberUserID,
+		"user_id", userID,
+	)
+	return nil
+}
+
+// authorize verifica se o usuário é membro da organização e se sua role concede a permissão
+func (s *OrganizationService) authorize(ctx context.Context, userID, organizationID string, permission entities.Permission) (*entities.OrganizationMember, error) {
+	return authorizeMember(ctx, s.memberRepo, userID, organizationID, permission)
+}
+
+// AuthorizeMember implementa domain.MemberAuthorizer para o middleware de permissões
+func (s *OrganizationService) AuthorizeMember(ctx context.Context, userID, organizationID string, permission entities.Permission) error {
+	_, err := s.authorize(domain.WithOrganizationID(ctx, organizationID), userID, organizationID, permission)
+	return err
+}
+
+// authorizeMember verifica se o usuário é membro da organização e se sua role concede a permissão
+// Quem não é membro recebe ErrOrganizationNotFound para não expor a existência da organização
+// Organizações que exigem 2FA só aceitam sessões autenticadas com o segundo fator
+func authorizeMember(
+	ctx context.Context,
+	memberRepo repositories.OrganizationMemberRepository,
+	userID, organizationID string,
+	permission entities.Permission,
+) (*entities.OrganizationMember, error) {
+	member, err := memberRepo.FindByUserAndOrganization(ctx, userID, organizationID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrMemberNotFound) {
+			return nil, domainerrors.ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+
+	if member.Organization != nil && member.Organization.RequireMFA && !domain.MFAVerifiedFromContext(ctx) {
+		return nil, domainerrors.ErrMFARequiredByOrganization
+	}
+
+	if !member.HasPermission(permission) {
+		return nil, domainerrors.ErrForbidden
+	}
+
+	return member, nil
+}
+
+// ensureAnotherAdmin impede que a organização fique sem nenhum admin
+func (s *OrganizationService) ensureAnotherAdmin(ctx context.Context, organizationID string) error {
+	admins, err := s.memberRepo.CountByRole(ctx, organizationID, entities.RoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	if admins <= 1 {
+		return domainerrors.ErrLastOrganizationAdmin
+	}
+
+	return nil
+}
+
+// normalizeOrigins valida e normaliza os origins CORS, removendo repetidos
+func normalizeOrigins(origins []string) ([]string, error) {
+	if len(origins) > maxAllowedOrigins {
+		return nil, domainerrors.ErrInvalidOrigin
+	}
+
+	normalized := make([]string, 0, len(origins))
+	seen := make(map[string]bool, len(origins))
+
+	for _, o := range origins {
+		origin, err := valueobjects.NewOrigin(o)
+		if err != nil {
+			return nil, domainerrors.ErrInvalidOrigin
+		}
+		if !seen[origin.String()] {
+			seen[origin.String()] = true
+			normalized = append(normalized, origin.String())
+		}
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+)
+
+type organizationFixture struct {
+	service *OrganizationService
+	orgs    *fakeOrganizationRepository
+	members *fakeOrganizationMemberRepository
+	lockout *LockoutService
+	alice   *entities.User
+	bob     *entities.User
+}
+
+func newOrganizationFixture(t *testing.T) *organizationFixture {
+	t.Helper()
+
+	alice := newTestUser(t, "alice", "alice@example.com", "Senha123")
+	bob := newTestUser(t, "bob", "bob@example.com", "Senha123")
+	orgs := newFakeOrganizationRepository()
+	members := newFakeOrganizationMemberRepository(orgs)
+	lockout := newTestLockoutService()
+
+	return &organizationFixture{
+		service: NewOrganizationService(orgs, members, newFakeUserRepository(alice, bob), lockout, fakeUnitOfWork{}, nopLogger{}),
+		orgs:    orgs,
+		members: members,
+		lockout: lockout,
+		alice:   alice,
+		bob:     bob,
+	}
+}
+
+func TestOrganizationService_Create(t *testing.T) {
+	f := newOrganizationFixture(t)
+
+	member, err := f.service.Create(context.Background(), f.alice.ID, "  Empresa ABC ")
+	if err != nil {
+		t.Fatalf("esperava sucesso, obteve erro: %v", err)
+	}
+
+	if member.Role != entities.RoleAdmin {
+		t.Errorf("esperava criador como admin, obteve '%s'", member.Role)
+	}
+	if member.Organization.Name != "Empresa ABC" {
+		t.Errorf("esperava nome 'Empresa ABC', obteve '%s'", member.Organization.Name)
+	}
+	if !member.Organization.IsActive() {
+		t.Error("esperava organização ativa")
+	}
+}
+
+func TestOrganizationService_RolesPerOrganization(t *testing.T) {
+	f := newOrganizationFixture(t)
+	ctx := context.Background()
+
+	orgA, _ := f.service.Create(ctx, f.alice.ID, "Empresa A")
+	orgB, _ := f.service.Create(ctx, f.bob.ID, "Empresa B")
+
+	// Bob é admin na B e guest na A
+	if _, err := f.service.AddMember(ctx, f.alice.ID, orgA.OrganizationID, "bob@example.com", entities.RoleGuest); err != nil {
+		t.Fatalf("falha ao adicionar membro: %v", err)
+	}
+
+	memberships, _ := f.service.List(ctx, f.bob.ID)
+	if len(memberships) != 2 {
+		t.Fatalf("esperava 2 organizações, obteve %d", len(memberships))
+	}
+
+	t.Run("guest não pode alterar a organização", func(t *testing.T) {
+		_, err := f.service.Update(ctx, f.bob.ID, orgA.OrganizationID, UpdateOrganizationInput{Name: "Novo Nome"})
+		if !errors.Is(err, domainerrors.ErrForbidden) {
+			t.Errorf("esperava ErrForbidden, obteve %v", err)
+		}
+	})
+
+	t.Run("admin pode alterar a própria organização", func(t *testing.T) {
+		member, err := f.service.Update(ctx, f.bob.ID, orgB.OrganizationID, UpdateOrganizationInput{Name: "Empresa B2"})
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if member.Organization.Name != "Empresa B2" {
+			t.Errorf("esperava nome 'Empresa B2', obteve '%s'", member.Organization.Name)
+		}
+	})
+
+	t.Run("não membro recebe not found", func(t *testing.T) {
+		_, err := f.service.Get(ctx, f.alice.ID, orgB.OrganizationID)
+		if !errors.Is(err, domainerrors.ErrOrganizationNotFound) {
+			t.Errorf("esperava ErrOrganizationNotFound, obteve %v", err)
+		}
+	})
+
+	t.Run("membro duplicado retorna conflito", func(t *testing.T) {
+		_, err := f.service.AddMember(ctx, f.alice.ID, orgA.OrganizationID, "bob@example.com", entities.RoleUser)
+		if !errors.Is(err, domainerrors.ErrMemberAlreadyExists) {
+			t.Errorf("esperava ErrMemberAlreadyExists, obteve %v", err)
+		}
+	})
+}
+
+func TestOrganizationService_LastAdmin(t *testing.T) {
+	f := newOrganizationFixture(t)
+	ctx := context.Background()
+
+	org, _ := f.service.Create(ctx, f.alice.ID, "Empresa A")
+
+	t.Run("não permite rebaixar o último admin", func(t *testing.T) {
+		_, err := f.service.UpdateMemberRole(ctx, f.alice.ID, org.OrganizationID, f.alice.ID, entities.RoleUser)
+		if !errors.Is(err, domainerrors.ErrLastOrganizationAdmin) {
+			t.Errorf("esperava ErrLastOrganizationAdmin, obteve %v", err)
+		}
+	})
+
+	t.Run("não permite remover o último admin", func(t *testing.T) {
+		err := f.service.RemoveMember(ctx, f.alice.ID, org.OrganizationID, f.alice.ID)
+		if !errors.Is(err, domainerrors.ErrLastOrganizationAdmin) {
+			t.Errorf("esperava ErrLastOrganizationAdmin, obteve %v", err)
+		}
+	})
+
+	t.Run("permite rebaixar quando há outro admin", func(t *testing.T) {
+		if _, err := f.service.AddMember(ctx, f.alice.ID, org.OrganizationID, "bob@example.com", entities.RoleAdmin); err != nil {
+			t.Fatalf("falha ao adicionar membro: %v", err)
+		}
+
+		member, err := f.service.UpdateMemberRole(ctx, f.bob.ID, org.OrganizationID, f.alice.ID, entities.RoleUser)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if member.Role != entities.RoleUser {
+			t.Errorf("esperava role 'user', obteve '%s'", member.Role)
+		}
+	})
+}
+
+func TestOrganizationService_UnlockMember(t *testing.T) {
+	f := newOrganizationFixture(t)
+	ctx := context.Background()
+
+	org, _ := f.service.Create(ctx, f.alice.ID, "Empresa A")
+	if _, err := f.service.AddMember(ctx, f.alice.ID, org.OrganizationID, "bob@example.com", entities.RoleUser); err != nil {
+		t.Fatalf("falha ao adicionar membro: %v", err)
+	}
+	for i := 0; i < accountLockoutPolicy.lockAfter; i++ {
+		f.lockout.RecordFailure(ctx, "bob@example.com", f.bob)
+	}
+
+	t.Run("membro comum não pode desbloquear", func(t *testing.T) {
+		err := f.service.UnlockMember(ctx, f.bob.ID, org.OrganizationID, f.alice.ID)
+		if !errors.Is(err, domainerrors.ErrForbidden) {
+			t.Errorf("esperava ErrForbidden, obteve %v", err)
+		}
+	})
+
+	t.Run("admin desbloqueia o membro", func(t *testing.T) {
+		if err := f.lockout.Check(ctx, "bob@example.com"); !errors.Is(err, domainerrors.ErrAccountLocked) {
+			t.Fatalf("esperava conta bloqueada, obteve %v", err)
+		}
+
+		if err := f.service.UnlockMember(ctx, f.alice.ID, org.OrganizationID, f.bob.ID); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if err := f.lockout.Check(ctx, "bob@example.com"); err != nil {
+			t.Errorf("esperava conta desbloqueada, obteve %v", err)
+		}
+	})
+
+	t.Run("não desbloqueia quem não é membro", func(t *testing.T) {
+		other, _ := f.service.Create(ctx, f.bob.ID, "Empresa B")
+		err := f.service.UnlockMember(ctx, f.bob.ID, other.OrganizationID, f.alice.ID)
+		if !errors.Is(err, domainerrors.ErrMemberNotFound) {
+			t.Errorf("esperava ErrMemberNotFound, obteve %v", err)
+		}
+	})
+
+	t.Run("não desbloqueia membro de outras organizações", func(t *testing.T) {
+		// Bob agora também pertence à Empresa B: o admin da A não libera a conta
+		for i := 0; i < accountLockoutPolicy.lockAfter; i++ {
+			f.lockout.RecordFailure(ctx, "bob@example.com", f.bob)
+		}
+
+		err := f.service.UnlockMember(ctx, f.alice.ID, org.OrganizationID, f.bob.ID)
+		if !errors.Is(err, domainerrors.ErrMemberInOtherOrganizations) {
+			t.Errorf("esperava ErrMemberInOtherOrganizations, obteve %v", err)
+		}
+		if err := f.lockout.Check(ctx, "bob@example.com"); !errors.Is(err, domainerrors.ErrAccountLocked) {
+			t.Errorf("esperava conta ainda bloqueada, obteve %v", err)
+		}
+	})
+}
+
+func TestOrganizationService_Delete(t *testing.T) {
+	f := newOrganizationFixture(t)
+	ctx := context.Background()
+
+	org, _ := f.service.Create(ctx, f.alice.ID, "Empresa A")
+
+	if err := f.service.Delete(ctx, f.alice.ID, org.OrganizationID); err != nil {
+		t.Fatalf("esperava sucesso, obteve erro: %v", err)
+	}
+
+	memberships, _ := f.service.List(ctx, f.alice.ID)
+	if len(memberships) != 0 {
+		t.Errorf("esperava nenhuma organização após remoção, obteve %d", len(memberships))
+	}
+}
+
+func TestOrganizationService_RequireMFA(t *testing.T) {
+	f := newOrganizationFixture(t)
+	ctx := context.Background()
+	mfaCtx := domain.WithMFAVerified(ctx, true)
+	requireMFA := true
+
+	org, _ := f.service.Create(ctx, f.alice.ID, "Empresa A")
+	if _, err := f.service.AddMember(ctx, f.alice.ID, org.OrganizationID, "bob@example.com", entities.RoleUser); err != nil {
+		t.Fatalf("falha ao adicionar membro: %v", err)
+	}
+
+	t.Run("ligar a exigência pede sessão com segundo fator", func(t *testing.T) {
+		_, err := f.service.Update(ctx, f.alice.ID, org.OrganizationID, UpdateOrganizationInput{Name: "Empresa A", RequireMFA: &requireMFA})
+		if !errors.Is(err, domainerrors.ErrMFASessionRequired) {
+			t.Errorf("esperava ErrMFASessionRequired, obteve %v", err)
+		}
+	})
+
+	t.Run("admin com segundo fator liga a exigência", func(t *testing.T) {
+		member, err := f.service.Update(mfaCtx, f.alice.ID, org.OrganizationID, UpdateOrganizationInput{Name: "Empresa A", RequireMFA: &requireMFA})
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if !member.Organization.RequireMFA {
+			t.Error("esperava organização exigindo 2FA")
+		}
+	})
+
+	t.Run("membro sem segundo fator perde o acesso", func(t *testing.T) {
+		if _, err := f.service.Get(ctx, f.bob.ID, org.OrganizationID); !errors.Is(err, domainerrors.ErrMFARequiredByOrganization) {
+			t.Errorf("esperava ErrMFARequiredByOrganization, obteve %v", err)
+		}
+		if _, err := f.service.Get(mfaCtx, f.bob.ID, org.OrganizationID); err != nil {
+			t.Errorf("esperava acesso com segundo fator, obteve %v", err)
+		}
+	})
+
+	t.Run("omitir require_mfa mantém a exigência", func(t *testing.T) {
+		member, err := f.service.Update(mfaCtx, f.alice.ID, org.OrganizationID, UpdateOrganizationInput{Name: "Empresa A2"})
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if !member.Organization.RequireMFA {
+			t.Error("esperava exigência de 2FA mantida")
+		}
+	})
+}
+
+func TestOrganizationService_AllowedOrigins(t *testing.T) {
+	f := newOrganizationFixture(t)
+	ctx := context.Background()
+
+	org, _ := f.service.Create(ctx, f.alice.ID, "Empresa A")
+
+	t.Run("normaliza e remove origins repetidos", func(t *testing.T) {
+		member, err := f.service.Update(ctx, f.alice.ID, org.OrganizationID, UpdateOrganizationInput{
+			Name:           "Empresa A",
+			AllowedOrigins: []string{"https://App.Empresa.com/", "https://app.empresa.com:443", "http://localhost:3000"},
+		})
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		want := []string{"https://app.empresa.com", "http://localhost:3000"}
+		if !slices.Equal(member.Organization.AllowedOrigins, want) {
+			t.Errorf("esperava %v, obteve %v", want, member.Organization.AllowedOrigins)
+		}
+	})
+
+	t.Run("rejeita origin inválido", func(t *testing.T) {
+		for _, origins := range [][]string{
+			{"https://app.empresa.com/login"},
+			{"https://*.empresa.com"},
+			{"*"},
+		} {
+			_, err := f.service.Update(ctx, f.alice.ID, org.OrganizationID, UpdateOrganizationInput{Name: "Empresa A", AllowedOrigins: origins})
+			if !errors.Is(err, domainerrors.ErrInvalidOrigin) {
+				t.Errorf("%v: esperava ErrInvalidOrigin, obteve %v", origins, err)
+			}
+		}
+	})
+
+	t.Run("omitir allowed_origins mantém os origins", func(t *testing.T) {
+		member, err := f.service.Update(ctx, f.alice.ID, org.OrganizationID, UpdateOrganizationInput{Name: "Empresa A"})
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if len(member.Organization.AllowedOrigins) != 2 {
+			t.Errorf("esperava origins mantidos, obteve %v", member.Organization.AllowedOrigins)
+		}
+	})
+
+	t.Run("lista vazia remove os origins", func(t *testing.T) {
+		member, err := f.service.Update(ctx, f.alice.ID, org.OrganizationID, UpdateOrganizationInput{Name: "Empresa A", AllowedOrigins: []string{}})
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if len(member.Organization.AllowedOrigins) != 0 {
+			t.Errorf("esperava nenhum origin, obteve %v", member.Organization.AllowedOrigins)
+		}
+	})
+}
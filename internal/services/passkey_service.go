@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/webauthn"
+)
+
+const (
+	// passkeyChallengeTTL é o tempo que o usuário tem para concluir a cerimônia no autenticador
+	passkeyChallengeTTL = 5 * time.Minute
+	// defaultPasskeyName é usado quando o usuário não nomeia o passkey
+	defaultPasskeyName = "Passkey"
+)
+
+// PasskeyService implementa o cadastro de passkeys (WebAuthn) e o login sem senha
+//
+// Os challenges ficam no banco (apenas o hash) e são consumidos uma única vez.
+// O login é discoverable: o navegador oferece os passkeys do site e o usuário
+// é identificado pela credencial escolhida. Como a verificação do usuário
+// (biometria ou PIN) é exigida, a sessão aberta conta como autenticada com
+// dois fatores.
+type PasskeyService struct {
+	rp            *webauthn.RelyingParty
+	passkeyRepo   repositories.PasskeyRepository
+	challengeRepo repositories.PasskeyChallengeRepository
+	userRepo      repositories.UserRepository
+	authService   *AuthService
+	logger        domain.Logger
+}
+
+// NewPasskeyService cria um novo PasskeyService
+func NewPasskeyService(
+	rp *webauthn.RelyingParty,
+	passkeyRepo repositories.PasskeyRepository,
+	challengeRepo repositories.PasskeyChallengeRepository,
+	userRepo repositories.UserRepository,
+	authService *AuthService,
+	logger domain.Logger,
+) *PasskeyService {
+	return &PasskeyService{
+		rp:            rp,
+		passkeyRepo:   passkeyRepo,
+		challengeRepo: challengeRepo,
+		userRepo:      userRepo,
+		authService:   authService,
+		logger:        logger,
+	}
+}
+
+// PasskeyCredential identifica um passkey já cadastrado nas opções das cerimônias
+type PasskeyCredential struct {
+	ID         string // Base64url
+	Transports []string
+}
+
+// PasskeyCreationOptions são as opções de navigator.credentials.create
+type PasskeyCreationOptions struct {
+	Challenge          string // Base64url
+	RPID               string
+	RPName             string
+	UserHandle         []byte
+	UserName           string
+	UserDisplayName    string
+	Algorithms         []int64
+	ExcludeCredentials []PasskeyCredential
+	Timeout            time.Duration
+}
+
+// PasskeyRequestOptions são as opções de navigator.credentials.get
+type PasskeyRequestOptions struct {
+	Challenge string // Base64url
+	RPID      string
+	Timeout   time.Duration
+}
+
+// PasskeyRegistrationInput é a resposta do autenticador ao cadastro
+type PasskeyRegistrationInput struct {
+	Name              string
+	ClientDataJSON    []byte
+	AttestationObject []byte
+	Transports        []string
+}
+
+// PasskeyLoginInput é a resposta do autenticador ao login
+type PasskeyLoginInput struct {
+	CredentialID      []byte
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+	UserHandle        []byte
+}
+
+// BeginRegistration emite o challenge do cadastro de um novo passkey
+// Os passkeys já cadastrados vão em excludeCredentials para que o mesmo
+// autenticador não seja cadastrado duas vezes
+func (s *PasskeyService) BeginRegistration(ctx context.Context, userID string) (*PasskeyCreationOptions, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	passkeys, err := s.passkeyRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.newChallenge(ctx, entities.PasskeyCeremonyRegistration, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := make([]PasskeyCredential, 0, len(passkeys))
+	for _, p := range passkeys {
+		exclude = append(exclude, PasskeyCredential{ID: p.CredentialID, Transports: p.Transports})
+	}
+
+	return &PasskeyCreationOptions{
+		Challenge:          challenge,
+		RPID:               s.rp.ID(),
+		RPName:             s.rp.Name(),
+		UserHandle:         userHandle(user.ID),
+		UserName:           user.Email.String(),
+		UserDisplayName:    user.Email.String(),
+		Algorithms:         webauthn.SupportedAlgorithms,
+		ExcludeCredentials: exclude,
+		Timeout:            passkeyChallengeTTL,
+	}, nil
+}
+
+// FinishRegistration verifica a resposta do autenticador e cadastra o passkey
+func (s *PasskeyService) FinishRegistration(ctx context.Context, userID string, input PasskeyRegistrationInput) (*entities.Passkey, error) {
+	challenge, err := s.consumeChallenge(ctx, entities.PasskeyCeremonyRegistration, input.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// O challenge só vale para o usuário que iniciou o cadastro
+	if challenge.UserID != userID {
+		return nil, domainerrors.ErrInvalidPasskey
+	}
+
+	credential, err := s.rp.VerifyRegistration(challenge.raw, input.ClientDataJSON, input.AttestationObject)
+	if err != nil {
+		s.logger.Info("passkey registration rejected", "user_id", userID, "reason", err.Error())
+		return nil, domainerrors.ErrInvalidPasskey
+	}
+
+	aaguid, err := uuid.FromBytes(credential.AAGUID)
+	if err != nil {
+		return nil, domainerrors.ErrInvalidPasskey
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		name = defaultPasskeyName
+	}
+
+	passkey := &entities.Passkey{
+		ID:             uuid.New().String(),
+		UserID:         userID,
+		CredentialID:   base64.RawURLEncoding.EncodeToString(credential.ID),
+		PublicKey:      credential.PublicKey,
+		SignCount:      credential.SignCount,
+		AAGUID:         aaguid.String(),
+		Transports:     input.Transports,
+		Name:           name,
+		BackupEligible: credential.BackupEligible,
+		BackupState:    credential.BackupState,
+	}
+	if passkey.Transports == nil {
+		passkey.Transports = []string{}
+	}
+
+	if err := s.passkeyRepo.Create(ctx, passkey); err != nil {
+		if !errors.Is(err, domainerrors.ErrPasskeyAlreadyRegistered) {
+			s.logger.Error("failed to store passkey", "user_id", userID, "error", err)
+		}
+		return nil, err
+	}
+
+	s.logger.Info("passkey registered", "user_id", userID, "passkey_id", passkey.ID, "aaguid", passkey.AAGUID)
+	return passkey, nil
+}
+
+// BeginLogin emite o challenge de um login com passkey
+func (s *PasskeyService) BeginLogin(ctx context.Context) (*PasskeyRequestOptions, error) {
+	challenge, err := s.newChallenge(ctx, entities.PasskeyCeremonyLogin, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &PasskeyRequestOptions{
+		Challenge: challenge,
+		RPID:      s.rp.ID(),
+		Timeout:   passkeyChallengeTTL,
+	}, nil
+}
+
+// FinishLogin verifica a assinatura do autenticador e abre uma sessão
+// Qualquer falha na verificação retorna ErrInvalidPasskey, sem indicar a causa
+func (s *PasskeyService) FinishLogin(ctx context.Context, input PasskeyLoginInput) (*AuthResult, error) {
+	challenge, err := s.consumeChallenge(ctx, entities.PasskeyCeremonyLogin, input.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	passkey, err := s.passkeyRepo.FindByCredentialID(ctx, base64.RawURLEncoding.EncodeToString(input.CredentialID))
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrPasskeyNotFound) {
+			return nil, domainerrors.ErrInvalidPasskey
+		}
+		return nil, err
+	}
+
+	// O user handle, quando enviado, precisa ser o do dono da credencial
+	if len(input.UserHandle) > 0 && subtle.ConstantTimeCompare(input.UserHandle, userHandle(passkey.UserID)) != 1 {
+		return nil, domainerrors.ErrInvalidPasskey
+	}
+
+	assertion, err := s.rp.VerifyAssertion(
+		challenge.raw, input.ClientDataJSON, input.AuthenticatorData, input.Signature,
+		passkey.PublicKey, passkey.SignCount,
+	)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrSignCountRegressed) {
+			s.logger.Warn("passkey sign count did not increase, possible cloned authenticator",
+				"user_id", passkey.UserID, "passkey_id", passkey.ID)
+		} else {
+			s.logger.Info("passkey assertion rejected", "passkey_id", passkey.ID, "reason", err.Error())
+		}
+		return nil, domainerrors.ErrInvalidPasskey
+	}
+
+	user, err := s.userRepo.FindByID(ctx, passkey.UserID)
+	if err != nil {
+		s.logger.Error("failed to find passkey owner", "user_id", passkey.UserID, "error", err)
+		return nil, err
+	}
+
+	if !user.IsActive() {
+		return nil, domainerrors.ErrAccountNotActive
+	}
+
+	if err := s.passkeyRepo.UpdateUsage(ctx, passkey.ID, passkey.SignCount, assertion.SignCount, assertion.BackupState); err != nil {
+		if !errors.Is(err, domainerrors.ErrInvalidPasskey) {
+			s.logger.Error("failed to update passkey usage", "passkey_id", passkey.ID, "error", err)
+		}
+		return nil, err
+	}
+
+	result, err := s.authService.IssueMFATokens(ctx, user)
+	if err != nil {
+		s.logger.Error("failed to issue tokens", "user_id", user.ID, "error", err)
+		return nil, err
+	}
+
+	s.logger.Info("user logged in", "user_id", user.ID, "method", "passkey", "passkey_id", passkey.ID)
+	return result, nil
+}
+
+// List retorna os passkeys do usuário
+func (s *PasskeyService) List(ctx context.Context, userID string) ([]*entities.Passkey, error) {
+	return s.passkeyRepo.ListByUser(ctx, userID)
+}
+
+// Delete remove um passkey do usuário
+func (s *PasskeyService) Delete(ctx context.Context, userID, passkeyID string) error {
+	if uuid.Validate(passkeyID) != nil {
+		return domainerrors.ErrPasskeyNotFound
+	}
+
+	if err := s.passkeyRepo.Delete(ctx, userID, passkeyID); err != nil {
+		return err
+	}
+
+	s.logger.Info("passkey deleted", "user_id", userID, "passkey_id", passkeyID)
+	return nil
+}
+
+// pendingChallenge é um challenge consumido junto com o valor original,
+// comparado com o clientDataJSON na verificação
+type pendingChallenge struct {
+	*entities.PasskeyChallenge
+	raw string
+}
+
+// newChallenge gera e persiste o challenge de uma cerimônia
+// 32 bytes aleatórios em base64url, o mesmo formato do clientDataJSON
+func (s *PasskeyService) newChallenge(ctx context.Context, ceremony, userID string) (string, error) {
+	challenge, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	stored := &entities.PasskeyChallenge{
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		Ceremony:      ceremony,
+		ChallengeHash: auth.HashToken(challenge),
+		ExpiresAt:     time.Now().Add(passkeyChallengeTTL),
+	}
+	if err := s.challengeRepo.Create(ctx, stored); err != nil {
+		s.logger.Error("failed to store passkey challenge", "ceremony", ceremony, "error", err)
+		return "", err
+	}
+
+	return challenge, nil
+}
+
+// consumeChallenge localiza pelo clientDataJSON o challenge pendente da
+// cerimônia e o consome, de modo que cada resposta seja aceita uma única vez
+func (s *PasskeyService) consumeChallenge(ctx context.Context, ceremony string, clientDataJSON []byte) (*pendingChallenge, error) {
+	raw, err := webauthn.ClientDataChallenge(clientDataJSON)
+	if err != nil {
+		return nil, domainerrors.ErrInvalidPasskey
+	}
+
+	challenge, err := s.challengeRepo.Consume(ctx, ceremony, auth.HashToken(raw))
+	if err != nil {
+		if !errors.Is(err, domainerrors.ErrInvalidPasskey) {
+			s.logger.Error("failed to consume passkey challenge", "ceremony", ceremony, "error", err)
+		}
+		return nil, err
+	}
+
+	if challenge.IsExpired(time.Now()) {
+		return nil, domainerrors.ErrInvalidPasskey
+	}
+
+	return &pendingChallenge{PasskeyChallenge: challenge, raw: raw}, nil
+}
+
+// userHandle é o identificador do usuário guardado no passkey: os 16 bytes
+// do UUID, que não expõem o email
+func userHandle(userID string) []byte {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return []byte(userID)
+	}
+	return id[:]
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/config"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/webauthn"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/webauthn/webauthntest"
+)
+
+const (
+	testRPID   = "localhost"
+	testOrigin = "http://localhost:3000"
+)
+
+type passkeyFixture struct {
+	service       *PasskeyService
+	jwt           *auth.JWTService
+	user          *entities.User
+	passkeys      *fakePasskeyRepository
+	challenges    *fakePasskeyChallengeRepository
+	authenticator *webauthntest.Authenticator
+}
+
+func newPasskeyFixture(t *testing.T) *passkeyFixture {
+	t.Helper()
+
+	rp, err := webauthn.NewRelyingParty(&config.WebAuthnConfig{RPID: testRPID, Origins: testOrigin})
+	if err != nil {
+		t.Fatalf("falha ao criar relying party: %v", err)
+	}
+
+	user := newTestUser(t, uuid.New().String(), "joao@email.com", "Senha123")
+	userRepo := newFakeUserRepository(user)
+	passkeys := &fakePasskeyRepository{}
+	challenges := &fakePasskeyChallengeRepository{}
+	jwtService := newTestJWTService(t)
+	authService := NewAuthService(userRepo, newFakeRefreshTokenRepository(), newFakeMFARepository(), fakeUnitOfWork{}, jwtService, newTestPasswordHasher(t), newTestLockoutService(), nopLogger{})
+
+	return &passkeyFixture{
+		service:       NewPasskeyService(rp, passkeys, challenges, userRepo, authService, nopLogger{}),
+		jwt:           jwtService,
+		user:          user,
+		passkeys:      passkeys,
+		challenges:    challenges,
+		authenticator: webauthntest.NewAuthenticator(testRPID, testOrigin),
+	}
+}
+
+// attest inicia o cadastro e retorna a resposta do autenticador
+func (f *passkeyFixture) attest(t *testing.T, authenticator *webauthntest.Authenticator) *webauthntest.Attestation {
+	t.Helper()
+
+	options, err := f.service.BeginRegistration(context.Background(), f.user.ID)
+	if err != nil {
+		t.Fatalf("falha ao iniciar cadastro: %v", err)
+	}
+
+	attestation, err := authenticator.Register(options.Challenge, options.UserHandle)
+	if err != nil {
+		t.Fatalf("falha no autenticador: %v", err)
+	}
+	return attestation
+}
+
+// register cadastra um passkey do autenticador informado
+func (f *passkeyFixture) register(t *testing.T, authenticator *webauthntest.Authenticator) *entities.Passkey {
+	t.Helper()
+
+	attestation := f.attest(t, authenticator)
+	passkey, err := f.service.FinishRegistration(context.Background(), f.user.ID, registrationInput(attestation))
+	if err != nil {
+		t.Fatalf("falha ao cadastrar passkey: %v", err)
+	}
+	return passkey
+}
+
+// assert inicia o login e retorna a resposta do autenticador para a credencial
+func (f *passkeyFixture) assert(t *testing.T, credentialID []byte) *webauthntest.Assertion {
+	t.Helper()
+
+	options, err := f.service.BeginLogin(context.Background())
+	if err != nil {
+		t.Fatalf("falha ao iniciar login: %v", err)
+	}
+
+	assertion, err := f.authenticator.Assert(options.Challenge, credentialID)
+	if err != nil {
+		t.Fatalf("falha no autenticador: %v", err)
+	}
+	return assertion
+}
+
+// authenticatorCredential retorna o ID bruto do primeiro passkey cadastrado
+func (f *passkeyFixture) authenticatorCredential(t *testing.T) []byte {
+	t.Helper()
+
+	if len(f.passkeys.passkeys) == 0 {
+		t.Fatal("nenhum passkey cadastrado")
+	}
+	id, err := base64.RawURLEncoding.DecodeString(f.passkeys.passkeys[0].CredentialID)
+	if err != nil {
+		t.Fatalf("credential ID inválido: %v", err)
+	}
+	return id
+}
+
+func registrationInput(attestation *webauthntest.Attestation) PasskeyRegistrationInput {
+	return PasskeyRegistrationInput{
+		Name:              "MacBook",
+		ClientDataJSON:    attestation.ClientDataJSON,
+		AttestationObject: attestation.AttestationObject,
+		Transports:        []string{"internal", "hybrid"},
+	}
+}
+
+func loginInput(assertion *webauthntest.Assertion) PasskeyLoginInput {
+	return PasskeyLoginInput{
+		CredentialID:      assertion.CredentialID,
+		ClientDataJSON:    assertion.ClientDataJSON,
+		AuthenticatorData: assertion.AuthenticatorData,
+		Signature:         assertion.Signature,
+		UserHandle:        assertion.UserHandle,
+	}
+}
+
+func TestPasskeyService_Registration(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cadastro guarda a credencial do usuário", func(t *testing.T) {
+		f := newPasskeyFixture(t)
+		passkey := f.register(t, f.authenticator)
+
+		if passkey.UserID != f.user.ID || passkey.Name != "MacBook" || len(passkey.PublicKey) == 0 {
+			t.Errorf("passkey inesperado: %+v", passkey)
+		}
+		if passkey.AAGUID != uuid.UUID(webauthntest.AAGUID).String() {
+			t.Errorf("esperava AAGUID do autenticador, obteve '%s'", passkey.AAGUID)
+		}
+		if !passkey.BackupEligible || !passkey.BackupState {
+			t.Error("esperava flags de backup do passkey sincronizado")
+		}
+	})
+
+	t.Run("opções usam o UUID como user handle e excluem os passkeys existentes", func(t *testing.T) {
+		f := newPasskeyFixture(t)
+		passkey := f.register(t, f.authenticator)
+
+		options, err := f.service.BeginRegistration(ctx, f.user.ID)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		id := uuid.MustParse(f.user.ID)
+		if !bytes.Equal(options.UserHandle, id[:]) {
+			t.Error("esperava os bytes do UUID como user handle")
+		}
+		if len(options.ExcludeCredentials) != 1 || options.ExcludeCredentials[0].ID != passkey.CredentialID {
+			t.Errorf("esperava o passkey existente em excludeCredentials, obteve %+v", options.ExcludeCredentials)
+		}
+	})
+
+	t.Run("usuário pode ter vários passkeys", func(t *testing.T) {
+		f := newPasskeyFixture(t)
+		f.register(t, f.authenticator)
+		f.register(t, webauthntest.NewAuthenticator(testRPID, testOrigin))
+
+		passkeys, _ := f.service.List(ctx, f.user.ID)
+		if len(passkeys) != 2 {
+			t.Errorf("esperava 2 passkeys, obteve %d", len(passkeys))
+		}
+	})
+
+	t.Run("challenge vale uma única vez", func(t *testing.T) {
+		f := newPasskeyFixture(t)
+		attestation := f.attest(t, f.authenticator)
+
+		if _, err := f.service.FinishRegistration(ctx, f.user.ID, registrationInput(attestation)); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if _, err := f.service.FinishRegistration(ctx, f.user.ID, registrationInput(attestation)); !errors.Is(err, domainerrors.ErrInvalidPasskey) {
+			t.Errorf("esperava ErrInvalidPasskey, obteve %v", err)
+		}
+	})
+
+	t.Run("challenge de outro usuário é rejeitado", func(t *testing.T) {
+		f := newPasskeyFixture(t)
+		attestation := f.attest(t, f.authenticator)
+
+		if _, err := f.service.FinishRegistration(ctx, uuid.New().String(), registrationInput(attestation)); !errors.Is(err, domainerrors.ErrInvalidPasskey) {
+			t.Errorf("esperava ErrInvalidPasskey, obteve %v", err)
+		}
+	})
+
+	t.Run("challenge expirado é rejeitado", func(t *testing.T) {
+		f := newPasskeyFixture(t)
+		attestation := f.attest(t, f.authenticator)
+		f.challenges.challenges[0].ExpiresAt = time.Now().Add(-time.Second)
+
+		if _, err := f.service.FinishRegistration(ctx, f.user.ID, registrationInput(attestation)); !errors.Is(err, domainerrors.ErrInvalidPasskey) {
+			t.Errorf("esperava ErrInvalidPasskey, obteve %v", err)
+		}
+	})
+
+	t.Run("autenticador sem verificação do usuário é rejeitado", func(t *testing.T) {
+		f := newPasskeyFixture(t)
+		f.authenticator.SkipUserVerification = true
+		attestation := f.attest(t, f.authenticator)
+
+		if _, err := f.service.FinishRegistration(ctx, f.user.ID, registrationInput(attestation)); !errors.Is(err, domainerrors.ErrInvalidPasskey) {
+			t.Errorf("esperava ErrInvalidPasskey, obteve %v", err)
+		}
+	})
+
+	t.Run("remover passkey de outro usuário retorna ErrPasskeyNotFound", func(t *testing.T) {
+		f := newPasskeyFixture(t)
+		passkey := f.register(t, f.authenticator)
+
+		for _, tc := range []struct{ userID, id string }{
+			{uuid.New().String(), passkey.ID},
+			{f.user.ID, "nao-e-uuid"},
+		} {
+			if err := f.service.Delete(ctx, tc.userID, tc.id); !errors.Is(err, domainerrors.ErrPasskeyNotFound) {
+				t.Errorf("esperava ErrPasskeyNotFound, obteve %v", err)
+			}
+		}
+	})
+}
+
+func TestPasskeyService_Login(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("login emite tokens com mfa e avança o contador", func(t *testing.T) {
+		f := newPasskeyFixture(t)
+		passkey := f.register(t, f.authenticator)
+		credentialID := f.authenticatorCredential(t)
+
+		result, err := f.service.FinishLogin(ctx, loginInput(f.assert(t, credentialID)))
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if result.MFARequired() || result.AccessToken == "" || result.RefreshToken == "" {
+			t.Fatalf("esperava tokens, obteve %+v", result)
+		}
+
+		claims, err := f.jwt.ValidateAccessToken(result.AccessToken)
+		if err != nil || claims.Subject != f.user.ID || !claims.MFA {
+			t.Errorf("esperava access token com mfa do usuário, obteve %+v (%v)", claims, err)
+		}
+
+		stored, _ := f.passkeys.FindByCredentialID(ctx, passkey.CredentialID)
+		if stored.SignCount != 1 || stored.LastUsedAt == nil {
+			t.Errorf("esperava contador 1 e último uso gravado, obteve %+v", stored)
+		}
+	})
+
+	t.Run("resposta não pode ser reapresentada", func(t *testing.T) {
+		f := newPasskeyFixture(t)
+		f.register(t, f.authenticator)
+		assertion := f.assert(t, f.authenticatorCredential(t))
+
+		if _, err := f.service.FinishLogin(ctx, loginInput(assertion)); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if _, err := f.service.FinishLogin(ctx, loginInput(assertion)); !errors.Is(err, domainerrors.ErrInvalidPasskey) {
+			t.Errorf("esperava ErrInvalidPasskey, obteve %v", err)
+		}
+	})
+
+	t.Run("contador que não avança é rejeitado", func(t *testing.T) {
+		f := newPasskeyFixture(t)
+		f.register(t, f.authenticator)
+		credentialID := f.authenticatorCredential(t)
+
+		if _, err := f.service.FinishLogin(ctx, loginInput(f.assert(t, credentialID))); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		f.authenticator.FrozenSignCount = true
+		if _, err := f.service.FinishLogin(ctx, loginInput(f.assert(t, credentialID))); !errors.Is(err, domainerrors.ErrInvalidPasskey) {
+			t.Errorf("esperava ErrInvalidPasskey, obteve %v", err)
+		}
+	})
+
+	t.Run("user handle de outro usuário é rejeitado", func(t *testing.T) {
+		f := newPasskeyFixture(t)
+		f.register(t, f.authenticator)
+
+		input := loginInput(f.assert(t, f.authenticatorCredential(t)))
+		other := uuid.New()
+		input.UserHandle = other[:]
+
+		if _, err := f.service.FinishLogin(ctx, input); !errors.Is(err, domainerrors.ErrInvalidPasskey) {
+			t.Errorf("esperava ErrInvalidPasskey, obteve %v", err)
+		}
+	})
+
+	t.Run("passkey removido não autentica", func(t *testing.T) {
+		f := newPasskeyFixture(t)
+		passkey := f.register(t, f.authenticator)
+		credentialID := f.authenticatorCredential(t)
+
+		if err := f.service.Delete(ctx, f.user.ID, passkey.ID); err != nil {
+			t.Fatalf("falha ao remover passkey: %v", err)
+		}
+		if _, err := f.service.FinishLogin(ctx, loginInput(f.assert(t, credentialID))); !errors.Is(err, domainerrors.ErrInvalidPasskey) {
+			t.Errorf("esperava ErrInvalidPasskey, obteve %v", err)
+		}
+	})
+
+	t.Run("conta suspensa não autentica", func(t *testing.T) {
+		f := newPasskeyFixture(t)
+		f.register(t, f.authenticator)
+		f.user.Status = entities.UserStatusSuspended
+
+		if _, err := f.service.FinishLogin(ctx, loginInput(f.assert(t, f.authenticatorCredential(t)))); !errors.Is(err, domainerrors.ErrAccountNotActive) {
+			t.Errorf("esperava ErrAccountNotActive, obteve %v", err)
+		}
+	})
+
+	t.Run("challenge de cadastro não serve para login", func(t *testing.T) {
+		f := newPasskeyFixture(t)
+		f.register(t, f.authenticator)
+
+		options, _ := f.service.BeginRegistration(ctx, f.user.ID)
+		assertion, _ := f.authenticator.Assert(options.Challenge, f.authenticatorCredential(t))
+
+		if _, err := f.service.FinishLogin(ctx, loginInput(assertion)); !errors.Is(err, domainerrors.ErrInvalidPasskey) {
+			t.Errorf("esperava ErrInvalidPasskey, obteve %v", err)
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
+)
+
+const (
+	// passwordResetTokenTTL é a validade do link de redefinição de senha
+	passwordResetTokenTTL = time.Hour
+	// passwordResetLimit é o máximo de tokens emitidos por usuário na janela
+	passwordResetLimit  = 3
+	passwordResetWindow = time.Hour
+	// passwordResetMinDuration é o tempo mínimo de resposta de ForgotPassword,
+	// maior que o trabalho feito para um email cadastrado
+	passwordResetMinDuration = 500 * time.Millisecond
+)
+
+// PasswordResetService implementa a redefinição de senha por link enviado por email
+type PasswordResetService struct {
+	userRepo         repositories.UserRepository
+	accountRepo      repositories.UserAccountRepository
+	resetRepo        repositories.PasswordResetTokenRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	authService      *AuthService
+	lockout          *LockoutService
+	notifier         domain.AccountNotifier
+	events           domain.EventPublisher
+	uow              domain.UnitOfWork
+	logger           domain.Logger
+	minDuration      time.Duration
+}
+
+// NewPasswordResetService cria um novo PasswordResetService
+func NewPasswordResetService(
+	userRepo repositories.UserRepository,
+	accountRepo repositories.UserAccountRepository,
+	resetRepo repositories.PasswordResetTokenRepository,
+	refreshTokenRepo repositories.RefreshTokenRepository,
+	authService *AuthService,
+	lockout *LockoutService,
+	notifier domain.AccountNotifier,
+	events domain.EventPublisher,
+	uow domain.UnitOfWork,
+	logger domain.Logger,
+) *PasswordResetService {
+	return &PasswordResetService{
+		userRepo:         userRepo,
+		accountRepo:      accountRepo,
+		resetRepo:        resetRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		authService:      authService,
+		lockout:          lockout,
+		notifier:         notifier,
+		events:           events,
+		uow:              uow,
+		logger:           logger,
+		minDuration:      passwordResetMinDuration,
+	}
+}
+
+// ForgotPassword envia um link de redefinição de senha, revogando os anteriores
+// Emails desconhecidos, contas não ativas e pedidos acima do limite não geram
+// erro, e a resposta leva sempre o mesmo tempo mínimo, para não revelar quais
+// emails estão cadastrados
+func (s *PasswordResetService) ForgotPassword(ctx context.Context, email string) error {
+	deadline := time.NewTimer(s.minDuration)
+	defer deadline.Stop()
+
+	err := s.requestReset(ctx, email)
+
+	select {
+	case <-deadline.C:
+	case <-ctx.Done():
+	}
+
+	return err
+}
+
+// requestReset emite o token e o email quando o email pertence a uma conta ativa
+func (s *PasswordResetService) requestReset(ctx context.Context, email string) error {
+	normalized, err := valueobjects.NewEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, normalized.String())
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil
+		}
+		s.logger.Error("failed to find user", "error", err)
+		return err
+	}
+
+	if !user.IsActive() {
+		s.logger.Info("password reset ignored", "user_id", user.ID, "status", user.Status)
+		return nil
+	}
+
+	count, err := s.resetRepo.CountCreatedSince(ctx, user.ID, time.Now().Add(-passwordResetWindow))
+	if err != nil {
+		s.logger.Error("failed to count password reset tokens", "user_id", user.ID, "error", err)
+		return err
+	}
+	if count >= passwordResetLimit {
+		s.logger.Warn("password reset rate limited", "user_id", user.ID)
+		return nil
+	}
+
+	locale := entities.DefaultAccountLocale
+	if account, err := s.accountRepo.FindByUserID(ctx, user.ID); err == nil {
+		locale = account.Locale
+	}
+
+	err = s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.resetRepo.RevokeByUser(txCtx, user.ID); err != nil {
+			return err
+		}
+
+		token, err := auth.GenerateOpaqueToken()
+		if err != nil {
+			return err
+		}
+
+		stored := &entities.PasswordResetToken{
+			ID:        uuid.New().String(),
+			UserID:    user.ID,
+			TokenHash: auth.HashToken(token),
+			ExpiresAt: time.Now().Add(passwordResetTokenTTL),
+		}
+		if err := s.resetRepo.Create(txCtx, stored); err != nil {
+			return err
+		}
+
+		// Chamado dentro da transação: com o outbox, o email só sai se ela for confirmada
+		return s.notifier.SendPasswordReset(txCtx, domain.PasswordResetNotice{
+			Email:     user.Email.String(),
+			Locale:    locale,
+			Token:     token,
+			ExpiresAt: stored.ExpiresAt,
+		})
+	})
+	if err != nil {
+		s.logger.Error("failed to request password reset", "user_id", user.ID, "error", err)
+		return err
+	}
+
+	s.logger.Info("password reset requested", "user_id", user.ID)
+	return nil
+}
+
+// ResetPassword consome o token, grava a nova senha e encerra todas as sessões
+// Também remove o bloqueio de login: o email de conta bloqueada orienta o
+// dono a redefinir a senha
+func (s *PasswordResetService) ResetPassword(ctx context.Context, token, password string) error {
+	if errs := valueobjects.ValidatePassword(password); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	stored, err := s.resetRepo.FindByHash(ctx, auth.HashToken(token))
+	if err != nil {
+		if !errors.Is(err, domainerrors.ErrInvalidPasswordResetToken) {
+			s.logger.Error("failed to find password reset token", "error", err)
+		}
+		return err
+	}
+
+	if stored.IsUsed() || stored.IsRevoked() {
+		return domainerrors.ErrInvalidPasswordResetToken
+	}
+	if stored.IsExpired(time.Now()) {
+		return domainerrors.ErrPasswordResetTokenExpired
+	}
+
+	user, err := s.userRepo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return domainerrors.ErrInvalidPasswordResetToken
+		}
+		s.logger.Error("failed to find user", "user_id", stored.UserID, "error", err)
+		return err
+	}
+
+	if !user.IsActive() {
+		return domainerrors.ErrAccountNotActive
+	}
+
+	hash, err := s.authService.HashPassword(password)
+	if err != nil {
+		s.logger.Error("failed to hash password", "error", err)
+		return err
+	}
+
+	err = s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.resetRepo.MarkAsUsed(txCtx, stored.ID); err != nil {
+			return err
+		}
+		if err := s.userRepo.UpdatePasswordHash(txCtx, user.ID, hash); err != nil {
+			return err
+		}
+		if err := s.resetRepo.RevokeByUser(txCtx, user.ID); err != nil {
+			return err
+		}
+
+		// Quem tinha a senha antiga perde todas as sessões abertas
+		if err := s.refreshTokenRepo.RevokeByUser(txCtx, user.ID); err != nil {
+			return err
+		}
+
+		return s.events.Publish(txCtx, domain.Event{
+			Type:    domain.EventPasswordReset,
+			Payload: domain.PasswordResetEvent{UserID: user.ID},
+		})
+	})
+	if err != nil {
+		if !errors.Is(err, domainerrors.ErrInvalidPasswordResetToken) {
+			s.logger.Error("failed to reset password", "user_id", user.ID, "error", err)
+		}
+		return err
+	}
+
+	// A senha já foi trocada: uma falha aqui não desfaz a redefinição, e o
+	// bloqueio expira sozinho
+	_ = s.lockout.Unlock(ctx, user)
+
+	s.logger.Info("password reset", "user_id", user.ID)
+	return nil
+}
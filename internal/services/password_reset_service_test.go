@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
+)
+
+type passwordResetFixture struct {
+	service  *PasswordResetService
+	auth     *AuthService
+	lockout  *LockoutService
+	user     *entities.User
+	resets   *fakePasswordResetTokenRepository
+	refresh  *fakeRefreshTokenRepository
+	notifier *fakeNotifier
+	events   *fakeEventPublisher
+}
+
+func newPasswordResetFixture(t *testing.T) *passwordResetFixture {
+	t.Helper()
+
+	user := newTestUser(t, "user-1", "joao@email.com", "Senha123")
+	userRepo := newFakeUserRepository(user)
+	resets := newFakePasswordResetTokenRepository()
+	refresh := newFakeRefreshTokenRepository()
+	notifier := newFakeNotifier()
+	events := &fakeEventPublisher{}
+	lockoutService := newTestLockoutService()
+	authService := NewAuthService(userRepo, refresh, newFakeMFARepository(), fakeUnitOfWork{}, newTestJWTService(t), newTestPasswordHasher(t), lockoutService, nopLogger{})
+
+	service := NewPasswordResetService(
+		userRepo, newFakeUserAccountRepository(), resets, refresh,
+		authService, lockoutService, notifier, events, fakeUnitOfWork{}, nopLogger{},
+	)
+	service.minDuration = 0
+
+	return &passwordResetFixture{
+		service:  service,
+		auth:     authService,
+		lockout:  lockoutService,
+		user:     user,
+		resets:   resets,
+		refresh:  refresh,
+		notifier: notifier,
+		events:   events,
+	}
+}
+
+// forgot pede a redefinição e retorna o token enviado
+func (f *passwordResetFixture) forgot(t *testing.T) string {
+	t.Helper()
+
+	if err := f.service.ForgotPassword(context.Background(), f.user.Email.String()); err != nil {
+		t.Fatalf("falha ao pedir redefinição: %v", err)
+	}
+
+	token, ok := f.notifier.passwordResets[f.user.Email.String()]
+	if !ok {
+		t.Fatal("esperava token de redefinição enviado")
+	}
+	return token
+}
+
+func TestPasswordResetService_ForgotPassword(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("envia token e persiste apenas o hash", func(t *testing.T) {
+		f := newPasswordResetFixture(t)
+		token := f.forgot(t)
+
+		stored, err := f.resets.FindByHash(ctx, auth.HashToken(token))
+		if err != nil {
+			t.Fatalf("esperava token persistido, obteve %v", err)
+		}
+		if stored.TokenHash == token {
+			t.Error("não esperava o token em texto plano")
+		}
+		if stored.UserID != f.user.ID {
+			t.Errorf("esperava usuário '%s', obteve '%s'", f.user.ID, stored.UserID)
+		}
+	})
+
+	t.Run("novo pedido revoga o token anterior", func(t *testing.T) {
+		f := newPasswordResetFixture(t)
+		first := f.forgot(t)
+		f.forgot(t)
+
+		stored, _ := f.resets.FindByHash(ctx, auth.HashToken(first))
+		if !stored.IsRevoked() {
+			t.Error("esperava token anterior revogado")
+		}
+	})
+
+	t.Run("email desconhecido ou inválido não gera erro nem envio", func(t *testing.T) {
+		f := newPasswordResetFixture(t)
+
+		for _, email := range []string{"ninguem@email.com", "invalido"} {
+			if err := f.service.ForgotPassword(ctx, email); err != nil {
+				t.Errorf("esperava sucesso silencioso para '%s', obteve %v", email, err)
+			}
+		}
+		if len(f.notifier.passwordResets) != 0 {
+			t.Errorf("não esperava envio, obteve %v", f.notifier.passwordResets)
+		}
+	})
+
+	t.Run("conta inativa não recebe link", func(t *testing.T) {
+		f := newPasswordResetFixture(t)
+		f.user.Status = entities.UserStatusInactive
+
+		if err := f.service.ForgotPassword(ctx, f.user.Email.String()); err != nil {
+			t.Fatalf("esperava sucesso silencioso, obteve %v", err)
+		}
+		if len(f.notifier.passwordResets) != 0 {
+			t.Error("não esperava envio para conta inativa")
+		}
+	})
+
+	t.Run("acima do limite ignora o pedido sem erro", func(t *testing.T) {
+		f := newPasswordResetFixture(t)
+		for i := 0; i < passwordResetLimit; i++ {
+			f.forgot(t)
+		}
+		delete(f.notifier.passwordResets, f.user.Email.String())
+
+		if err := f.service.ForgotPassword(ctx, f.user.Email.String()); err != nil {
+			t.Fatalf("esperava sucesso silencioso, obteve %v", err)
+		}
+		if _, ok := f.notifier.passwordResets[f.user.Email.String()]; ok {
+			t.Error("não esperava envio acima do limite")
+		}
+	})
+
+	t.Run("responde no tempo mínimo mesmo para email desconhecido", func(t *testing.T) {
+		f := newPasswordResetFixture(t)
+		f.service.minDuration = 50 * time.Millisecond
+
+		start := time.Now()
+		if err := f.service.ForgotPassword(ctx, "ninguem@email.com"); err != nil {
+			t.Fatalf("esperava sucesso, obteve %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < f.service.minDuration {
+			t.Errorf("esperava pelo menos %v, obteve %v", f.service.minDuration, elapsed)
+		}
+	})
+}
+
+func TestPasswordResetService_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("grava a nova senha e encerra todas as sessões", func(t *testing.T) {
+		f := newPasswordResetFixture(t)
+		session, err := f.auth.Login(ctx, f.user.Email.String(), "Senha123")
+		if err != nil {
+			t.Fatalf("falha no login: %v", err)
+		}
+		token := f.forgot(t)
+
+		if err := f.service.ResetPassword(ctx, token, "NovaSenha456"); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		if _, err := f.auth.Login(ctx, f.user.Email.String(), "Senha123"); !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+			t.Errorf("esperava senha antiga rejeitada, obteve %v", err)
+		}
+		if _, err := f.auth.Login(ctx, f.user.Email.String(), "NovaSenha456"); err != nil {
+			t.Errorf("esperava login com a nova senha, obteve %v", err)
+		}
+		if _, err := f.auth.Refresh(ctx, session.RefreshToken); !errors.Is(err, domainerrors.ErrInvalidRefreshToken) {
+			t.Errorf("esperava sessão anterior revogada, obteve %v", err)
+		}
+		if types := f.events.types(); len(types) != 1 || types[0] != domain.EventPasswordReset {
+			t.Errorf("esperava evento %s, obteve %v", domain.EventPasswordReset, types)
+		}
+	})
+
+	t.Run("remove o bloqueio de login da conta", func(t *testing.T) {
+		f := newPasswordResetFixture(t)
+		for i := 0; i < accountLockoutPolicy.lockAfter; i++ {
+			f.lockout.RecordFailure(ctx, f.user.Email.String(), f.user)
+		}
+		if _, err := f.auth.Login(ctx, f.user.Email.String(), "Senha123"); !errors.Is(err, domainerrors.ErrAccountLocked) {
+			t.Fatalf("esperava conta bloqueada, obteve %v", err)
+		}
+		token := f.forgot(t)
+
+		if err := f.service.ResetPassword(ctx, token, "NovaSenha456"); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		if _, err := f.auth.Login(ctx, f.user.Email.String(), "NovaSenha456"); err != nil {
+			t.Errorf("esperava login com a nova senha, obteve %v", err)
+		}
+	})
+
+	t.Run("token é de uso único", func(t *testing.T) {
+		f := newPasswordResetFixture(t)
+		token := f.forgot(t)
+
+		if err := f.service.ResetPassword(ctx, token, "NovaSenha456"); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if err := f.service.ResetPassword(ctx, token, "OutraSenha789"); !errors.Is(err, domainerrors.ErrInvalidPasswordResetToken) {
+			t.Errorf("esperava ErrInvalidPasswordResetToken, obteve %v", err)
+		}
+	})
+
+	t.Run("token revogado por novo pedido é inválido", func(t *testing.T) {
+		f := newPasswordResetFixture(t)
+		first := f.forgot(t)
+		f.forgot(t)
+
+		if err := f.service.ResetPassword(ctx, first, "NovaSenha456"); !errors.Is(err, domainerrors.ErrInvalidPasswordResetToken) {
+			t.Errorf("esperava ErrInvalidPasswordResetToken, obteve %v", err)
+		}
+	})
+
+	t.Run("token expirado retorna ErrPasswordResetTokenExpired", func(t *testing.T) {
+		f := newPasswordResetFixture(t)
+		token := f.forgot(t)
+
+		stored, _ := f.resets.FindByHash(ctx, auth.HashToken(token))
+		f.resets.tokens[stored.ID].ExpiresAt = time.Now().Add(-time.Minute)
+
+		if err := f.service.ResetPassword(ctx, token, "NovaSenha456"); !errors.Is(err, domainerrors.ErrPasswordResetTokenExpired) {
+			t.Errorf("esperava ErrPasswordResetTokenExpired, obteve %v", err)
+		}
+	})
+
+	t.Run("token desconhecido retorna ErrInvalidPasswordResetToken", func(t *testing.T) {
+		f := newPasswordResetFixture(t)
+
+		if err := f.service.ResetPassword(ctx, "desconhecido", "NovaSenha456"); !errors.Is(err, domainerrors.ErrInvalidPasswordResetToken) {
+			t.Errorf("esperava ErrInvalidPasswordResetToken, obteve %v", err)
+		}
+	})
+
+	t.Run("aplica a política de senha", func(t *testing.T) {
+		f := newPasswordResetFixture(t)
+		token := f.forgot(t)
+
+		err := f.service.ResetPassword(ctx, token, "abc")
+		if !errors.Is(err, domainerrors.ErrPasswordLength) || !errors.Is(err, domainerrors.ErrPasswordNoNumber) {
+			t.Errorf("esperava todas as violações da política, obteve %v", err)
+		}
+
+		stored, _ := f.resets.FindByHash(ctx, auth.HashToken(token))
+		if stored.IsUsed() {
+			t.Error("não esperava token consumido por senha inválida")
+		}
+	})
+}
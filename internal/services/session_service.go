@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+)
+
+// SessionService implementa a listagem e a revogação das sessões do usuário
+//
+// Uma sessão é uma família de refresh tokens. Revogá-la impede novas rotações
+// e coloca a sessão na denylist pela validade de um access token, encerrando
+// também os access tokens já emitidos a outros dispositivos (ex: um laptop perdido)
+type SessionService struct {
+	refreshTokenRepo repositories.RefreshTokenRepository
+	denylist         domain.TokenDenylist
+	accessTTL        time.Duration
+	logger           domain.Logger
+}
+
+// NewSessionService cria um novo SessionService
+// accessTTL é a validade dos access tokens, pela qual a sessão revogada fica na denylist
+func NewSessionService(
+	refreshTokenRepo repositories.RefreshTokenRepository,
+	denylist domain.TokenDenylist,
+	accessTTL time.Duration,
+	logger domain.Logger,
+) *SessionService {
+	return &SessionService{
+		refreshTokenRepo: refreshTokenRepo,
+		denylist:         denylist,
+		accessTTL:        accessTTL,
+		logger:           logger,
+	}
+}
+
+// List retorna as sessões ativas do usuário
+// A sessão do access token da requisição é marcada como atual
+func (s *SessionService) List(ctx context.Context, userID string) ([]*entities.Session, error) {
+	sessions, err := s.refreshTokenRepo.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, _ := domain.AccessTokenFromContext(ctx)
+	for _, session := range sessions {
+		session.Current = current.SessionID != "" && session.ID == current.SessionID
+	}
+
+	return sessions, nil
+}
+
+// Logout encerra a sessão atual: revoga o refresh token e os access tokens da sessão
+// É idempotente; uma sessão já encerrada não é erro
+func (s *SessionService) Logout(ctx context.Context, userID string) error {
+	current, _ := domain.AccessTokenFromContext(ctx)
+	if current.SessionID != "" {
+		err := s.refreshTokenRepo.RevokeSession(ctx, userID, current.SessionID)
+		if err != nil && !errors.Is(err, domainerrors.ErrSessionNotFound) {
+			return err
+		}
+
+		if err := s.revokeSessionTokens(ctx, current.SessionID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.revokeCurrentToken(ctx); err != nil {
+		return err
+	}
+
+	s.logger.Info("user logged out", "user_id", userID, "session_id", current.SessionID)
+	return nil
+}
+
+// Revoke encerra uma sessão do usuário; nem o refresh token nem os access
+// tokens dela são mais aceitos
+func (s *SessionService) Revoke(ctx context.Context, userID, sessionID string) error {
+	if uuid.Validate(sessionID) != nil {
+		return domainerrors.ErrSessionNotFound
+	}
+
+	if err := s.refreshTokenRepo.RevokeSession(ctx, userID, sessionID); err != nil {
+		return err
+	}
+
+	if err := s.revokeSessionTokens(ctx, sessionID); err != nil {
+		return err
+	}
+
+	if current, ok := domain.AccessTokenFromContext(ctx); ok && current.SessionID == sessionID {
+		if err := s.revokeCurrentToken(ctx); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info("session revoked", "user_id", userID, "session_id", sessionID)
+	return nil
+}
+
+// RevokeAll encerra todas as sessões do usuário, inclusive a atual
+func (s *SessionService) RevokeAll(ctx context.Context, userID string) error {
+	sessions, err := s.refreshTokenRepo.ListSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.refreshTokenRepo.RevokeByUser(ctx, userID); err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if err := s.revokeSessionTokens(ctx, session.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.revokeCurrentToken(ctx); err != nil {
+		return err
+	}
+
+	s.logger.Info("all sessions revoked", "user_id", userID)
+	return nil
+}
+
+// revokeSessionTokens coloca a sessão na denylist pela validade de um access
+// token: nenhum access token emitido antes da revogação continua aceito
+func (s *SessionService) revokeSessionTokens(ctx context.Context, sessionID string) error {
+	if err := s.denylist.Revoke(ctx, domain.SessionDenylistKey(sessionID), time.Now().Add(s.accessTTL)); err != nil {
+		s.logger.Error("failed to revoke session access tokens", "session_id", sessionID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// revokeCurrentToken coloca o access token da requisição na denylist até ele expirar
+func (s *SessionService) revokeCurrentToken(ctx context.Context) error {
+	current, ok := domain.AccessTokenFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if err := s.denylist.Revoke(ctx, current.ID, current.ExpiresAt); err != nil {
+		s.logger.Error("failed to revoke access token", "jti", current.ID, "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/denylist"
+)
+
+func TestSessionService(t *testing.T) {
+	jwtService := newTestJWTService(t)
+	user := newTestUser(t, "user-1", "user@example.com", "Senha123")
+
+	newServices := func() (*AuthService, *SessionService, *denylist.MemoryDenylist) {
+		refreshRepo := newFakeRefreshTokenRepository()
+		tokenDenylist := denylist.NewMemoryDenylist()
+		authService := NewAuthService(newFakeUserRepository(user), refreshRepo, newFakeMFARepository(), fakeUnitOfWork{}, jwtService, newTestPasswordHasher(t), newTestLockoutService(), nopLogger{})
+		return authService, NewSessionService(refreshRepo, tokenDenylist, jwtService.AccessExpiry(), nopLogger{}), tokenDenylist
+	}
+
+	// authenticated retorna o contexto de uma requisição feita com o access token
+	authenticated := func(t *testing.T, result *AuthResult) (context.Context, domain.AccessToken) {
+		t.Helper()
+
+		claims, err := jwtService.ValidateAccessToken(result.AccessToken)
+		if err != nil {
+			t.Fatalf("access token inválido: %v", err)
+		}
+
+		token := domain.AccessToken{ID: claims.ID, SessionID: claims.SessionID, ExpiresAt: claims.ExpiresAt.Time}
+		return domain.WithAccessToken(context.Background(), token), token
+	}
+
+	// login autentica a partir do cliente informado e retorna a sessão do access token
+	login := func(t *testing.T, authService *AuthService, client domain.ClientInfo) (*AuthResult, string) {
+		t.Helper()
+
+		result, err := authService.Login(domain.WithClientInfo(context.Background(), client), "user@example.com", "Senha123")
+		if err != nil {
+			t.Fatalf("falha no login: %v", err)
+		}
+
+		claims, err := jwtService.ValidateAccessToken(result.AccessToken)
+		if err != nil {
+			t.Fatalf("access token inválido: %v", err)
+		}
+		if claims.SessionID == "" {
+			t.Fatal("esperava sid no access token")
+		}
+
+		return result, claims.SessionID
+	}
+
+	laptop := domain.ClientInfo{IPAddress: "203.0.113.10", UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) Firefox/131.0"}
+	phone := domain.ClientInfo{IPAddress: "198.51.100.7", UserAgent: "Mozilla/5.0 (Android 14; Mobile; rv:131.0) Firefox/131.0"}
+
+	t.Run("lista sessões com cliente e marca a atual", func(t *testing.T) {
+		authService, service, _ := newServices()
+		_, laptopSession := login(t, authService, laptop)
+		phoneLogin, phoneSession := login(t, authService, phone)
+		ctx, _ := authenticated(t, phoneLogin)
+
+		sessions, err := service.List(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if len(sessions) != 2 {
+			t.Fatalf("esperava 2 sessões, obteve %d", len(sessions))
+		}
+
+		for _, session := range sessions {
+			switch session.ID {
+			case laptopSession:
+				if session.Current || session.IPAddress != laptop.IPAddress || session.UserAgent != laptop.UserAgent {
+					t.Errorf("sessão do laptop inesperada: %+v", session)
+				}
+			case phoneSession:
+				if !session.Current || session.IPAddress != phone.IPAddress {
+					t.Errorf("sessão do celular inesperada: %+v", session)
+				}
+			default:
+				t.Errorf("sessão desconhecida: %s", session.ID)
+			}
+		}
+	})
+
+	t.Run("rotação mantém a sessão e atualiza o cliente", func(t *testing.T) {
+		authService, service, _ := newServices()
+		result, sessionID := login(t, authService, laptop)
+
+		if _, err := authService.Refresh(domain.WithClientInfo(context.Background(), phone), result.RefreshToken); err != nil {
+			t.Fatalf("falha na rotação: %v", err)
+		}
+
+		sessions, _ := service.List(context.Background(), user.ID)
+		if len(sessions) != 1 {
+			t.Fatalf("esperava 1 sessão, obteve %d", len(sessions))
+		}
+		if sessions[0].ID != sessionID || sessions[0].IPAddress != phone.IPAddress {
+			t.Errorf("esperava sessão '%s' com IP '%s', obteve %+v", sessionID, phone.IPAddress, sessions[0])
+		}
+	})
+
+	t.Run("revogar sessão invalida o refresh token dela", func(t *testing.T) {
+		authService, service, _ := newServices()
+		revoked, revokedSession := login(t, authService, laptop)
+		kept, _ := login(t, authService, phone)
+
+		if err := service.Revoke(context.Background(), user.ID, revokedSession); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		if _, err := authService.Refresh(context.Background(), revoked.RefreshToken); !errors.Is(err, domainerrors.ErrInvalidRefreshToken) {
+			t.Errorf("esperava ErrInvalidRefreshToken, obteve %v", err)
+		}
+		if _, err := authService.Refresh(context.Background(), kept.RefreshToken); err != nil {
+			t.Errorf("esperava a outra sessão ativa, obteve %v", err)
+		}
+	})
+
+	t.Run("sessão de outro usuário não é encontrada", func(t *testing.T) {
+		authService, service, _ := newServices()
+		_, sessionID := login(t, authService, laptop)
+
+		if err := service.Revoke(context.Background(), "user-2", sessionID); !errors.Is(err, domainerrors.ErrSessionNotFound) {
+			t.Errorf("esperava ErrSessionNotFound, obteve %v", err)
+		}
+	})
+
+	t.Run("sessão inexistente ou inválida não é encontrada", func(t *testing.T) {
+		_, service, _ := newServices()
+
+		for _, id := range []string{uuid.New().String(), "nao-e-uuid"} {
+			if err := service.Revoke(context.Background(), user.ID, id); !errors.Is(err, domainerrors.ErrSessionNotFound) {
+				t.Errorf("id '%s': esperava ErrSessionNotFound, obteve %v", id, err)
+			}
+		}
+	})
+
+	t.Run("revogar a sessão atual revoga o access token", func(t *testing.T) {
+		authService, service, tokenDenylist := newServices()
+		result, sessionID := login(t, authService, laptop)
+		ctx, current := authenticated(t, result)
+
+		if err := service.Revoke(ctx, user.ID, sessionID); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		if revoked, _ := tokenDenylist.IsRevoked(context.Background(), current.ID); !revoked {
+			t.Error("esperava access token na denylist")
+		}
+	})
+
+	t.Run("revogar sessão revoga os access tokens de outros dispositivos", func(t *testing.T) {
+		authService, service, tokenDenylist := newServices()
+		current, _ := login(t, authService, phone)
+		_, lostSession := login(t, authService, laptop)
+		ctx, _ := authenticated(t, current)
+
+		if err := service.Revoke(ctx, user.ID, lostSession); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		if revoked, _ := tokenDenylist.IsRevoked(context.Background(), domain.SessionDenylistKey(lostSession)); !revoked {
+			t.Error("esperava a sessão revogada na denylist")
+		}
+	})
+
+	t.Run("revogar outra sessão mantém o access token atual", func(t *testing.T) {
+		authService, service, tokenDenylist := newServices()
+		result, _ := login(t, authService, laptop)
+		_, otherSession := login(t, authService, phone)
+		ctx, current := authenticated(t, result)
+
+		if err := service.Revoke(ctx, user.ID, otherSession); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		if revoked, _ := tokenDenylist.IsRevoked(context.Background(), current.ID); revoked {
+			t.Error("não esperava access token atual na denylist")
+		}
+	})
+
+	t.Run("logout encerra apenas a sessão atual", func(t *testing.T) {
+		authService, service, tokenDenylist := newServices()
+		result, _ := login(t, authService, laptop)
+		other, _ := login(t, authService, phone)
+		ctx, current := authenticated(t, result)
+
+		if err := service.Logout(ctx, user.ID); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		// Repetir o logout não é erro
+		if err := service.Logout(ctx, user.ID); err != nil {
+			t.Fatalf("esperava logout idempotente, obteve erro: %v", err)
+		}
+
+		if revoked, _ := tokenDenylist.IsRevoked(context.Background(), current.ID); !revoked {
+			t.Error("esperava access token na denylist")
+		}
+		if _, err := authService.Refresh(context.Background(), result.RefreshToken); !errors.Is(err, domainerrors.ErrInvalidRefreshToken) {
+			t.Errorf("esperava ErrInvalidRefreshToken, obteve %v", err)
+		}
+		if _, err := authService.Refresh(context.Background(), other.RefreshToken); err != nil {
+			t.Errorf("esperava a outra sessão ativa, obteve %v", err)
+		}
+	})
+
+	t.Run("sair de todos os dispositivos encerra todas as sessões", func(t *testing.T) {
+		authService, service, tokenDenylist := newServices()
+		first, firstSession := login(t, authService, laptop)
+		second, secondSession := login(t, authService, phone)
+		ctx, current := authenticated(t, first)
+
+		if err := service.RevokeAll(ctx, user.ID); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		for _, sessionID := range []string{firstSession, secondSession} {
+			if revoked, _ := tokenDenylist.IsRevoked(context.Background(), domain.SessionDenylistKey(sessionID)); !revoked {
+				t.Errorf("esperava a sessão '%s' na denylist", sessionID)
+			}
+		}
+
+		if revoked, _ := tokenDenylist.IsRevoked(context.Background(), current.ID); !revoked {
+			t.Error("esperava access token na denylist")
+		}
+
+		sessions, _ := service.List(context.Background(), user.ID)
+		if len(sessions) != 0 {
+			t.Errorf("esperava nenhuma sessão, obteve %d", len(sessions))
+		}
+		for _, result := range []*AuthResult{first, second} {
+			if _, err := authService.Refresh(context.Background(), result.RefreshToken); !errors.Is(err, domainerrors.ErrInvalidRefreshToken) {
+				t.Errorf("esperava ErrInvalidRefreshToken, obteve %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,548 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
+)
+
+// SSOService implementa o SSO corporativo: cada organização configura o seu
+// próprio provedor OpenID Connect
+//
+// O fluxo reaproveita os states e as identidades do login OAuth2, com o
+// provedor "oidc:<organization_id>". O nonce do ID token é derivado do
+// verifier PKCE, que só existe no banco, então não precisa de coluna própria.
+//
+// O IdP de uma organização só responde pelos seus membros e pelos domínios
+// que ela comprovou ser dona (registro TXT): um email que já pertence a um
+// usuário de fora da organização não é vinculado, e só emails de domínios
+// comprovados criam contas, pois o admin poderia configurar um IdP que afirma
+// qualquer email.
+type SSOService struct {
+	oidc         domain.OIDCClient
+	dns          domain.TXTResolver
+	configRepo   repositories.SSOConfigRepository
+	stateRepo    repositories.OAuthStateRepository
+	identityRepo repositories.UserIdentityRepository
+	userRepo     repositories.UserRepository
+	accountRepo  repositories.UserAccountRepository
+	memberRepo   repositories.OrganizationMemberRepository
+	authService  *AuthService
+	callbackURL  func(organizationID string) string
+	uow          domain.UnitOfWork
+	logger       domain.Logger
+}
+
+// NewSSOService cria um novo SSOService
+// callbackURL retorna a URL de callback registrada no IdP de cada organização
+func NewSSOService(
+	oidc domain.OIDCClient,
+	dns domain.TXTResolver,
+	configRepo repositories.SSOConfigRepository,
+	stateRepo repositories.OAuthStateRepository,
+	identityRepo repositories.UserIdentityRepository,
+	userRepo repositories.UserRepository,
+	accountRepo repositories.UserAccountRepository,
+	memberRepo repositories.OrganizationMemberRepository,
+	authService *AuthService,
+	callbackURL func(organizationID string) string,
+	uow domain.UnitOfWork,
+	logger domain.Logger,
+) *SSOService {
+	return &SSOService{
+		oidc:         oidc,
+		dns:          dns,
+		configRepo:   configRepo,
+		stateRepo:    stateRepo,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		accountRepo:  accountRepo,
+		memberRepo:   memberRepo,
+		authService:  authService,
+		callbackURL:  callbackURL,
+		uow:          uow,
+		logger:       logger,
+	}
+}
+
+// ssoDomainLookupTimeout limita a consulta do registro TXT de cada domínio
+const ssoDomainLookupTimeout = 5 * time.Second
+
+// SSOConfigInput contém a configuração do provedor OIDC da organização
+type SSOConfigInput struct {
+	Issuer         string
+	ClientID       string
+	ClientSecret   string
+	AllowedDomains []string
+	DefaultRole    entities.Role
+	Enabled        bool
+}
+
+// SSOCallbackInput contém os parâmetros devolvidos pelo IdP
+// Locale só é usado quando o login cria um novo usuário
+type SSOCallbackInput struct {
+	OrganizationID string
+	State          string
+	Code           string
+	Locale         string
+}
+
+// GetConfig retorna a configuração de SSO da organização
+func (s *SSOService) GetConfig(ctx context.Context, userID, organizationID string) (*entities.SSOConfig, error) {
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	if _, err := authorizeMember(ctx, s.memberRepo, userID, organizationID, entities.PermissionSSORead); err != nil {
+		return nil, err
+	}
+
+	return s.findConfig(ctx)
+}
+
+// SaveConfig cria ou substitui a configuração de SSO da organização
+// O issuer é validado lendo o documento de descoberta antes de salvar
+func (s *SSOService) SaveConfig(ctx context.Context, userID, organizationID string, input SSOConfigInput) (*entities.SSOConfig, error) {
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	if _, err := authorizeMember(ctx, s.memberRepo, userID, organizationID, entities.PermissionSSOWrite); err != nil {
+		return nil, err
+	}
+
+	domains, err := normalizeSSODomains(input.AllowedDomains)
+	if err != nil {
+		return nil, err
+	}
+
+	issuer := strings.TrimSpace(input.Issuer)
+	if err := s.oidc.Discover(ctx, issuer); err != nil {
+		s.logger.Warn("sso issuer discovery failed", "organization_id", organizationID, "issuer", issuer, "error", err)
+		return nil, domainerrors.ErrInvalidSSOIssuer
+	}
+
+	role := input.DefaultRole
+	if role == "" {
+		role = entities.RoleUser
+	}
+
+	config := &entities.SSOConfig{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+		Issuer:         issuer,
+		ClientID:       strings.TrimSpace(input.ClientID),
+		ClientSecret:   input.ClientSecret,
+		AllowedDomains: domains,
+		DefaultRole:    role,
+		Enabled:        input.Enabled,
+	}
+
+	err = s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		// Substituir a configuração mantém o id, a data de criação, o token e
+		// os domínios já comprovados que continuam na lista
+		existing, err := s.configRepo.Find(txCtx)
+		switch {
+		case err == nil:
+			config.ID = existing.ID
+			config.CreatedAt = existing.CreatedAt
+			config.DomainVerificationToken = existing.DomainVerificationToken
+			for _, d := range domains {
+				if existing.IsDomainVerified(d) {
+					config.VerifiedDomains = append(config.VerifiedDomains, d)
+				}
+			}
+		case !errors.Is(err, domainerrors.ErrSSONotConfigured):
+			return err
+		}
+
+		if config.DomainVerificationToken == "" {
+			if config.DomainVerificationToken, err = auth.GenerateOpaqueToken(); err != nil {
+				return err
+			}
+		}
+
+		return s.configRepo.Save(txCtx, config)
+	})
+	if err != nil {
+		s.logger.Error("failed to save sso config", "organization_id", organizationID, "error", err)
+		return nil, err
+	}
+
+	s.logger.Info("sso config saved", "organization_id", organizationID, "issuer", issuer, "enabled", config.Enabled, "user_id", userID)
+	return config, nil
+}
+
+// VerifyDomains consulta o registro TXT dos domínios pendentes e marca como
+// comprovados os que publicam o token da organização
+// Domínios que não conferem continuam pendentes; a resposta mostra quais
+func (s *SSOService) VerifyDomains(ctx context.Context, userID, organizationID string) (*entities.SSOConfig, error) {
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	if _, err := authorizeMember(ctx, s.memberRepo, userID, organizationID, entities.PermissionSSOWrite); err != nil {
+		return nil, err
+	}
+
+	config, err := s.findConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// As consultas DNS ficam fora da transação
+	var proven []string
+	for _, d := range config.AllowedDomains {
+		if !config.IsDomainVerified(d) && config.DomainVerificationToken != "" && s.publishesToken(ctx, config, d) {
+			proven = append(proven, d)
+		}
+	}
+
+	err = s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		current, err := s.configRepo.Find(txCtx)
+		if err != nil {
+			return err
+		}
+
+		// Configurações anteriores à verificação ainda não têm token
+		if current.DomainVerificationToken == "" {
+			if current.DomainVerificationToken, err = auth.GenerateOpaqueToken(); err != nil {
+				return err
+			}
+		}
+
+		// A configuração pode ter mudado durante as consultas: só vale o
+		// domínio que continua declarado e com o mesmo token
+		if current.DomainVerificationToken == config.DomainVerificationToken {
+			for _, d := range proven {
+				if slices.Contains(current.AllowedDomains, d) && !current.IsDomainVerified(d) {
+					current.VerifiedDomains = append(current.VerifiedDomains, d)
+				}
+			}
+		}
+
+		config = current
+		return s.configRepo.Save(txCtx, config)
+	})
+	if err != nil {
+		s.logger.Error("failed to save sso domain verification", "organization_id", organizationID, "error", err)
+		return nil, err
+	}
+
+	for _, d := range proven {
+		s.logger.Info("sso domain verified", "organization_id", organizationID, "domain", d, "user_id", userID)
+	}
+	return config, nil
+}
+
+// publishesToken verifica se o registro TXT do domínio contém o token da organização
+func (s *SSOService) publishesToken(ctx context.Context, config *entities.SSOConfig, d string) bool {
+	ctx, cancel := context.WithTimeout(ctx, ssoDomainLookupTimeout)
+	defer cancel()
+
+	name, want := config.DomainVerificationRecord(d)
+	records, err := s.dns.LookupTXT(ctx, name)
+	if err != nil {
+		s.logger.Info("sso domain verification lookup failed", "organization_id", config.OrganizationID, "domain", d, "error", err)
+		return false
+	}
+
+	return slices.Contains(records, want)
+}
+
+// DeleteConfig remove a configuração de SSO da organização
+// Os vínculos já criados permanecem, mas deixam de ser usados
+func (s *SSOService) DeleteConfig(ctx context.Context, userID, organizationID string) error {
+	ctx = domain.WithOrganizationID(ctx, organizationID)
+
+	if _, err := authorizeMember(ctx, s.memberRepo, userID, organizationID, entities.PermissionSSOWrite); err != nil {
+		return err
+	}
+
+	err := s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		return s.configRepo.Delete(txCtx)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("sso config deleted", "organization_id", organizationID, "user_id", userID)
+	return nil
+}
+
+// Start emite o state e o verifier PKCE e retorna a URL de autorização do IdP da organização
+func (s *SSOService) Start(ctx context.Context, organizationID string) (*OAuthStart, error) {
+	config, err := s.enabledConfig(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &entities.OAuthState{
+		ID:           uuid.New().String(),
+		Provider:     config.Provider(),
+		StateHash:    auth.HashToken(state),
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(oauthStateTTL),
+	}
+	if err := s.stateRepo.Create(ctx, stored); err != nil {
+		s.logger.Error("failed to store sso state", "organization_id", organizationID, "error", err)
+		return nil, err
+	}
+
+	authURL, err := s.oidc.AuthCodeURL(ctx, s.clientConfig(config), state, auth.HashToken(verifier), verifier)
+	if err != nil {
+		s.logger.Warn("sso issuer discovery failed", "organization_id", organizationID, "error", err)
+		return nil, domainerrors.ErrOAuthExchangeFailed
+	}
+
+	return &OAuthStart{AuthURL: authURL, State: state}, nil
+}
+
+// Callback consome o state, valida o ID token e abre uma sessão na organização
+// No primeiro login, o usuário e a associação à organização são criados
+func (s *SSOService) Callback(ctx context.Context, input SSOCallbackInput) (*ActivationResult, error) {
+	config, err := s.enabledConfig(ctx, input.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = domain.WithOrganizationID(ctx, config.OrganizationID)
+
+	stored, err := s.stateRepo.Consume(ctx, config.Provider(), auth.HashToken(input.State))
+	if err != nil {
+		if !errors.Is(err, domainerrors.ErrInvalidOAuthState) {
+			s.logger.Error("failed to consume sso state", "organization_id", config.OrganizationID, "error", err)
+		}
+		return nil, err
+	}
+	if stored.IsExpired(time.Now()) {
+		return nil, domainerrors.ErrInvalidOAuthState
+	}
+
+	identity, err := s.oidc.Exchange(ctx, s.clientConfig(config), input.Code, auth.HashToken(stored.CodeVerifier), stored.CodeVerifier)
+	if err != nil {
+		s.logger.Warn("sso exchange failed", "organization_id", config.OrganizationID, "error", err)
+		return nil, domainerrors.ErrOAuthExchangeFailed
+	}
+	identity.Provider = config.Provider()
+
+	result := &ActivationResult{}
+	err = s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		user, err := s.resolveUser(txCtx, config, identity, input.Locale)
+		if err != nil {
+			return err
+		}
+
+		if !user.IsActive() {
+			return domainerrors.ErrAccountNotActive
+		}
+
+		membership, err := s.memberRepo.FindByUserAndOrganization(txCtx, user.ID, config.OrganizationID)
+		if err != nil {
+			return err
+		}
+
+		issued, err := s.authService.StartSession(txCtx, user)
+		if err != nil {
+			return err
+		}
+
+		result.Auth = issued
+		result.Membership = membership
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domainerrors.ErrOAuthEmailNotVerified),
+			errors.Is(err, domainerrors.ErrSSOEmailDomainNotAllowed),
+			errors.Is(err, domainerrors.ErrSSOAccountConflict),
+			errors.Is(err, domainerrors.ErrAccountNotActive),
+			errors.Is(err, domainerrors.ErrForbidden):
+			s.logger.Info("sso login rejected", "organization_id", config.OrganizationID, "reason", err.Error())
+		default:
+			s.logger.Error("failed to complete sso login", "organization_id", config.OrganizationID, "error", err)
+		}
+		return nil, err
+	}
+
+	s.logger.Info("user logged in", "user_id", result.Auth.User.ID, "provider", config.Provider())
+	return result, nil
+}
+
+// resolveUser retorna o usuário vinculado à identidade ou, no primeiro login,
+// vincula um membro existente com o mesmo email ou cria usuário e associação
+func (s *SSOService) resolveUser(ctx context.Context, config *entities.SSOConfig, identity *domain.OAuthIdentity, locale string) (*entities.User, error) {
+	if !identity.EmailVerified {
+		return nil, domainerrors.ErrOAuthEmailNotVerified
+	}
+
+	email, err := valueobjects.NewEmail(identity.Email)
+	if err != nil {
+		return nil, domainerrors.ErrOAuthEmailNotVerified
+	}
+	// Conferido a cada login: os domínios podem mudar depois do vínculo
+	// Só domínios com a posse comprovada valem, inclusive para membros existentes
+	if !config.AllowsEmail(email) {
+		return nil, domainerrors.ErrSSOEmailDomainNotAllowed
+	}
+
+	linked, err := s.identityRepo.FindByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		// Um membro removido pelo admin não volta à organização pelo SSO
+		if _, err := s.memberRepo.FindByUserAndOrganization(ctx, linked.UserID, config.OrganizationID); err != nil {
+			if errors.Is(err, domainerrors.ErrMemberNotFound) {
+				return nil, domainerrors.ErrForbidden
+			}
+			return nil, err
+		}
+		return s.userRepo.FindByID(ctx, linked.UserID)
+	}
+	if !errors.Is(err, domainerrors.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	user, err := s.userRepo.FindByEmail(ctx, email.String())
+	switch {
+	case err == nil:
+		if _, err := s.memberRepo.FindByUserAndOrganization(ctx, user.ID, config.OrganizationID); err != nil {
+			if errors.Is(err, domainerrors.ErrMemberNotFound) {
+				return nil, domainerrors.ErrSSOAccountConflict
+			}
+			return nil, err
+		}
+
+		if user.Status == entities.UserStatusInactive {
+			if err := activateExternalUser(ctx, s.userRepo, user, now); err != nil {
+				return nil, err
+			}
+		}
+	case errors.Is(err, domainerrors.ErrUserNotFound):
+		user, err = createExternalUser(ctx, s.userRepo, s.accountRepo, email, identity, locale, now)
+		if err != nil {
+			return nil, err
+		}
+
+		member := &entities.OrganizationMember{
+			ID:             uuid.New().String(),
+			OrganizationID: config.OrganizationID,
+			UserID:         user.ID,
+			Role:           config.DefaultRole,
+			InvitedAt:      now,
+			JoinedAt:       &now,
+		}
+		if err := s.memberRepo.Create(ctx, member); err != nil {
+			return nil, err
+		}
+
+		s.logger.Info("sso member provisioned", "user_id", user.ID, "organization_id", config.OrganizationID, "role", member.Role)
+	default:
+		return nil, err
+	}
+
+	link := &entities.UserIdentity{
+		ID:       uuid.New().String(),
+		UserID:   user.ID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    email.String(),
+	}
+	if err := s.identityRepo.Create(ctx, link); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("sso identity linked", "user_id", user.ID, "organization_id", config.OrganizationID)
+	return user, nil
+}
+
+// enabledConfig retorna a configuração da organização quando o SSO está habilitado
+// Organizações inexistentes ou sem SSO respondem igual, para não revelar quais existem
+func (s *SSOService) enabledConfig(ctx context.Context, organizationID string) (*entities.SSOConfig, error) {
+	if uuid.Validate(organizationID) != nil {
+		return nil, domainerrors.ErrSSONotConfigured
+	}
+
+	config, err := s.findConfig(domain.WithOrganizationID(ctx, organizationID))
+	if err != nil {
+		return nil, err
+	}
+	if !config.Enabled {
+		return nil, domainerrors.ErrSSONotConfigured
+	}
+
+	return config, nil
+}
+
+// findConfig lê a configuração da organização do contexto
+func (s *SSOService) findConfig(ctx context.Context) (*entities.SSOConfig, error) {
+	var config *entities.SSOConfig
+	err := s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		found, err := s.configRepo.Find(txCtx)
+		if err != nil {
+			return err
+		}
+
+		config = found
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, domainerrors.ErrSSONotConfigured) {
+			s.logger.Error("failed to find sso config", "error", err)
+		}
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// CallbackURL retorna o redirect URI que a organização registra no seu IdP
+func (s *SSOService) CallbackURL(organizationID string) string {
+	return s.callbackURL(organizationID)
+}
+
+// clientConfig monta os parâmetros do cliente OIDC da organização
+func (s *SSOService) clientConfig(config *entities.SSOConfig) domain.OIDCClientConfig {
+	return domain.OIDCClientConfig{
+		Issuer:       config.Issuer,
+		ClientID:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+		RedirectURL:  s.CallbackURL(config.OrganizationID),
+	}
+}
+
+// normalizeSSODomains valida os domínios de email e os converte para minúsculas, sem repetição
+func normalizeSSODomains(domains []string) ([]string, error) {
+	normalized := make([]string, 0, len(domains))
+	seen := make(map[string]bool, len(domains))
+
+	for _, d := range domains {
+		d = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), "@")
+		if _, err := valueobjects.NewEmail("sso@" + d); err != nil {
+			return nil, domainerrors.ErrInvalidSSODomain
+		}
+		if !seen[d] {
+			seen[d] = true
+			normalized = append(normalized, d)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return nil, domainerrors.ErrInvalidSSODomain
+	}
+
+	return normalized, nil
+}
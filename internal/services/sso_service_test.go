@@ -0,0 +1,468 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/oauth"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/oauth/oauthtest"
+)
+
+type ssoFixture struct {
+	service    *SSOService
+	server     *oauthtest.Server
+	users      *fakeUserRepository
+	members    *fakeOrganizationMemberRepository
+	configs    *fakeSSOConfigRepository
+	identities *fakeUserIdentityRepository
+	dns        *fakeTXTResolver
+	admin      *entities.User
+	orgID      string
+}
+
+// newSSOFixture cria uma organização cujo admin é admin@acme.com e um IdP OIDC falso
+func newSSOFixture(t *testing.T, users ...*entities.User) *ssoFixture {
+	t.Helper()
+
+	server := oauthtest.NewServer("client-id", "client-secret")
+	t.Cleanup(server.Close)
+
+	admin := newTestUser(t, "admin", "admin@acme.com", "Senha123")
+	userRepo := newFakeUserRepository(append(users, admin)...)
+	orgs := newFakeOrganizationRepository()
+	members := newFakeOrganizationMemberRepository(orgs)
+	configs := newFakeSSOConfigRepository()
+	identities := &fakeUserIdentityRepository{}
+	dns := newFakeTXTResolver()
+	authService := NewAuthService(userRepo, newFakeRefreshTokenRepository(), newFakeMFARepository(), fakeUnitOfWork{}, newTestJWTService(t), newTestPasswordHasher(t), newTestLockoutService(), nopLogger{})
+
+	org, err := NewOrganizationService(orgs, members, userRepo, newTestLockoutService(), fakeUnitOfWork{}, nopLogger{}).
+		Create(context.Background(), admin.ID, "Acme")
+	if err != nil {
+		t.Fatalf("falha ao criar organização: %v", err)
+	}
+
+	callbackURL := func(organizationID string) string {
+		return "http://localhost:8080/api/v1/auth/sso/" + organizationID + "/callback"
+	}
+
+	return &ssoFixture{
+		service: NewSSOService(
+			oauth.NewOIDCClient(oauth.OIDCConfig{AllowPrivateNetworks: true}), dns, configs, newFakeOAuthStateRepository(), identities,
+			userRepo, newFakeUserAccountRepository(), members, authService, callbackURL,
+			fakeUnitOfWork{}, nopLogger{},
+		),
+		server:     server,
+		users:      userRepo,
+		members:    members,
+		configs:    configs,
+		identities: identities,
+		dns:        dns,
+		admin:      admin,
+		orgID:      org.OrganizationID,
+	}
+}
+
+// configure habilita o SSO da organização apontando para o IdP falso e
+// comprova a posse dos domínios
+func (f *ssoFixture) configure(t *testing.T) *entities.SSOConfig {
+	t.Helper()
+
+	config := f.save(t, " Acme.com ", "@acme.com.br", "acme.com")
+	for _, d := range config.AllowedDomains {
+		f.publish(config, d)
+	}
+
+	config, err := f.service.VerifyDomains(context.Background(), f.admin.ID, f.orgID)
+	if err != nil {
+		t.Fatalf("falha ao verificar os domínios: %v", err)
+	}
+	return config
+}
+
+// save grava a configuração apontando para o IdP falso, sem verificar os domínios
+func (f *ssoFixture) save(t *testing.T, domains ...string) *entities.SSOConfig {
+	t.Helper()
+
+	config, err := f.service.SaveConfig(context.Background(), f.admin.ID, f.orgID, SSOConfigInput{
+		Issuer:         f.server.URL,
+		ClientID:       "client-id",
+		ClientSecret:   "client-secret",
+		AllowedDomains: domains,
+		Enabled:        true,
+	})
+	if err != nil {
+		t.Fatalf("falha ao configurar SSO: %v", err)
+	}
+	return config
+}
+
+// publish publica no DNS falso o registro TXT que comprova a posse do domínio
+func (f *ssoFixture) publish(config *entities.SSOConfig, domain string) {
+	name, value := config.DomainVerificationRecord(domain)
+	f.dns.records[name] = append(f.dns.records[name], value)
+}
+
+// authorize inicia o login e autoriza no IdP falso, retornando o callback
+func (f *ssoFixture) authorize(t *testing.T, user oauthtest.User) SSOCallbackInput {
+	t.Helper()
+	f.server.SetUser(user)
+
+	start, err := f.service.Start(context.Background(), f.orgID)
+	if err != nil {
+		t.Fatalf("falha ao iniciar login: %v", err)
+	}
+
+	code, state, err := f.server.Authorize(start.AuthURL)
+	if err != nil {
+		t.Fatalf("falha na autorização: %v", err)
+	}
+
+	return SSOCallbackInput{OrganizationID: f.orgID, State: state, Code: code, Locale: "en"}
+}
+
+func TestSSOService_SaveConfig(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("normaliza os domínios e valida o issuer", func(t *testing.T) {
+		f := newSSOFixture(t)
+		config := f.configure(t)
+
+		if len(config.AllowedDomains) != 2 || config.AllowedDomains[0] != "acme.com" || config.AllowedDomains[1] != "acme.com.br" {
+			t.Errorf("domínios inesperados: %v", config.AllowedDomains)
+		}
+		if config.DefaultRole != entities.RoleUser {
+			t.Errorf("esperava role padrão user, obteve '%s'", config.DefaultRole)
+		}
+		if config.OrganizationID != f.orgID {
+			t.Errorf("esperava configuração da organização, obteve '%s'", config.OrganizationID)
+		}
+	})
+
+	t.Run("substituir mantém o id", func(t *testing.T) {
+		f := newSSOFixture(t)
+		first := f.configure(t)
+		second := f.configure(t)
+
+		if second.ID != first.ID {
+			t.Errorf("esperava o mesmo id, obteve '%s' e '%s'", first.ID, second.ID)
+		}
+	})
+
+	t.Run("issuer sem documento de descoberta", func(t *testing.T) {
+		f := newSSOFixture(t)
+
+		_, err := f.service.SaveConfig(ctx, f.admin.ID, f.orgID, SSOConfigInput{
+			Issuer:         f.server.URL + "/desconhecido",
+			ClientID:       "client-id",
+			ClientSecret:   "client-secret",
+			AllowedDomains: []string{"acme.com"},
+		})
+		if !errors.Is(err, domainerrors.ErrInvalidSSOIssuer) {
+			t.Errorf("esperava ErrInvalidSSOIssuer, obteve %v", err)
+		}
+	})
+
+	t.Run("domínio inválido", func(t *testing.T) {
+		f := newSSOFixture(t)
+
+		_, err := f.service.SaveConfig(ctx, f.admin.ID, f.orgID, SSOConfigInput{
+			Issuer:         f.server.URL,
+			AllowedDomains: []string{"acme"},
+		})
+		if !errors.Is(err, domainerrors.ErrInvalidSSODomain) {
+			t.Errorf("esperava ErrInvalidSSODomain, obteve %v", err)
+		}
+	})
+
+	t.Run("apenas admins configuram", func(t *testing.T) {
+		bob := newTestUser(t, "bob", "bob@acme.com", "Senha123")
+		f := newSSOFixture(t, bob)
+		if err := f.members.Create(ctx, &entities.OrganizationMember{ID: "bob-acme", OrganizationID: f.orgID, UserID: bob.ID, Role: entities.RoleUser}); err != nil {
+			t.Fatalf("falha ao adicionar membro: %v", err)
+		}
+
+		_, err := f.service.SaveConfig(ctx, bob.ID, f.orgID, SSOConfigInput{Issuer: f.server.URL, AllowedDomains: []string{"acme.com"}})
+		if !errors.Is(err, domainerrors.ErrForbidden) {
+			t.Errorf("esperava ErrForbidden, obteve %v", err)
+		}
+	})
+}
+
+func TestSSOService_VerifyDomains(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("domínios começam pendentes", func(t *testing.T) {
+		f := newSSOFixture(t)
+		config := f.save(t, "acme.com")
+
+		if config.DomainVerificationToken == "" {
+			t.Error("esperava token de verificação")
+		}
+		if config.IsDomainVerified("acme.com") {
+			t.Error("não esperava domínio comprovado antes do registro TXT")
+		}
+	})
+
+	t.Run("comprova apenas os domínios que publicam o token", func(t *testing.T) {
+		f := newSSOFixture(t)
+		config := f.save(t, "acme.com", "gmail.com")
+		f.publish(config, "acme.com")
+		name, _ := config.DomainVerificationRecord("gmail.com")
+		f.dns.records[name] = []string{"avantpro-verification=outro-token"}
+
+		config, err := f.service.VerifyDomains(ctx, f.admin.ID, f.orgID)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if !config.IsDomainVerified("acme.com") || config.IsDomainVerified("gmail.com") {
+			t.Errorf("esperava apenas acme.com comprovado, obteve %v", config.VerifiedDomains)
+		}
+	})
+
+	t.Run("substituir a configuração mantém o token e os domínios que continuam", func(t *testing.T) {
+		f := newSSOFixture(t)
+		first := f.configure(t)
+
+		second := f.save(t, "acme.com", "acme.io")
+		if second.DomainVerificationToken != first.DomainVerificationToken {
+			t.Error("esperava o mesmo token de verificação")
+		}
+		if !second.IsDomainVerified("acme.com") || second.IsDomainVerified("acme.io") || second.IsDomainVerified("acme.com.br") {
+			t.Errorf("domínios comprovados inesperados: %v", second.VerifiedDomains)
+		}
+	})
+
+	t.Run("apenas admins verificam", func(t *testing.T) {
+		bob := newTestUser(t, "bob", "bob@acme.com", "Senha123")
+		f := newSSOFixture(t, bob)
+		f.save(t, "acme.com")
+		if err := f.members.Create(ctx, &entities.OrganizationMember{ID: "bob-acme", OrganizationID: f.orgID, UserID: bob.ID, Role: entities.RoleUser}); err != nil {
+			t.Fatalf("falha ao adicionar membro: %v", err)
+		}
+
+		if _, err := f.service.VerifyDomains(ctx, bob.ID, f.orgID); !errors.Is(err, domainerrors.ErrForbidden) {
+			t.Errorf("esperava ErrForbidden, obteve %v", err)
+		}
+	})
+}
+
+func TestSSOService_DeleteConfig(t *testing.T) {
+	ctx := context.Background()
+	f := newSSOFixture(t)
+	f.configure(t)
+
+	if err := f.service.DeleteConfig(ctx, f.admin.ID, f.orgID); err != nil {
+		t.Fatalf("esperava sucesso, obteve erro: %v", err)
+	}
+	if _, err := f.service.GetConfig(ctx, f.admin.ID, f.orgID); !errors.Is(err, domainerrors.ErrSSONotConfigured) {
+		t.Errorf("esperava ErrSSONotConfigured, obteve %v", err)
+	}
+	if _, err := f.service.Start(ctx, f.orgID); !errors.Is(err, domainerrors.ErrSSONotConfigured) {
+		t.Errorf("esperava login desabilitado, obteve %v", err)
+	}
+}
+
+func TestSSOService_Start(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("organização sem SSO", func(t *testing.T) {
+		f := newSSOFixture(t)
+
+		for _, organizationID := range []string{f.orgID, uuid.New().String(), "nao-e-uuid"} {
+			if _, err := f.service.Start(ctx, organizationID); !errors.Is(err, domainerrors.ErrSSONotConfigured) {
+				t.Errorf("%s: esperava ErrSSONotConfigured, obteve %v", organizationID, err)
+			}
+		}
+	})
+
+	t.Run("SSO desabilitado", func(t *testing.T) {
+		f := newSSOFixture(t)
+		f.configure(t).Enabled = false
+
+		if _, err := f.service.Start(ctx, f.orgID); !errors.Is(err, domainerrors.ErrSSONotConfigured) {
+			t.Errorf("esperava ErrSSONotConfigured, obteve %v", err)
+		}
+	})
+}
+
+func TestSSOService_Callback(t *testing.T) {
+	ctx := context.Background()
+	ana := oauthtest.User{
+		Subject:       "00u-ana",
+		Email:         "Ana@Acme.com",
+		EmailVerified: true,
+		Name:          "Ana Souza",
+	}
+
+	t.Run("provisiona usuário e membro no primeiro login", func(t *testing.T) {
+		f := newSSOFixture(t)
+		f.configure(t)
+
+		result, err := f.service.Callback(ctx, f.authorize(t, ana))
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if result.Auth.AccessToken == "" || result.Auth.RefreshToken == "" {
+			t.Error("esperava tokens emitidos")
+		}
+		if result.Membership == nil || result.Membership.OrganizationID != f.orgID || result.Membership.Role != entities.RoleUser {
+			t.Errorf("esperava membro user da organização, obteve %+v", result.Membership)
+		}
+
+		user, err := f.users.FindByEmail(ctx, "ana@acme.com")
+		if err != nil || !user.IsActive() || user.PasswordHash != "" {
+			t.Errorf("esperava usuário ativo e sem senha: %+v (erro: %v)", user, err)
+		}
+
+		if len(f.identities.identities) != 1 || f.identities.identities[0].Provider != entities.SSOProvider(f.orgID) {
+			t.Errorf("esperava identidade do provedor da organização, obteve %+v", f.identities.identities)
+		}
+	})
+
+	t.Run("logins seguintes usam o vínculo existente", func(t *testing.T) {
+		f := newSSOFixture(t)
+		f.configure(t)
+
+		first, err := f.service.Callback(ctx, f.authorize(t, ana))
+		if err != nil {
+			t.Fatalf("falha no primeiro login: %v", err)
+		}
+		second, err := f.service.Callback(ctx, f.authorize(t, ana))
+		if err != nil {
+			t.Fatalf("falha no segundo login: %v", err)
+		}
+		if second.Auth.User.ID != first.Auth.User.ID || len(f.members.members) != 2 {
+			t.Error("esperava o mesmo usuário e uma única associação")
+		}
+	})
+
+	t.Run("vincula o membro existente com o mesmo email", func(t *testing.T) {
+		f := newSSOFixture(t)
+		f.configure(t)
+
+		admin := ana
+		admin.Email = "admin@acme.com"
+
+		result, err := f.service.Callback(ctx, f.authorize(t, admin))
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if result.Auth.User.ID != f.admin.ID || result.Membership.Role != entities.RoleAdmin {
+			t.Errorf("esperava o admin existente, obteve %+v", result.Membership)
+		}
+	})
+
+	t.Run("descarta a senha do membro com cadastro pendente", func(t *testing.T) {
+		f := newSSOFixture(t)
+		f.configure(t)
+		f.admin.Status = entities.UserStatusInactive
+
+		admin := ana
+		admin.Email = "admin@acme.com"
+
+		if _, err := f.service.Callback(ctx, f.authorize(t, admin)); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+
+		user, _ := f.users.FindByID(ctx, f.admin.ID)
+		if !user.IsActive() || user.PasswordHash != "" {
+			t.Errorf("esperava conta ativa e sem a senha do cadastro pendente: %+v", user)
+		}
+	})
+
+	t.Run("email de usuário de fora da organização não é vinculado", func(t *testing.T) {
+		outsider := newTestUser(t, "ana", "ana@acme.com", "Senha123")
+		f := newSSOFixture(t, outsider)
+		f.configure(t)
+
+		if _, err := f.service.Callback(ctx, f.authorize(t, ana)); !errors.Is(err, domainerrors.ErrSSOAccountConflict) {
+			t.Errorf("esperava ErrSSOAccountConflict, obteve %v", err)
+		}
+		if len(f.identities.identities) != 0 {
+			t.Error("não esperava identidade vinculada")
+		}
+	})
+
+	t.Run("domínio fora da lista", func(t *testing.T) {
+		f := newSSOFixture(t)
+		f.configure(t)
+
+		other := ana
+		other.Email = "ana@gmail.com"
+
+		if _, err := f.service.Callback(ctx, f.authorize(t, other)); !errors.Is(err, domainerrors.ErrSSOEmailDomainNotAllowed) {
+			t.Errorf("esperava ErrSSOEmailDomainNotAllowed, obteve %v", err)
+		}
+	})
+
+	t.Run("domínio sem posse comprovada não entra", func(t *testing.T) {
+		f := newSSOFixture(t)
+		f.save(t, "acme.com")
+
+		if _, err := f.service.Callback(ctx, f.authorize(t, ana)); !errors.Is(err, domainerrors.ErrSSOEmailDomainNotAllowed) {
+			t.Errorf("esperava ErrSSOEmailDomainNotAllowed, obteve %v", err)
+		}
+		if _, err := f.users.FindByEmail(ctx, "ana@acme.com"); !errors.Is(err, domainerrors.ErrUserNotFound) {
+			t.Error("não esperava usuário provisionado")
+		}
+	})
+
+	t.Run("email não verificado", func(t *testing.T) {
+		f := newSSOFixture(t)
+		f.configure(t)
+
+		unverified := ana
+		unverified.EmailVerified = false
+
+		if _, err := f.service.Callback(ctx, f.authorize(t, unverified)); !errors.Is(err, domainerrors.ErrOAuthEmailNotVerified) {
+			t.Errorf("esperava ErrOAuthEmailNotVerified, obteve %v", err)
+		}
+	})
+
+	t.Run("membro removido não volta pelo SSO", func(t *testing.T) {
+		f := newSSOFixture(t)
+		f.configure(t)
+
+		first, err := f.service.Callback(ctx, f.authorize(t, ana))
+		if err != nil {
+			t.Fatalf("falha no primeiro login: %v", err)
+		}
+		if err := f.members.Delete(ctx, first.Membership.ID); err != nil {
+			t.Fatalf("falha ao remover membro: %v", err)
+		}
+
+		if _, err := f.service.Callback(ctx, f.authorize(t, ana)); !errors.Is(err, domainerrors.ErrForbidden) {
+			t.Errorf("esperava ErrForbidden, obteve %v", err)
+		}
+	})
+
+	t.Run("state de uso único", func(t *testing.T) {
+		f := newSSOFixture(t)
+		f.configure(t)
+		input := f.authorize(t, ana)
+
+		if _, err := f.service.Callback(ctx, input); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if _, err := f.service.Callback(ctx, input); !errors.Is(err, domainerrors.ErrInvalidOAuthState) {
+			t.Errorf("esperava ErrInvalidOAuthState, obteve %v", err)
+		}
+	})
+
+	t.Run("código recusado pelo IdP", func(t *testing.T) {
+		f := newSSOFixture(t)
+		f.configure(t)
+		input := f.authorize(t, ana)
+		input.Code = "codigo-invalido"
+
+		if _, err := f.service.Callback(ctx, input); !errors.Is(err, domainerrors.ErrOAuthExchangeFailed) {
+			t.Errorf("esperava ErrOAuthExchangeFailed, obteve %v", err)
+		}
+	})
+}
@@ -0,0 +1,386 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/repositories"
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+	"github.com/rafabene/avantpro-backend/internal/infrastructure/auth"
+)
+
+const (
+	// activationTokenTTL é a validade do link de ativação
+	activationTokenTTL = 24 * time.Hour
+	// activationResendLimit é o máximo de tokens emitidos por usuário na janela
+	activationResendLimit  = 3
+	activationResendWindow = time.Hour
+)
+
+// UserService implementa os casos de uso de cadastro e ativação de usuários
+type UserService struct {
+	userRepo       repositories.UserRepository
+	accountRepo    repositories.UserAccountRepository
+	activationRepo repositories.ActivationTokenRepository
+	orgRepo        repositories.OrganizationRepository
+	memberRepo     repositories.OrganizationMemberRepository
+	authService    *AuthService
+	notifier       domain.AccountNotifier
+	events         domain.EventPublisher
+	uow            domain.UnitOfWork
+	logger         domain.Logger
+}
+
+// NewUserService cria um novo UserService
+func NewUserService(
+	userRepo repositories.UserRepository,
+	accountRepo repositories.UserAccountRepository,
+	activationRepo repositories.ActivationTokenRepository,
+	orgRepo repositories.OrganizationRepository,
+	memberRepo repositories.OrganizationMemberRepository,
+	authService *AuthService,
+	notifier domain.AccountNotifier,
+	events domain.EventPublisher,
+	uow domain.UnitOfWork,
+	logger domain.Logger,
+) *UserService {
+	return &UserService{
+		userRepo:       userRepo,
+		accountRepo:    accountRepo,
+		activationRepo: activationRepo,
+		orgRepo:        orgRepo,
+		memberRepo:     memberRepo,
+		authService:    authService,
+		notifier:       notifier,
+		events:         events,
+		uow:            uow,
+		logger:         logger,
+	}
+}
+
+// SignupInput contém os dados do cadastro self-service
+type SignupInput struct {
+	Email            string
+	Password         string
+	OrganizationName string
+	Locale           string
+}
+
+// SignupResult é devolvido ao cliente tanto para emails novos quanto para
+// emails já cadastrados, para não revelar quais emails existem
+type SignupResult struct {
+	Email            string
+	OrganizationName string
+}
+
+// ActivationResult contém a sessão aberta pela ativação e a organização do usuário
+type ActivationResult struct {
+	Auth       *AuthResult
+	Membership *entities.OrganizationMember
+}
+
+// Signup cria usuário (inativo), perfil, organização, a associação do
+// criador como admin, o token de ativação e o email de ativação numa única transação
+// Um email já cadastrado não gera erro: a resposta é idêntica à de sucesso
+func (s *UserService) Signup(ctx context.Context, input SignupInput) (*SignupResult, error) {
+	email, err := valueobjects.NewEmail(input.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if errs := valueobjects.ValidatePassword(input.Password); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	result := &SignupResult{
+		Email:            email.String(),
+		OrganizationName: strings.TrimSpace(input.OrganizationName),
+	}
+
+	// O hash é gerado antes de tocar no banco para que emails novos e
+	// existentes levem o mesmo tempo de resposta
+	hash, err := s.authService.HashPassword(input.Password)
+	if err != nil {
+		s.logger.Error("failed to hash password", "error", err)
+		return nil, err
+	}
+
+	locale := input.Locale
+	if locale == "" {
+		locale = entities.DefaultAccountLocale
+	}
+
+	now := time.Now()
+
+	user := &entities.User{
+		ID:           uuid.New().String(),
+		Email:        email,
+		PasswordHash: hash,
+		Role:         entities.RoleUser,
+		Status:       entities.UserStatusInactive,
+	}
+
+	account := &entities.UserAccount{
+		ID:       uuid.New().String(),
+		UserID:   user.ID,
+		Locale:   locale,
+		Timezone: entities.DefaultAccountTimezone,
+		Theme:    entities.DefaultAccountTheme,
+	}
+
+	org := &entities.Organization{
+		ID:     uuid.New().String(),
+		Name:   result.OrganizationName,
+		Status: entities.OrganizationStatusActive,
+	}
+
+	member := &entities.OrganizationMember{
+		ID:             uuid.New().String(),
+		OrganizationID: org.ID,
+		UserID:         user.ID,
+		Role:           entities.RoleAdmin,
+		InvitedAt:      now,
+		JoinedAt:       &now,
+	}
+
+	ctx = domain.WithOrganizationID(ctx, org.ID)
+	err = s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.userRepo.Create(txCtx, user); err != nil {
+			return err
+		}
+		if err := s.accountRepo.Create(txCtx, account); err != nil {
+			return err
+		}
+		if err := s.orgRepo.Create(txCtx, org); err != nil {
+			return err
+		}
+		if err := s.memberRepo.Create(txCtx, member); err != nil {
+			return err
+		}
+
+		token, err := s.issueActivationToken(txCtx, user.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.sendActivation(txCtx, user, account.Locale, org.Name, token); err != nil {
+			return err
+		}
+
+		return s.events.Publish(txCtx, domain.Event{
+			Type:    domain.EventUserSignedUp,
+			Payload: domain.UserSignedUpEvent{UserID: user.ID, OrganizationID: org.ID},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrEmailAlreadyExists) {
+			s.logger.Info("signup attempted with existing email")
+			s.sendSignupAttempt(ctx, email.String())
+			return result, nil
+		}
+		s.logger.Error("failed to sign up user", "error", err)
+		return nil, err
+	}
+
+	s.logger.Info("user signed up", "user_id", user.ID, "organization_id", org.ID)
+	return result, nil
+}
+
+// Activate consome o token de ativação, ativa a conta e abre uma sessão
+func (s *UserService) Activate(ctx context.Context, token string) (*ActivationResult, error) {
+	stored, err := s.activationRepo.FindByHash(ctx, auth.HashToken(token))
+	if err != nil {
+		if !errors.Is(err, domainerrors.ErrInvalidActivationToken) {
+			s.logger.Error("failed to find activation token", "error", err)
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, domainerrors.ErrInvalidActivationToken
+		}
+		s.logger.Error("failed to find user", "user_id", stored.UserID, "error", err)
+		return nil, err
+	}
+
+	if user.IsActive() {
+		return nil, domainerrors.ErrAccountAlreadyActive
+	}
+	if user.Status != entities.UserStatusInactive || stored.IsUsed() || stored.IsRevoked() {
+		return nil, domainerrors.ErrInvalidActivationToken
+	}
+
+	now := time.Now()
+	if stored.IsExpired(now) {
+		return nil, domainerrors.ErrActivationTokenExpired
+	}
+
+	memberships, err := s.memberRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		s.logger.Error("failed to find memberships", "user_id", user.ID, "error", err)
+		return nil, err
+	}
+
+	result := &ActivationResult{}
+	if len(memberships) > 0 {
+		result.Membership = memberships[0]
+	}
+
+	err = s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.activationRepo.MarkAsUsed(txCtx, stored.ID); err != nil {
+			return err
+		}
+		if err := s.userRepo.Activate(txCtx, user.ID, now); err != nil {
+			return err
+		}
+
+		user.Status = entities.UserStatusActive
+		user.EmailVerifiedAt = &now
+
+		issued, err := s.authService.IssueTokens(txCtx, user)
+		if err != nil {
+			return err
+		}
+
+		result.Auth = issued
+		return s.events.Publish(txCtx, domain.Event{
+			Type:    domain.EventUserActivated,
+			Payload: domain.UserActivatedEvent{UserID: user.ID},
+		})
+	})
+	if err != nil {
+		if !errors.Is(err, domainerrors.ErrInvalidActivationToken) {
+			s.logger.Error("failed to activate user", "user_id", user.ID, "error", err)
+		}
+		return nil, err
+	}
+
+	s.logger.Info("user activated", "user_id", user.ID)
+	return result, nil
+}
+
+// ResendActivation emite um novo token de ativação, revogando os anteriores
+// Emails desconhecidos, contas já ativas e reenvios acima do limite não geram
+// erro, para não revelar quais emails estão cadastrados; o limite por email da
+// rota (429) vale para qualquer email
+func (s *UserService) ResendActivation(ctx context.Context, email string) error {
+	normalized, err := valueobjects.NewEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, normalized.String())
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil
+		}
+		s.logger.Error("failed to find user", "error", err)
+		return err
+	}
+
+	if user.Status != entities.UserStatusInactive {
+		s.logger.Info("activation resend ignored", "user_id", user.ID, "status", user.Status)
+		return nil
+	}
+
+	count, err := s.activationRepo.CountCreatedSince(ctx, user.ID, time.Now().Add(-activationResendWindow))
+	if err != nil {
+		s.logger.Error("failed to count activation tokens", "user_id", user.ID, "error", err)
+		return err
+	}
+	if count >= activationResendLimit {
+		s.logger.Warn("activation resend rate limited", "user_id", user.ID)
+		return nil
+	}
+
+	// O nome da organização compõe o assunto do email; sem ele o email segue genérico
+	var organizationName string
+	if memberships, err := s.memberRepo.FindByUserID(ctx, user.ID); err == nil && len(memberships) > 0 {
+		organizationName = memberships[0].Organization.Name
+	}
+	locale := s.localeOf(ctx, user.ID)
+
+	err = s.uow.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.activationRepo.RevokeByUser(txCtx, user.ID); err != nil {
+			return err
+		}
+
+		token, err := s.issueActivationToken(txCtx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		return s.sendActivation(txCtx, user, locale, organizationName, token)
+	})
+	if err != nil {
+		s.logger.Error("failed to resend activation", "user_id", user.ID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// issueActivationToken gera um token de ativação e persiste apenas o hash
+func (s *UserService) issueActivationToken(ctx context.Context, userID string) (string, error) {
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	stored := &entities.ActivationToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		TokenHash: auth.HashToken(token),
+		ExpiresAt: time.Now().Add(activationTokenTTL),
+	}
+	if err := s.activationRepo.Create(ctx, stored); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// sendActivation entrega o token de ativação
+// Chamado dentro da transação: com o outbox, o email só sai se ela for confirmada
+func (s *UserService) sendActivation(ctx context.Context, user *entities.User, locale, organizationName, token string) error {
+	return s.notifier.SendActivation(ctx, domain.ActivationNotice{
+		Email:            user.Email.String(),
+		Locale:           locale,
+		OrganizationName: organizationName,
+		Token:            token,
+	})
+}
+
+// sendSignupAttempt avisa o dono do email sobre a tentativa de cadastro,
+// no idioma do perfil dele e não no de quem tentou se cadastrar
+func (s *UserService) sendSignupAttempt(ctx context.Context, email string) {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		s.logger.Error("failed to find user for signup attempt notice", "error", err)
+		return
+	}
+
+	notice := domain.SignupAttemptNotice{
+		Email:  user.Email.String(),
+		Locale: s.localeOf(ctx, user.ID),
+	}
+	if err := s.notifier.SendSignupAttempt(ctx, notice); err != nil {
+		s.logger.Error("failed to send signup attempt notice", "user_id", user.ID, "error", err)
+	}
+}
+
+// localeOf retorna o idioma do perfil do usuário, ou o padrão se não houver perfil
+func (s *UserService) localeOf(ctx context.Context, userID string) string {
+	if account, err := s.accountRepo.FindByUserID(ctx, userID); err == nil {
+		return account.Locale
+	}
+	return entities.DefaultAccountLocale
+}
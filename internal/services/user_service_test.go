@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	"github.com/rafabene/avantpro-backend/internal/domain/entities"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	"github.com/rafabene/avantpro-backend/internal/domain/valueobjects"
+)
+
+type userFixture struct {
+	service     *UserService
+	users       *fakeUserRepository
+	accounts    *fakeUserAccountRepository
+	activations *fakeActivationTokenRepository
+	orgs        *fakeOrganizationRepository
+	members     *fakeOrganizationMemberRepository
+	notifier    *fakeNotifier
+	events      *fakeEventPublisher
+}
+
+func newUserFixture(t *testing.T, users ...*entities.User) *userFixture {
+	t.Helper()
+
+	userRepo := newFakeUserRepository(users...)
+	accounts := newFakeUserAccountRepository()
+	activations := newFakeActivationTokenRepository()
+	orgs := newFakeOrganizationRepository()
+	members := newFakeOrganizationMemberRepository(orgs)
+	notifier := newFakeNotifier()
+	events := &fakeEventPublisher{}
+	authService := NewAuthService(userRepo, newFakeRefreshTokenRepository(), newFakeMFARepository(), fakeUnitOfWork{}, newTestJWTService(t), newTestPasswordHasher(t), newTestLockoutService(), nopLogger{})
+
+	return &userFixture{
+		service: NewUserService(
+			userRepo, accounts, activations, orgs, members,
+			authService, notifier, events, fakeUnitOfWork{}, nopLogger{},
+		),
+		users:       userRepo,
+		accounts:    accounts,
+		activations: activations,
+		orgs:        orgs,
+		members:     members,
+		notifier:    notifier,
+		events:      events,
+	}
+}
+
+// signup cadastra um usuário e retorna o token de ativação enviado
+func (f *userFixture) signup(t *testing.T, email string) string {
+	t.Helper()
+
+	_, err := f.service.Signup(context.Background(), SignupInput{
+		Email:            email,
+		Password:         "Senha123",
+		OrganizationName: "Empresa",
+	})
+	if err != nil {
+		t.Fatalf("falha no cadastro: %v", err)
+	}
+
+	token, ok := f.notifier.activations[email]
+	if !ok {
+		t.Fatal("esperava token de ativação enviado")
+	}
+	return token
+}
+
+func TestUserService_Signup(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cria usuário inativo, perfil, organização e admin", func(t *testing.T) {
+		f := newUserFixture(t)
+
+		result, err := f.service.Signup(ctx, SignupInput{
+			Email:            "  Joao@Email.com ",
+			Password:         "Senha123",
+			OrganizationName: " Minha Empresa ",
+		})
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if result.Email != "joao@email.com" || result.OrganizationName != "Minha Empresa" {
+			t.Errorf("resultado inesperado: %+v", result)
+		}
+
+		user, err := f.users.FindByEmail(ctx, "joao@email.com")
+		if err != nil {
+			t.Fatalf("esperava usuário criado, obteve erro: %v", err)
+		}
+		if user.Status != entities.UserStatusInactive {
+			t.Errorf("esperava status inactive, obteve '%s'", user.Status)
+		}
+		if user.PasswordHash == "Senha123" {
+			t.Error("senha não deveria ser armazenada em texto plano")
+		}
+
+		account, err := f.accounts.FindByUserID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("esperava perfil criado, obteve erro: %v", err)
+		}
+		if account.Locale != entities.DefaultAccountLocale {
+			t.Errorf("esperava locale padrão, obteve '%s'", account.Locale)
+		}
+
+		memberships, _ := f.members.FindByUserID(ctx, user.ID)
+		if len(memberships) != 1 {
+			t.Fatalf("esperava 1 organização, obteve %d", len(memberships))
+		}
+		if memberships[0].Role != entities.RoleAdmin {
+			t.Errorf("esperava criador como admin, obteve '%s'", memberships[0].Role)
+		}
+
+		if types := f.events.types(); len(types) != 1 || types[0] != domain.EventUserSignedUp {
+			t.Errorf("esperava evento %s, obteve %v", domain.EventUserSignedUp, types)
+		}
+	})
+
+	t.Run("falha ao gravar o email de ativação aborta o cadastro", func(t *testing.T) {
+		f := newUserFixture(t)
+		outboxErr := errors.New("outbox unavailable")
+		f.notifier.err = outboxErr
+
+		_, err := f.service.Signup(ctx, SignupInput{Email: "joao@email.com", Password: "Senha123", OrganizationName: "Empresa"})
+		if !errors.Is(err, outboxErr) {
+			t.Errorf("esperava erro do outbox propagado para desfazer a transação, obteve %v", err)
+		}
+		if len(f.events.events) != 0 {
+			t.Error("não esperava evento publicado")
+		}
+	})
+
+	t.Run("email existente retorna a mesma resposta sem criar nada", func(t *testing.T) {
+		existing := newTestUser(t, "alice", "alice@example.com", "Senha123")
+		f := newUserFixture(t, existing)
+
+		result, err := f.service.Signup(ctx, SignupInput{
+			Email:            "alice@example.com",
+			Password:         "Outra123",
+			OrganizationName: "Empresa",
+		})
+		if err != nil {
+			t.Fatalf("esperava resposta anti-enumeração, obteve erro: %v", err)
+		}
+		if result.Email != "alice@example.com" {
+			t.Errorf("esperava email ecoado, obteve '%s'", result.Email)
+		}
+		if len(f.orgs.orgs) != 0 || len(f.accounts.accounts) != 0 {
+			t.Error("não esperava organização nem perfil criados")
+		}
+		if f.notifier.signupAttempts["alice@example.com"] != 1 {
+			t.Errorf("esperava aviso de tentativa de cadastro, obteve %d", f.notifier.signupAttempts["alice@example.com"])
+		}
+		if len(f.notifier.activations) != 0 {
+			t.Error("não esperava token de ativação enviado")
+		}
+	})
+
+	t.Run("senha fora da política", func(t *testing.T) {
+		f := newUserFixture(t)
+
+		_, err := f.service.Signup(ctx, SignupInput{Email: "joao@email.com", Password: "abc", OrganizationName: "Empresa"})
+		if !errors.Is(err, domainerrors.ErrPasswordLength) || !errors.Is(err, domainerrors.ErrPasswordNoNumber) {
+			t.Errorf("esperava erros de tamanho e número, obteve %v", err)
+		}
+	})
+
+	t.Run("email inválido", func(t *testing.T) {
+		f := newUserFixture(t)
+
+		_, err := f.service.Signup(ctx, SignupInput{Email: "invalido", Password: "Senha123", OrganizationName: "Empresa"})
+		if !errors.Is(err, valueobjects.ErrInvalidEmail) {
+			t.Errorf("esperava ErrInvalidEmail, obteve %v", err)
+		}
+	})
+}
+
+func TestUserService_Activate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ativa a conta e abre sessão", func(t *testing.T) {
+		f := newUserFixture(t)
+		token := f.signup(t, "joao@email.com")
+
+		result, err := f.service.Activate(ctx, token)
+		if err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if result.Auth.AccessToken == "" || result.Auth.RefreshToken == "" {
+			t.Error("esperava tokens emitidos")
+		}
+		if result.Membership == nil || result.Membership.Organization.Name != "Empresa" {
+			t.Errorf("esperava organização do cadastro, obteve %+v", result.Membership)
+		}
+
+		user, _ := f.users.FindByEmail(ctx, "joao@email.com")
+		if !user.IsActive() || user.EmailVerifiedAt == nil {
+			t.Error("esperava conta ativa com email verificado")
+		}
+
+		types := f.events.types()
+		if len(types) != 2 || types[1] != domain.EventUserActivated {
+			t.Errorf("esperava evento %s após o cadastro, obteve %v", domain.EventUserActivated, types)
+		}
+	})
+
+	t.Run("token de uso único", func(t *testing.T) {
+		f := newUserFixture(t)
+		token := f.signup(t, "joao@email.com")
+
+		if _, err := f.service.Activate(ctx, token); err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if _, err := f.service.Activate(ctx, token); !errors.Is(err, domainerrors.ErrAccountAlreadyActive) {
+			t.Errorf("esperava ErrAccountAlreadyActive, obteve %v", err)
+		}
+	})
+
+	t.Run("token desconhecido", func(t *testing.T) {
+		f := newUserFixture(t)
+
+		if _, err := f.service.Activate(ctx, "inexistente"); !errors.Is(err, domainerrors.ErrInvalidActivationToken) {
+			t.Errorf("esperava ErrInvalidActivationToken, obteve %v", err)
+		}
+	})
+
+	t.Run("token expirado", func(t *testing.T) {
+		f := newUserFixture(t)
+		token := f.signup(t, "joao@email.com")
+
+		for _, stored := range f.activations.tokens {
+			stored.ExpiresAt = time.Now().Add(-time.Minute)
+		}
+
+		if _, err := f.service.Activate(ctx, token); !errors.Is(err, domainerrors.ErrActivationTokenExpired) {
+			t.Errorf("esperava ErrActivationTokenExpired, obteve %v", err)
+		}
+	})
+
+	t.Run("reenvio invalida o token anterior", func(t *testing.T) {
+		f := newUserFixture(t)
+		first := f.signup(t, "joao@email.com")
+
+		if err := f.service.ResendActivation(ctx, "joao@email.com"); err != nil {
+			t.Fatalf("esperava sucesso no reenvio, obteve erro: %v", err)
+		}
+		second := f.notifier.activations["joao@email.com"]
+
+		if _, err := f.service.Activate(ctx, first); !errors.Is(err, domainerrors.ErrInvalidActivationToken) {
+			t.Errorf("esperava token anterior inválido, obteve %v", err)
+		}
+		if _, err := f.service.Activate(ctx, second); err != nil {
+			t.Errorf("esperava ativação com o novo token, obteve erro: %v", err)
+		}
+	})
+}
+
+func TestUserService_ResendActivation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("email desconhecido não gera erro nem envio", func(t *testing.T) {
+		f := newUserFixture(t)
+
+		if err := f.service.ResendActivation(ctx, "ninguem@email.com"); err != nil {
+			t.Errorf("esperava resposta genérica, obteve %v", err)
+		}
+		if len(f.notifier.activations) != 0 {
+			t.Error("não esperava envio para email desconhecido")
+		}
+	})
+
+	t.Run("limita reenvios por hora", func(t *testing.T) {
+		f := newUserFixture(t)
+		f.signup(t, "joao@email.com")
+
+		for i := 1; i < activationResendLimit; i++ {
+			if err := f.service.ResendActivation(ctx, "joao@email.com"); err != nil {
+				t.Fatalf("reenvio %d: esperava sucesso, obteve %v", i, err)
+			}
+		}
+
+		sent := f.notifier.activations["joao@email.com"]
+		if err := f.service.ResendActivation(ctx, "joao@email.com"); err != nil {
+			t.Errorf("esperava resposta genérica acima do limite, obteve %v", err)
+		}
+		if f.notifier.activations["joao@email.com"] != sent {
+			t.Error("não esperava novo envio acima do limite")
+		}
+	})
+}
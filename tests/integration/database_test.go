@@ -0,0 +1,44 @@
+// Package integration reúne os testes que rodam contra um Postgres real
+//
+// Os testes são pulados quando TEST_DATABASE_URL não está definida; o
+// `make test/integration` aponta para o banco do docker-compose, que precisa
+// estar com as migrations aplicadas (`make db/migrate-up`).
+package integration
+
+import (
+	"os"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	persistence "github.com/rafabene/avantpro-backend/internal/infrastructure/persistence/postgres"
+)
+
+// openDatabase conecta no banco de TEST_DATABASE_URL com o TenantPlugin registrado
+func openDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+
+	if err := db.Use(persistence.TenantPlugin{}); err != nil {
+		t.Fatalf("failed to register tenant plugin: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	return db
+}
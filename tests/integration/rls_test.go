@@ -0,0 +1,281 @@
+package integration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// rlsRole é o papel sem privilégios de dono usado para exercitar as policies
+// O dono das tabelas e superusuários ignoram o RLS; um papel comum, não
+const rlsRole = "avantpro_rls_probe"
+
+// sqlStateRLSViolation é o erro do Postgres para linhas barradas pelo WITH CHECK
+const sqlStateRLSViolation = "42501"
+
+// tenant são as linhas semeadas para uma organização
+type tenant struct {
+	orgID    string
+	userID   string
+	memberID string
+	inviteID string
+}
+
+// setupRLSRole cria o papel de teste com acesso às tabelas de negócio
+func setupRLSRole(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	statements := []string{
+		fmt.Sprintf(`DO $$ BEGIN
+			IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = '%[1]s') THEN
+				CREATE ROLE %[1]s NOLOGIN;
+			END IF;
+		END $$`, rlsRole),
+		fmt.Sprintf("GRANT SELECT, INSERT, UPDATE, DELETE ON invites, organization_members TO %s", rlsRole),
+		fmt.Sprintf("GRANT %s TO CURRENT_USER", rlsRole),
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("failed to set up rls role: %v", err)
+		}
+	}
+
+	t.Cleanup(func() {
+		_ = db.Exec(fmt.Sprintf("DROP OWNED BY %s", rlsRole)).Error
+		_ = db.Exec(fmt.Sprintf("DROP ROLE IF EXISTS %s", rlsRole)).Error
+	})
+}
+
+// seedTenant cria uma organização com um membro e um convite
+// As linhas são inseridas com app.current_org definido, então o seed também
+// funciona quando o usuário do teste é o dono das tabelas (FORCE RLS)
+func seedTenant(t *testing.T, db *gorm.DB) tenant {
+	t.Helper()
+
+	seeded := tenant{
+		orgID:    uuid.New().String(),
+		userID:   uuid.New().String(),
+		memberID: uuid.New().String(),
+		inviteID: uuid.New().String(),
+	}
+	now := time.Now().Unix()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		statements := []struct {
+			sql  string
+			args []any
+		}{
+			{fmt.Sprintf("SET LOCAL app.current_org = '%s'", seeded.orgID), nil},
+			{"INSERT INTO users (id, email, name, password_hash, status) VALUES (?, ?, 'RLS', 'x', 'active')",
+				[]any{seeded.userID, seeded.userID + "@rls.test"}},
+			{"INSERT INTO organizations (id, name) VALUES (?, 'RLS')", []any{seeded.orgID}},
+			{"INSERT INTO organization_members (id, organization_id, user_id, role, invited_at) VALUES (?, ?, ?, 'admin', ?)",
+				[]any{seeded.memberID, seeded.orgID, seeded.userID, now}},
+			{"INSERT INTO invites (id, organization_id, email, role, token_hash, invited_by, expires_at) VALUES (?, ?, 'convidado@rls.test', 'user', ?, ?, ?)",
+				[]any{seeded.inviteID, seeded.orgID, tokenHash(), seeded.userID, now + 3600}},
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement.sql, statement.args...).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to seed tenant: %v", err)
+	}
+
+	// A exclusão da organização remove membros e convites em cascata
+	t.Cleanup(func() {
+		_ = db.Exec("DELETE FROM organizations WHERE id = ?", seeded.orgID).Error
+		_ = db.Exec("DELETE FROM users WHERE id = ?", seeded.userID).Error
+	})
+
+	return seeded
+}
+
+// tokenHash gera um token_hash único para o convite
+func tokenHash() string {
+	sum := sha256.Sum256([]byte(uuid.New().String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// asTenant executa fn como o papel de teste, com app.current_org definido
+// (vazio deixa sem organização); a transação é sempre desfeita
+func asTenant(t *testing.T, db *gorm.DB, orgID string, fn func(tx *gorm.DB) error) error {
+	t.Helper()
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("failed to begin transaction: %v", tx.Error)
+	}
+	defer tx.Rollback()
+
+	if err := tx.Exec(fmt.Sprintf("SET LOCAL ROLE %s", rlsRole)).Error; err != nil {
+		t.Fatalf("failed to set role: %v", err)
+	}
+	if orgID != "" {
+		switchOrganization(t, tx, orgID)
+	}
+
+	return fn(tx)
+}
+
+// switchOrganization troca o app.current_org da transação
+func switchOrganization(t *testing.T, tx *gorm.DB, orgID string) {
+	t.Helper()
+
+	if err := tx.Exec(fmt.Sprintf("SET LOCAL app.current_org = '%s'", orgID)).Error; err != nil {
+		t.Fatalf("failed to set current org: %v", err)
+	}
+}
+
+// countRows conta as linhas visíveis de uma organização na tabela
+func countRows(tx *gorm.DB, table, orgID string) (int64, error) {
+	var count int64
+	err := tx.Raw(fmt.Sprintf("SELECT count(*) FROM %s WHERE organization_id = ?", table), orgID).Scan(&count).Error
+	return count, err
+}
+
+// assertRLSViolation verifica se o Postgres barrou a escrita pela policy
+func assertRLSViolation(t *testing.T, err error) {
+	t.Helper()
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != sqlStateRLSViolation {
+		t.Errorf("esperava violação de RLS (%s), obteve %v", sqlStateRLSViolation, err)
+	}
+}
+
+func TestRowLevelSecurity(t *testing.T) {
+	db := openDatabase(t)
+	setupRLSRole(t, db)
+
+	orgA := seedTenant(t, db)
+	orgB := seedTenant(t, db)
+
+	tables := []struct {
+		name string
+		// insertInto grava na organização informada uma linha nova
+		insertInto func(tx *gorm.DB, target tenant) error
+	}{
+		{
+			name: "invites",
+			insertInto: func(tx *gorm.DB, target tenant) error {
+				return tx.Exec(
+					"INSERT INTO invites (id, organization_id, email, role, token_hash, invited_by, expires_at) VALUES (?, ?, 'intruso@rls.test', 'user', ?, ?, ?)",
+					uuid.New().String(), target.orgID, tokenHash(), target.userID, time.Now().Unix()+3600,
+				).Error
+			},
+		},
+		{
+			name: "organization_members",
+			insertInto: func(tx *gorm.DB, target tenant) error {
+				return tx.Exec(
+					"INSERT INTO organization_members (id, organization_id, user_id, role, invited_at) VALUES (?, ?, ?, 'user', ?)",
+					uuid.New().String(), target.orgID, orgA.userID, time.Now().Unix(),
+				).Error
+			},
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Run("leitura só enxerga a organização atual", func(t *testing.T) {
+				_ = asTenant(t, db, orgA.orgID, func(tx *gorm.DB) error {
+					own, err := countRows(tx, table.name, orgA.orgID)
+					if err != nil || own != 1 {
+						t.Errorf("esperava 1 linha da própria organização, obteve %d (%v)", own, err)
+					}
+					other, err := countRows(tx, table.name, orgB.orgID)
+					if err != nil || other != 0 {
+						t.Errorf("esperava 0 linhas da outra organização, obteve %d (%v)", other, err)
+					}
+					return nil
+				})
+			})
+
+			t.Run("update não afeta a outra organização", func(t *testing.T) {
+				_ = asTenant(t, db, orgA.orgID, func(tx *gorm.DB) error {
+					result := tx.Exec(fmt.Sprintf("UPDATE %s SET role = 'guest' WHERE organization_id = ?", table.name), orgB.orgID)
+					if result.Error != nil || result.RowsAffected != 0 {
+						t.Errorf("esperava 0 linhas afetadas, obteve %d (%v)", result.RowsAffected, result.Error)
+					}
+
+					// Na mesma transação, a linha de B continua com o papel original
+					switchOrganization(t, tx, orgB.orgID)
+					var role string
+					if err := tx.Raw(fmt.Sprintf("SELECT role FROM %s WHERE organization_id = ?", table.name), orgB.orgID).Scan(&role).Error; err != nil || role == "guest" {
+						t.Errorf("esperava a linha de B inalterada, obteve papel '%s' (%v)", role, err)
+					}
+					return nil
+				})
+			})
+
+			t.Run("delete não afeta a outra organização", func(t *testing.T) {
+				_ = asTenant(t, db, orgA.orgID, func(tx *gorm.DB) error {
+					result := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE organization_id = ?", table.name), orgB.orgID)
+					if result.Error != nil || result.RowsAffected != 0 {
+						t.Errorf("esperava 0 linhas afetadas, obteve %d (%v)", result.RowsAffected, result.Error)
+					}
+
+					switchOrganization(t, tx, orgB.orgID)
+					if count, err := countRows(tx, table.name, orgB.orgID); err != nil || count != 1 {
+						t.Errorf("esperava a linha de B preservada, obteve %d (%v)", count, err)
+					}
+					return nil
+				})
+			})
+
+			t.Run("insert na outra organização é barrado", func(t *testing.T) {
+				err := asTenant(t, db, orgA.orgID, func(tx *gorm.DB) error {
+					return table.insertInto(tx, orgB)
+				})
+				assertRLSViolation(t, err)
+			})
+
+			t.Run("mover linha para a outra organização é barrado", func(t *testing.T) {
+				err := asTenant(t, db, orgA.orgID, func(tx *gorm.DB) error {
+					return tx.Exec(fmt.Sprintf("UPDATE %s SET organization_id = ? WHERE organization_id = ?", table.name), orgB.orgID, orgA.orgID).Error
+				})
+				assertRLSViolation(t, err)
+			})
+
+			t.Run("sem organização nenhuma linha é visível", func(t *testing.T) {
+				_ = asTenant(t, db, "", func(tx *gorm.DB) error {
+					for _, seeded := range []tenant{orgA, orgB} {
+						if count, err := countRows(tx, table.name, seeded.orgID); err != nil || count != 0 {
+							t.Errorf("esperava 0 linhas, obteve %d (%v)", count, err)
+						}
+					}
+					return nil
+				})
+			})
+		})
+	}
+
+	t.Run("member_self libera só a leitura das próprias associações", func(t *testing.T) {
+		_ = asTenant(t, db, orgA.orgID, func(tx *gorm.DB) error {
+			if err := tx.Exec(fmt.Sprintf("SET LOCAL app.current_user = '%s'", orgB.userID)).Error; err != nil {
+				t.Fatalf("failed to set current user: %v", err)
+			}
+
+			if count, err := countRows(tx, "organization_members", orgB.orgID); err != nil || count != 1 {
+				t.Errorf("esperava a própria associação em B, obteve %d (%v)", count, err)
+			}
+
+			result := tx.Exec("UPDATE organization_members SET role = 'guest' WHERE organization_id = ?", orgB.orgID)
+			if result.Error != nil || result.RowsAffected != 0 {
+				t.Errorf("esperava 0 linhas afetadas, obteve %d (%v)", result.RowsAffected, result.Error)
+			}
+			return nil
+		})
+	})
+}
@@ -0,0 +1,167 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/rafabene/avantpro-backend/internal/domain"
+	domainerrors "github.com/rafabene/avantpro-backend/internal/domain/errors"
+	persistence "github.com/rafabene/avantpro-backend/internal/infrastructure/persistence/postgres"
+)
+
+// noteModel é um model de negócio numa tabela sem RLS, para que só o
+// TenantPlugin isole as organizações
+type noteModel struct {
+	ID string `gorm:"type:uuid;primary_key"`
+	persistence.TenantModel
+	Title string
+}
+
+func (noteModel) TableName() string {
+	return "tenant_plugin_notes"
+}
+
+// setupNotes cria a tabela de notas e a remove ao final do teste
+func setupNotes(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	if err := db.Exec(`CREATE TABLE tenant_plugin_notes (
+		id UUID PRIMARY KEY,
+		organization_id UUID NOT NULL,
+		title VARCHAR(255) NOT NULL
+	)`).Error; err != nil {
+		t.Fatalf("failed to create notes table: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = db.Exec("DROP TABLE IF EXISTS tenant_plugin_notes").Error
+	})
+}
+
+func TestTenantPlugin_ExecutedQueries(t *testing.T) {
+	db := openDatabase(t)
+	setupNotes(t, db)
+
+	orgA := uuid.New().String()
+	orgB := uuid.New().String()
+	ctxA := domain.WithOrganizationID(context.Background(), orgA)
+	ctxB := domain.WithOrganizationID(context.Background(), orgB)
+
+	noteA := &noteModel{ID: uuid.New().String(), Title: "nota A"}
+	noteB := &noteModel{ID: uuid.New().String(), Title: "nota B"}
+	if err := db.WithContext(ctxA).Create(noteA).Error; err != nil {
+		t.Fatalf("failed to seed org A: %v", err)
+	}
+	if err := db.WithContext(ctxB).Create(noteB).Error; err != nil {
+		t.Fatalf("failed to seed org B: %v", err)
+	}
+
+	// assertNoteB confere que a nota de B continua intacta
+	assertNoteB := func(t *testing.T) {
+		t.Helper()
+
+		var note noteModel
+		if err := db.WithContext(ctxB).First(&note, "id = ?", noteB.ID).Error; err != nil {
+			t.Fatalf("esperava a nota de B, obteve erro: %v", err)
+		}
+		if note.Title != "nota B" || note.OrganizationID != orgB {
+			t.Errorf("nota de B alterada: %+v", note)
+		}
+	}
+
+	t.Run("create preenche a organização do contexto", func(t *testing.T) {
+		if noteA.OrganizationID != orgA || noteB.OrganizationID != orgB {
+			t.Errorf("esperava organizações %s e %s, obteve %s e %s", orgA, orgB, noteA.OrganizationID, noteB.OrganizationID)
+		}
+	})
+
+	t.Run("leitura de A não retorna linhas de B", func(t *testing.T) {
+		var notes []noteModel
+		if err := db.WithContext(ctxA).Find(&notes).Error; err != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", err)
+		}
+		if len(notes) != 1 || notes[0].ID != noteA.ID {
+			t.Errorf("esperava só a nota de A, obteve %+v", notes)
+		}
+
+		var note noteModel
+		err := db.WithContext(ctxA).First(&note, "id = ?", noteB.ID).Error
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("esperava ErrRecordNotFound, obteve %v", err)
+		}
+
+		var count int64
+		if err := db.WithContext(ctxA).Model(&noteModel{}).Where("id = ?", noteB.ID).Count(&count).Error; err != nil || count != 0 {
+			t.Errorf("esperava contagem 0, obteve %d (%v)", count, err)
+		}
+	})
+
+	t.Run("update de A não afeta linhas de B", func(t *testing.T) {
+		result := db.WithContext(ctxA).Model(&noteModel{}).Where("id = ?", noteB.ID).Update("title", "alterada")
+		if result.Error != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", result.Error)
+		}
+		if result.RowsAffected != 0 {
+			t.Errorf("esperava 0 linhas afetadas, obteve %d", result.RowsAffected)
+		}
+
+		assertNoteB(t)
+	})
+
+	t.Run("delete de A não afeta linhas de B", func(t *testing.T) {
+		result := db.WithContext(ctxA).Where("id = ?", noteB.ID).Delete(&noteModel{})
+		if result.Error != nil {
+			t.Fatalf("esperava sucesso, obteve erro: %v", result.Error)
+		}
+		if result.RowsAffected != 0 {
+			t.Errorf("esperava 0 linhas afetadas, obteve %d", result.RowsAffected)
+		}
+
+		assertNoteB(t)
+	})
+
+	t.Run("create com organização de outro tenant é rejeitado", func(t *testing.T) {
+		note := &noteModel{ID: uuid.New().String(), TenantModel: persistence.TenantModel{OrganizationID: orgB}, Title: "intrusa"}
+		if err := db.WithContext(ctxA).Create(note).Error; !errors.Is(err, domainerrors.ErrCrossTenantAccess) {
+			t.Errorf("esperava ErrCrossTenantAccess, obteve %v", err)
+		}
+	})
+
+	t.Run("queries sem organização retornam erro", func(t *testing.T) {
+		ctx := context.Background()
+
+		tests := map[string]func() error{
+			"find": func() error {
+				var notes []noteModel
+				return db.WithContext(ctx).Find(&notes).Error
+			},
+			"count": func() error {
+				var count int64
+				return db.WithContext(ctx).Model(&noteModel{}).Count(&count).Error
+			},
+			"update": func() error {
+				return db.WithContext(ctx).Model(&noteModel{}).Where("id = ?", noteB.ID).Update("title", "alterada").Error
+			},
+			"delete": func() error {
+				return db.WithContext(ctx).Where("id = ?", noteB.ID).Delete(&noteModel{}).Error
+			},
+			"create": func() error {
+				return db.WithContext(ctx).Create(&noteModel{ID: uuid.New().String(), Title: "sem organização"}).Error
+			},
+		}
+
+		for name, run := range tests {
+			t.Run(name, func(t *testing.T) {
+				if err := run(); !errors.Is(err, domainerrors.ErrMissingOrganization) {
+					t.Errorf("esperava ErrMissingOrganization, obteve %v", err)
+				}
+			})
+		}
+
+		assertNoteB(t)
+	})
+}